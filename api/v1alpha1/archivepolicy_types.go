@@ -0,0 +1,142 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// defaultArchiveWarning is how long before archiving an inactive channel a warning is posted to it
+const defaultArchiveWarning = 7 * 24 * time.Hour
+
+// ArchivePolicySpec defines which Channels are archived when they become inactive
+type ArchivePolicySpec struct {
+	// Namespaces of the Channels the policy applies to, supports wildcards, e.g. team-*. All namespaces when empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Labels of the Channels the policy applies to, all Channels in the namespaces when unset
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Channels without a message for this long are archived
+	// +required
+	Inactivity metav1.Duration `json:"inactivity"`
+
+	// How long before archiving a channel a warning is posted to it
+	// +kubebuilder:default="168h"
+	// +optional
+	WarningBefore *metav1.Duration `json:"warningBefore,omitempty"`
+
+	// Text of the warning, a default text naming the archive date is posted when empty
+	// +optional
+	WarningMessage string `json:"warningMessage,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// ArchivePolicy is the Schema for the archivepolicies API, Channels without their own autoArchiveAfterInactivity
+// are archived after the inactivity of the strictest policy applying to them
+type ArchivePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ArchivePolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ArchivePolicyList contains a list of ArchivePolicy
+type ArchivePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ArchivePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ArchivePolicy{}, &ArchivePolicyList{})
+}
+
+// AutoArchive is how an inactive channel is archived
+type AutoArchive struct {
+	// Inactivity after which the channel is archived
+	Inactivity time.Duration
+
+	// How long before archiving the warning is posted
+	WarningBefore time.Duration
+
+	// Text of the warning, empty for the default text
+	WarningMessage string
+}
+
+// AppliesTo checks whether the policy applies to the Channel, an invalid selector matches no Channel
+func (policy *ArchivePolicy) AppliesTo(channel *Channel) bool {
+	if !matchesNamespace(policy.Spec.Namespaces, channel.Namespace) {
+		return false
+	}
+	if policy.Spec.Selector == nil {
+		return true
+	}
+	selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(channel.Labels))
+}
+
+// AutoArchiveFor returns how the Channel is archived when it becomes inactive, its own autoArchiveAfterInactivity
+// overrides the policies, otherwise the policy with the shortest inactivity applies. It returns nil when the channel
+// isn't archived for inactivity. The warning is posted at most half of the inactivity before archiving.
+func AutoArchiveFor(channel *Channel, policies []ArchivePolicy) *AutoArchive {
+	var autoArchive *AutoArchive
+	if channel.Spec.AutoArchiveAfterInactivity != nil {
+		autoArchive = &AutoArchive{
+			Inactivity:    channel.Spec.AutoArchiveAfterInactivity.Duration,
+			WarningBefore: defaultArchiveWarning,
+		}
+	} else {
+		for i := range policies {
+			policy := &policies[i]
+			if !policy.AppliesTo(channel) {
+				continue
+			}
+			if autoArchive != nil && autoArchive.Inactivity <= policy.Spec.Inactivity.Duration {
+				continue
+			}
+			autoArchive = &AutoArchive{
+				Inactivity:     policy.Spec.Inactivity.Duration,
+				WarningBefore:  defaultArchiveWarning,
+				WarningMessage: policy.Spec.WarningMessage,
+			}
+			if policy.Spec.WarningBefore != nil {
+				autoArchive.WarningBefore = policy.Spec.WarningBefore.Duration
+			}
+		}
+	}
+
+	if autoArchive == nil || autoArchive.Inactivity <= 0 {
+		return nil
+	}
+	if autoArchive.WarningBefore > autoArchive.Inactivity/2 {
+		autoArchive.WarningBefore = autoArchive.Inactivity / 2
+	}
+	return autoArchive
+}
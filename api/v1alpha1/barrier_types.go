@@ -0,0 +1,100 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BarrierSubject is a way of communication an information barrier restricts
+// +kubebuilder:validation:Enum=im;mpim;call
+type BarrierSubject string
+
+const (
+	// BarrierSubjectIM restricts direct messages
+	BarrierSubjectIM BarrierSubject = "im"
+	// BarrierSubjectMPIM restricts group direct messages
+	BarrierSubjectMPIM BarrierSubject = "mpim"
+	// BarrierSubjectCall restricts calls
+	BarrierSubjectCall BarrierSubject = "call"
+)
+
+// BarrierSpec defines the desired state of Barrier
+type BarrierSpec struct {
+	// ID of the usergroup whose members are barriered, e.g. S012ABC
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	PrimaryUsergroup string `json:"primaryUsergroup"`
+
+	// IDs of the usergroups the members of the primary usergroup can't communicate with
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	BarrieredFrom []string `json:"barrieredFrom"`
+
+	// Ways of communication which are restricted, Slack currently requires all of them
+	// +kubebuilder:default={im,mpim,call}
+	// +optional
+	RestrictedSubjects []BarrierSubject `json:"restrictedSubjects,omitempty"`
+}
+
+// BarrierStatus defines the observed state of Barrier
+type BarrierStatus struct {
+	// ID of the information barrier
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Primary Usergroup",type=string,JSONPath=`.spec.primaryUsergroup`
+// +kubebuilder:printcolumn:name="Barrier ID",type=string,JSONPath=`.status.id`
+
+// Barrier is the Schema for the barriers API, an information barrier of Enterprise Grid between usergroups
+type Barrier struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BarrierSpec   `json:"spec,omitempty"`
+	Status BarrierStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BarrierList contains a list of Barrier
+type BarrierList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Barrier `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Barrier{}, &BarrierList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making Barrier ConditionsStatusAware
+func (barrier *Barrier) GetReconcileStatus() []metav1.Condition {
+	return barrier.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making Barrier ConditionsStatusAware
+func (barrier *Barrier) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	barrier.Status.Conditions = reconcileStatus
+}
@@ -0,0 +1,104 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BroadcastSpec defines the desired state of Broadcast
+type BroadcastSpec struct {
+	// Selects the Channel resources the message is posted to
+	// +required
+	ChannelSelector metav1.LabelSelector `json:"channelSelector"`
+
+	// Namespaces to select channels from, defaults to the namespace of the Broadcast. Only broadcasts in the trusted
+	// namespaces of the operator select the channels of other namespaces.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Text of the message, used as fallback text when a template is referenced
+	// +optional
+	Text string `json:"text,omitempty"`
+
+	// NotificationTemplate used to render the message, the template is executed with the Broadcast
+	// +optional
+	TemplateRef *NotificationTemplateReference `json:"templateRef,omitempty"`
+}
+
+// BroadcastDelivery is the delivery result of the message to a single channel
+type BroadcastDelivery struct {
+	// Channel resource the message was posted to, as <namespace>/<name>
+	Channel string `json:"channel"`
+
+	// ID of the slack channel
+	// +optional
+	ChannelID string `json:"channelId,omitempty"`
+
+	// Timestamp of the posted message
+	// +optional
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// Error returned while posting the message
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// BroadcastStatus defines the observed state of Broadcast
+type BroadcastStatus struct {
+	// Delivery results per channel
+	// +optional
+	Deliveries []BroadcastDelivery `json:"deliveries,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Broadcast is the Schema for the broadcasts API
+type Broadcast struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BroadcastSpec   `json:"spec,omitempty"`
+	Status BroadcastStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BroadcastList contains a list of Broadcast
+type BroadcastList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Broadcast `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Broadcast{}, &BroadcastList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making Broadcast ConditionsStatusAware
+func (broadcast *Broadcast) GetReconcileStatus() []metav1.Condition {
+	return broadcast.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making Broadcast ConditionsStatusAware
+func (broadcast *Broadcast) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	broadcast.Status.Conditions = reconcileStatus
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,18 +34,589 @@ type ChannelSpec struct {
 	// +optional
 	Private bool `json:"private,omitempty"`
 
-	// List of user IDs of the users to invite
+	// Emails of the users to invite, or slack user IDs prefixed with "id:", e.g. id:U012ABC. Operators started with
+	// --user-key=id or --user-key=username match users by their ID or username instead of their email.
+	// Members who aren't listed are removed from the channel.
+	// Membership isn't enforced when neither users nor temporaryUsers are set.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Display names of SCIM groups whose active members are also members of the channel, requires SCIM to be
+	// configured for the operator
+	// +optional
+	SCIMGroups []string `json:"scimGroups,omitempty"`
+
+	// Slack emails to try, in order, for user emails without a slack account, e.g. when corporate aliases differ
+	// from slack profiles
+	// +optional
+	EmailAliases map[string][]string `json:"emailAliases,omitempty"`
+
+	// Emails or user IDs of members who are never removed from the channel, even when they are not listed in users
+	// +optional
+	ProtectedUsers []string `json:"protectedUsers,omitempty"`
+
+	// Users who are only members of the channel until their access expires
+	// +optional
+	TemporaryUsers []TemporaryUser `json:"temporaryUsers,omitempty"`
+
+	// Groups of users who are only members of the channel during weekly time windows, e.g. the regional teams of
+	// a follow-the-sun support channel. Users of inactive schedules are removed unless they are listed in users.
+	// +optional
+	MemberSchedules []MemberSchedule `json:"memberSchedules,omitempty"`
+
+	// Description of the channel, a Go template when it contains {{, see templateValues
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Topic of the channel, a Go template when it contains {{, see templateValues
+	// +optional
+	Topic string `json:"topic,omitempty"`
+
+	// Text marking the topic as managed, e.g. "[ops]". The topic is set to the marker followed by the topic and only
+	// enforced while the topic in Slack starts with the marker: text people append to the topic is kept, and topics
+	// people replaced without the marker are left alone until the marker is put back.
+	// +optional
+	TopicMarker string `json:"topicMarker,omitempty"`
+
+	// Canvas of the channel, kept in sync with its markdown source
+	// +optional
+	Canvas *CanvasSource `json:"canvas,omitempty"`
+
+	// Truncate a topic or description longer than the 250 characters allowed by Slack, with an ellipsis, instead of
+	// rejecting the channel
+	// +optional
+	TruncateLongFields bool `json:"truncateLongFields,omitempty"`
+
+	// What to do when the channel was archived in Slack. Unarchive restores the channel, Hold stops reconciling it
+	// and reports an Archived condition until it is unarchived in Slack.
+	// +kubebuilder:default=Unarchive
+	// +optional
+	OnExternalArchive ExternalArchivePolicy `json:"onExternalArchive,omitempty"`
+
+	// What to do when a new channel's name is taken by an existing slack channel. Unarchive adopts the existing
+	// channel and unarchives it, Adopt only adopts channels which aren't archived, Suffix creates the channel with the
+	// first free name of name-2, name-3 and so on, and Fail reports a NameTaken condition.
+	// +kubebuilder:default=Unarchive
+	// +optional
+	OnNameConflict NameConflictPolicy `json:"onNameConflict,omitempty"`
+
+	// What to do when the channel was deleted in Slack. Recreate creates the channel again, Adopt adopts a channel
+	// which took its name and Hold reports a Deleted condition until the Channel's status.id is cleared.
+	// +kubebuilder:default=Recreate
+	// +optional
+	OnExternalDeletion ExternalDeletionPolicy `json:"onExternalDeletion,omitempty"`
+
+	// What happens to the slack channel when the Channel is deleted. Retain keeps it as it is, Archive archives it.
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Archive the slack channel once no message was posted to it for this long, a warning is posted to the channel
+	// a week before. It overrides ArchivePolicies, 0s disables auto-archiving.
+	// +optional
+	AutoArchiveAfterInactivity *metav1.Duration `json:"autoArchiveAfterInactivity,omitempty"`
+
+	// Retention of the messages of the channel, set with the admin retention APIs of Enterprise Grid which need a
+	// user token. The retention isn't managed when unset.
+	// +optional
+	Retention *ChannelRetention `json:"retention,omitempty"`
+
+	// New members of the workspace join the channel automatically, it's added to the default channels of the
+	// workspace with the admin APIs of Enterprise Grid which need a user token
+	// +optional
+	DefaultForNewMembers bool `json:"defaultForNewMembers,omitempty"`
+
+	// Workspace section the channel belongs to, e.g. Platform. Slack has no public API for sidebar sections, so it's
+	// recorded in the managed-by bookmark of the channel for admins and tooling.
+	// +optional
+	Section string `json:"section,omitempty"`
+
+	// Values of ConfigMaps in the namespace, available to a templated topic and description as .Values.<name>.
+	// The templates are rendered on every reconcile, along with the labels of the namespace as .NamespaceLabels
+	// and the SLACK_TEMPLATE_ environment variables of the operator as .Env.
+	// +optional
+	TemplateValues []TemplateValue `json:"templateValues,omitempty"`
+
+	// Keep the topic updated with @mentions of who is on call for a PagerDuty or Opsgenie schedule. The mentions
+	// are available to a templated topic as .OnCall, the topic defaults to "On call: {{ .OnCall }}".
+	// +optional
+	OnCall *ChannelOnCall `json:"onCall,omitempty"`
+
+	// Source of the topic text, e.g. a status page or a deployment freeze flag. The text is available to a templated
+	// topic as .TopicSource, the topic defaults to the text.
+	// +optional
+	TopicFrom *TopicSource `json:"topicFrom,omitempty"`
+
+	// Prototype slack channel whose bookmarks, pinned messages and members are copied when the channel is created.
+	// Its topic and description are used unless the spec sets them. Channels which already existed aren't cloned.
+	// +optional
+	CloneFrom *CloneSource `json:"cloneFrom,omitempty"`
+
+	// Seconds a reconcile may spend calling Slack before it stops and continues on the next reconcile, so large
+	// membership syncs don't hold a worker for long. Changes made so far are kept and the progress is recorded in the
+	// status. Reconciles aren't limited when unset.
+	// +kubebuilder:validation:Minimum=10
+	// +optional
+	ReconcileTimeoutSeconds *int32 `json:"reconcileTimeoutSeconds,omitempty"`
+}
+
+// CloneSource is the slack channel a channel is cloned from, exactly one of id and name must be set
+type CloneSource struct {
+	// ID of the slack channel
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name of the slack channel
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// TopicSource is where the topic text of a channel is read from, exactly one of url and configMapKeyRef must be set
+type TopicSource struct {
+	// URL the text is fetched from with a GET request, at most 4KiB of the response are used
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Key of a ConfigMap holding the text, the channel is updated when the ConfigMap changes
+	// +optional
+	ConfigMapKeyRef *KeyReference `json:"configMapKeyRef,omitempty"`
+
+	// How often the URL is fetched
+	// +kubebuilder:default="5m"
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// DefaultSourceTopic is the topic of channels with a topic source and no topic of their own
+const DefaultSourceTopic = `{{ .TopicSource }}`
+
+// DefaultOnCallTopic is the topic of channels with an on-call schedule and no topic of their own
+const DefaultOnCallTopic = `On call: {{ .OnCall | default "nobody" }}`
+
+// ChannelOnCall is the on-call schedule mentioned in the topic of a channel
+type ChannelOnCall struct {
+	// Schedule whose current on-call engineers are mentioned
+	// +required
+	ScheduleRef OnCallScheduleReference `json:"scheduleRef"`
+}
+
+// OnCallProvider is the service an on-call schedule is managed in
+// +kubebuilder:validation:Enum=PagerDuty;Opsgenie
+type OnCallProvider string
+
+const (
+	// OnCallPagerDuty schedules are read from PagerDuty, the on-call engineers of the first escalation level are
+	// mentioned
+	OnCallPagerDuty OnCallProvider = "PagerDuty"
+	// OnCallOpsgenie schedules are read from Opsgenie
+	OnCallOpsgenie OnCallProvider = "Opsgenie"
+)
+
+// OnCallScheduleReference selects a schedule of PagerDuty or Opsgenie
+type OnCallScheduleReference struct {
+	// Provider of the schedule
+	// +required
+	Provider OnCallProvider `json:"provider"`
+
+	// ID of the schedule
+	// +required
+	ID string `json:"id"`
+
+	// Key of a Secret in the namespace holding the API token of the provider, a read-only token is sufficient
+	// +required
+	TokenSecretRef KeyReference `json:"tokenSecretRef"`
+}
+
+// TemplateValue is a value for the templated topic and description of a channel
+type TemplateValue struct {
+	// Name of the value in the templates
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	// +required
+	Name string `json:"name"`
+
+	// Key of a ConfigMap holding the value
+	// +required
+	ConfigMapKeyRef KeyReference `json:"configMapKeyRef"`
+}
+
+// ChannelRetention is how long the messages of a channel are kept, exactly one of the fields must be set
+type ChannelRetention struct {
+	// Days the messages of the channel are kept
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Days int32 `json:"days,omitempty"`
+
+	// Keep the messages as long as the workspace does, a custom retention of the channel is removed
+	// +optional
+	WorkspaceDefault bool `json:"workspaceDefault,omitempty"`
+}
+
+// DesiredDays returns the days the messages of the channel should be kept, 0 for the workspace retention
+func (retention *ChannelRetention) DesiredDays() int {
+	if retention.WorkspaceDefault {
+		return 0
+	}
+	return int(retention.Days)
+}
+
+// ExternalArchivePolicy decides how channels archived outside of the operator are handled
+// +kubebuilder:validation:Enum=Unarchive;Hold
+type ExternalArchivePolicy string
+
+const (
+	// ExternalArchiveUnarchive unarchives the channel and continues reconciling it
+	ExternalArchiveUnarchive ExternalArchivePolicy = "Unarchive"
+	// ExternalArchiveHold leaves the channel archived and holds its reconciliation
+	ExternalArchiveHold ExternalArchivePolicy = "Hold"
+)
+
+// NameConflictPolicy decides what happens when the name of a new channel is taken by an existing slack channel
+// +kubebuilder:validation:Enum=Unarchive;Adopt;Suffix;Fail
+type NameConflictPolicy string
+
+const (
+	// NameConflictUnarchive adopts the existing channel and unarchives it if it's archived
+	NameConflictUnarchive NameConflictPolicy = "Unarchive"
+	// NameConflictAdopt adopts the existing channel unless it's archived
+	NameConflictAdopt NameConflictPolicy = "Adopt"
+	// NameConflictSuffix creates the channel with a numbered suffix, leaving the existing channel alone
+	NameConflictSuffix NameConflictPolicy = "Suffix"
+	// NameConflictFail leaves the existing channel alone and reports the conflict
+	NameConflictFail NameConflictPolicy = "Fail"
+)
+
+// ExternalDeletionPolicy decides how channels deleted outside of the operator are handled
+// +kubebuilder:validation:Enum=Recreate;Adopt;Hold
+type ExternalDeletionPolicy string
+
+const (
+	// ExternalDeletionRecreate creates the channel again, taken names are handled by the NameConflictPolicy
+	ExternalDeletionRecreate ExternalDeletionPolicy = "Recreate"
+	// ExternalDeletionAdopt adopts a channel with the name of the deleted channel, the Channel is held until there is one
+	ExternalDeletionAdopt ExternalDeletionPolicy = "Adopt"
+	// ExternalDeletionHold keeps the ID of the deleted channel and holds its reconciliation
+	ExternalDeletionHold ExternalDeletionPolicy = "Hold"
+)
+
+// DeletionPolicy decides what happens to the slack channel when its Channel is deleted
+// +kubebuilder:validation:Enum=Retain;Archive
+type DeletionPolicy string
+
+const (
+	// DeletionRetain keeps the slack channel
+	DeletionRetain DeletionPolicy = "Retain"
+	// DeletionArchive archives the slack channel
+	DeletionArchive DeletionPolicy = "Archive"
+)
+
+// ReconcileAtAnnotation requests an immediate full reconcile of the Channel when it's set to a new value, e.g. the
+// current time, the handled value is written to status.lastHandledReconcileAt
+const ReconcileAtAnnotation = "slack.stakater.com/reconcile-at"
+
+// CanaryLabel marks Channels whose spec changes are applied to the canary workspace of the operator first, set to
+// "true". The production workspace is only changed once the canary channel converged.
+const CanaryLabel = "slack.stakater.com/canary"
+
+// TemporaryUser is a channel member whose membership expires, exactly one of expiresAt and ttl must be set
+type TemporaryUser struct {
+	// Email of the user
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Email string `json:"email"`
+
+	// Time the user is removed from the channel at
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Duration of the membership, counted from the first reconcile that saw the user
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// MemberSchedule is a group of users who are members of the channel while one of its windows is active
+type MemberSchedule struct {
+	// Name of the group, e.g. emea
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+
+	// Emails of the users, or slack user IDs prefixed with "id:"
 	// +kubebuilder:validation:MinItems=1
 	// +required
 	Users []string `json:"users"`
 
-	// Description of the channel
+	// Weekly windows the group is active in
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Windows []ScheduleWindow `json:"windows"`
+
+	// IANA time zone of the windows, e.g. Asia/Singapore
+	// +kubebuilder:default=UTC
 	// +optional
-	Description string `json:"description,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ScheduleWindow is a weekly time window, windows which end before they start end on the next day
+type ScheduleWindow struct {
+	// Days the window starts on, every day when empty
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start of the window, HH:MM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	// +required
+	Start string `json:"start"`
+
+	// End of the window, HH:MM, 24:00 for the end of the day
+	// +kubebuilder:validation:Pattern=`^(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$`
+	// +required
+	End string `json:"end"`
+}
+
+// Weekday is a day of the week
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
+// TemporaryUserStatus is the resolved expiration of a temporary member
+type TemporaryUserStatus struct {
+	// Email of the user
+	Email string `json:"email"`
+
+	// Time the user is removed from the channel at
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// MemberError is a user who couldn't be added to or removed from the channel
+type MemberError struct {
+	// Email of the user, or the ID prefixed with "id:" of members who couldn't be removed
+	User string `json:"user"`
 
-	// Topic of the channel
+	// Why the user couldn't be added or removed, e.g. NotFound, Deactivated, Restricted, LookupFailed, InviteFailed
+	// or RemoveFailed
+	Reason string `json:"reason"`
+}
+
+// MembershipSyncStatus is the progress of a membership sync which takes several reconciles, e.g. of a channel with
+// thousands of members
+type MembershipSyncStatus struct {
+	// Time the sync started
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// Members of the slack channel at the last reconcile
+	Members int `json:"members"`
+
+	// Users invited and members removed since the sync started
+	Changed int `json:"changed"`
+
+	// Users still to invite
+	PendingInvites int `json:"pendingInvites"`
+
+	// Members still to remove
+	PendingRemovals int `json:"pendingRemovals"`
+}
+
+// ChannelDrift describes how the slack channel differs from the spec while it can't be synced
+type ChannelDrift struct {
+	// Fields of the slack channel which differ from the spec, e.g. name, topic, description, members or archived
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+
+	// Reason the channel isn't synced, the reason of its condition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Since when the channel is out of sync
+	Since metav1.Time `json:"since"`
+}
+
+// ChannelAction is a change the operator made to the slack channel
+type ChannelAction struct {
+	// When the change was made
+	Time metav1.Time `json:"time"`
+
+	// Action, e.g. Create, Rename, Archive, Invite or Kick
+	Action string `json:"action"`
+
+	// IDs of the invited or removed users
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// New name, topic or description
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// AutoArchiveStatus tracks the inactivity of a channel which is archived when it's inactive
+type AutoArchiveStatus struct {
+	// Time the inactivity warning was posted, unset while the channel is active
+	// +optional
+	WarnedAt *metav1.Time `json:"warnedAt,omitempty"`
+
+	// Timestamp of the warning message, which doesn't count as activity
+	// +optional
+	WarningTimestamp string `json:"warningTimestamp,omitempty"`
+
+	// Time the channel was archived for inactivity
+	// +optional
+	ArchivedAt *metav1.Time `json:"archivedAt,omitempty"`
+
+	// Inactivity is counted from this time at the earliest, e.g. after the channel was unarchived
+	// +optional
+	ActiveSince *metav1.Time `json:"activeSince,omitempty"`
+}
+
+// CanaryStatus is the state of the channel in the canary workspace
+type CanaryStatus struct {
+	// ID of the slack channel in the canary workspace
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Generation of the spec which converged in the canary workspace, it is applied to the production workspace
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// Time the generation converged in the canary workspace
+	// +optional
+	ConvergedAt *metav1.Time `json:"convergedAt,omitempty"`
+
+	// Users who couldn't be added to the canary channel, e.g. because they aren't in the canary workspace
+	// +optional
+	MemberErrors int `json:"memberErrors,omitempty"`
+}
+
+// DeadLetterStatus records why a channel stopped being reconciled after failing too many reconciles in a row
+type DeadLetterStatus struct {
+	// Time the channel stopped being reconciled
+	Since metav1.Time `json:"since"`
+
+	// Error of the last failed reconcile
+	Error string `json:"error"`
+
+	// Generation of the spec which failed, a new spec is reconciled again
+	Generation int64 `json:"generation"`
+
+	// Value of the reconcile-at annotation when the channel stopped being reconciled, a new value retries it
+	// +optional
+	ReconcileAt string `json:"reconcileAt,omitempty"`
+}
+
+// ChannelActivity is how active the slack channel is, refreshed periodically
+type ChannelActivity struct {
+	// Number of members of the channel
+	Members int `json:"members"`
+
+	// Time of the latest message in the channel
+	// +optional
+	LastMessageTime *metav1.Time `json:"lastMessageTime,omitempty"`
+
+	// Number of messages posted in the past 7 days
+	MessagesLastWeek int `json:"messagesLastWeek"`
+
+	// Whether messagesLastWeek is a lower bound, the messages are only counted up to a limit
+	// +optional
+	Sampled bool `json:"sampled,omitempty"`
+
+	// Time the activity was refreshed at
+	UpdatedAt metav1.Time `json:"updatedAt"`
+}
+
+// OnCallStatus is who is on call for the schedule of the channel, refreshed periodically
+type OnCallStatus struct {
+	// Schedule the on-call engineers were read from, as <provider>/<id>
+	Schedule string `json:"schedule"`
+
+	// Slack user IDs of the on-call engineers
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Emails of on-call engineers without a slack account, they aren't mentioned
+	// +optional
+	UnknownEmails []string `json:"unknownEmails,omitempty"`
+
+	// Time the on-call engineers were refreshed at
+	UpdatedAt metav1.Time `json:"updatedAt"`
+}
+
+// Mentions returns the @mentions of the on-call engineers, separated by commas
+func (status *OnCallStatus) Mentions() string {
+	if status == nil {
+		return ""
+	}
+	mentions := make([]string, 0, len(status.Users))
+	for _, user := range status.Users {
+		mentions = append(mentions, "<@"+user+">")
+	}
+	return strings.Join(mentions, ", ")
+}
+
+// CloneStatus is the state of cloning the prototype channel, set when the channel is created
+type CloneStatus struct {
+	// ID of the prototype channel
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// Topic of the prototype channel
 	// +optional
 	Topic string `json:"topic,omitempty"`
+
+	// Description of the prototype channel
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Slack user IDs of the members copied from the prototype channel, they are kept as members of the channel
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// Time the channel was cloned at, unset while the clone is pending
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+}
+
+// TopicSourceStatus is the text last fetched from the topic URL of the channel
+type TopicSourceStatus struct {
+	// URL the text was fetched from
+	URL string `json:"url"`
+
+	// Text fetched from the URL
+	// +optional
+	Text string `json:"text,omitempty"`
+
+	// Time the text was fetched at
+	UpdatedAt metav1.Time `json:"updatedAt"`
+}
+
+// CanvasSource is the markdown content of a channel canvas, exactly one of the fields must be set
+type CanvasSource struct {
+	// Inline markdown
+	// +optional
+	Markdown string `json:"markdown,omitempty"`
+
+	// Key of a ConfigMap in the namespace of the channel holding the markdown
+	// +optional
+	ConfigMapRef *KeyReference `json:"configMapRef,omitempty"`
+}
+
+// ChannelPolicyStatus are the merged defaults of the ChannelPolicies applying to a channel
+type ChannelPolicyStatus struct {
+	// Names of the policies
+	Policies []string `json:"policies"`
+
+	// Bookmarks the channel must have
+	// +optional
+	Bookmarks []PolicyBookmark `json:"bookmarks,omitempty"`
+
+	// Texts the topic must end with
+	// +optional
+	TopicSuffixes []string `json:"topicSuffixes,omitempty"`
+
+	// Users who must be members
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// Users who must not be members
+	// +optional
+	ForbiddenMembers []string `json:"forbiddenMembers,omitempty"`
 }
 
 // ChannelStatus defines the observed state of Channel
@@ -49,12 +624,157 @@ type ChannelStatus struct {
 	// ID of the slack channel
 	ID string `json:"id"`
 
-	// Status conditions
+	// ID of the channel canvas
+	// +optional
+	CanvasID string `json:"canvasId,omitempty"`
+
+	// Hash of the markdown last written to the canvas
+	// +optional
+	CanvasHash string `json:"canvasHash,omitempty"`
+
+	// Expiration of the temporary members
+	// +optional
+	TemporaryUsers []TemporaryUserStatus `json:"temporaryUsers,omitempty"`
+
+	// Emails of the members of the SCIM groups
+	// +optional
+	GroupMembers []string `json:"groupMembers,omitempty"`
+
+	// Names of the member schedules which are active
+	// +optional
+	ActiveSchedules []string `json:"activeSchedules,omitempty"`
+
+	// Users of the active member schedules
+	// +optional
+	ScheduledUsers []string `json:"scheduledUsers,omitempty"`
+
+	// Defaults the ChannelPolicies applying to the channel inject
+	// +optional
+	Policy *ChannelPolicyStatus `json:"policy,omitempty"`
+
+	// Users who were skipped because they couldn't be added to the channel
+	// +optional
+	MemberErrors []MemberError `json:"memberErrors,omitempty"`
+
+	// Progress of a membership sync with more changes than a single reconcile makes, unset once the members match
+	// +optional
+	MembershipSync *MembershipSyncStatus `json:"membershipSync,omitempty"`
+
+	// Fields which were truncated to fit into Slack
+	// +optional
+	TruncatedFields []string `json:"truncatedFields,omitempty"`
+
+	// Generation of the spec which was last reconciled successfully
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Hash of the spec and members which were last applied to the slack channel
+	// +optional
+	AppliedHash string `json:"appliedHash,omitempty"`
+
+	// Timestamp in milliseconds of the last change of the slack channel after the spec was applied
+	// +optional
+	SlackUpdated int64 `json:"slackUpdated,omitempty"`
+
+	// Value of the reconcile-at annotation which was last reconciled
+	// +optional
+	LastHandledReconcileAt string `json:"lastHandledReconcileAt,omitempty"`
+
+	// Drift of the slack channel from the spec, unset while it's in sync
+	// +optional
+	Drift *ChannelDrift `json:"drift,omitempty"`
+
+	// Activity of the slack channel
+	// +optional
+	Activity *ChannelActivity `json:"activity,omitempty"`
+
+	// On-call engineers mentioned in the topic
+	// +optional
+	OnCall *OnCallStatus `json:"onCall,omitempty"`
+
+	// State of cloning the prototype channel of cloneFrom
+	// +optional
+	Clone *CloneStatus `json:"clone,omitempty"`
+
+	// Text last fetched from topicFrom.url
+	// +optional
+	TopicSource *TopicSourceStatus `json:"topicSource,omitempty"`
+
+	// Section recorded for the slack channel
+	// +optional
+	Section string `json:"section,omitempty"`
+
+	// Auto-archiving state of the channel, set while the channel is archived when it becomes inactive
+	// +optional
+	AutoArchive *AutoArchiveStatus `json:"autoArchive,omitempty"`
+
+	// State of the channel in the canary workspace, set for Channels with the canary label
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+
+	// Disruptive changes which wait for the next maintenance window of the operator, e.g. renaming the channel
+	// +optional
+	DeferredActions []string `json:"deferredActions,omitempty"`
+
+	// Number of reconciles in a row which failed, reset by a successful reconcile
+	// +optional
+	FailedReconciles int `json:"failedReconciles,omitempty"`
+
+	// Set while the channel isn't reconciled because it failed too many reconciles in a row
+	// +optional
+	DeadLetter *DeadLetterStatus `json:"deadLetter,omitempty"`
+
+	// ID of the slack channel a ChannelMerge merged this channel into, the channel stays archived then
+	// +optional
+	MergedInto string `json:"mergedInto,omitempty"`
+
+	// Latest changes the operator made to the slack channel, oldest first
+	// +optional
+	History []ChannelAction `json:"history,omitempty"`
+
+	// Suffix appended to the name of the slack channel because the name was taken when it was created, e.g. -2
+	// +optional
+	NameSuffix string `json:"nameSuffix,omitempty"`
+
+	// Summary of the state of the channel
+	// +optional
+	Phase ChannelPhase `json:"phase,omitempty"`
+
+	// Status conditions, the condition of the last reconcile followed by the Ready condition
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ChannelPhase is a summary of the state of a Channel
+// +kubebuilder:validation:Enum=Pending;Ready;Held;Failed;Deleting
+type ChannelPhase string
+
+const (
+	// ChannelPending channels weren't reconciled yet
+	ChannelPending ChannelPhase = "Pending"
+
+	// ChannelReady channels have their spec applied to the slack channel
+	ChannelReady ChannelPhase = "Ready"
+
+	// ChannelHeld channels are left alone until the condition holding them is resolved, e.g. a NameConflict
+	ChannelHeld ChannelPhase = "Held"
+
+	// ChannelFailed channels failed their last reconcile, they are retried
+	ChannelFailed ChannelPhase = "Failed"
+
+	// ChannelDeleting channels are being archived or released before they are deleted
+	ChannelDeleting ChannelPhase = "Deleting"
+)
+
+// ReadyCondition is the condition type which is true while the spec of the Channel is applied, for GitOps tools
+const ReadyCondition = "Ready"
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Slack Name",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Channel is the Schema for the channels API
 type Channel struct {
@@ -78,12 +798,106 @@ func init() {
 	SchemeBuilder.Register(&Channel{}, &ChannelList{})
 }
 
+// Members returns the emails of the users who should currently be members of the channel, including the members
+// of the SCIM groups, the active member schedules, the ChannelPolicies and the members cloned from a prototype. Temporary users are included until the expiration recorded
+// in the status.
+func (channel *Channel) Members() []string {
+	members := append([]string{}, channel.Spec.Users...)
+	members = append(members, channel.Status.GroupMembers...)
+	members = append(members, channel.Status.ScheduledUsers...)
+	if channel.Status.Policy != nil {
+		members = append(members, channel.Status.Policy.Members...)
+	}
+	if channel.Status.Clone != nil {
+		for _, userID := range channel.Status.Clone.Members {
+			members = append(members, "id:"+userID)
+		}
+	}
+
+	now := time.Now()
+	for _, user := range channel.Status.TemporaryUsers {
+		if user.ExpiresAt.After(now) {
+			members = append(members, user.Email)
+		}
+	}
+
+	return members
+}
+
+// ReconcileTimeout returns how long a reconcile may call Slack, 0 if it isn't limited
+func (channel *Channel) ReconcileTimeout() time.Duration {
+	if channel.Spec.ReconcileTimeoutSeconds == nil {
+		return 0
+	}
+	return time.Duration(*channel.Spec.ReconcileTimeoutSeconds) * time.Second
+}
+
+// SlackName returns the name of the slack channel, the spec name with the suffix it was created with
+func (channel *Channel) SlackName() string {
+	return channel.Spec.Name + channel.Status.NameSuffix
+}
+
+// ManagedTopic returns the topic the operator sets, prefixed with the topic marker if there is one
+func (channel *Channel) ManagedTopic() string {
+	if channel.Spec.TopicMarker == "" {
+		return channel.Spec.Topic
+	}
+	return strings.TrimSpace(channel.Spec.TopicMarker + " " + channel.Spec.Topic)
+}
+
+// IsCanary checks whether spec changes of the channel are applied to the canary workspace first
+func (channel *Channel) IsCanary() bool {
+	return channel.Labels[CanaryLabel] == "true"
+}
+
+// ManagesMembers checks whether the members of the channel are enforced
+func (channel *Channel) ManagesMembers() bool {
+	return len(channel.Spec.Users) > 0 || len(channel.Spec.TemporaryUsers) > 0 || len(channel.Spec.SCIMGroups) > 0 ||
+		len(channel.Spec.MemberSchedules) > 0
+}
+
 // GetReconcileStatus - returns conditions, required for making Channel ConditionsStatusAware
 func (channel *Channel) GetReconcileStatus() []metav1.Condition {
 	return channel.Status.Conditions
 }
 
-// SetReconcileStatus - sets status, required for making Channel ConditionsStatusAware
+// SetReconcileStatus - sets status, required for making Channel ConditionsStatusAware. The Ready condition and the
+// phase are derived from the condition of the reconcile.
 func (channel *Channel) SetReconcileStatus(reconcileStatus []metav1.Condition) {
-	channel.Status.Conditions = reconcileStatus
+	ready := metav1.Condition{
+		Type:               ReadyCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: channel.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(ChannelPending),
+	}
+	channel.Status.Phase = ChannelPending
+	if len(reconcileStatus) > 0 {
+		condition := reconcileStatus[0]
+		ready.Reason, ready.Message = condition.Reason, condition.Message
+		switch condition.Type {
+		case "ReconcileSuccess":
+			ready.Status, channel.Status.Phase = metav1.ConditionTrue, ChannelReady
+		case "ReconcileError", "Failed":
+			channel.Status.Phase = ChannelFailed
+		default:
+			channel.Status.Phase = ChannelHeld
+		}
+	}
+	if channel.GetDeletionTimestamp() != nil {
+		channel.Status.Phase = ChannelDeleting
+	}
+
+	// The transition time is kept while the Ready condition doesn't change its status
+	if previous := meta.FindStatusCondition(channel.Status.Conditions, ReadyCondition); previous != nil && previous.Status == ready.Status {
+		ready.LastTransitionTime = previous.LastTransitionTime
+	}
+
+	conditions := []metav1.Condition{}
+	for _, condition := range reconcileStatus {
+		if condition.Type != ReadyCondition {
+			conditions = append(conditions, condition)
+		}
+	}
+	channel.Status.Conditions = append(conditions, ready)
 }
@@ -17,17 +17,57 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"unicode/utf8"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/stakater/slack-operator/pkg/templating"
 )
 
 // log is for logging in this package.
 var channellog = logf.Log.WithName("channel-resource")
 
+// MinChannelUsers is the minimum number of members a channel must have, set from the operator flags
+var MinChannelUsers = 0
+
+// MaxFieldLength is the maximum number of characters of the topic and description of a slack channel
+const MaxFieldLength = 250
+
+// AllowedNamespaces are the namespaces Channels may be created in, set from the operator flags. Entries may be
+// patterns like team-*, every namespace is allowed when it is empty.
+var AllowedNamespaces []string
+
+// TrustedNamespaces are the namespaces whose resources may reach the Channels and events of other namespaces, e.g. the
+// EventRoutes and Broadcasts of a platform team, set from the operator flags. Entries may be patterns like platform-*,
+// resources of every other namespace are limited to their own namespace.
+var TrustedNamespaces []string
+
+// ChannelAdmission evaluates custom rules of the platform team against Channels, e.g. a policy of an Open Policy
+// Agent
+// +kubebuilder:object:generate=false
+type ChannelAdmission interface {
+	// Violations returns why the channel is rejected, nothing when it is admitted. old is nil for new channels.
+	Violations(ctx context.Context, channel *Channel, old *Channel, namespaceLabels map[string]string) ([]string, error)
+}
+
+// AdmissionPolicy evaluates the custom rules of Channels, set by the operator. No custom rules are evaluated when it
+// is nil.
+var AdmissionPolicy ChannelAdmission
+
+// ChannelReader lists the existing Channels to reject a second Channel for the same slack channel, set by the
+// operator. Name conflicts aren't checked when it is nil.
+var ChannelReader client.Reader
+
 func (r *Channel) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
@@ -44,7 +84,12 @@ var _ webhook.Defaulter = &Channel{}
 func (r *Channel) Default() {
 	channellog.Info("default", "name", r.Name)
 
-	// TODO(user): fill in your defaulting logic.
+	// Only channels which weren't created yet get prefixes, renaming existing channels is left to their owners
+	if r.Status.ID == "" {
+		if err := applyNamingPrefixes(r); err != nil {
+			channellog.Error(err, "Error adding naming prefixes", "name", r.Name)
+		}
+	}
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
@@ -56,11 +101,39 @@ var _ webhook.Validator = &Channel{}
 func (r *Channel) ValidateCreate() error {
 	channellog.Info("validate create", "name", r.Name)
 
-	if len(r.Spec.Users) < 1 {
-		return fmt.Errorf("Users can not be empty")
+	if err := ValidateNamespace(r); err != nil {
+		return err
 	}
 
-	return nil
+	if err := ValidateFeaturePolicies(r, nil); err != nil {
+		return err
+	}
+
+	if err := ValidateMinUsers(r, MinChannelUsers); err != nil {
+		return err
+	}
+
+	if err := ValidateFieldLengths(r); err != nil {
+		return err
+	}
+
+	if err := ValidateTemplates(r); err != nil {
+		return err
+	}
+
+	if err := validateQuotas(r, true); err != nil {
+		return err
+	}
+
+	if err := validateNamingPolicies(r); err != nil {
+		return err
+	}
+
+	if err := validateAdmissionPolicy(r, nil); err != nil {
+		return err
+	}
+
+	return validateNameConflict(r)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -72,11 +145,44 @@ func (r *Channel) ValidateUpdate(old runtime.Object) error {
 		return fmt.Errorf("Error casting old runtime object to %T from %T", oldChannel, old)
 	}
 
-	if len(r.Spec.Users) < 1 {
-		return fmt.Errorf("Users can not be empty")
+	if err := ValidateFeaturePolicies(r, oldChannel); err != nil {
+		return err
 	}
 
-	return ValidateImmutableFields(r, oldChannel)
+	// Channels which are deleted or whose spec didn't change are left alone, so that raising the minimum doesn't
+	// block the operator from removing its finalizer
+	if r.DeletionTimestamp == nil && !reflect.DeepEqual(r.Spec, oldChannel.Spec) {
+		if err := ValidateMinUsers(r, MinChannelUsers); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateFieldLengths(r); err != nil {
+		return err
+	}
+
+	if err := ValidateTemplates(r); err != nil {
+		return err
+	}
+
+	if NormalizeChannelName(r.Spec.Name) != NormalizeChannelName(oldChannel.Spec.Name) {
+		if err := validateQuotas(r, false); err != nil {
+			return err
+		}
+		if err := validateNamingPolicies(r); err != nil {
+			return err
+		}
+		if err := validateNameConflict(r); err != nil {
+			return err
+		}
+	}
+
+	// Updates of the finalizers or annotations by the operator aren't evaluated, so that Channels created before a
+	// rule was added can still be reconciled and deleted
+	if r.DeletionTimestamp == nil && (!reflect.DeepEqual(r.Spec, oldChannel.Spec) || !reflect.DeepEqual(r.Labels, oldChannel.Labels)) {
+		return validateAdmissionPolicy(r, oldChannel)
+	}
+	return nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -87,9 +193,310 @@ func (r *Channel) ValidateDelete() error {
 	return nil
 }
 
-func ValidateImmutableFields(newChannel *Channel, oldChannel *Channel) error {
-	if oldChannel.Spec.Private != newChannel.Spec.Private {
-		return fmt.Errorf("Field 'isPrivate' is immutable and cannot be changed after Slack Channel has been created")
+// ValidateNamespace checks that the channel is in one of the AllowedNamespaces
+func ValidateNamespace(channel *Channel) error {
+	if !IsNamespaceAllowed(channel.Namespace) {
+		return fmt.Errorf("Channels can't be created in namespace %s, it isn't allowed to use the Slack workspace", channel.Namespace)
+	}
+	return nil
+}
+
+// IsNamespaceAllowed checks whether the namespace matches one of the AllowedNamespaces
+func IsNamespaceAllowed(namespace string) bool {
+	if len(AllowedNamespaces) == 0 {
+		return true
+	}
+	for _, pattern := range AllowedNamespaces {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNamespaceTrusted checks whether the namespace matches one of the TrustedNamespaces
+func IsNamespaceTrusted(namespace string) bool {
+	return len(TrustedNamespaces) > 0 && matchesNamespace(TrustedNamespaces, namespace)
+}
+
+// ValidateMinUsers checks that the channel has at least minUsers members, counting the same sources as
+// ManagesMembers. SCIM groups are counted with the members of the SCIMGroup of the same display name, the channel
+// is accepted when one of them has no SCIMGroup since the size of the group is unknown.
+func ValidateMinUsers(channel *Channel, minUsers int) error {
+	if minUsers <= 0 {
+		return nil
+	}
+
+	members := map[string]bool{}
+	for _, user := range channel.Spec.Users {
+		members[strings.ToLower(user)] = true
+	}
+	for _, user := range channel.Spec.TemporaryUsers {
+		members[strings.ToLower(user.Email)] = true
+	}
+	for _, schedule := range channel.Spec.MemberSchedules {
+		for _, user := range schedule.Users {
+			members[strings.ToLower(user)] = true
+		}
+	}
+
+	if len(members) < minUsers && len(channel.Spec.SCIMGroups) > 0 {
+		groupMembers, ok, err := scimGroupMembers(channel.Spec.SCIMGroups)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		for _, member := range groupMembers {
+			members[strings.ToLower(member)] = true
+		}
+	}
+
+	if len(members) < minUsers {
+		return fmt.Errorf("At least %d users are required", minUsers)
+	}
+	return nil
+}
+
+// scimGroupMembers returns the members of the SCIMGroups with the display names, it returns false when a group has
+// no SCIMGroup or the SCIMGroups can't be read
+func scimGroupMembers(displayNames []string) ([]string, bool, error) {
+	if ChannelReader == nil {
+		return nil, false, nil
+	}
+
+	groups := &SCIMGroupList{}
+	if err := ChannelReader.List(context.Background(), groups); err != nil {
+		return nil, false, fmt.Errorf("Error listing SCIMGroups: %v", err)
+	}
+
+	var members []string
+	for _, displayName := range displayNames {
+		found := false
+		for _, group := range groups.Items {
+			if group.Spec.DisplayName == displayName {
+				members = append(members, group.Spec.Members...)
+				found = true
+			}
+		}
+		if !found {
+			return nil, false, nil
+		}
+	}
+	return members, true, nil
+}
+
+// ValidateFieldLengths checks that the topic and description fit into slack, unless they are truncated. Templates
+// are checked once they are rendered.
+func ValidateFieldLengths(channel *Channel) error {
+	if channel.Spec.TruncateLongFields {
+		return nil
+	}
+	if !templating.IsTemplate(channel.Spec.Topic) && utf8.RuneCountInString(channel.ManagedTopic()) > MaxFieldLength {
+		return fmt.Errorf("Field 'topic' must not be longer than %d characters, set 'truncateLongFields' to truncate it", MaxFieldLength)
+	}
+	if !templating.IsTemplate(channel.Spec.Description) && utf8.RuneCountInString(channel.Spec.Description) > MaxFieldLength {
+		return fmt.Errorf("Field 'description' must not be longer than %d characters, set 'truncateLongFields' to truncate it", MaxFieldLength)
+	}
+	return nil
+}
+
+// ValidateTemplates checks that a templated topic and description can be parsed
+func ValidateTemplates(channel *Channel) error {
+	if err := templating.Validate("topic", channel.Spec.Topic); err != nil {
+		return fmt.Errorf("Field 'topic' is an invalid template: %v", err)
+	}
+	if err := templating.Validate("description", channel.Spec.Description); err != nil {
+		return fmt.Errorf("Field 'description' is an invalid template: %v", err)
+	}
+	return nil
+}
+
+// TruncateLongFields returns a copy of the channel whose topic and description are truncated to MaxFieldLength
+// characters, ending with an ellipsis, along with the names of the truncated fields
+func TruncateLongFields(channel *Channel) (*Channel, []string) {
+	truncated := channel.DeepCopy()
+	var fields []string
+
+	// The marker counts towards the length of the topic
+	markerLength := utf8.RuneCountInString(channel.ManagedTopic()) - utf8.RuneCountInString(channel.Spec.Topic)
+	if topic, ok := truncate(channel.Spec.Topic, MaxFieldLength-markerLength); ok {
+		truncated.Spec.Topic = topic
+		fields = append(fields, "topic")
+	}
+	if description, ok := truncate(channel.Spec.Description, MaxFieldLength); ok {
+		truncated.Spec.Description = description
+		fields = append(fields, "description")
+	}
+
+	return truncated, fields
+}
+
+func truncate(value string, length int) (string, bool) {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value, false
+	}
+	return string(runes[:length-1]) + "…", true
+}
+
+// NormalizeChannelName returns the name as Slack stores it, so names referring to the same channel are equal
+func NormalizeChannelName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+}
+
+// FindNameConflict returns the Channel among channels which manages the slack channel of the same name, if it isn't
+// the given channel. The oldest Channel manages the slack channel, new Channels are newer than all existing ones.
+func FindNameConflict(channel *Channel, channels []Channel) *Channel {
+	name := NormalizeChannelName(channel.Spec.Name)
+
+	var owner *Channel
+	for i := range channels {
+		other := &channels[i]
+		if other.Namespace == channel.Namespace && other.Name == channel.Name {
+			continue
+		}
+		if other.DeletionTimestamp != nil || NormalizeChannelName(other.Spec.Name) != name {
+			continue
+		}
+		if claimsFirst(other, channel) && (owner == nil || claimsFirst(other, owner)) {
+			owner = other
+		}
+	}
+	return owner
+}
+
+// claimsFirst checks whether channel a was created before b, ties are broken by namespace and name
+func claimsFirst(a *Channel, b *Channel) bool {
+	if b.CreationTimestamp.IsZero() {
+		return true
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+}
+
+// validateNameConflict rejects channels whose slack channel is already managed by another Channel
+func validateNameConflict(channel *Channel) error {
+	if ChannelReader == nil {
+		return nil
+	}
+
+	channels := &ChannelList{}
+	if err := ChannelReader.List(context.Background(), channels); err != nil {
+		return fmt.Errorf("Error listing Channels: %v", err)
+	}
+
+	if owner := FindNameConflict(channel, channels.Items); owner != nil {
+		return fmt.Errorf("The slack channel %s is already managed by Channel %s/%s", NormalizeChannelName(channel.Spec.Name), owner.Namespace, owner.Name)
+	}
+	return nil
+}
+
+// validateQuotas checks the name of the channel against the ChannelQuotas of its namespace and, for new channels,
+// that the namespace has room for another Channel
+func validateQuotas(channel *Channel, create bool) error {
+	if ChannelReader == nil {
+		return nil
+	}
+
+	quotas := &ChannelQuotaList{}
+	if err := ChannelReader.List(context.Background(), quotas, client.InNamespace(channel.Namespace)); err != nil {
+		return fmt.Errorf("Error listing ChannelQuotas: %v", err)
+	}
+	if len(quotas.Items) == 0 {
+		return nil
+	}
+
+	channels := &ChannelList{}
+	if err := ChannelReader.List(context.Background(), channels, client.InNamespace(channel.Namespace)); err != nil {
+		return fmt.Errorf("Error listing Channels: %v", err)
+	}
+
+	for _, quota := range quotas.Items {
+		if !quota.AllowsName(channel.Spec.Name) {
+			return fmt.Errorf("ChannelQuota %s only allows slack channels starting with %s", quota.Name, strings.Join(quota.Spec.AllowedPrefixes, ", "))
+		}
+		if create && quota.Spec.MaxChannels != nil && len(channels.Items) >= int(*quota.Spec.MaxChannels) {
+			return fmt.Errorf("ChannelQuota %s allows at most %d Channels in namespace %s", quota.Name, *quota.Spec.MaxChannels, channel.Namespace)
+		}
+	}
+	return nil
+}
+
+// namingPolicies returns the ChannelNamingPolicies and the labels of the channel's namespace
+func namingPolicies(channel *Channel) ([]ChannelNamingPolicy, map[string]string, error) {
+	if ChannelReader == nil {
+		return nil, nil, nil
+	}
+
+	policies := &ChannelNamingPolicyList{}
+	if err := ChannelReader.List(context.Background(), policies); err != nil {
+		return nil, nil, fmt.Errorf("Error listing ChannelNamingPolicies: %v", err)
+	}
+	if len(policies.Items) == 0 {
+		return nil, nil, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := ChannelReader.Get(context.Background(), client.ObjectKey{Name: channel.Namespace}, namespace); err != nil {
+		return nil, nil, fmt.Errorf("Error fetching namespace %s: %v", channel.Namespace, err)
+	}
+	return policies.Items, namespace.Labels, nil
+}
+
+// validateAdmissionPolicy rejects channels which violate the custom rules of the AdmissionPolicy, old is nil for new
+// channels
+func validateAdmissionPolicy(channel *Channel, old *Channel) error {
+	if AdmissionPolicy == nil {
+		return nil
+	}
+
+	var namespaceLabels map[string]string
+	if ChannelReader != nil {
+		namespace := &corev1.Namespace{}
+		if err := ChannelReader.Get(context.Background(), client.ObjectKey{Name: channel.Namespace}, namespace); err != nil {
+			return fmt.Errorf("Error fetching namespace %s: %v", channel.Namespace, err)
+		}
+		namespaceLabels = namespace.Labels
+	}
+
+	violations, err := AdmissionPolicy.Violations(context.Background(), channel, old, namespaceLabels)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("Channel violates the admission policy: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// validateNamingPolicies checks the name of the channel against all ChannelNamingPolicies
+func validateNamingPolicies(channel *Channel) error {
+	policies, namespaceLabels, err := namingPolicies(channel)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if err := policy.Validate(channel.Spec.Name, namespaceLabels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyNamingPrefixes prepends the prefixes the ChannelNamingPolicies add to the name of the channel
+func applyNamingPrefixes(channel *Channel) error {
+	policies, namespaceLabels, err := namingPolicies(channel)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		if channel.Spec.Name, err = policy.AddPrefixes(channel.Spec.Name, namespaceLabels); err != nil {
+			return err
+		}
 	}
 	return nil
 }
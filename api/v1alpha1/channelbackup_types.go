@@ -0,0 +1,131 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChannelBackupSpec selects the Channels which are backed up and where the backups are written to
+type ChannelBackupSpec struct {
+	// Namespaces, or patterns like team-*, of the Channels to back up. Channels of all namespaces are backed up
+	// when empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// How often the Channels are backed up
+	// +kubebuilder:default="24h"
+	// +optional
+	Period metav1.Duration `json:"period,omitempty"`
+
+	// Where the backups are written to
+	// +required
+	Destination BackupDestination `json:"destination"`
+}
+
+// BackupDestination is where backups are written to, exactly one of configMap and path must be set
+type BackupDestination struct {
+	// ConfigMap holding the latest backup in its channels.yaml key. ConfigMaps are limited to 1MiB, use a path for
+	// large workspaces.
+	// +optional
+	ConfigMap *ObjectReference `json:"configMap,omitempty"`
+
+	// Directory in the operator container the backups are written to, e.g. the mount path of a persistent volume
+	// or of a bucket mounted by a CSI driver. Every backup is written to a new file named after its time.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Number of backups kept in path, older files are deleted
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Keep int32 `json:"keep,omitempty"`
+}
+
+// ObjectReference refers to a namespaced object
+type ObjectReference struct {
+	// Namespace of the object
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Namespace string `json:"namespace"`
+
+	// Name of the object
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
+// ChannelBackupStatus describes the latest backup
+type ChannelBackupStatus struct {
+	// Time of the latest backup
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// Number of Channels in the latest backup
+	// +optional
+	Channels int32 `json:"channels,omitempty"`
+
+	// ConfigMap or file the latest backup was written to
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Last backup",type=date,JSONPath=`.status.lastBackupTime`
+// +kubebuilder:printcolumn:name="Channels",type=integer,JSONPath=`.status.channels`
+
+// ChannelBackup is the Schema for the channelbackups API, it periodically backs up the managed channels
+type ChannelBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChannelBackupSpec   `json:"spec,omitempty"`
+	Status ChannelBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChannelBackupList contains a list of ChannelBackup
+type ChannelBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChannelBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChannelBackup{}, &ChannelBackupList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making ChannelBackup ConditionsStatusAware
+func (backup *ChannelBackup) GetReconcileStatus() []metav1.Condition {
+	return backup.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making ChannelBackup ConditionsStatusAware
+func (backup *ChannelBackup) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	backup.Status.Conditions = reconcileStatus
+}
+
+// Includes checks whether the Channels of the namespace are backed up
+func (backup *ChannelBackup) Includes(namespace string) bool {
+	return matchesNamespace(backup.Spec.Namespaces, namespace)
+}
@@ -0,0 +1,134 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MergeChannel is a channel of a merge, exactly one of channelRef and id must be set
+type MergeChannel struct {
+	// Channel resource managing the slack channel, only merges in the trusted namespaces of the operator reference
+	// Channels of other namespaces
+	// +optional
+	ChannelRef *ChannelReference `json:"channelRef,omitempty"`
+
+	// ID of a slack channel which isn't managed by a Channel, only merges in the trusted namespaces of the operator
+	// merge channels by ID
+	// +optional
+	ID string `json:"id,omitempty"`
+}
+
+// ChannelMergeSpec defines the desired state of ChannelMerge
+type ChannelMergeSpec struct {
+	// Channel which is merged into the target and archived
+	// +required
+	Source MergeChannel `json:"source"`
+
+	// Channel the members of the source are invited to
+	// +required
+	Target MergeChannel `json:"target"`
+
+	// Repost the messages pinned in the source to the target and pin them there
+	// +optional
+	CopyPins bool `json:"copyPins,omitempty"`
+
+	// Message posted to the source before it's archived, {{target}} is replaced with the ID of the target, e.g.
+	// <#{{target}}> links to it
+	// +optional
+	RedirectMessage string `json:"redirectMessage,omitempty"`
+}
+
+// ChannelMergeStatus defines the observed state of ChannelMerge
+type ChannelMergeStatus struct {
+	// ID of the source slack channel
+	// +optional
+	SourceID string `json:"sourceId,omitempty"`
+
+	// ID of the target slack channel
+	// +optional
+	TargetID string `json:"targetId,omitempty"`
+
+	// Number of members of the source who were invited to the target
+	// +optional
+	MembersInvited int `json:"membersInvited,omitempty"`
+
+	// Number of pinned messages copied to the target
+	// +optional
+	PinsCopied int `json:"pinsCopied,omitempty"`
+
+	// Timestamp of the redirect message posted to the source
+	// +optional
+	RedirectTimestamp string `json:"redirectTimestamp,omitempty"`
+
+	// Time the source was archived at, the merge is complete then
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.status.sourceId`
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.status.targetId`
+// +kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completedAt`
+
+// ChannelMerge is the Schema for the channelmerges API, it merges a channel into another one once: the members of
+// the source are invited to the target, a redirect message is posted to the source and the source is archived
+type ChannelMerge struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChannelMergeSpec   `json:"spec,omitempty"`
+	Status ChannelMergeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChannelMergeList contains a list of ChannelMerge
+type ChannelMergeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChannelMerge `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChannelMerge{}, &ChannelMergeList{})
+}
+
+// RedirectText returns the redirect message of the merge for the target channel
+func (merge *ChannelMerge) RedirectText(targetID string) string {
+	message := merge.Spec.RedirectMessage
+	if message == "" {
+		message = "This channel was merged into <#{{target}}>, please continue the conversation there."
+	}
+	return strings.ReplaceAll(message, "{{target}}", targetID)
+}
+
+// GetReconcileStatus - returns conditions, required for making ChannelMerge ConditionsStatusAware
+func (merge *ChannelMerge) GetReconcileStatus() []metav1.Condition {
+	return merge.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making ChannelMerge ConditionsStatusAware
+func (merge *ChannelMerge) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	merge.Status.Conditions = reconcileStatus
+}
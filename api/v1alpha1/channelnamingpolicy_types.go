@@ -0,0 +1,147 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ChannelNamingPolicySpec defines the naming conventions of slack channels
+type ChannelNamingPolicySpec struct {
+	// Regular expressions of allowed slack channel names, names must match one of them. Any name is allowed when
+	// empty.
+	// +optional
+	AllowedPatterns []string `json:"allowedPatterns,omitempty"`
+
+	// Prefixes the names of the Channels in some namespaces must start with
+	// +optional
+	Prefixes []NamingPrefix `json:"prefixes,omitempty"`
+
+	// Slack channel names no Channel may use, e.g. general or announcements
+	// +optional
+	ReservedNames []string `json:"reservedNames,omitempty"`
+}
+
+// NamingPrefix is a prefix required for the Channels of the namespaces matching the selector
+type NamingPrefix struct {
+	// Labels of the namespaces whose Channels need the prefix, an empty selector matches every namespace
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Prefix of the slack channel names
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Prefix string `json:"prefix"`
+
+	// Prepend the prefix to the names of new Channels without it instead of rejecting them
+	// +optional
+	AddPrefix bool `json:"addPrefix,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// ChannelNamingPolicy is the Schema for the channelnamingpolicies API, the Channel webhook enforces it for Channels
+// in all namespaces
+type ChannelNamingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ChannelNamingPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChannelNamingPolicyList contains a list of ChannelNamingPolicy
+type ChannelNamingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChannelNamingPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChannelNamingPolicy{}, &ChannelNamingPolicyList{})
+}
+
+// Validate checks the name of a Channel in a namespace with the labels against the policy
+func (policy *ChannelNamingPolicy) Validate(name string, namespaceLabels map[string]string) error {
+	name = NormalizeChannelName(name)
+
+	for _, reserved := range policy.Spec.ReservedNames {
+		if name == NormalizeChannelName(reserved) {
+			return fmt.Errorf("The slack channel name %s is reserved by ChannelNamingPolicy %s", name, policy.Name)
+		}
+	}
+
+	prefixes, err := policy.prefixesFor(namespaceLabels)
+	if err != nil {
+		return err
+	}
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(name, NormalizeChannelName(prefix.Prefix)) {
+			return fmt.Errorf("ChannelNamingPolicy %s requires slack channel names in this namespace to start with %s", policy.Name, prefix.Prefix)
+		}
+	}
+
+	if len(policy.Spec.AllowedPatterns) == 0 {
+		return nil
+	}
+	for _, pattern := range policy.Spec.AllowedPatterns {
+		matched, err := regexp.MatchString(pattern, name)
+		if err != nil {
+			return fmt.Errorf("ChannelNamingPolicy %s has an invalid pattern %s: %v", policy.Name, pattern, err)
+		}
+		if matched {
+			return nil
+		}
+	}
+	return fmt.Errorf("The slack channel name %s doesn't match the patterns of ChannelNamingPolicy %s: %s", name, policy.Name, strings.Join(policy.Spec.AllowedPatterns, ", "))
+}
+
+// AddPrefixes prepends the prefixes with addPrefix for the namespace labels which the name lacks
+func (policy *ChannelNamingPolicy) AddPrefixes(name string, namespaceLabels map[string]string) (string, error) {
+	prefixes, err := policy.prefixesFor(namespaceLabels)
+	if err != nil {
+		return name, err
+	}
+	for _, prefix := range prefixes {
+		if prefix.AddPrefix && !strings.HasPrefix(NormalizeChannelName(name), NormalizeChannelName(prefix.Prefix)) {
+			name = prefix.Prefix + NormalizeChannelName(name)
+		}
+	}
+	return name, nil
+}
+
+// prefixesFor returns the prefixes whose namespace selector matches the labels
+func (policy *ChannelNamingPolicy) prefixesFor(namespaceLabels map[string]string) ([]NamingPrefix, error) {
+	var prefixes []NamingPrefix
+	for _, prefix := range policy.Spec.Prefixes {
+		selector, err := metav1.LabelSelectorAsSelector(&prefix.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("ChannelNamingPolicy %s has an invalid namespace selector: %v", policy.Name, err)
+		}
+		if selector.Matches(labels.Set(namespaceLabels)) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes, nil
+}
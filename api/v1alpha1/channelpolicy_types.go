@@ -0,0 +1,195 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ChannelPolicySpec defines the defaults injected into the Channels the policy applies to
+type ChannelPolicySpec struct {
+	// Namespaces of the Channels the policy applies to, supports wildcards, e.g. team-*. All namespaces when empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Labels of the Channels the policy applies to, all Channels in the namespaces when unset
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Link bookmarks every channel must have, e.g. a link to the security policy. Bookmarks are matched by their
+	// link and added when missing, other bookmarks are kept.
+	// +optional
+	Bookmarks []PolicyBookmark `json:"bookmarks,omitempty"`
+
+	// Text the topic of every channel must end with, it is appended to topics without it
+	// +optional
+	TopicSuffix string `json:"topicSuffix,omitempty"`
+
+	// Emails of users, or slack user IDs with the id: prefix, who must be members of every channel, e.g. a
+	// compliance bot. They are invited and never removed, also from channels which don't manage their members.
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// Emails of users, or slack user IDs with the id: prefix, who must not be members of any channel. They are
+	// removed even if a Channel lists them, except for the operator's protected users.
+	// +optional
+	ForbiddenMembers []string `json:"forbiddenMembers,omitempty"`
+}
+
+// PolicyBookmark is a link bookmark of a slack channel
+type PolicyBookmark struct {
+	// Title of the bookmark
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Title string `json:"title"`
+
+	// Link of the bookmark
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Link string `json:"link"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// ChannelPolicy is the Schema for the channelpolicies API, its bookmarks, topic suffix and members are injected into
+// every Channel it applies to when the Channel is reconciled
+type ChannelPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ChannelPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChannelPolicyList contains a list of ChannelPolicy
+type ChannelPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChannelPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChannelPolicy{}, &ChannelPolicyList{})
+}
+
+// AppliesTo checks whether the policy applies to the Channel, an invalid selector matches no Channel
+func (policy *ChannelPolicy) AppliesTo(channel *Channel) bool {
+	if !matchesNamespace(policy.Spec.Namespaces, channel.Namespace) {
+		return false
+	}
+	if policy.Spec.Selector == nil {
+		return true
+	}
+	selector, err := metav1.LabelSelectorAsSelector(policy.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(channel.Labels))
+}
+
+// ChannelPolicyDefaults merges the policies applying to the Channel in the order of their names, it returns nil when
+// none applies. Members who are forbidden by any policy aren't mandatory.
+func ChannelPolicyDefaults(channel *Channel, policies []ChannelPolicy) *ChannelPolicyStatus {
+	sorted := append([]ChannelPolicy{}, policies...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var defaults *ChannelPolicyStatus
+	links := map[string]bool{}
+	for i := range sorted {
+		policy := &sorted[i]
+		if !policy.AppliesTo(channel) {
+			continue
+		}
+		if defaults == nil {
+			defaults = &ChannelPolicyStatus{}
+		}
+
+		defaults.Policies = append(defaults.Policies, policy.Name)
+		for _, bookmark := range policy.Spec.Bookmarks {
+			if !links[bookmark.Link] {
+				links[bookmark.Link] = true
+				defaults.Bookmarks = append(defaults.Bookmarks, bookmark)
+			}
+		}
+		if suffix := strings.TrimSpace(policy.Spec.TopicSuffix); suffix != "" {
+			defaults.TopicSuffixes = appendUnique(defaults.TopicSuffixes, suffix)
+		}
+		for _, member := range policy.Spec.Members {
+			defaults.Members = appendUnique(defaults.Members, member)
+		}
+		for _, member := range policy.Spec.ForbiddenMembers {
+			defaults.ForbiddenMembers = appendUnique(defaults.ForbiddenMembers, member)
+		}
+	}
+	if defaults == nil {
+		return nil
+	}
+
+	var members []string
+	for _, member := range defaults.Members {
+		if !defaults.Forbids(member) {
+			members = append(members, member)
+		}
+	}
+	defaults.Members = members
+	return defaults
+}
+
+// Forbids checks whether the user, an email or a slack user ID with the id: prefix, is a forbidden member
+func (defaults *ChannelPolicyStatus) Forbids(user string) bool {
+	if defaults == nil {
+		return false
+	}
+	for _, forbidden := range defaults.ForbiddenMembers {
+		if strings.EqualFold(forbidden, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendTopicSuffixes appends the topic suffixes which the topic doesn't end with yet
+func (defaults *ChannelPolicyStatus) AppendTopicSuffixes(topic string) string {
+	if defaults == nil {
+		return topic
+	}
+	for _, suffix := range defaults.TopicSuffixes {
+		topic = strings.TrimSpace(topic)
+		if !strings.HasSuffix(topic, suffix) {
+			topic = strings.TrimSpace(topic + " " + suffix)
+		}
+	}
+	return topic
+}
+
+// appendUnique appends the value unless the values contain it, compared case-insensitively
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if strings.EqualFold(existing, value) {
+			return values
+		}
+	}
+	return append(values, value)
+}
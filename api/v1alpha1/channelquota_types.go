@@ -0,0 +1,101 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChannelQuotaSpec defines the desired state of ChannelQuota
+type ChannelQuotaSpec struct {
+	// Maximum number of Channels in the namespace, unlimited when unset
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxChannels *int32 `json:"maxChannels,omitempty"`
+
+	// Prefixes the names of the slack channels in the namespace must start with, any name is allowed when empty
+	// +optional
+	AllowedPrefixes []string `json:"allowedPrefixes,omitempty"`
+}
+
+// ChannelQuotaStatus defines the observed state of ChannelQuota
+type ChannelQuotaStatus struct {
+	// Number of Channels in the namespace
+	// +optional
+	Used int32 `json:"used"`
+
+	// Channels whose names don't start with an allowed prefix, e.g. ones created before the quota
+	// +optional
+	Violations []string `json:"violations,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=channelquotas
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Max",type=integer,JSONPath=`.spec.maxChannels`
+// +kubebuilder:printcolumn:name="Used",type=integer,JSONPath=`.status.used`
+
+// ChannelQuota is the Schema for the channelquotas API, it limits the Channels of its namespace
+type ChannelQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChannelQuotaSpec   `json:"spec,omitempty"`
+	Status ChannelQuotaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChannelQuotaList contains a list of ChannelQuota
+type ChannelQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChannelQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChannelQuota{}, &ChannelQuotaList{})
+}
+
+// AllowsName checks whether the normalized slack channel name starts with one of the allowed prefixes
+func (quota *ChannelQuota) AllowsName(name string) bool {
+	if len(quota.Spec.AllowedPrefixes) == 0 {
+		return true
+	}
+	name = NormalizeChannelName(name)
+	for _, prefix := range quota.Spec.AllowedPrefixes {
+		if strings.HasPrefix(name, NormalizeChannelName(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetReconcileStatus - returns conditions, required for making ChannelQuota ConditionsStatusAware
+func (quota *ChannelQuota) GetReconcileStatus() []metav1.Condition {
+	return quota.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making ChannelQuota ConditionsStatusAware
+func (quota *ChannelQuota) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	quota.Status.Conditions = reconcileStatus
+}
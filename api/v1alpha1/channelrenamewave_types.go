@@ -0,0 +1,196 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NameReplacement replaces the prefix or suffix of channel names
+type NameReplacement struct {
+	// Prefix or suffix the names must have, e.g. proj-
+	// +required
+	From string `json:"from"`
+
+	// Prefix or suffix it is replaced with, e.g. prj-
+	// +optional
+	To string `json:"to,omitempty"`
+}
+
+// ChannelRenameWaveSpec defines the desired state of ChannelRenameWave
+type ChannelRenameWaveSpec struct {
+	// Selects the Channel resources which are renamed
+	// +required
+	ChannelSelector metav1.LabelSelector `json:"channelSelector"`
+
+	// Namespaces to select channels from, defaults to the namespace of the ChannelRenameWave. Only waves in the trusted
+	// namespaces of the operator select the channels of other namespaces.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Replaces the prefix of the channel names, at least one of prefix and suffix must be set
+	// +optional
+	Prefix *NameReplacement `json:"prefix,omitempty"`
+
+	// Replaces the suffix of the channel names
+	// +optional
+	Suffix *NameReplacement `json:"suffix,omitempty"`
+
+	// Number of channels renamed at once
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	// +optional
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// Time between two batches, which keeps the renames below the rate limit of Slack
+	// +kubebuilder:default="1m"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Renames Channels whose name is managed by another tool, e.g. a GitOps tool which may revert the rename. Those
+	// Channels fail with a conflict otherwise.
+	// +optional
+	ForceConflicts bool `json:"forceConflicts,omitempty"`
+}
+
+// RenameState is the progress of renaming a single channel
+// +kubebuilder:validation:Enum=Pending;Updated;Renamed;Failed
+type RenameState string
+
+const (
+	// RenamePending channels are waiting for their batch
+	RenamePending RenameState = "Pending"
+
+	// RenameUpdated channels have the new name in their spec and wait for the Channel controller to rename them
+	RenameUpdated RenameState = "Updated"
+
+	// RenameRenamed channels were renamed in Slack
+	RenameRenamed RenameState = "Renamed"
+
+	// RenameFailed channels couldn't be renamed, e.g. because the new name is invalid
+	RenameFailed RenameState = "Failed"
+)
+
+// RenameWaveChannel is the progress of renaming a single channel
+type RenameWaveChannel struct {
+	// Channel resource which is renamed, as <namespace>/<name>
+	Channel string `json:"channel"`
+
+	// Name of the slack channel before the wave
+	From string `json:"from"`
+
+	// New name of the slack channel
+	To string `json:"to"`
+
+	// State of the rename
+	State RenameState `json:"state"`
+
+	// Generation of the Channel with the new name
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// Error returned while renaming the channel
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ChannelRenameWaveStatus defines the observed state of ChannelRenameWave
+type ChannelRenameWaveStatus struct {
+	// Channels selected when the wave started, the selection isn't changed afterwards
+	// +optional
+	Channels []RenameWaveChannel `json:"channels,omitempty"`
+
+	// Number of channels which were renamed
+	// +optional
+	Renamed int `json:"renamed,omitempty"`
+
+	// Number of channels which couldn't be renamed
+	// +optional
+	Failed int `json:"failed,omitempty"`
+
+	// Time the last batch was started at
+	// +optional
+	LastBatchTime *metav1.Time `json:"lastBatchTime,omitempty"`
+
+	// Time all channels were renamed or failed at
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Renamed",type=integer,JSONPath=`.status.renamed`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+// +kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completedAt`
+
+// ChannelRenameWave is the Schema for the channelrenamewaves API, it renames the selected channels in batches by
+// replacing the prefix or suffix of their names in the Channel specs
+type ChannelRenameWave struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChannelRenameWaveSpec   `json:"spec,omitempty"`
+	Status ChannelRenameWaveStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChannelRenameWaveList contains a list of ChannelRenameWave
+type ChannelRenameWaveList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChannelRenameWave `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChannelRenameWave{}, &ChannelRenameWaveList{})
+}
+
+// Rename returns the new name of a channel, or false if the name doesn't have the prefix or suffix of the wave
+func (wave *ChannelRenameWave) Rename(name string) (string, bool) {
+	renamed := NormalizeChannelName(name)
+
+	if prefix := wave.Spec.Prefix; prefix != nil {
+		if !strings.HasPrefix(renamed, prefix.From) {
+			return "", false
+		}
+		renamed = prefix.To + strings.TrimPrefix(renamed, prefix.From)
+	}
+	if suffix := wave.Spec.Suffix; suffix != nil {
+		if !strings.HasSuffix(renamed, suffix.From) {
+			return "", false
+		}
+		renamed = strings.TrimSuffix(renamed, suffix.From) + suffix.To
+	}
+
+	return renamed, renamed != NormalizeChannelName(name)
+}
+
+// GetReconcileStatus - returns conditions, required for making ChannelRenameWave ConditionsStatusAware
+func (wave *ChannelRenameWave) GetReconcileStatus() []metav1.Condition {
+	return wave.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making ChannelRenameWave ConditionsStatusAware
+func (wave *ChannelRenameWave) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	wave.Status.Conditions = reconcileStatus
+}
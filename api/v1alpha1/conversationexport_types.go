@@ -0,0 +1,130 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExportFormat is the file format of exported messages
+// +kubebuilder:validation:Enum=JSON;NDJSON
+type ExportFormat string
+
+const (
+	// ExportJSON writes the messages of an export as a JSON array
+	ExportJSON ExportFormat = "JSON"
+	// ExportNDJSON writes every message of an export as a JSON object on its own line
+	ExportNDJSON ExportFormat = "NDJSON"
+)
+
+// ConversationExportSpec defines the desired state of ConversationExport
+type ConversationExportSpec struct {
+	// Channel whose messages are exported
+	// +required
+	Channel ChannelReference `json:"channel"`
+
+	// Directory in the operator container the archives are written to, e.g. the mount path of a persistent volume
+	// or of a bucket mounted by a CSI driver. Every export writes the messages posted since the previous one to a
+	// new file in <path>/<namespace>/<channel>.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Path string `json:"path"`
+
+	// File format of the archives
+	// +kubebuilder:default=NDJSON
+	// +optional
+	Format ExportFormat `json:"format,omitempty"`
+
+	// How often new messages are exported, the messages are exported once when it isn't set
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+
+	// Export the messages once more when the Channel is deleted, before the operator archives its slack channel.
+	// The Channel isn't deleted until the export succeeded.
+	// +optional
+	BeforeArchive bool `json:"beforeArchive,omitempty"`
+}
+
+// ConversationExportStatus defines the observed state of ConversationExport
+type ConversationExportStatus struct {
+	// Time of the latest export
+	// +optional
+	LastExportTime *metav1.Time `json:"lastExportTime,omitempty"`
+
+	// Timestamp of the newest exported message, the next export starts after it
+	// +optional
+	Latest string `json:"latest,omitempty"`
+
+	// Number of messages exported in total
+	// +optional
+	Messages int64 `json:"messages,omitempty"`
+
+	// Archive the latest messages were written to
+	// +optional
+	LastFile string `json:"lastFile,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Channel",type=string,JSONPath=`.spec.channel.name`
+// +kubebuilder:printcolumn:name="Last export",type=date,JSONPath=`.status.lastExportTime`
+// +kubebuilder:printcolumn:name="Messages",type=integer,JSONPath=`.status.messages`
+
+// ConversationExport is the Schema for the conversationexports API, it archives the messages of a channel
+type ConversationExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ConversationExportSpec   `json:"spec,omitempty"`
+	Status ConversationExportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ConversationExportList contains a list of ConversationExport
+type ConversationExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConversationExport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ConversationExport{}, &ConversationExportList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making ConversationExport ConditionsStatusAware
+func (export *ConversationExport) GetReconcileStatus() []metav1.Condition {
+	return export.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making ConversationExport ConditionsStatusAware
+func (export *ConversationExport) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	export.Status.Conditions = reconcileStatus
+}
+
+// PendingBeforeArchive checks whether the messages of the Channel, which is being deleted, still have to be
+// exported before its slack channel is archived
+func (export *ConversationExport) PendingBeforeArchive(channel *Channel) bool {
+	if !export.Spec.BeforeArchive || channel.DeletionTimestamp == nil {
+		return false
+	}
+	last := export.Status.LastExportTime
+	return last == nil || last.Before(channel.DeletionTimestamp)
+}
@@ -0,0 +1,114 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DriftReportSpec selects the Channels summarized by a DriftReport
+type DriftReportSpec struct {
+	// Namespaces, or patterns like team-*, of the reported Channels. Channels of all namespaces are reported when
+	// empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// DriftedChannel is a Channel whose slack channel is out of sync
+type DriftedChannel struct {
+	// Namespace of the Channel
+	Namespace string `json:"namespace"`
+
+	// Name of the Channel
+	Name string `json:"name"`
+
+	// ID of the slack channel
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	ChannelDrift `json:",inline"`
+}
+
+// DriftReportStatus lists the Channels which are out of sync
+type DriftReportStatus struct {
+	// Number of Channels which are out of sync
+	OutOfSync int32 `json:"outOfSync"`
+
+	// Channels which are out of sync, the longest out of sync first
+	// +optional
+	Channels []DriftedChannel `json:"channels,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Out of sync",type=integer,JSONPath=`.status.outOfSync`
+
+// DriftReport is the Schema for the driftreports API, it summarizes the Channels which are out of sync
+type DriftReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DriftReportSpec   `json:"spec,omitempty"`
+	Status DriftReportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DriftReportList contains a list of DriftReport
+type DriftReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DriftReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DriftReport{}, &DriftReportList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making DriftReport ConditionsStatusAware
+func (report *DriftReport) GetReconcileStatus() []metav1.Condition {
+	return report.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making DriftReport ConditionsStatusAware
+func (report *DriftReport) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	report.Status.Conditions = reconcileStatus
+}
+
+// Reports checks whether the Channels of the namespace are reported
+func (report *DriftReport) Reports(namespace string) bool {
+	return matchesNamespace(report.Spec.Namespaces, namespace)
+}
+
+// matchesNamespace checks whether the namespace matches one of the patterns, all namespaces match if there are none
+func matchesNamespace(patterns []string, namespace string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EmojiImage is a custom emoji and the source of its image, exactly one of url and configMapRef must be set
+type EmojiImage struct {
+	// Name of the emoji, without colons
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_'-]+$`
+	// +required
+	Name string `json:"name"`
+
+	// URL of the image
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Key of a ConfigMap in the same namespace holding the image as binary data
+	// +optional
+	ConfigMapRef *KeyReference `json:"configMapRef,omitempty"`
+}
+
+// EmojiSpec defines the desired state of Emoji, it manages a single emoji, a pack of emoji or both
+type EmojiSpec struct {
+	// Name of a single emoji
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_'-]+$`
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// URL of the image of the single emoji
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Key of a ConfigMap holding the image of the single emoji
+	// +optional
+	ConfigMapRef *KeyReference `json:"configMapRef,omitempty"`
+
+	// Pack of emoji synced together
+	// +optional
+	Pack []EmojiImage `json:"pack,omitempty"`
+}
+
+// ManagedEmoji is an emoji added by the operator
+type ManagedEmoji struct {
+	// Name of the emoji
+	Name string `json:"name"`
+
+	// Hash of the image source the emoji was added with
+	SourceHash string `json:"sourceHash"`
+}
+
+// EmojiStatus defines the observed state of Emoji
+type EmojiStatus struct {
+	// Emoji added to the workspace
+	// +optional
+	Emojis []ManagedEmoji `json:"emojis,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Emoji is the Schema for the emojis API
+type Emoji struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EmojiSpec   `json:"spec,omitempty"`
+	Status EmojiStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EmojiList contains a list of Emoji
+type EmojiList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Emoji `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Emoji{}, &EmojiList{})
+}
+
+// Images returns the single emoji followed by the pack
+func (emoji *Emoji) Images() []EmojiImage {
+	images := []EmojiImage{}
+	if emoji.Spec.Name != "" {
+		images = append(images, EmojiImage{Name: emoji.Spec.Name, URL: emoji.Spec.URL, ConfigMapRef: emoji.Spec.ConfigMapRef})
+	}
+	return append(images, emoji.Spec.Pack...)
+}
+
+// GetReconcileStatus - returns conditions, required for making Emoji ConditionsStatusAware
+func (emoji *Emoji) GetReconcileStatus() []metav1.Condition {
+	return emoji.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making Emoji ConditionsStatusAware
+func (emoji *Emoji) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	emoji.Status.Conditions = reconcileStatus
+}
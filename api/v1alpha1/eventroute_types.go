@@ -0,0 +1,141 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ChannelReference references a Channel custom resource
+type ChannelReference struct {
+	// Name of the Channel resource
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the Channel resource, defaults to the namespace of the referencing resource
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ClusterChannelReference is a reference to a Channel resource from a cluster scoped resource, which has no
+// namespace to default to
+type ClusterChannelReference struct {
+	// Name of the Channel resource
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the Channel resource
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Namespace string `json:"namespace"`
+}
+
+// Key returns the namespaced name of the referenced Channel
+func (ref ClusterChannelReference) Key() types.NamespacedName {
+	return types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+}
+
+// EventType is the type of a Kubernetes event
+// +kubebuilder:validation:Enum=Normal;Warning
+type EventType string
+
+// EventRouteSpec defines the desired state of EventRoute
+type EventRouteSpec struct {
+	// Channel the matching events are posted to, only routes in the trusted namespaces of the operator post to Channels
+	// of other namespaces
+	// +required
+	Channel ChannelReference `json:"channel"`
+
+	// Namespaces, or patterns like team-*, to forward events from, defaults to the namespace of the route. Only routes in
+	// the trusted namespaces of the operator forward the events of other namespaces.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Only forward events from namespaces whose labels match this selector
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Only forward events with one of these reasons, e.g. BackOff or FailedScheduling
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Only forward events of these types
+	// +optional
+	Types []EventType `json:"types,omitempty"`
+
+	// Only forward events about objects of these kinds, e.g. Pod or Deployment
+	// +optional
+	InvolvedObjectKinds []string `json:"involvedObjectKinds,omitempty"`
+
+	// Maximum number of messages posted to the channel per minute, extra events are dropped
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=20
+	// +optional
+	MaxEventsPerMinute int32 `json:"maxEventsPerMinute,omitempty"`
+
+	// Repeats of the same event within this window are only posted once
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=600
+	// +optional
+	DeduplicationWindowSeconds int32 `json:"deduplicationWindowSeconds,omitempty"`
+
+	// NotificationTemplate used to render the events, the template is executed with the Kubernetes Event
+	// +optional
+	TemplateRef *NotificationTemplateReference `json:"templateRef,omitempty"`
+}
+
+// EventRouteStatus defines the observed state of EventRoute
+type EventRouteStatus struct {
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// EventRoute is the Schema for the eventroutes API
+type EventRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EventRouteSpec   `json:"spec,omitempty"`
+	Status EventRouteStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EventRouteList contains a list of EventRoute
+type EventRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EventRoute `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EventRoute{}, &EventRouteList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making EventRoute ConditionsStatusAware
+func (route *EventRoute) GetReconcileStatus() []metav1.Condition {
+	return route.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making EventRoute ConditionsStatusAware
+func (route *EventRoute) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	route.Status.Conditions = reconcileStatus
+}
@@ -0,0 +1,65 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// FeaturePolicy restricts a feature to Channels in some namespaces or with some labels
+// +kubebuilder:object:generate=false
+type FeaturePolicy struct {
+	// Namespaces are namespaces or patterns like team-* whose Channels may use the feature
+	Namespaces []string
+	// Selector matches the labels of Channels which may use the feature, no Channel matches when it is nil
+	Selector labels.Selector
+}
+
+// Allows checks whether the channel may use the feature, a nil policy allows every channel
+func (p *FeaturePolicy) Allows(channel *Channel) bool {
+	if p == nil {
+		return true
+	}
+	for _, pattern := range p.Namespaces {
+		if matched, _ := path.Match(pattern, channel.Namespace); matched {
+			return true
+		}
+	}
+	return p.Selector != nil && p.Selector.Matches(labels.Set(channel.Labels))
+}
+
+// PrivateChannelPolicy restricts private channels and MemberEnforcementPolicy restricts Channels which manage
+// their members, and so remove unlisted members from the slack channel. Both are set from the operator flags.
+var (
+	PrivateChannelPolicy    *FeaturePolicy
+	MemberEnforcementPolicy *FeaturePolicy
+)
+
+// ValidateFeaturePolicies checks that the features the channel enables, compared to the old channel if it is
+// updated, are allowed by their policies
+func ValidateFeaturePolicies(channel *Channel, old *Channel) error {
+	if channel.Spec.Private && (old == nil || !old.Spec.Private) && !PrivateChannelPolicy.Allows(channel) {
+		return fmt.Errorf("Private channels aren't allowed in namespace %s", channel.Namespace)
+	}
+	if channel.ManagesMembers() && (old == nil || !old.ManagesMembers()) && !MemberEnforcementPolicy.Allows(channel) {
+		return fmt.Errorf("Channels in namespace %s can't manage their members, remove users and scimGroups", channel.Namespace)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FileUploadSecretLabel opts a Secret in to being uploaded by FileUploads, Secrets without the label set to "true"
+// aren't read
+const FileUploadSecretLabel = "slack.stakater.com/file-upload"
+
+// KeyReference selects a key of a ConfigMap or Secret in the same namespace
+type KeyReference struct {
+	// Name of the ConfigMap or Secret
+	// +required
+	Name string `json:"name"`
+
+	// Key holding the content
+	// +required
+	Key string `json:"key"`
+}
+
+// FileSource is the content of the file, exactly one of the fields must be set
+type FileSource struct {
+	// Inline content of the file
+	// +optional
+	Content string `json:"content,omitempty"`
+
+	// Key of a ConfigMap holding the content
+	// +optional
+	ConfigMapRef *KeyReference `json:"configMapRef,omitempty"`
+
+	// Key of a Secret holding the content, the Secret must be labeled slack.stakater.com/file-upload=true
+	// +optional
+	SecretRef *KeyReference `json:"secretRef,omitempty"`
+}
+
+// FileUploadSpec defines the desired state of FileUpload
+type FileUploadSpec struct {
+	// Channel the file is shared in
+	// +required
+	Channel ChannelReference `json:"channel"`
+
+	// Name of the file, its extension is used by slack to pick the snippet type
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Filename string `json:"filename"`
+
+	// Title of the file, defaults to the filename
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Source of the file content
+	// +required
+	Source FileSource `json:"source"`
+}
+
+// FileUploadStatus defines the observed state of FileUpload
+type FileUploadStatus struct {
+	// ID of the uploaded slack file
+	// +optional
+	FileID string `json:"fileId,omitempty"`
+
+	// ID of the slack channel the file was shared in
+	// +optional
+	ChannelID string `json:"channelId,omitempty"`
+
+	// Hash of the uploaded content and file details, the file is uploaded again when it changes
+	// +optional
+	ContentHash string `json:"contentHash,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// FileUpload is the Schema for the fileuploads API
+type FileUpload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FileUploadSpec   `json:"spec,omitempty"`
+	Status FileUploadStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FileUploadList contains a list of FileUpload
+type FileUploadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FileUpload `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FileUpload{}, &FileUploadList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making FileUpload ConditionsStatusAware
+func (upload *FileUpload) GetReconcileStatus() []metav1.Condition {
+	return upload.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making FileUpload ConditionsStatusAware
+func (upload *FileUpload) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	upload.Status.Conditions = reconcileStatus
+}
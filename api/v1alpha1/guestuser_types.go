@@ -0,0 +1,141 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// InviteStateExpired means the guest account was deactivated because it expired
+	InviteStateExpired InviteState = "Expired"
+)
+
+// GuestUserSpec defines the desired state of GuestUser
+type GuestUserSpec struct {
+	// Email of the guest
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Email string `json:"email"`
+
+	// Full name of the guest
+	// +optional
+	RealName string `json:"realName,omitempty"`
+
+	// Kind of guest account
+	// +required
+	Type GuestType `json:"type"`
+
+	// Channels the guest can access, single-channel guests are limited to one
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Channels []ClusterChannelReference `json:"channels"`
+
+	// Time the guest account is deactivated at
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Lifetime of the guest account counted from the creation of the GuestUser, ignored when expiresAt is set
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Message added to the invitation email
+	// +optional
+	CustomMessage string `json:"customMessage,omitempty"`
+
+	// ID of the workspace to invite the guest to, defaults to the workspace of the operator's token
+	// +optional
+	TeamID string `json:"teamId,omitempty"`
+}
+
+// GuestUserStatus defines the observed state of GuestUser
+type GuestUserStatus struct {
+	// State of the guest account
+	// +optional
+	State InviteState `json:"state,omitempty"`
+
+	// ID of the workspace the guest was invited to
+	// +optional
+	TeamID string `json:"teamId,omitempty"`
+
+	// ID of the guest once they joined the workspace
+	// +optional
+	UserID string `json:"userId,omitempty"`
+
+	// Time the invitation was sent
+	// +optional
+	InvitedAt *metav1.Time `json:"invitedAt,omitempty"`
+
+	// Expiration last applied to the guest account
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Email",type=string,JSONPath=`.spec.email`
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Expires",type=string,JSONPath=`.status.expiresAt`
+
+// GuestUser is the Schema for the guestusers API
+type GuestUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GuestUserSpec   `json:"spec,omitempty"`
+	Status GuestUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GuestUserList contains a list of GuestUser
+type GuestUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GuestUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GuestUser{}, &GuestUserList{})
+}
+
+// Expiration returns the time the guest account expires at, or nil if it doesn't expire
+func (guest *GuestUser) Expiration() *metav1.Time {
+	if guest.Spec.ExpiresAt != nil {
+		return guest.Spec.ExpiresAt
+	}
+	if guest.Spec.TTL != nil {
+		expiration := metav1.NewTime(guest.CreationTimestamp.Add(guest.Spec.TTL.Duration))
+		return &expiration
+	}
+	return nil
+}
+
+// GetReconcileStatus - returns conditions, required for making GuestUser ConditionsStatusAware
+func (guest *GuestUser) GetReconcileStatus() []metav1.Condition {
+	return guest.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making GuestUser ConditionsStatusAware
+func (guest *GuestUser) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	guest.Status.Conditions = reconcileStatus
+}
@@ -0,0 +1,112 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ThreadReference references the parent message of a thread, either as a Message resource or a raw timestamp
+type ThreadReference struct {
+	// Name of a Message resource in the same namespace
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Timestamp of a message in the same channel
+	// +optional
+	Timestamp string `json:"ts,omitempty"`
+}
+
+// MessageSpec defines the desired state of Message
+type MessageSpec struct {
+	// Channel the message is posted to
+	// +required
+	Channel ChannelReference `json:"channel"`
+
+	// Text of the message, used as fallback text when a template is referenced
+	// +optional
+	Text string `json:"text,omitempty"`
+
+	// NotificationTemplate used to render the message, the template is executed with the Message
+	// +optional
+	TemplateRef *NotificationTemplateReference `json:"templateRef,omitempty"`
+
+	// Post the message as a reply in the thread of this message
+	// +optional
+	ThreadOf *ThreadReference `json:"threadOf,omitempty"`
+
+	// Also send the reply to the channel, only used with threadOf
+	// +optional
+	ReplyBroadcast bool `json:"replyBroadcast,omitempty"`
+}
+
+// MessageStatus defines the observed state of Message
+type MessageStatus struct {
+	// ID of the slack channel the message was posted to
+	// +optional
+	ChannelID string `json:"channelId,omitempty"`
+
+	// Timestamp of the posted message, used by slack as its ID
+	// +optional
+	Timestamp string `json:"ts,omitempty"`
+
+	// Timestamp of the parent message when the message is a thread reply
+	// +optional
+	ThreadTimestamp string `json:"threadTs,omitempty"`
+
+	// Generation of the spec last posted to slack
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Message is the Schema for the messages API
+type Message struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MessageSpec   `json:"spec,omitempty"`
+	Status MessageStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MessageList contains a list of Message
+type MessageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Message `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Message{}, &MessageList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making Message ConditionsStatusAware
+func (message *Message) GetReconcileStatus() []metav1.Condition {
+	return message.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making Message ConditionsStatusAware
+func (message *Message) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	message.Status.Conditions = reconcileStatus
+}
@@ -0,0 +1,107 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestRenderAnnotation holds JSON data the template is rendered with, the result is written to the status
+const TestRenderAnnotation = "slack.stakater.com/test-render"
+
+// NotificationTemplateReference references a NotificationTemplate custom resource
+type NotificationTemplateReference struct {
+	// Name of the NotificationTemplate resource
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the NotificationTemplate resource, defaults to the namespace of the referencing resource
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// NotificationTemplateSpec defines the desired state of NotificationTemplate
+type NotificationTemplateSpec struct {
+	// Go template rendering to a JSON array of Block Kit blocks
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Blocks string `json:"blocks"`
+
+	// Go template rendering the plain text shown in notifications and by clients that can't render blocks
+	// +optional
+	Text string `json:"text,omitempty"`
+}
+
+// TestRenderStatus is the result of rendering the template with the test-render annotation's data
+type TestRenderStatus struct {
+	// Rendered Block Kit JSON
+	// +optional
+	Blocks string `json:"blocks,omitempty"`
+
+	// Rendered fallback text
+	// +optional
+	Text string `json:"text,omitempty"`
+
+	// Error returned while rendering
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// NotificationTemplateStatus defines the observed state of NotificationTemplate
+type NotificationTemplateStatus struct {
+	// Result of the last test render, only set when the test-render annotation is present
+	// +optional
+	TestRender *TestRenderStatus `json:"testRender,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// NotificationTemplate is the Schema for the notificationtemplates API
+type NotificationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationTemplateSpec   `json:"spec,omitempty"`
+	Status NotificationTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotificationTemplateList contains a list of NotificationTemplate
+type NotificationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationTemplate{}, &NotificationTemplateList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making NotificationTemplate ConditionsStatusAware
+func (template *NotificationTemplate) GetReconcileStatus() []metav1.Condition {
+	return template.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making NotificationTemplate ConditionsStatusAware
+func (template *NotificationTemplate) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	template.Status.Conditions = reconcileStatus
+}
@@ -0,0 +1,71 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/stakater/slack-operator/pkg/blockkit"
+)
+
+// log is for logging in this package.
+var notificationtemplatelog = logf.Log.WithName("notificationtemplate-resource")
+
+func (r *NotificationTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-slack-stakater-com-v1alpha1-notificationtemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=slack.stakater.com,resources=notificationtemplates,versions=v1alpha1,name=vnotificationtemplate.kb.io,admissionReviewVersions={v1,v1beta1}
+
+var _ webhook.Validator = &NotificationTemplate{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationTemplate) ValidateCreate() error {
+	notificationtemplatelog.Info("validate create", "name", r.Name)
+
+	return r.ValidateTemplates()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationTemplate) ValidateUpdate(old runtime.Object) error {
+	notificationtemplatelog.Info("validate update", "name", r.Name)
+
+	return r.ValidateTemplates()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *NotificationTemplate) ValidateDelete() error {
+	notificationtemplatelog.Info("validate delete", "name", r.Name)
+
+	return nil
+}
+
+// ValidateTemplates checks that the blocks and text templates parse
+func (r *NotificationTemplate) ValidateTemplates() error {
+	if _, err := blockkit.Compile(r.Name, r.Spec.Blocks, r.Spec.Text); err != nil {
+		return fmt.Errorf("NotificationTemplate %s is invalid: %v", r.Name, err)
+	}
+
+	return nil
+}
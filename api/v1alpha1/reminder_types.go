@@ -0,0 +1,92 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReminderSpec defines the desired state of Reminder
+type ReminderSpec struct {
+	// Text of the reminder
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Text string `json:"text"`
+
+	// When the reminder fires, either a unix timestamp or natural language like "in 15 minutes" or
+	// "every weekday at 9am" for recurring reminders
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Time string `json:"time"`
+
+	// Channel the reminder is posted to
+	// +optional
+	Channel *ChannelReference `json:"channel,omitempty"`
+
+	// Email of the user to remind, used when no channel is set
+	// +optional
+	User string `json:"user,omitempty"`
+}
+
+// ReminderStatus defines the observed state of Reminder
+type ReminderStatus struct {
+	// ID of the slack reminder
+	// +optional
+	ReminderID string `json:"reminderId,omitempty"`
+
+	// Hash of the spec the reminder was added with, the reminder is replaced when it changes
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Reminder is the Schema for the reminders API
+type Reminder struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReminderSpec   `json:"spec,omitempty"`
+	Status ReminderStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReminderList contains a list of Reminder
+type ReminderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Reminder `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Reminder{}, &ReminderList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making Reminder ConditionsStatusAware
+func (reminder *Reminder) GetReconcileStatus() []metav1.Condition {
+	return reminder.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making Reminder ConditionsStatusAware
+func (reminder *Reminder) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	reminder.Status.Conditions = reconcileStatus
+}
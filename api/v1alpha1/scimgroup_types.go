@@ -0,0 +1,88 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SCIMGroupSpec defines the desired state of SCIMGroup
+type SCIMGroupSpec struct {
+	// Display name of the group
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	DisplayName string `json:"displayName"`
+
+	// Emails of the members of the group
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// Provision workspace users for members who don't have an account yet
+	// +optional
+	ProvisionUsers bool `json:"provisionUsers,omitempty"`
+}
+
+// SCIMGroupStatus defines the observed state of SCIMGroup
+type SCIMGroupStatus struct {
+	// ID of the SCIM group
+	// +optional
+	GroupID string `json:"groupId,omitempty"`
+
+	// Members who were skipped because they have no active account
+	// +optional
+	MemberErrors []MemberError `json:"memberErrors,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Display Name",type=string,JSONPath=`.spec.displayName`
+// +kubebuilder:printcolumn:name="Group ID",type=string,JSONPath=`.status.groupId`
+
+// SCIMGroup is the Schema for the scimgroups API
+type SCIMGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SCIMGroupSpec   `json:"spec,omitempty"`
+	Status SCIMGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SCIMGroupList contains a list of SCIMGroup
+type SCIMGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SCIMGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SCIMGroup{}, &SCIMGroupList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making SCIMGroup ConditionsStatusAware
+func (group *SCIMGroup) GetReconcileStatus() []metav1.Condition {
+	return group.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making SCIMGroup ConditionsStatusAware
+func (group *SCIMGroup) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	group.Status.Conditions = reconcileStatus
+}
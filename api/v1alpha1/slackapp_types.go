@@ -0,0 +1,112 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AppDeletionPolicy decides what happens to the slack app when the SlackApp is deleted
+// +kubebuilder:validation:Enum=Retain;Delete
+type AppDeletionPolicy string
+
+const (
+	// AppDeletionPolicyRetain keeps the slack app
+	AppDeletionPolicyRetain AppDeletionPolicy = "Retain"
+	// AppDeletionPolicyDelete deletes the slack app
+	AppDeletionPolicyDelete AppDeletionPolicy = "Delete"
+)
+
+// SlackAppSpec defines the desired state of SlackApp
+type SlackAppSpec struct {
+	// App manifest with the app's scopes, slash commands, event subscriptions etc.
+	// See https://api.slack.com/reference/manifests
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +required
+	Manifest runtime.RawExtension `json:"manifest"`
+
+	// Secret in the same namespace holding an app configuration token, which the App Manifest API requires
+	// +required
+	ConfigTokenSecretRef KeyReference `json:"configTokenSecretRef"`
+
+	// Name of a Secret the app credentials are written to, credentials are only returned when the app is created
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+
+	// What happens to the slack app when the SlackApp is deleted
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy AppDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// SlackAppStatus defines the observed state of SlackApp
+type SlackAppStatus struct {
+	// ID of the slack app
+	// +optional
+	AppID string `json:"appId,omitempty"`
+
+	// OAuth client ID of the slack app
+	// +optional
+	ClientID string `json:"clientId,omitempty"`
+
+	// URL to install the app to a workspace
+	// +optional
+	OAuthAuthorizeURL string `json:"oauthAuthorizeUrl,omitempty"`
+
+	// Hash of the manifest last applied to the app
+	// +optional
+	ManifestHash string `json:"manifestHash,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// SlackApp is the Schema for the slackapps API
+type SlackApp struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SlackAppSpec   `json:"spec,omitempty"`
+	Status SlackAppStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SlackAppList contains a list of SlackApp
+type SlackAppList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SlackApp `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SlackApp{}, &SlackAppList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making SlackApp ConditionsStatusAware
+func (app *SlackApp) GetReconcileStatus() []metav1.Condition {
+	return app.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making SlackApp ConditionsStatusAware
+func (app *SlackApp) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	app.Status.Conditions = reconcileStatus
+}
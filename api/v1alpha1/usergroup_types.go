@@ -0,0 +1,94 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UserGroupSpec defines the desired state of UserGroup
+type UserGroupSpec struct {
+	// ID of the existing usergroup, e.g. S012ABC
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	ID string `json:"id"`
+
+	// Channels the members of the usergroup join automatically when they join the usergroup. Default channels which
+	// aren't listed are removed from the usergroup.
+	// +optional
+	DefaultChannels []ClusterChannelReference `json:"defaultChannels,omitempty"`
+
+	// IDs of slack channels which aren't managed by a Channel to add to the default channels
+	// +optional
+	DefaultChannelIDs []string `json:"defaultChannelIds,omitempty"`
+
+	// IDs of the workspaces an organization-wide usergroup is added to, workspaces are never removed
+	// +optional
+	Teams []string `json:"teams,omitempty"`
+}
+
+// UserGroupStatus defines the observed state of UserGroup
+type UserGroupStatus struct {
+	// IDs of the default channels of the usergroup
+	// +optional
+	DefaultChannelIDs []string `json:"defaultChannelIds,omitempty"`
+
+	// IDs of the workspaces the usergroup was added to
+	// +optional
+	Teams []string `json:"teams,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Usergroup ID",type=string,JSONPath=`.spec.id`
+
+// UserGroup is the Schema for the usergroups API, it manages the default channels and workspaces of an existing
+// usergroup with the admin.usergroups API of Enterprise Grid
+type UserGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserGroupSpec   `json:"spec,omitempty"`
+	Status UserGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UserGroupList contains a list of UserGroup
+type UserGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UserGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&UserGroup{}, &UserGroupList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making UserGroup ConditionsStatusAware
+func (group *UserGroup) GetReconcileStatus() []metav1.Condition {
+	return group.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making UserGroup ConditionsStatusAware
+func (group *UserGroup) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	group.Status.Conditions = reconcileStatus
+}
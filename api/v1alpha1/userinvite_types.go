@@ -0,0 +1,131 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GuestType is the kind of guest account a user is invited as
+// +kubebuilder:validation:Enum=MultiChannel;SingleChannel
+type GuestType string
+
+const (
+	// MultiChannelGuest can access the channels they are invited to
+	MultiChannelGuest GuestType = "MultiChannel"
+	// SingleChannelGuest can only access a single channel
+	SingleChannelGuest GuestType = "SingleChannel"
+)
+
+// InviteState is the state of a workspace invitation
+type InviteState string
+
+const (
+	// InviteStateInvited means the invitation was sent and the user hasn't joined yet
+	InviteStateInvited InviteState = "Invited"
+	// InviteStateJoined means the user is a member of the workspace
+	InviteStateJoined InviteState = "Joined"
+)
+
+// UserInviteSpec defines the desired state of UserInvite
+type UserInviteSpec struct {
+	// Email of the user to invite
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Email string `json:"email"`
+
+	// Full name of the user
+	// +optional
+	RealName string `json:"realName,omitempty"`
+
+	// Channels the user joins when accepting the invitation
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Channels []ClusterChannelReference `json:"channels"`
+
+	// Invite the user as a guest instead of a full member
+	// +optional
+	GuestType GuestType `json:"guestType,omitempty"`
+
+	// Time the guest account is deactivated at
+	// +optional
+	GuestExpiration *metav1.Time `json:"guestExpiration,omitempty"`
+
+	// Message added to the invitation email
+	// +optional
+	CustomMessage string `json:"customMessage,omitempty"`
+
+	// ID of the workspace to invite the user to, defaults to the workspace of the operator's token
+	// +optional
+	TeamID string `json:"teamId,omitempty"`
+}
+
+// UserInviteStatus defines the observed state of UserInvite
+type UserInviteStatus struct {
+	// State of the invitation
+	// +optional
+	State InviteState `json:"state,omitempty"`
+
+	// Time the invitation was sent
+	// +optional
+	InvitedAt *metav1.Time `json:"invitedAt,omitempty"`
+
+	// ID of the user once they joined the workspace
+	// +optional
+	UserID string `json:"userId,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Email",type=string,JSONPath=`.spec.email`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+
+// UserInvite is the Schema for the userinvites API
+type UserInvite struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserInviteSpec   `json:"spec,omitempty"`
+	Status UserInviteStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UserInviteList contains a list of UserInvite
+type UserInviteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UserInvite `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&UserInvite{}, &UserInviteList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making UserInvite ConditionsStatusAware
+func (invite *UserInvite) GetReconcileStatus() []metav1.Condition {
+	return invite.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making UserInvite ConditionsStatusAware
+func (invite *UserInvite) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	invite.Status.Conditions = reconcileStatus
+}
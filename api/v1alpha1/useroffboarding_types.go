@@ -0,0 +1,118 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OffboardingResult is the outcome of removing the user from a channel or group
+type OffboardingResult string
+
+const (
+	// OffboardingRemoved means the user was removed
+	OffboardingRemoved OffboardingResult = "Removed"
+	// OffboardingNotMember means the user wasn't a member
+	OffboardingNotMember OffboardingResult = "NotMember"
+	// OffboardingFailed means the user couldn't be removed, the error is reported alongside
+	OffboardingFailed OffboardingResult = "Failed"
+)
+
+// UserOffboardingSpec defines the desired state of UserOffboarding
+type UserOffboardingSpec struct {
+	// Email of the departed user
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Email string `json:"email"`
+
+	// Deactivate the user with the SCIM API after removing them, requires SCIM to be configured for the operator
+	// +optional
+	Deactivate bool `json:"deactivate,omitempty"`
+}
+
+// OffboardingReport is the result of offboarding the user from a channel or group
+type OffboardingReport struct {
+	// Namespaced name of the Channel or SCIMGroup resource
+	Name string `json:"name"`
+
+	// Result of removing the user
+	Result OffboardingResult `json:"result"`
+
+	// Error of a failed removal
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// UserOffboardingStatus defines the observed state of UserOffboarding
+type UserOffboardingStatus struct {
+	// Results per managed channel
+	// +optional
+	Channels []OffboardingReport `json:"channels,omitempty"`
+
+	// Results per managed SCIM group
+	// +optional
+	Groups []OffboardingReport `json:"groups,omitempty"`
+
+	// Whether the user was deactivated
+	// +optional
+	Deactivated bool `json:"deactivated,omitempty"`
+
+	// Whether the user was removed from all channels and groups
+	// +optional
+	Completed bool `json:"completed,omitempty"`
+
+	// Status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Email",type=string,JSONPath=`.spec.email`
+// +kubebuilder:printcolumn:name="Completed",type=boolean,JSONPath=`.status.completed`
+
+// UserOffboarding is the Schema for the useroffboardings API
+type UserOffboarding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserOffboardingSpec   `json:"spec,omitempty"`
+	Status UserOffboardingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UserOffboardingList contains a list of UserOffboarding
+type UserOffboardingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UserOffboarding `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&UserOffboarding{}, &UserOffboardingList{})
+}
+
+// GetReconcileStatus - returns conditions, required for making UserOffboarding ConditionsStatusAware
+func (offboarding *UserOffboarding) GetReconcileStatus() []metav1.Condition {
+	return offboarding.Status.Conditions
+}
+
+// SetReconcileStatus - sets status, required for making UserOffboarding ConditionsStatusAware
+func (offboarding *UserOffboarding) SetReconcileStatus(reconcileStatus []metav1.Condition) {
+	offboarding.Status.Conditions = reconcileStatus
+}
@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -25,6 +26,429 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchivePolicy) DeepCopyInto(out *ArchivePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchivePolicy.
+func (in *ArchivePolicy) DeepCopy() *ArchivePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchivePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArchivePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchivePolicyList) DeepCopyInto(out *ArchivePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ArchivePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchivePolicyList.
+func (in *ArchivePolicyList) DeepCopy() *ArchivePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchivePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ArchivePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchivePolicySpec) DeepCopyInto(out *ArchivePolicySpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Inactivity = in.Inactivity
+	if in.WarningBefore != nil {
+		in, out := &in.WarningBefore, &out.WarningBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchivePolicySpec.
+func (in *ArchivePolicySpec) DeepCopy() *ArchivePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchivePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoArchive) DeepCopyInto(out *AutoArchive) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoArchive.
+func (in *AutoArchive) DeepCopy() *AutoArchive {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoArchive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoArchiveStatus) DeepCopyInto(out *AutoArchiveStatus) {
+	*out = *in
+	if in.WarnedAt != nil {
+		in, out := &in.WarnedAt, &out.WarnedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ArchivedAt != nil {
+		in, out := &in.ArchivedAt, &out.ArchivedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ActiveSince != nil {
+		in, out := &in.ActiveSince, &out.ActiveSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoArchiveStatus.
+func (in *AutoArchiveStatus) DeepCopy() *AutoArchiveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoArchiveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupDestination) DeepCopyInto(out *BackupDestination) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestination.
+func (in *BackupDestination) DeepCopy() *BackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Barrier) DeepCopyInto(out *Barrier) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Barrier.
+func (in *Barrier) DeepCopy() *Barrier {
+	if in == nil {
+		return nil
+	}
+	out := new(Barrier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Barrier) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BarrierList) DeepCopyInto(out *BarrierList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Barrier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BarrierList.
+func (in *BarrierList) DeepCopy() *BarrierList {
+	if in == nil {
+		return nil
+	}
+	out := new(BarrierList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BarrierList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BarrierSpec) DeepCopyInto(out *BarrierSpec) {
+	*out = *in
+	if in.BarrieredFrom != nil {
+		in, out := &in.BarrieredFrom, &out.BarrieredFrom
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestrictedSubjects != nil {
+		in, out := &in.RestrictedSubjects, &out.RestrictedSubjects
+		*out = make([]BarrierSubject, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BarrierSpec.
+func (in *BarrierSpec) DeepCopy() *BarrierSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BarrierSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BarrierStatus) DeepCopyInto(out *BarrierStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BarrierStatus.
+func (in *BarrierStatus) DeepCopy() *BarrierStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BarrierStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Broadcast) DeepCopyInto(out *Broadcast) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Broadcast.
+func (in *Broadcast) DeepCopy() *Broadcast {
+	if in == nil {
+		return nil
+	}
+	out := new(Broadcast)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Broadcast) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BroadcastDelivery) DeepCopyInto(out *BroadcastDelivery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BroadcastDelivery.
+func (in *BroadcastDelivery) DeepCopy() *BroadcastDelivery {
+	if in == nil {
+		return nil
+	}
+	out := new(BroadcastDelivery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BroadcastList) DeepCopyInto(out *BroadcastList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Broadcast, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BroadcastList.
+func (in *BroadcastList) DeepCopy() *BroadcastList {
+	if in == nil {
+		return nil
+	}
+	out := new(BroadcastList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BroadcastList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BroadcastSpec) DeepCopyInto(out *BroadcastSpec) {
+	*out = *in
+	in.ChannelSelector.DeepCopyInto(&out.ChannelSelector)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(NotificationTemplateReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BroadcastSpec.
+func (in *BroadcastSpec) DeepCopy() *BroadcastSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BroadcastSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BroadcastStatus) DeepCopyInto(out *BroadcastStatus) {
+	*out = *in
+	if in.Deliveries != nil {
+		in, out := &in.Deliveries, &out.Deliveries
+		*out = make([]BroadcastDelivery, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BroadcastStatus.
+func (in *BroadcastStatus) DeepCopy() *BroadcastStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BroadcastStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
+	*out = *in
+	if in.ConvergedAt != nil {
+		in, out := &in.ConvergedAt, &out.ConvergedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanvasSource) DeepCopyInto(out *CanvasSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(KeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanvasSource.
+func (in *CanvasSource) DeepCopy() *CanvasSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CanvasSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Channel) DeepCopyInto(out *Channel) {
 	*out = *in
@@ -34,18 +458,2795 @@ func (in *Channel) DeepCopyInto(out *Channel) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Channel.
-func (in *Channel) DeepCopy() *Channel {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Channel.
+func (in *Channel) DeepCopy() *Channel {
+	if in == nil {
+		return nil
+	}
+	out := new(Channel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Channel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelAction) DeepCopyInto(out *ChannelAction) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelAction.
+func (in *ChannelAction) DeepCopy() *ChannelAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelActivity) DeepCopyInto(out *ChannelActivity) {
+	*out = *in
+	if in.LastMessageTime != nil {
+		in, out := &in.LastMessageTime, &out.LastMessageTime
+		*out = (*in).DeepCopy()
+	}
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelActivity.
+func (in *ChannelActivity) DeepCopy() *ChannelActivity {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelActivity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelBackup) DeepCopyInto(out *ChannelBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelBackup.
+func (in *ChannelBackup) DeepCopy() *ChannelBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelBackupList) DeepCopyInto(out *ChannelBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChannelBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelBackupList.
+func (in *ChannelBackupList) DeepCopy() *ChannelBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelBackupSpec) DeepCopyInto(out *ChannelBackupSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Period = in.Period
+	in.Destination.DeepCopyInto(&out.Destination)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelBackupSpec.
+func (in *ChannelBackupSpec) DeepCopy() *ChannelBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelBackupStatus) DeepCopyInto(out *ChannelBackupStatus) {
+	*out = *in
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelBackupStatus.
+func (in *ChannelBackupStatus) DeepCopy() *ChannelBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelDrift) DeepCopyInto(out *ChannelDrift) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelDrift.
+func (in *ChannelDrift) DeepCopy() *ChannelDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelList) DeepCopyInto(out *ChannelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Channel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelList.
+func (in *ChannelList) DeepCopy() *ChannelList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelMerge) DeepCopyInto(out *ChannelMerge) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelMerge.
+func (in *ChannelMerge) DeepCopy() *ChannelMerge {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelMerge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelMerge) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelMergeList) DeepCopyInto(out *ChannelMergeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChannelMerge, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelMergeList.
+func (in *ChannelMergeList) DeepCopy() *ChannelMergeList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelMergeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelMergeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelMergeSpec) DeepCopyInto(out *ChannelMergeSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelMergeSpec.
+func (in *ChannelMergeSpec) DeepCopy() *ChannelMergeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelMergeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelMergeStatus) DeepCopyInto(out *ChannelMergeStatus) {
+	*out = *in
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelMergeStatus.
+func (in *ChannelMergeStatus) DeepCopy() *ChannelMergeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelMergeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelNamingPolicy) DeepCopyInto(out *ChannelNamingPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelNamingPolicy.
+func (in *ChannelNamingPolicy) DeepCopy() *ChannelNamingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelNamingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelNamingPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelNamingPolicyList) DeepCopyInto(out *ChannelNamingPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChannelNamingPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelNamingPolicyList.
+func (in *ChannelNamingPolicyList) DeepCopy() *ChannelNamingPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelNamingPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelNamingPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelNamingPolicySpec) DeepCopyInto(out *ChannelNamingPolicySpec) {
+	*out = *in
+	if in.AllowedPatterns != nil {
+		in, out := &in.AllowedPatterns, &out.AllowedPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]NamingPrefix, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReservedNames != nil {
+		in, out := &in.ReservedNames, &out.ReservedNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelNamingPolicySpec.
+func (in *ChannelNamingPolicySpec) DeepCopy() *ChannelNamingPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelNamingPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelOnCall) DeepCopyInto(out *ChannelOnCall) {
+	*out = *in
+	out.ScheduleRef = in.ScheduleRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelOnCall.
+func (in *ChannelOnCall) DeepCopy() *ChannelOnCall {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelOnCall)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelPolicy) DeepCopyInto(out *ChannelPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelPolicy.
+func (in *ChannelPolicy) DeepCopy() *ChannelPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelPolicyList) DeepCopyInto(out *ChannelPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChannelPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelPolicyList.
+func (in *ChannelPolicyList) DeepCopy() *ChannelPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelPolicySpec) DeepCopyInto(out *ChannelPolicySpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bookmarks != nil {
+		in, out := &in.Bookmarks, &out.Bookmarks
+		*out = make([]PolicyBookmark, len(*in))
+		copy(*out, *in)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForbiddenMembers != nil {
+		in, out := &in.ForbiddenMembers, &out.ForbiddenMembers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelPolicySpec.
+func (in *ChannelPolicySpec) DeepCopy() *ChannelPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelPolicyStatus) DeepCopyInto(out *ChannelPolicyStatus) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bookmarks != nil {
+		in, out := &in.Bookmarks, &out.Bookmarks
+		*out = make([]PolicyBookmark, len(*in))
+		copy(*out, *in)
+	}
+	if in.TopicSuffixes != nil {
+		in, out := &in.TopicSuffixes, &out.TopicSuffixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForbiddenMembers != nil {
+		in, out := &in.ForbiddenMembers, &out.ForbiddenMembers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelPolicyStatus.
+func (in *ChannelPolicyStatus) DeepCopy() *ChannelPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelQuota) DeepCopyInto(out *ChannelQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelQuota.
+func (in *ChannelQuota) DeepCopy() *ChannelQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelQuotaList) DeepCopyInto(out *ChannelQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChannelQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelQuotaList.
+func (in *ChannelQuotaList) DeepCopy() *ChannelQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelQuotaSpec) DeepCopyInto(out *ChannelQuotaSpec) {
+	*out = *in
+	if in.MaxChannels != nil {
+		in, out := &in.MaxChannels, &out.MaxChannels
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedPrefixes != nil {
+		in, out := &in.AllowedPrefixes, &out.AllowedPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelQuotaSpec.
+func (in *ChannelQuotaSpec) DeepCopy() *ChannelQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelQuotaStatus) DeepCopyInto(out *ChannelQuotaStatus) {
+	*out = *in
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelQuotaStatus.
+func (in *ChannelQuotaStatus) DeepCopy() *ChannelQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelReference) DeepCopyInto(out *ChannelReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelReference.
+func (in *ChannelReference) DeepCopy() *ChannelReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelRenameWave) DeepCopyInto(out *ChannelRenameWave) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelRenameWave.
+func (in *ChannelRenameWave) DeepCopy() *ChannelRenameWave {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelRenameWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelRenameWave) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelRenameWaveList) DeepCopyInto(out *ChannelRenameWaveList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChannelRenameWave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelRenameWaveList.
+func (in *ChannelRenameWaveList) DeepCopy() *ChannelRenameWaveList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelRenameWaveList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelRenameWaveList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelRenameWaveSpec) DeepCopyInto(out *ChannelRenameWaveSpec) {
+	*out = *in
+	in.ChannelSelector.DeepCopyInto(&out.ChannelSelector)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(NameReplacement)
+		**out = **in
+	}
+	if in.Suffix != nil {
+		in, out := &in.Suffix, &out.Suffix
+		*out = new(NameReplacement)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelRenameWaveSpec.
+func (in *ChannelRenameWaveSpec) DeepCopy() *ChannelRenameWaveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelRenameWaveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelRenameWaveStatus) DeepCopyInto(out *ChannelRenameWaveStatus) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]RenameWaveChannel, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastBatchTime != nil {
+		in, out := &in.LastBatchTime, &out.LastBatchTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelRenameWaveStatus.
+func (in *ChannelRenameWaveStatus) DeepCopy() *ChannelRenameWaveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelRenameWaveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelRetention) DeepCopyInto(out *ChannelRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelRetention.
+func (in *ChannelRetention) DeepCopy() *ChannelRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SCIMGroups != nil {
+		in, out := &in.SCIMGroups, &out.SCIMGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmailAliases != nil {
+		in, out := &in.EmailAliases, &out.EmailAliases
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ProtectedUsers != nil {
+		in, out := &in.ProtectedUsers, &out.ProtectedUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TemporaryUsers != nil {
+		in, out := &in.TemporaryUsers, &out.TemporaryUsers
+		*out = make([]TemporaryUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MemberSchedules != nil {
+		in, out := &in.MemberSchedules, &out.MemberSchedules
+		*out = make([]MemberSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Canvas != nil {
+		in, out := &in.Canvas, &out.Canvas
+		*out = new(CanvasSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoArchiveAfterInactivity != nil {
+		in, out := &in.AutoArchiveAfterInactivity, &out.AutoArchiveAfterInactivity
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(ChannelRetention)
+		**out = **in
+	}
+	if in.TemplateValues != nil {
+		in, out := &in.TemplateValues, &out.TemplateValues
+		*out = make([]TemplateValue, len(*in))
+		copy(*out, *in)
+	}
+	if in.OnCall != nil {
+		in, out := &in.OnCall, &out.OnCall
+		*out = new(ChannelOnCall)
+		**out = **in
+	}
+	if in.TopicFrom != nil {
+		in, out := &in.TopicFrom, &out.TopicFrom
+		*out = new(TopicSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloneFrom != nil {
+		in, out := &in.CloneFrom, &out.CloneFrom
+		*out = new(CloneSource)
+		**out = **in
+	}
+	if in.ReconcileTimeoutSeconds != nil {
+		in, out := &in.ReconcileTimeoutSeconds, &out.ReconcileTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSpec.
+func (in *ChannelSpec) DeepCopy() *ChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
+	*out = *in
+	if in.TemporaryUsers != nil {
+		in, out := &in.TemporaryUsers, &out.TemporaryUsers
+		*out = make([]TemporaryUserStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GroupMembers != nil {
+		in, out := &in.GroupMembers, &out.GroupMembers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ActiveSchedules != nil {
+		in, out := &in.ActiveSchedules, &out.ActiveSchedules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScheduledUsers != nil {
+		in, out := &in.ScheduledUsers, &out.ScheduledUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(ChannelPolicyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemberErrors != nil {
+		in, out := &in.MemberErrors, &out.MemberErrors
+		*out = make([]MemberError, len(*in))
+		copy(*out, *in)
+	}
+	if in.MembershipSync != nil {
+		in, out := &in.MembershipSync, &out.MembershipSync
+		*out = new(MembershipSyncStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TruncatedFields != nil {
+		in, out := &in.TruncatedFields, &out.TruncatedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(ChannelDrift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Activity != nil {
+		in, out := &in.Activity, &out.Activity
+		*out = new(ChannelActivity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OnCall != nil {
+		in, out := &in.OnCall, &out.OnCall
+		*out = new(OnCallStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clone != nil {
+		in, out := &in.Clone, &out.Clone
+		*out = new(CloneStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopicSource != nil {
+		in, out := &in.TopicSource, &out.TopicSource
+		*out = new(TopicSourceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoArchive != nil {
+		in, out := &in.AutoArchive, &out.AutoArchive
+		*out = new(AutoArchiveStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeferredActions != nil {
+		in, out := &in.DeferredActions, &out.DeferredActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeadLetter != nil {
+		in, out := &in.DeadLetter, &out.DeadLetter
+		*out = new(DeadLetterStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ChannelAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelStatus.
+func (in *ChannelStatus) DeepCopy() *ChannelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSource) DeepCopyInto(out *CloneSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSource.
+func (in *CloneSource) DeepCopy() *CloneSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneStatus) DeepCopyInto(out *CloneStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneStatus.
+func (in *CloneStatus) DeepCopy() *CloneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterChannelReference) DeepCopyInto(out *ClusterChannelReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterChannelReference.
+func (in *ClusterChannelReference) DeepCopy() *ClusterChannelReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterChannelReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConversationExport) DeepCopyInto(out *ConversationExport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConversationExport.
+func (in *ConversationExport) DeepCopy() *ConversationExport {
+	if in == nil {
+		return nil
+	}
+	out := new(ConversationExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConversationExport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConversationExportList) DeepCopyInto(out *ConversationExportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ConversationExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConversationExportList.
+func (in *ConversationExportList) DeepCopy() *ConversationExportList {
+	if in == nil {
+		return nil
+	}
+	out := new(ConversationExportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConversationExportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConversationExportSpec) DeepCopyInto(out *ConversationExportSpec) {
+	*out = *in
+	out.Channel = in.Channel
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConversationExportSpec.
+func (in *ConversationExportSpec) DeepCopy() *ConversationExportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConversationExportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConversationExportStatus) DeepCopyInto(out *ConversationExportStatus) {
+	*out = *in
+	if in.LastExportTime != nil {
+		in, out := &in.LastExportTime, &out.LastExportTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConversationExportStatus.
+func (in *ConversationExportStatus) DeepCopy() *ConversationExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConversationExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadLetterStatus) DeepCopyInto(out *DeadLetterStatus) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadLetterStatus.
+func (in *DeadLetterStatus) DeepCopy() *DeadLetterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeadLetterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftReport) DeepCopyInto(out *DriftReport) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftReport.
+func (in *DriftReport) DeepCopy() *DriftReport {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DriftReport) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftReportList) DeepCopyInto(out *DriftReportList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DriftReport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftReportList.
+func (in *DriftReportList) DeepCopy() *DriftReportList {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftReportList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DriftReportList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftReportSpec) DeepCopyInto(out *DriftReportSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftReportSpec.
+func (in *DriftReportSpec) DeepCopy() *DriftReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftReportStatus) DeepCopyInto(out *DriftReportStatus) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]DriftedChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftReportStatus.
+func (in *DriftReportStatus) DeepCopy() *DriftReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedChannel) DeepCopyInto(out *DriftedChannel) {
+	*out = *in
+	in.ChannelDrift.DeepCopyInto(&out.ChannelDrift)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedChannel.
+func (in *DriftedChannel) DeepCopy() *DriftedChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Emoji) DeepCopyInto(out *Emoji) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Emoji.
+func (in *Emoji) DeepCopy() *Emoji {
+	if in == nil {
+		return nil
+	}
+	out := new(Emoji)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Emoji) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmojiImage) DeepCopyInto(out *EmojiImage) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(KeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmojiImage.
+func (in *EmojiImage) DeepCopy() *EmojiImage {
+	if in == nil {
+		return nil
+	}
+	out := new(EmojiImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmojiList) DeepCopyInto(out *EmojiList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Emoji, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmojiList.
+func (in *EmojiList) DeepCopy() *EmojiList {
+	if in == nil {
+		return nil
+	}
+	out := new(EmojiList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EmojiList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmojiSpec) DeepCopyInto(out *EmojiSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(KeyReference)
+		**out = **in
+	}
+	if in.Pack != nil {
+		in, out := &in.Pack, &out.Pack
+		*out = make([]EmojiImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmojiSpec.
+func (in *EmojiSpec) DeepCopy() *EmojiSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EmojiSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmojiStatus) DeepCopyInto(out *EmojiStatus) {
+	*out = *in
+	if in.Emojis != nil {
+		in, out := &in.Emojis, &out.Emojis
+		*out = make([]ManagedEmoji, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmojiStatus.
+func (in *EmojiStatus) DeepCopy() *EmojiStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EmojiStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRoute) DeepCopyInto(out *EventRoute) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRoute.
+func (in *EventRoute) DeepCopy() *EventRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventRoute) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRouteList) DeepCopyInto(out *EventRouteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EventRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRouteList.
+func (in *EventRouteList) DeepCopy() *EventRouteList {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRouteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventRouteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRouteSpec) DeepCopyInto(out *EventRouteSpec) {
+	*out = *in
+	out.Channel = in.Channel
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]EventType, len(*in))
+		copy(*out, *in)
+	}
+	if in.InvolvedObjectKinds != nil {
+		in, out := &in.InvolvedObjectKinds, &out.InvolvedObjectKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(NotificationTemplateReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRouteSpec.
+func (in *EventRouteSpec) DeepCopy() *EventRouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventRouteStatus) DeepCopyInto(out *EventRouteStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventRouteStatus.
+func (in *EventRouteStatus) DeepCopy() *EventRouteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EventRouteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileSource) DeepCopyInto(out *FileSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(KeyReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(KeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileSource.
+func (in *FileSource) DeepCopy() *FileSource {
+	if in == nil {
+		return nil
+	}
+	out := new(FileSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileUpload) DeepCopyInto(out *FileUpload) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileUpload.
+func (in *FileUpload) DeepCopy() *FileUpload {
+	if in == nil {
+		return nil
+	}
+	out := new(FileUpload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FileUpload) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileUploadList) DeepCopyInto(out *FileUploadList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FileUpload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileUploadList.
+func (in *FileUploadList) DeepCopy() *FileUploadList {
+	if in == nil {
+		return nil
+	}
+	out := new(FileUploadList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FileUploadList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileUploadSpec) DeepCopyInto(out *FileUploadSpec) {
+	*out = *in
+	out.Channel = in.Channel
+	in.Source.DeepCopyInto(&out.Source)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileUploadSpec.
+func (in *FileUploadSpec) DeepCopy() *FileUploadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FileUploadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileUploadStatus) DeepCopyInto(out *FileUploadStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileUploadStatus.
+func (in *FileUploadStatus) DeepCopy() *FileUploadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FileUploadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestUser) DeepCopyInto(out *GuestUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestUser.
+func (in *GuestUser) DeepCopy() *GuestUser {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GuestUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestUserList) DeepCopyInto(out *GuestUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GuestUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestUserList.
+func (in *GuestUserList) DeepCopy() *GuestUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GuestUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestUserSpec) DeepCopyInto(out *GuestUserSpec) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]ClusterChannelReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestUserSpec.
+func (in *GuestUserSpec) DeepCopy() *GuestUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestUserStatus) DeepCopyInto(out *GuestUserStatus) {
+	*out = *in
+	if in.InvitedAt != nil {
+		in, out := &in.InvitedAt, &out.InvitedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestUserStatus.
+func (in *GuestUserStatus) DeepCopy() *GuestUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyReference) DeepCopyInto(out *KeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyReference.
+func (in *KeyReference) DeepCopy() *KeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedEmoji) DeepCopyInto(out *ManagedEmoji) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedEmoji.
+func (in *ManagedEmoji) DeepCopy() *ManagedEmoji {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedEmoji)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberError) DeepCopyInto(out *MemberError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberError.
+func (in *MemberError) DeepCopy() *MemberError {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberSchedule) DeepCopyInto(out *MemberSchedule) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ScheduleWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberSchedule.
+func (in *MemberSchedule) DeepCopy() *MemberSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MembershipSyncStatus) DeepCopyInto(out *MembershipSyncStatus) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MembershipSyncStatus.
+func (in *MembershipSyncStatus) DeepCopy() *MembershipSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MembershipSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergeChannel) DeepCopyInto(out *MergeChannel) {
+	*out = *in
+	if in.ChannelRef != nil {
+		in, out := &in.ChannelRef, &out.ChannelRef
+		*out = new(ChannelReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergeChannel.
+func (in *MergeChannel) DeepCopy() *MergeChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(MergeChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Message) DeepCopyInto(out *Message) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Message.
+func (in *Message) DeepCopy() *Message {
+	if in == nil {
+		return nil
+	}
+	out := new(Message)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Message) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MessageList) DeepCopyInto(out *MessageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Message, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MessageList.
+func (in *MessageList) DeepCopy() *MessageList {
+	if in == nil {
+		return nil
+	}
+	out := new(MessageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MessageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MessageSpec) DeepCopyInto(out *MessageSpec) {
+	*out = *in
+	out.Channel = in.Channel
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(NotificationTemplateReference)
+		**out = **in
+	}
+	if in.ThreadOf != nil {
+		in, out := &in.ThreadOf, &out.ThreadOf
+		*out = new(ThreadReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MessageSpec.
+func (in *MessageSpec) DeepCopy() *MessageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MessageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MessageStatus) DeepCopyInto(out *MessageStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MessageStatus.
+func (in *MessageStatus) DeepCopy() *MessageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MessageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NameReplacement) DeepCopyInto(out *NameReplacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NameReplacement.
+func (in *NameReplacement) DeepCopy() *NameReplacement {
+	if in == nil {
+		return nil
+	}
+	out := new(NameReplacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamingPrefix) DeepCopyInto(out *NamingPrefix) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamingPrefix.
+func (in *NamingPrefix) DeepCopy() *NamingPrefix {
+	if in == nil {
+		return nil
+	}
+	out := new(NamingPrefix)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTemplate) DeepCopyInto(out *NotificationTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTemplate.
+func (in *NotificationTemplate) DeepCopy() *NotificationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTemplateList) DeepCopyInto(out *NotificationTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NotificationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTemplateList.
+func (in *NotificationTemplateList) DeepCopy() *NotificationTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTemplateReference) DeepCopyInto(out *NotificationTemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTemplateReference.
+func (in *NotificationTemplateReference) DeepCopy() *NotificationTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTemplateSpec) DeepCopyInto(out *NotificationTemplateSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTemplateSpec.
+func (in *NotificationTemplateSpec) DeepCopy() *NotificationTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTemplateStatus) DeepCopyInto(out *NotificationTemplateStatus) {
+	*out = *in
+	if in.TestRender != nil {
+		in, out := &in.TestRender, &out.TestRender
+		*out = new(TestRenderStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTemplateStatus.
+func (in *NotificationTemplateStatus) DeepCopy() *NotificationTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectReference.
+func (in *ObjectReference) DeepCopy() *ObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OffboardingReport) DeepCopyInto(out *OffboardingReport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OffboardingReport.
+func (in *OffboardingReport) DeepCopy() *OffboardingReport {
+	if in == nil {
+		return nil
+	}
+	out := new(OffboardingReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallScheduleReference) DeepCopyInto(out *OnCallScheduleReference) {
+	*out = *in
+	out.TokenSecretRef = in.TokenSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallScheduleReference.
+func (in *OnCallScheduleReference) DeepCopy() *OnCallScheduleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallScheduleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallStatus) DeepCopyInto(out *OnCallStatus) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnknownEmails != nil {
+		in, out := &in.UnknownEmails, &out.UnknownEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallStatus.
+func (in *OnCallStatus) DeepCopy() *OnCallStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyBookmark) DeepCopyInto(out *PolicyBookmark) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyBookmark.
+func (in *PolicyBookmark) DeepCopy() *PolicyBookmark {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyBookmark)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Reminder) DeepCopyInto(out *Reminder) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Reminder.
+func (in *Reminder) DeepCopy() *Reminder {
+	if in == nil {
+		return nil
+	}
+	out := new(Reminder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Reminder) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReminderList) DeepCopyInto(out *ReminderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Reminder, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReminderList.
+func (in *ReminderList) DeepCopy() *ReminderList {
+	if in == nil {
+		return nil
+	}
+	out := new(ReminderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReminderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReminderSpec) DeepCopyInto(out *ReminderSpec) {
+	*out = *in
+	if in.Channel != nil {
+		in, out := &in.Channel, &out.Channel
+		*out = new(ChannelReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReminderSpec.
+func (in *ReminderSpec) DeepCopy() *ReminderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReminderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReminderStatus) DeepCopyInto(out *ReminderStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReminderStatus.
+func (in *ReminderStatus) DeepCopy() *ReminderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReminderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenameWaveChannel) DeepCopyInto(out *RenameWaveChannel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenameWaveChannel.
+func (in *RenameWaveChannel) DeepCopy() *RenameWaveChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(RenameWaveChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCIMGroup) DeepCopyInto(out *SCIMGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCIMGroup.
+func (in *SCIMGroup) DeepCopy() *SCIMGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(SCIMGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SCIMGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCIMGroupList) DeepCopyInto(out *SCIMGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SCIMGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCIMGroupList.
+func (in *SCIMGroupList) DeepCopy() *SCIMGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(SCIMGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SCIMGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCIMGroupSpec) DeepCopyInto(out *SCIMGroupSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCIMGroupSpec.
+func (in *SCIMGroupSpec) DeepCopy() *SCIMGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SCIMGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCIMGroupStatus) DeepCopyInto(out *SCIMGroupStatus) {
+	*out = *in
+	if in.MemberErrors != nil {
+		in, out := &in.MemberErrors, &out.MemberErrors
+		*out = make([]MemberError, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCIMGroupStatus.
+func (in *SCIMGroupStatus) DeepCopy() *SCIMGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SCIMGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackApp) DeepCopyInto(out *SlackApp) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackApp.
+func (in *SlackApp) DeepCopy() *SlackApp {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackApp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlackApp) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackAppList) DeepCopyInto(out *SlackAppList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SlackApp, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackAppList.
+func (in *SlackAppList) DeepCopy() *SlackAppList {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackAppList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlackAppList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackAppSpec) DeepCopyInto(out *SlackAppSpec) {
+	*out = *in
+	in.Manifest.DeepCopyInto(&out.Manifest)
+	out.ConfigTokenSecretRef = in.ConfigTokenSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackAppSpec.
+func (in *SlackAppSpec) DeepCopy() *SlackAppSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackAppSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackAppStatus) DeepCopyInto(out *SlackAppStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackAppStatus.
+func (in *SlackAppStatus) DeepCopy() *SlackAppStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackAppStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateValue) DeepCopyInto(out *TemplateValue) {
+	*out = *in
+	out.ConfigMapKeyRef = in.ConfigMapKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateValue.
+func (in *TemplateValue) DeepCopy() *TemplateValue {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemporaryUser) DeepCopyInto(out *TemporaryUser) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemporaryUser.
+func (in *TemporaryUser) DeepCopy() *TemporaryUser {
+	if in == nil {
+		return nil
+	}
+	out := new(TemporaryUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemporaryUserStatus) DeepCopyInto(out *TemporaryUserStatus) {
+	*out = *in
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemporaryUserStatus.
+func (in *TemporaryUserStatus) DeepCopy() *TemporaryUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TemporaryUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestRenderStatus) DeepCopyInto(out *TestRenderStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestRenderStatus.
+func (in *TestRenderStatus) DeepCopy() *TestRenderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TestRenderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreadReference) DeepCopyInto(out *ThreadReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreadReference.
+func (in *ThreadReference) DeepCopy() *ThreadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreadReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicSource) DeepCopyInto(out *TopicSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(KeyReference)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicSource.
+func (in *TopicSource) DeepCopy() *TopicSource {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicSourceStatus) DeepCopyInto(out *TopicSourceStatus) {
+	*out = *in
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicSourceStatus.
+func (in *TopicSourceStatus) DeepCopy() *TopicSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserGroup) DeepCopyInto(out *UserGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserGroup.
+func (in *UserGroup) DeepCopy() *UserGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(Channel)
+	out := new(UserGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Channel) DeepCopyObject() runtime.Object {
+func (in *UserGroup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -53,31 +3254,31 @@ func (in *Channel) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChannelList) DeepCopyInto(out *ChannelList) {
+func (in *UserGroupList) DeepCopyInto(out *UserGroupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Channel, len(*in))
+		*out = make([]UserGroup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelList.
-func (in *ChannelList) DeepCopy() *ChannelList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserGroupList.
+func (in *UserGroupList) DeepCopy() *UserGroupList {
 	if in == nil {
 		return nil
 	}
-	out := new(ChannelList)
+	out := new(UserGroupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ChannelList) DeepCopyObject() runtime.Object {
+func (in *UserGroupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -85,28 +3286,48 @@ func (in *ChannelList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
+func (in *UserGroupSpec) DeepCopyInto(out *UserGroupSpec) {
 	*out = *in
-	if in.Users != nil {
-		in, out := &in.Users, &out.Users
+	if in.DefaultChannels != nil {
+		in, out := &in.DefaultChannels, &out.DefaultChannels
+		*out = make([]ClusterChannelReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultChannelIDs != nil {
+		in, out := &in.DefaultChannelIDs, &out.DefaultChannelIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSpec.
-func (in *ChannelSpec) DeepCopy() *ChannelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserGroupSpec.
+func (in *UserGroupSpec) DeepCopy() *UserGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ChannelSpec)
+	out := new(UserGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
+func (in *UserGroupStatus) DeepCopyInto(out *UserGroupStatus) {
 	*out = *in
+	if in.DefaultChannelIDs != nil {
+		in, out := &in.DefaultChannelIDs, &out.DefaultChannelIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -116,12 +3337,227 @@ func (in *ChannelStatus) DeepCopyInto(out *ChannelStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelStatus.
-func (in *ChannelStatus) DeepCopy() *ChannelStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserGroupStatus.
+func (in *UserGroupStatus) DeepCopy() *UserGroupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ChannelStatus)
+	out := new(UserGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserInvite) DeepCopyInto(out *UserInvite) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserInvite.
+func (in *UserInvite) DeepCopy() *UserInvite {
+	if in == nil {
+		return nil
+	}
+	out := new(UserInvite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UserInvite) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserInviteList) DeepCopyInto(out *UserInviteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]UserInvite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserInviteList.
+func (in *UserInviteList) DeepCopy() *UserInviteList {
+	if in == nil {
+		return nil
+	}
+	out := new(UserInviteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UserInviteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserInviteSpec) DeepCopyInto(out *UserInviteSpec) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]ClusterChannelReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.GuestExpiration != nil {
+		in, out := &in.GuestExpiration, &out.GuestExpiration
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserInviteSpec.
+func (in *UserInviteSpec) DeepCopy() *UserInviteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserInviteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserInviteStatus) DeepCopyInto(out *UserInviteStatus) {
+	*out = *in
+	if in.InvitedAt != nil {
+		in, out := &in.InvitedAt, &out.InvitedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserInviteStatus.
+func (in *UserInviteStatus) DeepCopy() *UserInviteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UserInviteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserOffboarding) DeepCopyInto(out *UserOffboarding) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserOffboarding.
+func (in *UserOffboarding) DeepCopy() *UserOffboarding {
+	if in == nil {
+		return nil
+	}
+	out := new(UserOffboarding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UserOffboarding) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserOffboardingList) DeepCopyInto(out *UserOffboardingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]UserOffboarding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserOffboardingList.
+func (in *UserOffboardingList) DeepCopy() *UserOffboardingList {
+	if in == nil {
+		return nil
+	}
+	out := new(UserOffboardingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UserOffboardingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserOffboardingSpec) DeepCopyInto(out *UserOffboardingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserOffboardingSpec.
+func (in *UserOffboardingSpec) DeepCopy() *UserOffboardingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UserOffboardingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserOffboardingStatus) DeepCopyInto(out *UserOffboardingStatus) {
+	*out = *in
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]OffboardingReport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]OffboardingReport, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserOffboardingStatus.
+func (in *UserOffboardingStatus) DeepCopy() *UserOffboardingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UserOffboardingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,190 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+// WorkspaceRefAnnotation keeps the workspaceRef of v1beta1 Channels, which v1alpha1 lacks
+const WorkspaceRefAnnotation = "slack.stakater.com/workspace-ref"
+
+// userIDPrefix marks slack user IDs in the users of v1alpha1 Channels
+const userIDPrefix = "id:"
+
+// ConvertTo converts the Channel to the v1alpha1 hub. Members with a ttl or expiresAt become temporary users,
+// protected members are also protected users.
+func (src *Channel) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Channel)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = v1alpha1.ChannelSpec{
+		Name:               spec.Name,
+		Private:            spec.Private,
+		SCIMGroups:         spec.SCIMGroups,
+		EmailAliases:       spec.EmailAliases,
+		ProtectedUsers:     spec.ProtectedUsers,
+		Description:        spec.Description,
+		Topic:              spec.Topic,
+		TopicMarker:        spec.TopicMarker,
+		Canvas:             spec.Canvas,
+		TruncateLongFields: spec.TruncateLongFields,
+		OnExternalArchive:  spec.OnExternalArchive,
+		OnNameConflict:     spec.OnNameConflict,
+		OnExternalDeletion: spec.OnExternalDeletion,
+		DeletionPolicy:     spec.DeletionPolicy,
+
+		AutoArchiveAfterInactivity: spec.AutoArchiveAfterInactivity,
+		Retention:                  spec.Retention,
+		DefaultForNewMembers:       spec.DefaultForNewMembers,
+		Section:                    spec.Section,
+		TemplateValues:             spec.TemplateValues,
+		OnCall:                     spec.OnCall,
+		TopicFrom:                  spec.TopicFrom,
+		MemberSchedules:            spec.MemberSchedules,
+		CloneFrom:                  spec.CloneFrom,
+		ReconcileTimeoutSeconds:    spec.ReconcileTimeoutSeconds,
+	}
+
+	for _, member := range spec.Members {
+		user := member.Email
+		if user == "" {
+			user = userIDPrefix + member.ID
+		}
+
+		if member.Role == RoleProtected {
+			dst.Spec.ProtectedUsers = append(dst.Spec.ProtectedUsers, user)
+		}
+		if member.TTL != nil || member.ExpiresAt != nil {
+			dst.Spec.TemporaryUsers = append(dst.Spec.TemporaryUsers, v1alpha1.TemporaryUser{
+				Email:     user,
+				TTL:       member.TTL,
+				ExpiresAt: member.ExpiresAt,
+			})
+		} else {
+			dst.Spec.Users = append(dst.Spec.Users, user)
+		}
+	}
+
+	if spec.WorkspaceRef != nil {
+		workspaceRef, err := json.Marshal(spec.WorkspaceRef)
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[WorkspaceRefAnnotation] = string(workspaceRef)
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v1alpha1 hub to the Channel. Users who are also protected users become protected
+// members, protected users who aren't listed stay protected users.
+func (dst *Channel) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.Channel)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = ChannelSpec{
+		Name:               spec.Name,
+		Private:            spec.Private,
+		SCIMGroups:         spec.SCIMGroups,
+		EmailAliases:       spec.EmailAliases,
+		Description:        spec.Description,
+		Topic:              spec.Topic,
+		TopicMarker:        spec.TopicMarker,
+		Canvas:             spec.Canvas,
+		TruncateLongFields: spec.TruncateLongFields,
+		OnExternalArchive:  spec.OnExternalArchive,
+		OnNameConflict:     spec.OnNameConflict,
+		OnExternalDeletion: spec.OnExternalDeletion,
+		DeletionPolicy:     spec.DeletionPolicy,
+
+		AutoArchiveAfterInactivity: spec.AutoArchiveAfterInactivity,
+		Retention:                  spec.Retention,
+		DefaultForNewMembers:       spec.DefaultForNewMembers,
+		Section:                    spec.Section,
+		TemplateValues:             spec.TemplateValues,
+		OnCall:                     spec.OnCall,
+		TopicFrom:                  spec.TopicFrom,
+		MemberSchedules:            spec.MemberSchedules,
+		CloneFrom:                  spec.CloneFrom,
+		ReconcileTimeoutSeconds:    spec.ReconcileTimeoutSeconds,
+	}
+
+	listed := map[string]bool{}
+	for _, user := range spec.Users {
+		listed[strings.ToLower(user)] = true
+	}
+	for _, user := range spec.TemporaryUsers {
+		listed[strings.ToLower(user.Email)] = true
+	}
+	protected := map[string]bool{}
+	for _, user := range spec.ProtectedUsers {
+		protected[strings.ToLower(user)] = true
+		if !listed[strings.ToLower(user)] {
+			dst.Spec.ProtectedUsers = append(dst.Spec.ProtectedUsers, user)
+		}
+	}
+
+	for _, user := range spec.Users {
+		member := newMember(user)
+		if protected[strings.ToLower(user)] {
+			member.Role = RoleProtected
+		}
+		dst.Spec.Members = append(dst.Spec.Members, member)
+	}
+	for _, user := range spec.TemporaryUsers {
+		member := newMember(user.Email)
+		if protected[strings.ToLower(user.Email)] {
+			member.Role = RoleProtected
+		}
+		member.TTL = user.TTL
+		member.ExpiresAt = user.ExpiresAt
+		dst.Spec.Members = append(dst.Spec.Members, member)
+	}
+
+	if workspaceRef, found := dst.Annotations[WorkspaceRefAnnotation]; found {
+		dst.Spec.WorkspaceRef = &WorkspaceReference{}
+		if err := json.Unmarshal([]byte(workspaceRef), dst.Spec.WorkspaceRef); err != nil {
+			return err
+		}
+		delete(dst.Annotations, WorkspaceRefAnnotation)
+	}
+
+	return nil
+}
+
+// newMember creates a member from an email or a user ID prefixed with id:
+func newMember(user string) Member {
+	if strings.HasPrefix(user, userIDPrefix) {
+		return Member{ID: strings.TrimPrefix(user, userIDPrefix), Role: RoleMember}
+	}
+	return Member{Email: user, Role: RoleMember}
+}
@@ -0,0 +1,209 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+// MemberRole decides how the operator manages a member of the channel
+// +kubebuilder:validation:Enum=Member;Protected
+type MemberRole string
+
+const (
+	// RoleMember is invited to the channel and removed once it's no longer listed
+	RoleMember MemberRole = "Member"
+	// RoleProtected is invited to the channel and never removed from it
+	RoleProtected MemberRole = "Protected"
+)
+
+// Member is a member of the channel, exactly one of email and id must be set
+type Member struct {
+	// Email of the user
+	// +optional
+	Email string `json:"email,omitempty"`
+
+	// Slack user ID of the user, which skips the email lookup
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Role of the member
+	// +kubebuilder:default=Member
+	// +optional
+	Role MemberRole `json:"role,omitempty"`
+
+	// Duration of the membership, counted from the first reconcile that saw the member
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Time the member is removed from the channel at
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// WorkspaceReference names the Slack workspace of a channel
+type WorkspaceReference struct {
+	// Name of the workspace
+	Name string `json:"name"`
+}
+
+// ChannelSpec defines the desired state of Channel
+type ChannelSpec struct {
+	// Name of the slack channel
+	// +required
+	Name string `json:"name"`
+
+	// Make the channel private or public
+	// +optional
+	Private bool `json:"private,omitempty"`
+
+	// Workspace of the channel. The operator manages the workspace of its token, the reference is kept for
+	// operators managing several workspaces.
+	// +optional
+	WorkspaceRef *WorkspaceReference `json:"workspaceRef,omitempty"`
+
+	// Members of the channel, members who aren't listed are removed from the channel. Membership isn't enforced
+	// when no members are set.
+	// +optional
+	Members []Member `json:"members,omitempty"`
+
+	// Emails or user IDs who are never removed from the channel without being invited to it
+	// +optional
+	ProtectedUsers []string `json:"protectedUsers,omitempty"`
+
+	// Display names of SCIM groups whose active members are also members of the channel, requires SCIM to be
+	// configured for the operator
+	// +optional
+	SCIMGroups []string `json:"scimGroups,omitempty"`
+
+	// Slack emails to try, in order, for member emails without a slack account, e.g. when corporate aliases differ
+	// from slack profiles
+	// +optional
+	EmailAliases map[string][]string `json:"emailAliases,omitempty"`
+
+	// Description of the channel, a Go template when it contains {{
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Topic of the channel, a Go template when it contains {{
+	// +optional
+	Topic string `json:"topic,omitempty"`
+
+	// Text marking the topic as managed, the topic is only enforced while it starts with the marker
+	// +optional
+	TopicMarker string `json:"topicMarker,omitempty"`
+
+	// Canvas of the channel
+	// +optional
+	Canvas *v1alpha1.CanvasSource `json:"canvas,omitempty"`
+
+	// Truncate topic and description which exceed the limits of Slack instead of rejecting the Channel
+	// +optional
+	TruncateLongFields bool `json:"truncateLongFields,omitempty"`
+
+	// What to do when the channel was archived in Slack
+	// +kubebuilder:default=Unarchive
+	// +optional
+	OnExternalArchive v1alpha1.ExternalArchivePolicy `json:"onExternalArchive,omitempty"`
+
+	// What to do when a new channel's name is taken by an existing slack channel
+	// +kubebuilder:default=Unarchive
+	// +optional
+	OnNameConflict v1alpha1.NameConflictPolicy `json:"onNameConflict,omitempty"`
+
+	// What to do when the channel was deleted in Slack
+	// +kubebuilder:default=Recreate
+	// +optional
+	OnExternalDeletion v1alpha1.ExternalDeletionPolicy `json:"onExternalDeletion,omitempty"`
+
+	// What happens to the slack channel when the Channel is deleted
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy v1alpha1.DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Archive the slack channel once no message was posted to it for this long, 0s disables auto-archiving
+	// +optional
+	AutoArchiveAfterInactivity *metav1.Duration `json:"autoArchiveAfterInactivity,omitempty"`
+
+	// Retention of the messages of the channel, set with the admin retention APIs of Enterprise Grid
+	// +optional
+	Retention *v1alpha1.ChannelRetention `json:"retention,omitempty"`
+
+	// New members of the workspace join the channel automatically
+	// +optional
+	DefaultForNewMembers bool `json:"defaultForNewMembers,omitempty"`
+
+	// Workspace section the channel belongs to, recorded in the managed-by bookmark of the channel
+	// +optional
+	Section string `json:"section,omitempty"`
+
+	// Values of ConfigMaps in the namespace, available to a templated topic and description as .Values.<name>
+	// +optional
+	TemplateValues []v1alpha1.TemplateValue `json:"templateValues,omitempty"`
+
+	// Keep the topic updated with @mentions of who is on call for a PagerDuty or Opsgenie schedule
+	// +optional
+	OnCall *v1alpha1.ChannelOnCall `json:"onCall,omitempty"`
+
+	// Source of the topic text, e.g. a status page or a deployment freeze flag
+	// +optional
+	TopicFrom *v1alpha1.TopicSource `json:"topicFrom,omitempty"`
+
+	// Groups of users who are only members of the channel during weekly time windows
+	// +optional
+	MemberSchedules []v1alpha1.MemberSchedule `json:"memberSchedules,omitempty"`
+
+	// Prototype slack channel whose bookmarks, pinned messages and members are copied when the channel is created
+	// +optional
+	CloneFrom *v1alpha1.CloneSource `json:"cloneFrom,omitempty"`
+
+	// Seconds a reconcile may spend calling Slack before it continues on the next reconcile
+	// +kubebuilder:validation:Minimum=10
+	// +optional
+	ReconcileTimeoutSeconds *int32 `json:"reconcileTimeoutSeconds,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Slack Name",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Channel is the Schema for the channels API
+type Channel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChannelSpec            `json:"spec,omitempty"`
+	Status v1alpha1.ChannelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChannelList contains a list of Channel
+type ChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Channel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Channel{}, &ChannelList{})
+}
@@ -0,0 +1,225 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"github.com/stakater/slack-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Channel) DeepCopyInto(out *Channel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Channel.
+func (in *Channel) DeepCopy() *Channel {
+	if in == nil {
+		return nil
+	}
+	out := new(Channel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Channel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelList) DeepCopyInto(out *ChannelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Channel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelList.
+func (in *ChannelList) DeepCopy() *ChannelList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChannelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChannelSpec) DeepCopyInto(out *ChannelSpec) {
+	*out = *in
+	if in.WorkspaceRef != nil {
+		in, out := &in.WorkspaceRef, &out.WorkspaceRef
+		*out = new(WorkspaceReference)
+		**out = **in
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]Member, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProtectedUsers != nil {
+		in, out := &in.ProtectedUsers, &out.ProtectedUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SCIMGroups != nil {
+		in, out := &in.SCIMGroups, &out.SCIMGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EmailAliases != nil {
+		in, out := &in.EmailAliases, &out.EmailAliases
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Canvas != nil {
+		in, out := &in.Canvas, &out.Canvas
+		*out = new(v1alpha1.CanvasSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoArchiveAfterInactivity != nil {
+		in, out := &in.AutoArchiveAfterInactivity, &out.AutoArchiveAfterInactivity
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(v1alpha1.ChannelRetention)
+		**out = **in
+	}
+	if in.TemplateValues != nil {
+		in, out := &in.TemplateValues, &out.TemplateValues
+		*out = make([]v1alpha1.TemplateValue, len(*in))
+		copy(*out, *in)
+	}
+	if in.OnCall != nil {
+		in, out := &in.OnCall, &out.OnCall
+		*out = new(v1alpha1.ChannelOnCall)
+		**out = **in
+	}
+	if in.TopicFrom != nil {
+		in, out := &in.TopicFrom, &out.TopicFrom
+		*out = new(v1alpha1.TopicSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemberSchedules != nil {
+		in, out := &in.MemberSchedules, &out.MemberSchedules
+		*out = make([]v1alpha1.MemberSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CloneFrom != nil {
+		in, out := &in.CloneFrom, &out.CloneFrom
+		*out = new(v1alpha1.CloneSource)
+		**out = **in
+	}
+	if in.ReconcileTimeoutSeconds != nil {
+		in, out := &in.ReconcileTimeoutSeconds, &out.ReconcileTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelSpec.
+func (in *ChannelSpec) DeepCopy() *ChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Member) DeepCopyInto(out *Member) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Member.
+func (in *Member) DeepCopy() *Member {
+	if in == nil {
+		return nil
+	}
+	out := new(Member)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceReference) DeepCopyInto(out *WorkspaceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceReference.
+func (in *WorkspaceReference) DeepCopy() *WorkspaceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,235 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/cli"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// listChannels prints a table of the Channels with their slack channel, status and drift
+func listChannels(ctx context.Context, o *options) error {
+	c, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	var listOptions []client.ListOption
+	if !o.allNamespaces {
+		listOptions = append(listOptions, client.InNamespace(o.Namespace))
+	}
+	channels := &slackv1alpha1.ChannelList{}
+	if err := c.List(ctx, channels, listOptions...); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(o.out, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	if o.allNamespaces {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tSLACK NAME\tID\tPRIVATE\tSTATUS\tDRIFT")
+	for _, channel := range channels.Items {
+		if o.allNamespaces {
+			fmt.Fprintf(w, "%s\t", channel.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\n", channel.Name, channel.Spec.Name, channel.Status.ID, channel.Spec.Private,
+			status(&channel), drift(&channel))
+	}
+	return nil
+}
+
+// status returns the reason of the latest condition of the Channel
+func status(channel *slackv1alpha1.Channel) string {
+	conditions := channel.Status.Conditions
+	if len(conditions) == 0 {
+		return "Pending"
+	}
+	return conditions[len(conditions)-1].Reason
+}
+
+// drift returns the drifted fields of the Channel and how long it is out of sync
+func drift(channel *slackv1alpha1.Channel) string {
+	if channel.Status.Drift == nil {
+		return "-"
+	}
+	since := time.Since(channel.Status.Drift.Since.Time).Round(time.Second)
+	return fmt.Sprintf("%s (%s)", strings.Join(channel.Status.Drift.Fields, ","), since)
+}
+
+// diffChannel prints the differences between the spec of the Channel and its slack channel
+func diffChannel(ctx context.Context, o *options, name string) error {
+	c, err := o.Client()
+	if err != nil {
+		return err
+	}
+	channel := &slackv1alpha1.Channel{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: name}, channel); err != nil {
+		return err
+	}
+	if channel.Status.ID == "" {
+		return fmt.Errorf("channel %s/%s wasn't created in Slack yet", o.Namespace, name)
+	}
+
+	service, err := o.slackService()
+	if err != nil {
+		return err
+	}
+	existing, err := service.GetChannel(channel.Status.ID)
+	if err != nil {
+		return err
+	}
+
+	differences := 0
+	desired := channel.DeepCopy()
+	desired.Spec.Name = channel.SlackName()
+	for _, change := range slack.MetadataDrift(existing, desired) {
+		fmt.Fprintf(o.out, "%s:\n- %s\n+ %s\n", change.Field, change.Old, change.New)
+		differences++
+	}
+	if existing.IsArchived {
+		fmt.Fprintln(o.out, "archived:\n- true\n+ false")
+		differences++
+	}
+
+	if channel.ManagesMembers() {
+		// Protected users and offboarded users of the operator's configuration aren't known to the plugin
+		plan, err := service.PlanMembership(ctx, channel.Status.ID, channel.Members(), channel.Spec.EmailAliases, channel.Spec.ProtectedUsers)
+		if err != nil {
+			return err
+		}
+		if plan.Changed() {
+			fmt.Fprintln(o.out, "members:")
+			for _, userID := range plan.Remove {
+				fmt.Fprintf(o.out, "- %s\n", userID)
+			}
+			for _, userID := range plan.Invite {
+				fmt.Fprintf(o.out, "+ %s\n", userID)
+			}
+			differences++
+		}
+		for _, memberError := range plan.MemberErrors {
+			fmt.Fprintf(o.out, "skipped %s: %s\n", memberError.User, memberError.Reason)
+		}
+	}
+
+	if differences == 0 {
+		fmt.Fprintf(o.out, "Channel %s/%s is in sync with Slack\n", o.Namespace, name)
+	}
+	return nil
+}
+
+// syncChannel annotates the Channel with the current time, so the operator reconciles it right away
+func syncChannel(ctx context.Context, o *options, name string) error {
+	c, err := o.Client()
+	if err != nil {
+		return err
+	}
+	if err := cli.RequestReconcile(ctx, c, types.NamespacedName{Namespace: o.Namespace, Name: name}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.out, "Channel %s/%s will be reconciled\n", o.Namespace, name)
+	return nil
+}
+
+// importChannel prints or creates a Channel managing an existing slack channel, its members are listed by user ID
+func importChannel(ctx context.Context, o *options, nameOrID string) error {
+	service, err := o.slackService()
+	if err != nil {
+		return err
+	}
+	existing, err := service.GetChannel(nameOrID)
+	if err != nil {
+		existing, err = service.GetChannelByName(strings.TrimPrefix(nameOrID, "#"))
+	}
+	if err != nil {
+		return fmt.Errorf("slack channel %s not found: %v", nameOrID, err)
+	}
+
+	members, err := service.GetUsersInChannel(ctx, existing.ID)
+	if err != nil {
+		return err
+	}
+
+	c, err := o.Client()
+	if err != nil {
+		return err
+	}
+
+	name := o.name
+	if name == "" {
+		name = existing.Name
+	}
+	channel := &slackv1alpha1.Channel{
+		TypeMeta:   metav1.TypeMeta{APIVersion: slackv1alpha1.GroupVersion.String(), Kind: "Channel"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: o.Namespace},
+		Spec:       service.GetChannelCRFromChannel(existing).Spec,
+	}
+	channel.Spec.Users = nil
+	for _, member := range members {
+		channel.Spec.Users = append(channel.Spec.Users, "id:"+member)
+	}
+
+	if !o.apply {
+		data, err := yaml.Marshal(channel)
+		if err != nil {
+			return err
+		}
+		_, err = o.out.Write(data)
+		return err
+	}
+
+	if err := c.Create(ctx, channel); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "Channel %s/%s created for slack channel %s\n", o.Namespace, name, existing.ID)
+	return nil
+}
+
+// whoami prints the workspace and user of the Slack token and whether it has the scopes the operator needs
+func whoami(o *options) error {
+	service, err := o.slackService()
+	if err != nil {
+		return err
+	}
+	teamID, err := service.GetTeamID()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "Workspace: %s\n", teamID)
+
+	if err := service.CheckAuth(); err != nil {
+		fmt.Fprintf(o.out, "Token: %v\n", err)
+		return nil
+	}
+	fmt.Fprintln(o.out, "Token: valid, all required scopes are granted")
+	return nil
+}
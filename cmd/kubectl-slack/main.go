@@ -0,0 +1,118 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-slack is a kubectl plugin to inspect and manage the Channels of the slack-operator
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/stakater/slack-operator/pkg/cli"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+const usage = `kubectl slack manages the Channels of the slack-operator.
+
+Usage:
+  kubectl slack channels list [-n namespace | -A]
+  kubectl slack channels diff <channel> [-n namespace]
+  kubectl slack channels sync <channel> [-n namespace]
+  kubectl slack import <slack channel name or ID> [-n namespace] [--name name] [--apply]
+  kubectl slack whoami
+
+Commands which call Slack read the bot token from --token or the SLACK_API_TOKEN environment variable.
+
+Flags:
+`
+
+// options are the flags shared by all commands
+type options struct {
+	cli.KubeOptions
+	allNamespaces bool
+	token         string
+	name          string
+	apply         bool
+
+	out io.Writer
+}
+
+// slackService creates a SlackService with the token of the flags or the environment
+func (o *options) slackService() (*slack.SlackService, error) {
+	token := cli.SlackToken(o.token)
+	if token == "" {
+		return nil, fmt.Errorf("a Slack token is required, set --token or %s", cli.TokenEnv)
+	}
+	return cli.NewSlackService(token), nil
+}
+
+func main() {
+	o := &options{out: os.Stdout}
+
+	flags := flag.NewFlagSet("kubectl-slack", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file.")
+	flags.StringVar(&o.Context, "context", "", "The kubeconfig context to use.")
+	flags.StringVar(&o.Namespace, "n", "", "The namespace of the Channels, the namespace of the context by default.")
+	flags.BoolVar(&o.allNamespaces, "A", false, "List the Channels of all namespaces.")
+	flags.StringVar(&o.token, "token", "", "The Slack bot token, SLACK_API_TOKEN by default.")
+	flags.StringVar(&o.name, "name", "", "The name of the imported Channel, the name of the slack channel by default.")
+	flags.BoolVar(&o.apply, "apply", false, "Create the imported Channel instead of printing it.")
+
+	args, err := cli.ParseInterspersed(flags, os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err == nil {
+		err = run(context.Background(), o, args)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command of the positional arguments
+func run(ctx context.Context, o *options, args []string) error {
+	command := strings.Join(args[:min(len(args), 2)], " ")
+	switch {
+	case command == "channels list":
+		return listChannels(ctx, o)
+	case command == "channels diff" && len(args) == 3:
+		return diffChannel(ctx, o, args[2])
+	case command == "channels sync" && len(args) == 3:
+		return syncChannel(ctx, o, args[2])
+	case len(args) == 2 && args[0] == "import":
+		return importChannel(ctx, o, args[1])
+	case len(args) == 1 && args[0] == "whoami":
+		return whoami(o)
+	}
+	return fmt.Errorf("unknown command %q, see kubectl slack -h", strings.Join(args, " "))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/stakater/slack-operator/pkg/backup"
+)
+
+// backupChannels prints a backup of the Channels of the namespace, or of all namespaces
+func backupChannels(ctx context.Context, o *options) error {
+	c, err := o.Client()
+	if err != nil {
+		return err
+	}
+	service, err := o.slackService(ctx)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := backup.Export(ctx, c, service, func(namespace string) bool {
+		return o.allNamespaces || namespace == o.Namespace
+	})
+	if err != nil {
+		return err
+	}
+	data, err := snapshot.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = o.out.Write(data)
+	return err
+}
+
+// restoreChannels recreates the Channels of the backup file which don't exist
+func restoreChannels(ctx context.Context, o *options, file string) error {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+	snapshot, err := backup.Parse(data)
+	if err != nil {
+		return fmt.Errorf("Error parsing backup %s: %v", file, err)
+	}
+
+	c, err := o.Client()
+	if err != nil {
+		return err
+	}
+	results, err := backup.Restore(ctx, c, snapshot, o.dryRun)
+	for _, result := range results {
+		fmt.Fprintf(o.out, "%s/%s: %s\n", result.Namespace, result.Name, result.Action)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "%d Channels restored from the backup of %s%s\n", count(results, backup.Created),
+		snapshot.Time.UTC().Format("2006-01-02 15:04:05"), dryRunSuffix(o))
+	return nil
+}
+
+func count(results []backup.Result, action string) int {
+	n := 0
+	for _, result := range results {
+		if result.Action == action {
+			n++
+		}
+	}
+	return n
+}
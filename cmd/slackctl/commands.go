@@ -0,0 +1,188 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	goslack "github.com/slack-go/slack"
+	"k8s.io/apimachinery/pkg/types"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/cli"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// channelsWithPrefix lists the channels of the prefix, an empty prefix is rejected so a typo doesn't match all
+// channels of the workspace
+func channelsWithPrefix(service *slack.SlackService, prefix string) ([]goslack.Channel, error) {
+	if slackv1alpha1.NormalizeChannelName(prefix) == "" {
+		return nil, fmt.Errorf("a channel name prefix is required")
+	}
+	return service.ListChannels(prefix)
+}
+
+// managed checks whether the channel is managed by a Channel and reports it as skipped unless --force is set
+func managed(o *options, service *slack.SlackService, channel *goslack.Channel) (bool, error) {
+	if o.force {
+		return false, nil
+	}
+	owner, err := service.GetManagedBy(channel.ID)
+	if err != nil {
+		return false, err
+	}
+	if owner != "" {
+		fmt.Fprintf(o.out, "skipped #%s: managed by Channel %s\n", channel.Name, owner)
+		return true, nil
+	}
+	return false, nil
+}
+
+// archiveChannels archives the unarchived channels of the prefix
+func archiveChannels(ctx context.Context, o *options, prefix string) error {
+	service, err := o.slackService(ctx)
+	if err != nil {
+		return err
+	}
+	channels, err := channelsWithPrefix(service, prefix)
+	if err != nil {
+		return err
+	}
+
+	archived := 0
+	for i := range channels {
+		channel := &channels[i]
+		if channel.IsArchived {
+			continue
+		}
+		if skip, err := managed(o, service, channel); err != nil || skip {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !o.dryRun {
+			if err := service.ArchiveChannel(channel.ID); err != nil {
+				return fmt.Errorf("archiving #%s: %v", channel.Name, err)
+			}
+		}
+		fmt.Fprintf(o.out, "archived #%s (%s)\n", channel.Name, channel.ID)
+		archived++
+	}
+
+	fmt.Fprintf(o.out, "%d channels archived%s\n", archived, dryRunSuffix(o))
+	return nil
+}
+
+// renameChannels replaces the prefix of the names of the channels with the new prefix
+func renameChannels(ctx context.Context, o *options, prefix string, newPrefix string) error {
+	service, err := o.slackService(ctx)
+	if err != nil {
+		return err
+	}
+	channels, err := channelsWithPrefix(service, prefix)
+	if err != nil {
+		return err
+	}
+
+	prefix = slackv1alpha1.NormalizeChannelName(prefix)
+	newPrefix = slackv1alpha1.NormalizeChannelName(newPrefix)
+
+	renamed := 0
+	for i := range channels {
+		channel := &channels[i]
+		if channel.IsArchived {
+			continue
+		}
+		if skip, err := managed(o, service, channel); err != nil || skip {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		newName := newPrefix + strings.TrimPrefix(slackv1alpha1.NormalizeChannelName(channel.Name), prefix)
+		if !o.dryRun {
+			if _, err := service.RenameChannel(channel.ID, newName); err != nil {
+				return fmt.Errorf("renaming #%s to #%s: %v", channel.Name, newName, err)
+			}
+		}
+		fmt.Fprintf(o.out, "renamed #%s to #%s\n", channel.Name, newName)
+		renamed++
+	}
+
+	fmt.Fprintf(o.out, "%d channels renamed%s\n", renamed, dryRunSuffix(o))
+	return nil
+}
+
+// auditMembers prints a table of the members of the channels of the prefix with their kind
+func auditMembers(ctx context.Context, o *options, prefix string) error {
+	service, err := o.slackService(ctx)
+	if err != nil {
+		return err
+	}
+	channels, err := channelsWithPrefix(service, prefix)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(o.out, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "CHANNEL\tUSER ID\tNAME\tEMAIL\tKIND")
+	for _, channel := range channels {
+		if channel.IsArchived {
+			continue
+		}
+		members, err := service.ListMembers(channel.ID)
+		if err != nil {
+			return fmt.Errorf("listing members of #%s: %v", channel.Name, err)
+		}
+		for _, member := range members {
+			if o.guests && member.Kind != slack.MemberKindGuest && member.Kind != slack.MemberKindDeactivated {
+				continue
+			}
+			fmt.Fprintf(w, "#%s\t%s\t%s\t%s\t%s\n", channel.Name, member.ID, member.Name, member.Email, member.Kind)
+		}
+	}
+	return nil
+}
+
+// reconcileChannel annotates the Channel with the current time, so the operator reconciles it right away
+func reconcileChannel(ctx context.Context, o *options, name string) error {
+	c, err := o.Client()
+	if err != nil {
+		return err
+	}
+	if err := cli.RequestReconcile(ctx, c, types.NamespacedName{Namespace: o.Namespace, Name: name}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.out, "Channel %s/%s will be reconciled\n", o.Namespace, name)
+	return nil
+}
+
+func dryRunSuffix(o *options) string {
+	if o.dryRun {
+		return " (dry run)"
+	}
+	return ""
+}
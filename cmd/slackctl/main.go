@@ -0,0 +1,156 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// slackctl runs one-off administrative operations on Slack with the operator's credentials and normalization rules
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stakater/slack-operator/pkg/cli"
+	"github.com/stakater/slack-operator/pkg/config"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+const usage = `slackctl runs one-off administrative operations on Slack.
+
+Usage:
+  slackctl archive <prefix> [--dry-run] [--force]
+  slackctl rename <prefix> <new prefix> [--dry-run] [--force]
+  slackctl members <prefix> [--guests]
+  slackctl reconcile <channel> [-n namespace]
+  slackctl backup [-n namespace | -A]
+  slackctl restore <file> [--dry-run]
+
+backup prints a backup of the Channels with the members, bookmarks and pins of their slack channels, restore
+recreates the Channels of a backup which don't exist, "-" reads the backup from stdin.
+
+archive and rename skip channels managed by a Channel, which the operator would restore, unless --force is set.
+
+The bot token is read from --token, the SLACK_API_TOKEN environment variable or the operator secret given with
+--secret namespace/name.
+
+Flags:
+`
+
+// options are the flags shared by all commands
+type options struct {
+	cli.KubeOptions
+	allNamespaces bool
+	token         string
+	secret        string
+	dryRun        bool
+	force         bool
+	guests        bool
+
+	out io.Writer
+}
+
+// slackService creates a SlackService with the token of the flags, the environment or the operator secret
+func (o *options) slackService(ctx context.Context) (*slack.SlackService, error) {
+	token := cli.SlackToken(o.token)
+	if token == "" && o.secret != "" {
+		secretToken, err := o.secretToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		token = secretToken
+	}
+	if token == "" {
+		return nil, fmt.Errorf("a Slack token is required, set --token, %s or --secret", cli.TokenEnv)
+	}
+	return cli.NewSlackService(token), nil
+}
+
+// secretToken reads the bot token from the operator secret namespace/name
+func (o *options) secretToken(ctx context.Context) (string, error) {
+	parts := strings.SplitN(o.secret, "/", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("--secret must be namespace/name, got %q", o.secret)
+	}
+
+	c, err := o.Client()
+	if err != nil {
+		return "", err
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: parts[0], Name: parts[1]}, secret); err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(secret.Data[config.SlackAPITokenSecretKey]))
+	if token == "" {
+		return "", fmt.Errorf("secret %s has no %s", o.secret, config.SlackAPITokenSecretKey)
+	}
+	return token, nil
+}
+
+func main() {
+	o := &options{out: os.Stdout}
+
+	flags := flag.NewFlagSet("slackctl", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file.")
+	flags.StringVar(&o.Context, "context", "", "The kubeconfig context to use.")
+	flags.StringVar(&o.Namespace, "n", "", "The namespace of the Channel, the namespace of the context by default.")
+	flags.BoolVar(&o.allNamespaces, "A", false, "Back up the Channels of all namespaces.")
+	flags.StringVar(&o.token, "token", "", "The Slack bot token, SLACK_API_TOKEN by default.")
+	flags.StringVar(&o.secret, "secret", "", "The operator secret to read the bot token from, as namespace/name.")
+	flags.BoolVar(&o.dryRun, "dry-run", false, "Print the changes without making them.")
+	flags.BoolVar(&o.force, "force", false, "Also change channels managed by a Channel.")
+	flags.BoolVar(&o.guests, "guests", false, "Only list guests and deactivated members.")
+
+	args, err := cli.ParseInterspersed(flags, os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err == nil {
+		err = run(context.Background(), o, args)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command of the positional arguments
+func run(ctx context.Context, o *options, args []string) error {
+	switch {
+	case len(args) == 2 && args[0] == "archive":
+		return archiveChannels(ctx, o, args[1])
+	case len(args) == 3 && args[0] == "rename":
+		return renameChannels(ctx, o, args[1], args[2])
+	case len(args) == 2 && args[0] == "members":
+		return auditMembers(ctx, o, args[1])
+	case len(args) == 2 && args[0] == "reconcile":
+		return reconcileChannel(ctx, o, args[1])
+	case len(args) == 1 && args[0] == "backup":
+		return backupChannels(ctx, o)
+	case len(args) == 2 && args[0] == "restore":
+		return restoreChannels(ctx, o, args[1])
+	}
+	return fmt.Errorf("unknown command %q, see slackctl -h", strings.Join(args, " "))
+}
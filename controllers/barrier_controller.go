@@ -0,0 +1,209 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+var (
+	barrierFinalizer string = "slack.stakater.com/barrier"
+
+	// barrierResyncPeriod is how often barriers are checked for changes made outside of the operator
+	barrierResyncPeriod = time.Hour
+)
+
+// BarrierReconciler reconciles a Barrier object
+type BarrierReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=barriers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=barriers/status,verbs=get;update;patch
+
+// Reconcile loop for the Barrier resource, it creates the information barrier or updates it when it differs from
+// the spec. A barrier of the primary usergroup which already exists is adopted.
+func (r *BarrierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("barrier", req.NamespacedName)
+
+	barrier := &slackv1alpha1.Barrier{}
+	err := r.Get(ctx, req.NamespacedName, barrier)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Barrier is marked for deletion
+	if barrier.GetDeletionTimestamp() != nil {
+		if finalizerUtil.HasFinalizer(barrier, barrierFinalizer) {
+			return r.finalizeBarrier(ctx, barrier)
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(barrier, barrierFinalizer) {
+		log.Info("Adding finalizer for barrier " + req.Name)
+
+		barrierPatchBase := client.MergeFrom(barrier.DeepCopy())
+		finalizerUtil.AddFinalizer(barrier, barrierFinalizer)
+
+		err := r.Client.Patch(ctx, barrier, barrierPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, barrier, err, true)
+		}
+	}
+
+	existing, err := r.findBarrier(barrier)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, barrier, err, true)
+	}
+
+	desired := desiredBarrier(barrier)
+	if existing == nil {
+		log.Info("Creating information barrier", "primaryUsergroup", desired.PrimaryUsergroup)
+		barrier.Status.ID, err = r.SlackService.CreateBarrier(desired)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, barrier, err, false)
+		}
+	} else {
+		barrier.Status.ID = existing.ID
+		desired.ID = existing.ID
+		if !sameBarrier(*existing, desired) {
+			log.Info("Updating information barrier", "barrierID", existing.ID)
+			if err := r.SlackService.UpdateBarrier(desired); err != nil {
+				return reconcilerUtil.ManageError(r.Client, barrier, err, false)
+			}
+		}
+	}
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, barrier)
+	if err != nil {
+		return result, err
+	}
+	return reconcilerUtil.RequeueAfter(barrierResyncPeriod)
+}
+
+// findBarrier returns the information barrier with the ID in the status, or else the barrier of the primary
+// usergroup, nil if there is none
+func (r *BarrierReconciler) findBarrier(barrier *slackv1alpha1.Barrier) (*slack.Barrier, error) {
+	barriers, err := r.SlackService.ListBarriers()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range barriers {
+		if barrier.Status.ID != "" && barriers[i].ID == barrier.Status.ID {
+			return &barriers[i], nil
+		}
+	}
+	for i := range barriers {
+		if barriers[i].PrimaryUsergroup == barrier.Spec.PrimaryUsergroup {
+			return &barriers[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *BarrierReconciler) finalizeBarrier(ctx context.Context, barrier *slackv1alpha1.Barrier) (ctrl.Result, error) {
+	log := r.Log.WithValues("barrierID", barrier.Status.ID)
+
+	if barrier.Status.ID != "" {
+		log.Info("Deleting information barrier")
+		err := r.SlackService.DeleteBarrier(barrier.Status.ID)
+		if err != nil && !goerrors.Is(err, slack.ErrBarrierNotFound) {
+			return reconcilerUtil.ManageError(r.Client, barrier, err, true)
+		}
+	}
+
+	barrierPatchBase := client.MergeFrom(barrier.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(barrier, barrierFinalizer)
+	log.V(1).Info("Finalizer removed for barrier")
+
+	err := r.Client.Patch(ctx, barrier, barrierPatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, barrier, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// desiredBarrier is the information barrier of the spec
+func desiredBarrier(barrier *slackv1alpha1.Barrier) slack.Barrier {
+	desired := slack.Barrier{
+		PrimaryUsergroup: barrier.Spec.PrimaryUsergroup,
+		BarrieredFrom:    barrier.Spec.BarrieredFrom,
+	}
+	for _, subject := range barrier.Spec.RestrictedSubjects {
+		desired.RestrictedSubjects = append(desired.RestrictedSubjects, string(subject))
+	}
+	if len(desired.RestrictedSubjects) == 0 {
+		desired.RestrictedSubjects = []string{
+			string(slackv1alpha1.BarrierSubjectIM), string(slackv1alpha1.BarrierSubjectMPIM), string(slackv1alpha1.BarrierSubjectCall),
+		}
+	}
+	return desired
+}
+
+// sameBarrier compares the usergroups and restricted subjects of the barriers regardless of their order
+func sameBarrier(a slack.Barrier, b slack.Barrier) bool {
+	return a.PrimaryUsergroup == b.PrimaryUsergroup && sameSet(a.BarrieredFrom, b.BarrieredFrom) &&
+		sameSet(a.RestrictedSubjects, b.RestrictedSubjects)
+}
+
+func sameSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *BarrierReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.Barrier{}).
+		Complete(r)
+}
@@ -0,0 +1,164 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/config"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+// BroadcastReconciler reconciles a Broadcast object
+type BroadcastReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=broadcasts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=broadcasts/status,verbs=get;update;patch
+
+// Reconcile loop for the Broadcast resource, it posts the message once to every selected channel
+func (r *BroadcastReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("broadcast", req.NamespacedName)
+
+	broadcast := &slackv1alpha1.Broadcast{}
+	err := r.Get(ctx, req.NamespacedName, broadcast)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if broadcast.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if broadcast.Spec.Text == "" && broadcast.Spec.TemplateRef == nil {
+		return reconcilerUtil.ManageError(r.Client, broadcast, fmt.Errorf("Either text or templateRef must be set"), false)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&broadcast.Spec.ChannelSelector)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, broadcast, err, false)
+	}
+
+	namespaces := broadcast.Spec.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{broadcast.Namespace}
+	}
+
+	var channels []slackv1alpha1.Channel
+	for _, namespace := range namespaces {
+		if err := pkgutil.CheckNamespaceAccess(broadcast.Namespace, namespace); err != nil {
+			return reconcilerUtil.ManageError(r.Client, broadcast, err, false)
+		}
+		channelList := &slackv1alpha1.ChannelList{}
+		err = r.List(ctx, channelList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return reconcilerUtil.RequeueWithError(err)
+		}
+		channels = append(channels, channelList.Items...)
+	}
+
+	options, err := pkgutil.MessageOptions(ctx, r.Client, broadcast.Spec.Text, broadcast.Spec.TemplateRef, broadcast.Namespace, broadcast)
+	if err != nil {
+		log.Error(err, "Unable to render broadcast")
+		return reconcilerUtil.ManageError(r.Client, broadcast, err, true)
+	}
+
+	deliveries := map[string]slackv1alpha1.BroadcastDelivery{}
+	for _, delivery := range broadcast.Status.Deliveries {
+		deliveries[delivery.Channel] = delivery
+	}
+
+	failed := false
+	for _, channel := range channels {
+		key := channel.Namespace + "/" + channel.Name
+
+		// Channels are only posted to once, failed deliveries are retried
+		if delivery, found := deliveries[key]; found && delivery.Error == "" {
+			continue
+		}
+
+		delivery := slackv1alpha1.BroadcastDelivery{Channel: key, ChannelID: channel.Status.ID}
+		if channel.Status.ID == "" {
+			delivery.Error = "Channel has not been created on Slack yet"
+		} else {
+			delivery.Timestamp, err = r.SlackService.PostMessage(channel.Status.ID, options...)
+			if err != nil {
+				delivery.Error = err.Error()
+			}
+		}
+
+		if delivery.Error != "" {
+			log.Info("Unable to deliver broadcast", "channel", key, "error", delivery.Error)
+			failed = true
+		}
+		deliveries[key] = delivery
+
+		// Every delivery is recorded right away, so that a failed status update doesn't post to the other channels again
+		broadcast.Status.Deliveries = sortDeliveries(deliveries)
+		if err := r.Status().Update(ctx, broadcast); err != nil {
+			return reconcilerUtil.RequeueWithError(err)
+		}
+	}
+
+	if failed {
+		result, err := reconcilerUtil.ManageError(r.Client, broadcast, fmt.Errorf("Broadcast could not be delivered to all channels"), false)
+		if err != nil {
+			return result, err
+		}
+		return reconcilerUtil.RequeueAfter(config.ErrorRequeueTime)
+	}
+
+	return reconcilerUtil.ManageSuccess(r.Client, broadcast)
+}
+
+// sortDeliveries returns the deliveries sorted by channel
+func sortDeliveries(deliveries map[string]slackv1alpha1.BroadcastDelivery) []slackv1alpha1.BroadcastDelivery {
+	sorted := make([]slackv1alpha1.BroadcastDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		sorted = append(sorted, delivery)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Channel < sorted[j].Channel
+	})
+	return sorted
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *BroadcastReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.Broadcast{}).
+		Complete(r)
+}
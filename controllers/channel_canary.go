@@ -0,0 +1,112 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// canaryRetryPeriod is how often a canary channel which didn't converge is retried
+const canaryRetryPeriod = 5 * time.Minute
+
+// canaryPending checks whether the spec of the channel has to converge in the canary workspace before it's applied
+// to the production workspace
+func (r *ChannelReconciler) canaryPending(channel *slackv1alpha1.Channel) bool {
+	if r.CanaryService == nil || !channel.IsCanary() {
+		return false
+	}
+	return channel.Status.Canary == nil || channel.Status.Canary.Generation != channel.Generation
+}
+
+// reconcileCanary applies the name, topic, description and members of the channel to its slack channel in the canary
+// workspace and records the converged generation. Members are looked up by email, user IDs of the production
+// workspace are left out and users missing in the canary workspace are only counted.
+func (r *ChannelReconciler) reconcileCanary(ctx context.Context, channel *slackv1alpha1.Channel) error {
+	log := r.Log.WithValues("channel", channel.Namespace+"/"+channel.Name)
+	service := r.CanaryService
+
+	status := &slackv1alpha1.CanaryStatus{}
+	if channel.Status.Canary != nil {
+		status = channel.Status.Canary.DeepCopy()
+	}
+
+	if status.ID == "" {
+		log.Info("Creating canary channel", "name", channel.Spec.Name)
+		channelID, err := service.CreateChannel(channel.Spec.Name, channel.Spec.Private)
+		if goerrors.Is(err, slack.ErrNameTaken) {
+			existing, err := service.GetChannelByName(channel.Spec.Name)
+			if err != nil {
+				return err
+			}
+			if existing.IsArchived {
+				if err := service.UnArchiveChannel(existing); err != nil {
+					return err
+				}
+			}
+			channelID = &existing.ID
+		} else if err != nil {
+			return err
+		}
+		status.ID = *channelID
+	}
+
+	err := retryPropagation(func() error {
+		_, err := service.SyncChannelMetadata(status.ID, r.desiredMetadata(channel))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	status.MemberErrors = 0
+	if channel.ManagesMembers() {
+		users, _, err := r.members(ctx, channel)
+		if err != nil {
+			return err
+		}
+		var emails []string
+		for _, user := range users {
+			if !strings.HasPrefix(user, "id:") {
+				emails = append(emails, user)
+			}
+		}
+
+		plan, err := service.PlanMembership(ctx, status.ID, emails, channel.Spec.EmailAliases, r.protectedUsers(channel))
+		if err != nil {
+			return err
+		}
+		memberErrors, errs := service.ApplyMembership(ctx, status.ID, plan)
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		status.MemberErrors = len(memberErrors)
+	}
+
+	status.Generation = channel.Generation
+	status.ConvergedAt = &metav1.Time{Time: time.Now()}
+	channel.Status.Canary = status
+	log.Info("Canary channel converged", "generation", channel.Generation, "canaryID", status.ID)
+	return nil
+}
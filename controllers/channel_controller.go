@@ -18,22 +18,89 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
 	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
 	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/maintenance"
+	"github.com/stakater/slack-operator/pkg/membership"
+	"github.com/stakater/slack-operator/pkg/resync"
+	"github.com/stakater/slack-operator/pkg/scim"
+	"github.com/stakater/slack-operator/pkg/shard"
 	slack "github.com/stakater/slack-operator/pkg/slack"
+	"github.com/stakater/slack-operator/pkg/templating"
 	pkgutil "github.com/stakater/slack-operator/pkg/util"
 )
 
 var (
 	channelFinalizer string = "slack.stakater.com/channel"
+
+	// finalizerFieldManager is the field manager applying the finalizer of Channels
+	finalizerFieldManager = "slack-operator-finalizer"
+
+	// applyConflictRetryPeriod is how often channels whose fields conflict with another tool are retried
+	applyConflictRetryPeriod = 10 * time.Minute
+
+	// groupResyncPeriod is how often channels with SCIM groups are reconciled to pick up group changes
+	groupResyncPeriod = 10 * time.Minute
+
+	// driftScanRetryDelay is how long a drift scan is postponed while other drift scans occupy their workers
+	driftScanRetryDelay = 30 * time.Second
+
+	// archivedCheckPeriod is how often held channels which were archived in Slack are checked again
+	archivedCheckPeriod = time.Hour
+
+	// rejoinPeriod is how often the bot tries to rejoin channels it was removed from
+	rejoinPeriod = 10 * time.Minute
+
+	// propagationBackoff bounds the retries of calls which fail with channel_not_found right after a channel
+	// was created, until the channel is visible to all of Slack's APIs
+	propagationBackoff = wait.Backoff{Steps: 4, Duration: 500 * time.Millisecond, Factor: 2, Jitter: 0.1}
+
+	// nameConflictCheckPeriod is how often channels whose slack channel is managed by another Channel are checked again
+	nameConflictCheckPeriod = 5 * time.Minute
+
+	// maxNameSuffix is the highest suffix tried for channels whose name is taken with onNameConflict Suffix
+	maxNameSuffix = 20
+
+	// membershipSyncContinuePeriod is how soon membership syncs with more than MaxMemberChanges changes, or which ran
+	// out of time, continue
+	membershipSyncContinuePeriod = 10 * time.Second
+
+	// memberChangesPerTimeoutCheck is how many users are invited or removed between the checks of the reconcile timeout
+	memberChangesPerTimeoutCheck = 100
+
+	// exportWaitPeriod is how often deleted channels check whether their messages were exported before archiving
+	exportWaitPeriod = 10 * time.Second
 )
 
 // ChannelReconciler reconciles a Channel object
@@ -42,11 +109,58 @@ type ChannelReconciler struct {
 	Log          logr.Logger
 	Scheme       *runtime.Scheme
 	SlackService slack.Service
+
+	// ProtectedUsers are emails or user IDs which are never removed from any channel
+	ProtectedUsers []string
+
+	// SCIMClient resolves the members of SCIM groups, nil if SCIM isn't configured
+	SCIMClient *scim.Client
+
+	// MaxConcurrentReconciles is the number of channels reconciled in parallel
+	MaxConcurrentReconciles int
+
+	// Recorder records events on Channels, e.g. when they are changed outside the operator
+	Recorder record.EventRecorder
+
+	// ExternalChanges are Channels which were changed outside the operator and are reconciled right away, nil if
+	// changes aren't watched
+	ExternalChanges chan event.GenericEvent
+
+	// AuditHistory buffers the changes made to the slack channels for their status history, nil to keep no history
+	AuditHistory *slack.AuditHistory
+
+	// CanaryService manages the canary workspace, Channels with the canary label converge there before the production
+	// workspace is changed. nil if there is no canary workspace.
+	CanaryService slack.Service
+
+	// MaintenanceWindows restricts removing members, renaming and archiving channels to maintenance windows, nil
+	// allows them at any time
+	MaintenanceWindows *maintenance.Windows
+
+	// Shard is the part of the Channels this deployment reconciles, nil to reconcile all of them
+	Shard *shard.Shard
+
+	// MaxMemberChanges is the number of users a reconcile invites and removes, channels with more changes continue on
+	// the next reconcile. 0 doesn't limit the changes.
+	MaxMemberChanges int
+
+	// MaxFailedReconciles is the number of reconciles in a row a channel may fail before it isn't reconciled anymore,
+	// 0 retries failing channels forever
+	MaxFailedReconciles int
+
+	// driftScans limits the periodic reconciles of unchanged channels to half of the workers
+	driftScans *resync.Gate
 }
 
 // +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=slack.stakater.com,resources=channels/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=useroffboardings,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelnamingpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=conversationexports,verbs=get;list;watch
 
 // Reconcile loop for the Channel resource
 func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -66,35 +180,158 @@ func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcilerUtil.RequeueWithError(err)
 	}
 
+	// Channels of other shards are reconciled by other deployments of the operator
+	if !r.Shard.Owns(channel) {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
 	// Channel is marked for deletion
 	if channel.GetDeletionTimestamp() != nil {
 		log.Info("Deletion timestamp found for channel " + req.Name)
 		if finalizerUtil.HasFinalizer(channel, channelFinalizer) {
-			return r.finalizeChannel(req, channel)
+			return r.finalizeChannel(ctx, req, channel)
 		}
 		// Finalizer doesn't exist so clean up is already done
 		return reconcilerUtil.DoNotRequeue()
 	}
 
+	// Dead-lettered channels don't use up the rate limits until they are fixed or retried
+	if isDeadLettered(channel) {
+		log.V(1).Info("Channel is dead-lettered, skipping it")
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// The Slack calls of the reconcile stop at the reconcile timeout of the channel, status updates use ctx
+	deadline, cancel := withReconcileTimeout(ctx, channel)
+	defer cancel()
+
+	// Channels which got past a disabled webhook are held, so tenants can't reach the workspace
+	if !slackv1alpha1.IsNamespaceAllowed(channel.Namespace) {
+		log.Info("Namespace isn't allowed to use the Slack workspace")
+		return r.hold(ctx, channel, metav1.Condition{
+			Type:    "NamespaceNotAllowed",
+			Reason:  "NamespaceNotAllowed",
+			Message: fmt.Sprintf("Namespace %s isn't allowed to use the Slack workspace", channel.Namespace),
+		}, 0)
+	}
+
 	// Add finalizer if it doesn't exist
 	if !finalizerUtil.HasFinalizer(channel, channelFinalizer) {
 		log.Info("Adding finalizer for channel " + req.Name)
 
-		// Base object for patch, which patches using the merge-patch strategy with the given object as base.
-		channelPatchBase := client.MergeFrom(channel.DeepCopy())
-
-		finalizerUtil.AddFinalizer(channel, channelFinalizer)
-
-		err := r.Client.Patch(ctx, channel, channelPatchBase)
+		err := r.applyFinalizer(ctx, channel, true)
 		if err != nil {
-			return reconcilerUtil.ManageError(r.Client, channel, err, true)
+			return r.manageError(ctx, channel, err, true)
 		}
 	}
 
+	channels := &slackv1alpha1.ChannelList{}
+	err = r.List(ctx, channels)
+	if err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+	if owner := slackv1alpha1.FindNameConflict(channel, channels.Items); owner != nil {
+		log.Info("Slack channel is managed by another Channel", "owner", owner.Namespace+"/"+owner.Name)
+		return r.hold(ctx, channel, metav1.Condition{
+			Type:    "NameConflict",
+			Reason:  "NameInUse",
+			Message: fmt.Sprintf("The slack channel %s is managed by Channel %s/%s", slackv1alpha1.NormalizeChannelName(channel.Spec.Name), owner.Namespace, owner.Name),
+		}, nameConflictCheckPeriod)
+	}
+
+	// Templates are rendered before the channel is checked, the rendered topic and description must fit into slack
+	err = r.renderTemplates(ctx, channel)
+	if err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+	useCloneDefaults(channel)
+	if err := r.applyChannelPolicies(ctx, channel); err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+
 	// Check for validity of slack channel custom resource
 	err = r.SlackService.IsValidChannel(channel)
 	if err != nil {
-		return reconcilerUtil.ManageError(r.Client, channel, err, true)
+		return r.manageError(ctx, channel, err, true)
+	}
+
+	// Spec changes of canary channels are only applied to the production workspace once they converged in the canary one
+	if r.canaryPending(channel) {
+		patchBase := client.MergeFrom(channel.DeepCopy())
+		if err := r.reconcileCanary(ctx, channel); err != nil {
+			log.Error(err, "Canary channel didn't converge")
+			return r.hold(ctx, channel, metav1.Condition{
+				Type:    "CanaryNotConverged",
+				Reason:  "CanaryFailed",
+				Message: fmt.Sprintf("The spec didn't converge in the canary workspace and isn't applied to the production workspace: %v", err),
+			}, canaryRetryPeriod)
+		}
+		if err := r.patchStatus(ctx, channel, patchBase); err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+	}
+
+	// A new value of the reconcile-at annotation forces a full reconcile right away
+	reconcileRequested := channel.Annotations[slackv1alpha1.ReconcileAtAnnotation] != channel.Status.LastHandledReconcileAt
+
+	// Reconciles of unchanged channels only check for drift, they give way to channels whose spec changed
+	if channel.Status.ID != "" && channel.Generation == channel.Status.ObservedGeneration && !reconcileRequested {
+		if !r.driftScans.TryEnter() {
+			log.V(1).Info("Postponing drift scan, other drift scans are running")
+			return reconcilerUtil.RequeueAfter(resync.Spread(req.String(), driftScanRetryDelay))
+		}
+		defer r.driftScans.Leave()
+	}
+
+	temporaryUsers, err := membership.SyncTemporaryUsers(channel.Spec.TemporaryUsers, channel.Status.TemporaryUsers, time.Now())
+	if err != nil {
+		return r.manageError(ctx, channel, err, false)
+	}
+	temporaryUsersChanged := !reflect.DeepEqual(temporaryUsers, channel.Status.TemporaryUsers)
+	channel.Status.TemporaryUsers = temporaryUsers
+
+	groupMembers, err := r.getGroupMembers(channel)
+	if err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+	groupMembersChanged := !reflect.DeepEqual(groupMembers, channel.Status.GroupMembers)
+	channel.Status.GroupMembers = groupMembers
+
+	activeSchedules, scheduledUsers, err := membership.ScheduledUsers(channel.Spec.MemberSchedules, time.Now())
+	if err != nil {
+		return r.manageError(ctx, channel, err, false)
+	}
+	schedulesChanged := !reflect.DeepEqual(activeSchedules, channel.Status.ActiveSchedules) ||
+		!reflect.DeepEqual(scheduledUsers, channel.Status.ScheduledUsers)
+	channel.Status.ActiveSchedules, channel.Status.ScheduledUsers = activeSchedules, scheduledUsers
+
+	appliedHash, err := r.appliedHash(ctx, channel)
+	if err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+
+	// The drift check is skipped when neither the spec nor the slack channel changed since the spec was applied
+	if channel.Status.ID != "" && channel.Status.AppliedHash == appliedHash && !reconcileRequested {
+		// Channels which can't be found are checked fully, the bot may have been removed from them
+		slackUpdated, err := r.SlackService.GetChannelUpdated(channel.Status.ID)
+		if err != nil && !goerrors.Is(err, slack.ErrChannelNotFound) {
+			return r.manageError(ctx, channel, err, true)
+		}
+		if err == nil && slackUpdated == channel.Status.SlackUpdated {
+			canvasUpdated, err := r.reconcileCanvas(ctx, channel)
+			if err != nil {
+				log.Error(err, "Error updating channel canvas")
+				return r.manageError(ctx, channel, err, true)
+			}
+			// Spec changes which don't change what is applied, e.g. reformatted templates, succeed right away
+			specChanged := channel.Generation != channel.Status.ObservedGeneration
+			if canvasUpdated || temporaryUsersChanged || groupMembersChanged || schedulesChanged || specChanged || channel.Status.Drift != nil {
+				return r.manageSuccess(channel)
+			}
+
+			log.Info("Skipping drift check. Nothing changed since the spec was applied")
+			return r.requeueForExpiration(channel)
+		}
 	}
 
 	log.Info("Start checking channel status")
@@ -104,112 +341,1199 @@ func (r *ChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 		log.Info("Creating new channel", "name", name)
 
+		created := true
 		channelID, err := r.SlackService.CreateChannel(name, isPrivate)
 		if err != nil {
-			if err.Error() == "name_taken" {
+			if goerrors.Is(err, slack.ErrNameTaken) {
+				created = false
+
 				// Check if the channel already exists and then just reconstruct the status accordingly
 				log.Info("Getting Channel by Name")
 				existingChannel, err := r.SlackService.GetChannelByName(name)
 				if err != nil {
-					return reconcilerUtil.ManageError(r.Client, channel, err, false)
+					return r.manageError(ctx, channel, err, false)
 				}
 
-				if existingChannel != nil && existingChannel.GroupConversation.IsArchived {
-					err = error(nil)
+				switch policy := channel.Spec.OnNameConflict; {
+				case policy == slackv1alpha1.NameConflictFail || (policy == slackv1alpha1.NameConflictAdopt && existingChannel.IsArchived):
+					log.Info("Name of the channel is taken, leaving the existing channel alone", "channelID", existingChannel.ID, "policy", policy)
+					return r.hold(ctx, channel, metav1.Condition{
+						Type:    "NameTaken",
+						Reason:  "NameTaken",
+						Message: fmt.Sprintf("The name %s is taken by %s, which onNameConflict %s doesn't adopt", name, describeExisting(existingChannel), policy),
+					}, nameConflictCheckPeriod)
+				case policy == slackv1alpha1.NameConflictSuffix:
+					suffix, suffixedID, err := r.createWithSuffix(name, isPrivate)
 					if err != nil {
-						return reconcilerUtil.ManageError(r.Client, channel, err, false)
+						return r.manageError(ctx, channel, err, false)
 					}
+					log.Info("Name of the channel is taken, created it with a suffix", "suffix", suffix)
+					created, channelID = true, suffixedID
+					channel.Status.NameSuffix = suffix
+				default:
+					// Held channels keep the archived channel, it is reported on the next reconcile
+					if existingChannel.IsArchived && channel.Spec.OnExternalArchive != slackv1alpha1.ExternalArchiveHold {
+						log.Info("Unarchiving existing channel", "channelID", existingChannel.ID)
+						err = r.SlackService.UnArchiveChannel(existingChannel)
+						if err != nil {
+							return r.manageError(ctx, channel, err, false)
+						}
+					}
+					channelID = &existingChannel.ID
 				}
-				channelID = &existingChannel.ID
 			} else {
-				return reconcilerUtil.ManageError(r.Client, channel, err, false)
+				return r.manageError(ctx, channel, err, false)
 			}
 		}
 
+		var managedBy string
+		err = retryPropagation(func() error {
+			managedBy, err = r.claimChannel(*channelID, channel)
+			return err
+		})
+		if err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+		if managedBy != "" {
+			return r.holdManagedByOther(ctx, channel, managedBy)
+		}
+
 		// Base object for patch, which patches using the merge-patch strategy with the given object as base.
 		channelPatchBase := client.MergeFrom(channel.DeepCopy())
 
 		channel.Status.ID = *channelID
+		// Only channels created by the operator are cloned, a failed clone is resumed by the next reconcile
+		if created && channel.Spec.CloneFrom != nil {
+			channel.Status.Clone = &slackv1alpha1.CloneStatus{}
+		}
 
-		err = r.Status().Patch(ctx, channel, channelPatchBase)
+		err = r.patchStatus(ctx, channel, channelPatchBase)
 		if err != nil {
 			log.Error(err, "Failed to update Channel status")
-			return reconcilerUtil.ManageError(r.Client, channel, err, true)
+			return r.manageError(ctx, channel, err, true)
+		}
+
+		if deadline.Err() != nil {
+			return r.continueLater(ctx, channel, "clone")
+		}
+		err = retryPropagation(func() error {
+			return r.cloneChannel(ctx, channel)
+		})
+		if err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+
+		if deadline.Err() != nil {
+			return r.continueLater(ctx, channel, "metadata")
 		}
-		return r.updateSlackChannel(ctx, channel)
+		err = retryPropagation(func() error {
+			_, err := r.SlackService.SyncChannelMetadata(channel.Status.ID, r.desiredMetadata(channel))
+			return err
+		})
+		if err != nil {
+			return r.manageError(ctx, channel, err, false)
+		}
+
+		if deadline.Err() != nil {
+			return r.continueLater(ctx, channel, "members")
+		}
+		plan, err := r.planMembership(ctx, deadline, channel)
+		if err != nil && deadline.Err() != nil {
+			return r.continueLater(ctx, channel, "members")
+		} else if err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+		return r.updateChannelMembers(ctx, deadline, channel, plan, appliedHash)
 	}
 	log.Info("Done checking channel status")
 
 	existingChannel, err := r.SlackService.GetChannel(channel.Status.ID)
+	if goerrors.Is(err, slack.ErrChannelNotFound) {
+		// Private channels can't be found once the bot was removed from them
+		log.Info("Channel not found, rejoining it in case the bot was removed")
+		if rejoinErr := r.SlackService.RejoinChannel(channel.Status.ID); rejoinErr != nil {
+			// The admin API finds every channel of the organization, channels it can't find were deleted
+			if goerrors.Is(rejoinErr, slack.ErrChannelNotFound) {
+				return r.handleExternalDeletion(ctx, channel)
+			}
+			return r.hold(ctx, channel, metav1.Condition{
+				Type:    "BotNotInChannel",
+				Reason:  "RejoinFailed",
+				Message: fmt.Sprintf("The channel can't be found and the bot couldn't rejoin it, invite the bot to the channel: %v", rejoinErr),
+			}, rejoinPeriod)
+		}
+		existingChannel, err = r.SlackService.GetChannel(channel.Status.ID)
+	}
 	if err != nil {
-		return reconcilerUtil.ManageError(r.Client, channel, err, true)
+		return r.manageError(ctx, channel, err, true)
 	}
 
-	existingChannelCR := r.SlackService.GetChannelCRFromChannel(existingChannel)
+	if existingChannel.IsArchived {
+		if channel.Status.MergedInto != "" {
+			log.Info("Channel was merged into another channel, holding reconciliation", "target", channel.Status.MergedInto)
+			return r.hold(ctx, channel, metav1.Condition{
+				Type:    "Archived",
+				Reason:  "MergedIntoOtherChannel",
+				Message: fmt.Sprintf("The channel was merged into <#%s> and archived, it is reconciled again once it is unarchived in Slack", channel.Status.MergedInto),
+			}, archivedCheckPeriod)
+		}
+		if autoArchive := channel.Status.AutoArchive; autoArchive != nil && autoArchive.ArchivedAt != nil {
+			log.Info("Channel was archived for inactivity, holding reconciliation")
+			return r.hold(ctx, channel, metav1.Condition{
+				Type:    "Archived",
+				Reason:  "ArchivedForInactivity",
+				Message: "The channel was archived because it was inactive, it is reconciled again once it is unarchived in Slack",
+			}, archivedCheckPeriod)
+		}
+		if channel.Spec.OnExternalArchive == slackv1alpha1.ExternalArchiveHold {
+			recordDrift(channel, []string{"archived"})
+			log.Info("Channel was archived in Slack, holding reconciliation")
+			return r.hold(ctx, channel, metav1.Condition{
+				Type:    "Archived",
+				Reason:  "ArchivedInSlack",
+				Message: "The channel was archived in Slack, it is reconciled again once it is unarchived",
+			}, archivedCheckPeriod)
+		}
+
+		log.Info("Channel was archived in Slack, unarchiving it")
+		err = r.SlackService.UnArchiveChannel(existingChannel)
+		if err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+	}
 
-	err = slackv1alpha1.ValidateImmutableFields(existingChannelCR, channel)
+	// The channel is verified to be managed by this Channel before it is renamed or members are removed
+	managedBy, err := r.claimChannel(channel.Status.ID, channel)
 	if err != nil {
-		return reconcilerUtil.ManageError(r.Client, channel, err, true)
+		return r.manageError(ctx, channel, err, true)
+	}
+	if managedBy != "" {
+		return r.holdManagedByOther(ctx, channel, managedBy)
+	}
+
+	if deadline.Err() != nil {
+		return r.continueLater(ctx, channel, "clone")
+	}
+	if err := r.cloneChannel(ctx, channel); err != nil {
+		return r.manageError(ctx, channel, err, true)
 	}
 
-	updated, err := r.SlackService.IsChannelUpdated(channel)
+	if deadline.Err() != nil {
+		return r.continueLater(ctx, channel, "metadata")
+	}
+	drift := slack.ChangedFields(slack.MetadataDrift(existingChannel, r.desiredMetadata(channel)))
+	if existingChannel.IsArchived {
+		drift = append(drift, "archived")
+	}
+	retentionDrifted, err := r.retentionDrifted(channel)
 	if err != nil {
-		return pkgutil.ManageError(ctx, r.Client, channel, err)
+		return r.manageError(ctx, channel, err, true)
+	}
+	if retentionDrifted {
+		drift = append(drift, "retention")
+	}
+	sectionDrifted, err := r.sectionDrifted(channel)
+	if err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+	if sectionDrifted {
+		drift = append(drift, "section")
+	}
+	if existingChannel.IsPrivate != channel.Spec.Private {
+		drift = append(drift, "visibility")
+	}
+	missingBookmarks, err := r.missingPolicyBookmarks(channel)
+	if err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+	if len(missingBookmarks) > 0 {
+		drift = append(drift, "bookmarks")
+	}
+	recordDrift(channel, drift)
+
+	// Disruptive changes wait for the next maintenance window, the others are applied right away
+	var deferred []string
+	maintenanceOpen := r.MaintenanceWindows.Open(time.Now())
+	desired := r.desiredMetadata(channel)
+	if !maintenanceOpen && slackv1alpha1.NormalizeChannelName(existingChannel.Name) != slackv1alpha1.NormalizeChannelName(desired.Spec.Name) {
+		log.Info("Renaming the channel waits for the next maintenance window", "name", desired.Spec.Name)
+		deferred = append(deferred, "rename to "+slackv1alpha1.NormalizeChannelName(desired.Spec.Name))
+		desired = desired.DeepCopy()
+		desired.Spec.Name = existingChannel.Name
 	}
 
-	if !updated {
+	// Converting between private and public channels needs the admin API, channels are held without it
+	converted := false
+	if existingChannel.IsPrivate != channel.Spec.Private {
+		if !maintenanceOpen {
+			log.Info("Converting the channel waits for the next maintenance window", "private", channel.Spec.Private)
+			deferred = append(deferred, fmt.Sprintf("convert to a %s channel", visibility(channel.Spec.Private)))
+		} else {
+			err := r.SlackService.ConvertChannel(channel.Status.ID, channel.Spec.Private)
+			if goerrors.Is(err, slack.ErrAdminUnavailable) || goerrors.Is(err, slack.ErrMissingScope) {
+				log.Info("Unable to convert the channel without the admin API", "private", channel.Spec.Private)
+				return r.hold(ctx, channel, metav1.Condition{
+					Type:   "ImmutableFieldChanged",
+					Reason: "ConversionUnavailable",
+					Message: fmt.Sprintf("spec.private was changed, but converting the slack channel to a %s channel needs an Enterprise Grid "+
+						"admin user token with the admin.conversations:write scope (%v). Convert the channel in Slack or revert spec.private.",
+						visibility(channel.Spec.Private), err),
+				}, 0)
+			}
+			if err != nil {
+				return r.manageError(ctx, channel, err, true)
+			}
+			r.recordChanges(channel, []slack.FieldChange{{
+				Field: "visibility", Old: visibility(existingChannel.IsPrivate), New: visibility(channel.Spec.Private),
+			}})
+			converted = true
+		}
+	}
+
+	changes, err := r.SlackService.SyncChannelMetadata(channel.Status.ID, desired)
+	r.recordChanges(channel, changes)
+	if err != nil {
+		log.Error(err, "Error updating channel details")
+		// Channels archived since they were fetched are handled on the retry
+		retriable := goerrors.Is(err, slack.ErrRateLimited) || goerrors.Is(err, slack.ErrChannelArchived)
+		return r.manageError(ctx, channel, err, retriable)
+	}
+	updated := len(changes) > 0 || converted
+
+	if (retentionDrifted || sectionDrifted || len(missingBookmarks) > 0) && deadline.Err() != nil {
+		return r.continueLater(ctx, channel, "settings")
+	}
+	if retentionDrifted {
+		log.Info("Updating channel retention", "days", channel.Spec.Retention.DesiredDays())
+		err = r.SlackService.SetRetention(channel.Status.ID, channel.Spec.Retention.DesiredDays())
+		if err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+		updated = true
+	}
+
+	if sectionDrifted {
+		log.Info("Updating channel section", "section", channel.Spec.Section)
+		err = r.SlackService.SetSection(channel.Status.ID, channel.Namespace+"/"+channel.Name, channel.Spec.Section)
+		if err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+		updated = true
+	}
+	channel.Status.Section = channel.Spec.Section
+
+	if len(missingBookmarks) > 0 {
+		if err := r.addPolicyBookmarks(channel, missingBookmarks); err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+		updated = true
+	}
+
+	if deadline.Err() != nil {
+		return r.continueLater(ctx, channel, "members")
+	}
+	plan, err := r.planMembership(ctx, deadline, channel)
+	if err != nil && deadline.Err() != nil {
+		return r.continueLater(ctx, channel, "members")
+	} else if err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+
+	if plan.Changed() {
+		recordDrift(channel, append(drift, "members"))
+	}
+	if !maintenanceOpen && len(plan.Remove) > 0 {
+		log.Info("Removing members waits for the next maintenance window", "users", len(plan.Remove))
+		deferred = append(deferred, fmt.Sprintf("remove %d members", len(plan.Remove)))
+		plan.Remove = nil
+	}
+	deferredChanged := !reflect.DeepEqual(deferred, channel.Status.DeferredActions)
+	channel.Status.DeferredActions = deferred
+
+	if !plan.Changed() {
+		if deadline.Err() != nil {
+			return r.continueLater(ctx, channel, "canvas")
+		}
+		canvasUpdated, err := r.reconcileCanvas(ctx, channel)
+		if err != nil {
+			log.Error(err, "Error updating channel canvas")
+			return r.manageError(ctx, channel, err, true)
+		}
+		memberErrorsChanged := !reflect.DeepEqual(plan.MemberErrors, channel.Status.MemberErrors)
+		channel.Status.MemberErrors = plan.MemberErrors
+		syncFinished := channel.Status.MembershipSync != nil
+		channel.Status.MembershipSync = nil
+		generationChanged := channel.Status.ObservedGeneration != channel.Generation
+		hashChanged := r.recordApplied(channel, appliedHash)
+		driftChanged := channel.Status.Drift != nil
+		if updated || canvasUpdated || temporaryUsersChanged || groupMembersChanged || schedulesChanged || memberErrorsChanged || syncFinished || generationChanged || hashChanged || driftChanged || deferredChanged || reconcileRequested {
+			return r.manageSuccess(channel)
+		}
+
 		log.Info("Skipping update. No changes found")
-		return reconcilerUtil.DoNotRequeue()
+		return r.requeueForExpiration(channel)
 	}
 
-	return r.updateSlackChannel(ctx, channel)
+	return r.updateChannelMembers(ctx, deadline, channel, plan, appliedHash)
 }
 
-func (r *ChannelReconciler) updateSlackChannel(ctx context.Context, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+// updateChannelMembers applies the membership plan, changes beyond MaxMemberChanges or the reconcile timeout of the
+// deadline context continue on the next reconcile
+func (r *ChannelReconciler) updateChannelMembers(ctx context.Context, deadline context.Context, channel *slackv1alpha1.Channel, plan *slack.MembershipPlan, appliedHash string) (ctrl.Result, error) {
 	channelID := channel.Status.ID
 	log := r.Log.WithValues("channelID", channelID)
 
-	log.Info("Updating channel details")
+	log.Info("Updating channel members")
+
+	pendingInvites, pendingRemovals := limitMemberChanges(plan, r.MaxMemberChanges)
+
+	var memberErrors []slackv1alpha1.MemberError
+	var errorlist []error
+	parts := plan.Split(memberChangesPerTimeoutCheck)
+	changed := 0
+	timedOut := false
+	for i, part := range parts {
+		// The reconcile timeout is checked between the parts, the changes left continue on the next reconcile
+		if i > 0 && deadline.Err() != nil {
+			pendingInvites, pendingRemovals = addPending(parts[i:], pendingInvites, pendingRemovals)
+			timedOut = true
+			break
+		}
+
+		var partErrors []slackv1alpha1.MemberError
+		var partErrorlist []error
+		_ = retryPropagation(func() error {
+			partErrors, partErrorlist = r.SlackService.ApplyMembership(deadline, channelID, part)
+			for _, err := range partErrorlist {
+				if goerrors.Is(err, slack.ErrChannelNotFound) {
+					return err
+				}
+			}
+			return nil
+		})
+		memberErrors = append(memberErrors, partErrors...)
+		// A part cut short by the reconcile timeout is planned again by the next reconcile
+		if len(partErrorlist) > 0 && deadline.Err() != nil {
+			pendingInvites, pendingRemovals = addPending(parts[i:], pendingInvites, pendingRemovals)
+			timedOut = true
+			break
+		}
+		errorlist = append(errorlist, partErrorlist...)
+		changed += len(part.Invite) + len(part.Remove)
+		if len(partErrorlist) > 0 {
+			break
+		}
+	}
+	channel.Status.MemberErrors = memberErrors
+	for _, err := range errorlist {
+		if goerrors.Is(err, slack.ErrMissingScope) {
+			return r.manageError(ctx, channel, err, true)
+		}
+	}
+	if len(errorlist) > 0 {
+		outOfSync(channel, "MembersNotUpdated")
+		log.Error(pkgutil.MapErrorListToError(errorlist), "Error updating members of the channel")
+		return pkgutil.ManageError(ctx, r.Client, channel, pkgutil.MapErrorListToError(errorlist))
+	}
+
+	if timedOut {
+		log.Info("Reconcile timed out while updating members", "timeout", channel.ReconcileTimeout())
+	} else if _, err := r.reconcileCanvas(ctx, channel); err != nil {
+		log.Error(err, "Error updating channel canvas")
+		return r.manageError(ctx, channel, err, true)
+	}
 
-	name := channel.Spec.Name
-	users := channel.Spec.Users
-	topic := channel.Spec.Topic
-	description := channel.Spec.Description
+	if pendingInvites+pendingRemovals > 0 {
+		log.Info("Membership sync continues on the next reconcile", "pendingInvites", pendingInvites, "pendingRemovals", pendingRemovals)
+		recordMembershipSync(channel, plan.Members, changed, pendingInvites, pendingRemovals)
+		// The members are compared again by the next reconcile
+		channel.Status.AppliedHash = ""
+		return r.manageSuccess(channel)
+	}
 
-	_, err := r.SlackService.RenameChannel(channelID, name)
+	channel.Status.MembershipSync = nil
+	r.recordApplied(channel, appliedHash)
+	return r.manageSuccess(channel)
+}
+
+// addPending adds the invites and removals of the parts to the pending ones
+func addPending(parts []*slack.MembershipPlan, pendingInvites, pendingRemovals int) (int, int) {
+	for _, part := range parts {
+		pendingInvites += len(part.Invite)
+		pendingRemovals += len(part.Remove)
+	}
+	return pendingInvites, pendingRemovals
+}
+
+// withReconcileTimeout returns a context which is done once the reconcile timeout of the channel passed, or ctx if
+// the channel has none
+func withReconcileTimeout(ctx context.Context, channel *slackv1alpha1.Channel) (context.Context, context.CancelFunc) {
+	if channel.ReconcileTimeout() == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, channel.ReconcileTimeout())
+}
+
+// continueLater stops a reconcile which ran out of time before the step, the next reconcile checks the channel again
+// and continues with the step
+func (r *ChannelReconciler) continueLater(ctx context.Context, channel *slackv1alpha1.Channel, step string) (ctrl.Result, error) {
+	r.Log.Info("Reconcile timed out, continuing on the next reconcile", "channel", channel.Namespace+"/"+channel.Name,
+		"timeout", channel.ReconcileTimeout(), "step", step)
+	outOfSync(channel, "ReconcileTimeout")
+	channel.Status.AppliedHash = ""
+
+	if err := r.Status().Update(ctx, channel); err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+	return reconcilerUtil.RequeueAfter(membershipSyncContinuePeriod)
+}
+
+// limitMemberChanges cuts the invites and removals of the plan to at most max changes, invites first, and returns the
+// number of invites and removals left for later reconciles. max 0 doesn't limit them.
+func limitMemberChanges(plan *slack.MembershipPlan, max int) (int, int) {
+	if max <= 0 || len(plan.Invite)+len(plan.Remove) <= max {
+		return 0, 0
+	}
+
+	pendingInvites := 0
+	if len(plan.Invite) > max {
+		pendingInvites = len(plan.Invite) - max
+		plan.Invite = plan.Invite[:max]
+	}
+	removals := max - len(plan.Invite)
+	pendingRemovals := len(plan.Remove) - removals
+	plan.Remove = plan.Remove[:removals]
+	return pendingInvites, pendingRemovals
+}
+
+// recordMembershipSync records the progress of a membership sync which continues on the next reconcile
+func recordMembershipSync(channel *slackv1alpha1.Channel, members, changed, pendingInvites, pendingRemovals int) {
+	progress := channel.Status.MembershipSync
+	if progress == nil {
+		progress = &slackv1alpha1.MembershipSyncStatus{StartedAt: metav1.Now()}
+	}
+	progress.Members = members
+	progress.Changed += changed
+	progress.PendingInvites, progress.PendingRemovals = pendingInvites, pendingRemovals
+	channel.Status.MembershipSync = progress
+}
+
+// handleExternalDeletion handles a channel which was deleted in Slack according to its onExternalDeletion policy.
+// Recreated channels forget the deleted channel and are created again by the next reconcile, adopted channels take
+// over the channel which has the name now.
+func (r *ChannelReconciler) handleExternalDeletion(ctx context.Context, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+	log := r.Log.WithValues("channel", channel.Namespace+"/"+channel.Name, "channelID", channel.Status.ID)
+	deletedID := channel.Status.ID
+
+	switch channel.Spec.OnExternalDeletion {
+	case slackv1alpha1.ExternalDeletionHold:
+		log.Info("Channel was deleted in Slack, holding reconciliation")
+		return r.hold(ctx, channel, metav1.Condition{
+			Type:    "Deleted",
+			Reason:  "DeletedInSlack",
+			Message: fmt.Sprintf("The channel %s was deleted in Slack, clear status.id to create it again", deletedID),
+		}, 0)
+	case slackv1alpha1.ExternalDeletionAdopt:
+		existing, err := r.SlackService.GetChannelByName(channel.SlackName())
+		if goerrors.Is(err, slack.ErrChannelNotFound) {
+			log.Info("Channel was deleted in Slack, waiting for a channel to adopt")
+			return r.hold(ctx, channel, metav1.Condition{
+				Type:    "Deleted",
+				Reason:  "NothingToAdopt",
+				Message: fmt.Sprintf("The channel %s was deleted in Slack and there is no channel named %s to adopt", deletedID, channel.SlackName()),
+			}, nameConflictCheckPeriod)
+		}
+		if err != nil {
+			return r.manageError(ctx, channel, err, true)
+		}
+		log.Info("Channel was deleted in Slack, adopting the channel with its name", "adoptedID", existing.ID)
+		forgetChannel(channel)
+		channel.Status.ID = existing.ID
+	default:
+		log.Info("Channel was deleted in Slack, creating it again")
+		forgetChannel(channel)
+		channel.Status.NameSuffix = ""
+	}
+
+	if r.Recorder != nil {
+		r.Recorder.Eventf(channel, corev1.EventTypeWarning, "DeletedInSlack", "The slack channel %s was deleted, onExternalDeletion is %s",
+			deletedID, channel.Spec.OnExternalDeletion)
+	}
+	if err := r.Status().Update(ctx, channel); err != nil {
+		return r.manageError(ctx, channel, err, true)
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// forgetChannel clears the status of the slack channel the Channel manages
+func forgetChannel(channel *slackv1alpha1.Channel) {
+	channel.Status.ID = ""
+	channel.Status.CanvasID, channel.Status.CanvasHash = "", ""
+	channel.Status.AppliedHash, channel.Status.SlackUpdated = "", 0
+	channel.Status.MemberErrors = nil
+	channel.Status.Drift = nil
+	channel.Status.Activity = nil
+	channel.Status.Clone = nil
+	channel.Status.Section = ""
+	channel.Status.AutoArchive = nil
+	channel.Status.MergedInto = ""
+}
+
+// createWithSuffix creates the channel with the first free name of name-2 to name-<maxNameSuffix> and returns the
+// suffix and the ID of the channel
+func (r *ChannelReconciler) createWithSuffix(name string, private bool) (string, *string, error) {
+	for i := 2; i <= maxNameSuffix; i++ {
+		suffix := fmt.Sprintf("-%d", i)
+		channelID, err := r.SlackService.CreateChannel(name+suffix, private)
+		if goerrors.Is(err, slack.ErrNameTaken) {
+			continue
+		}
+		return suffix, channelID, err
+	}
+	return "", nil, fmt.Errorf("The names %s-2 to %s-%d are taken as well", name, name, maxNameSuffix)
+}
+
+// describeExisting describes the slack channel holding the name of a new channel
+func describeExisting(existing *slackapi.Channel) string {
+	if existing.IsArchived {
+		return fmt.Sprintf("the archived channel %s", existing.ID)
+	}
+	return fmt.Sprintf("the channel %s", existing.ID)
+}
+
+// retryPropagation calls fn until it doesn't fail with channel_not_found, with propagationBackoff between the calls.
+// Channels take a moment to propagate through Slack after they were created.
+func retryPropagation(fn func() error) error {
+	return retry.OnError(propagationBackoff, func(err error) bool {
+		return goerrors.Is(err, slack.ErrChannelNotFound)
+	}, fn)
+}
+
+// claimChannel stamps the slack channel with the managed-by marker of the channel unless it has one. The
+// namespace/name of the Channel in the marker is returned if it's another Channel.
+func (r *ChannelReconciler) claimChannel(channelID string, channel *slackv1alpha1.Channel) (string, error) {
+	owner := channel.Namespace + "/" + channel.Name
+
+	managedBy, err := r.SlackService.GetManagedBy(channelID)
 	if err != nil {
-		log.Error(err, "Error renaming channel")
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		return "", err
+	}
+	if managedBy == owner {
+		return "", nil
+	}
+	if managedBy != "" {
+		return managedBy, nil
 	}
 
-	_, err = r.SlackService.SetTopic(channelID, topic)
+	return "", r.SlackService.AddManagedByMarker(channelID, owner)
+}
+
+// holdManagedByOther leaves slack channels managed by another Channel alone
+func (r *ChannelReconciler) holdManagedByOther(ctx context.Context, channel *slackv1alpha1.Channel, managedBy string) (ctrl.Result, error) {
+	r.Log.Info("Slack channel is managed by another Channel", "channel", channel.Namespace+"/"+channel.Name, "managedBy", managedBy)
+	return r.hold(ctx, channel, metav1.Condition{
+		Type:    "ManagedByOther",
+		Reason:  "MarkerMismatch",
+		Message: fmt.Sprintf("The slack channel is marked as managed by Channel %s, it isn't changed", managedBy),
+	}, nameConflictCheckPeriod)
+}
+
+// manageError reports the error in the status of the channel. Errors for missing scopes are held with the
+// MissingScope condition instead of being retried, they persist until the token is granted the scope and the
+// operator is restarted with it.
+func (r *ChannelReconciler) manageError(ctx context.Context, channel *slackv1alpha1.Channel, err error, retry bool) (ctrl.Result, error) {
+	var missingScope *slack.MissingScopeError
+	if goerrors.As(err, &missingScope) {
+		r.Log.Info("Token is missing a scope", "method", missingScope.Method, "scope", missingScope.Needed)
+		return r.hold(ctx, channel, metav1.Condition{
+			Type:    "MissingScope",
+			Reason:  "MissingScope",
+			Message: fmt.Sprintf("The token needs the %s scope to call %s, grant it to the Slack app", missingScope.Needed, missingScope.Method),
+		}, 0)
+	}
+
+	if pkgutil.IsApplyConflict(err) {
+		r.Log.Info("Fields of the channel are managed by another tool", "channel", channel.Namespace+"/"+channel.Name, "error", err.Error())
+		return r.hold(ctx, channel, metav1.Condition{
+			Type:    "ApplyConflict",
+			Reason:  "FieldManagerConflict",
+			Message: fmt.Sprintf("The operator's changes conflict with fields managed by another tool: %v", err),
+		}, applyConflictRetryPeriod)
+	}
+
+	r.recordHistory(channel)
+	channel.Status.FailedReconciles++
+	if r.MaxFailedReconciles > 0 && channel.Status.FailedReconciles >= r.MaxFailedReconciles {
+		return r.deadLetter(ctx, channel, err)
+	}
+	outOfSync(channel, "ReconcileError")
+	return reconcilerUtil.ManageError(r.Client, channel, err, retry)
+}
+
+// hold reports the condition which keeps the channel from being reconciled and checks it again after period, a
+// zero period holds the channel until it changes
+func (r *ChannelReconciler) hold(ctx context.Context, channel *slackv1alpha1.Channel, condition metav1.Condition, period time.Duration) (ctrl.Result, error) {
+	condition.Status = metav1.ConditionTrue
+	condition.LastTransitionTime = metav1.Now()
+	channel.SetReconcileStatus([]metav1.Condition{condition})
+	channel.Status.AppliedHash = ""
+	outOfSync(channel, condition.Reason)
+
+	err := r.Status().Update(ctx, channel)
 	if err != nil {
-		log.Error(err, "Error setting channel topic")
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		return reconcilerUtil.ManageError(r.Client, channel, err, true)
 	}
 
-	_, err = r.SlackService.SetDescription(channelID, description)
+	if period == 0 {
+		return ctrl.Result{}, nil
+	}
+	return reconcilerUtil.RequeueAfter(resync.Spread(channel.Namespace+"/"+channel.Name, period))
+}
+
+// appliedHash hashes the spec and the desired members of the channel, along with the operator's protected users and
+// the defaults of the ChannelPolicies
+func (r *ChannelReconciler) appliedHash(ctx context.Context, channel *slackv1alpha1.Channel) (string, error) {
+	users, _, err := r.members(ctx, channel)
 	if err != nil {
-		log.Error(err, "Error setting channel description")
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		return "", err
 	}
 
-	errorlist := r.SlackService.InviteUsers(channelID, users)
-	if len(errorlist) > 0 {
-		log.Error(err, "Error inviting users to channel")
-		return pkgutil.ManageError(ctx, r.Client, channel, pkgutil.MapErrorListToError(errorlist))
+	data, err := json.Marshal(struct {
+		Spec           slackv1alpha1.ChannelSpec
+		Members        []string
+		ProtectedUsers []string
+		Policy         *slackv1alpha1.ChannelPolicyStatus
+	}{channel.Spec, users, r.ProtectedUsers, channel.Status.Policy})
+	if err != nil {
+		return "", err
 	}
 
-	err = r.SlackService.RemoveUsers(channelID, users)
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// recordApplied stores the hash of the applied spec and the change timestamp of the slack channel on the status,
+// true is returned if they changed. Channels with member errors aren't recorded, so their drift is checked until
+// all users could be added.
+func (r *ChannelReconciler) recordApplied(channel *slackv1alpha1.Channel, appliedHash string) bool {
+	previousHash, previousUpdated := channel.Status.AppliedHash, channel.Status.SlackUpdated
+
+	channel.Status.AppliedHash, channel.Status.SlackUpdated = "", 0
+	if len(channel.Status.MemberErrors) == 0 && len(channel.Status.DeferredActions) == 0 {
+		slackUpdated, err := r.SlackService.GetChannelUpdated(channel.Status.ID)
+		if err != nil {
+			r.Log.Error(err, "Error fetching channel, its drift is checked on the next reconcile", "channelID", channel.Status.ID)
+		} else {
+			channel.Status.AppliedHash, channel.Status.SlackUpdated = appliedHash, slackUpdated
+		}
+	}
+
+	return channel.Status.AppliedHash != previousHash || channel.Status.SlackUpdated != previousUpdated
+}
+
+// members returns the desired members of the channel, users with a UserOffboarding or forbidden by a ChannelPolicy
+// are left out and returned as member errors
+func (r *ChannelReconciler) members(ctx context.Context, channel *slackv1alpha1.Channel) ([]string, []slackv1alpha1.MemberError, error) {
+	offboardedEmails, err := pkgutil.GetOffboardedEmails(ctx, r.Client)
 	if err != nil {
-		log.Error(err, "Error removing users from the channel")
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		return nil, nil, err
+	}
+
+	var users []string
+	var skipped []slackv1alpha1.MemberError
+	for _, user := range channel.Members() {
+		if offboardedEmails[strings.ToLower(user)] {
+			skipped = append(skipped, slackv1alpha1.MemberError{User: user, Reason: slack.MemberOffboarded})
+		} else if channel.Status.Policy.Forbids(user) {
+			skipped = append(skipped, slackv1alpha1.MemberError{User: user, Reason: slack.MemberForbidden})
+		} else {
+			users = append(users, user)
+		}
+	}
+
+	return users, skipped, nil
+}
+
+// desiredMetadata returns the channel with the name suffix it was created with and with the topic and description
+// truncated to fit into slack if the spec asks for it, the truncated fields are recorded on the status
+func (r *ChannelReconciler) desiredMetadata(channel *slackv1alpha1.Channel) *slackv1alpha1.Channel {
+	desired := channel
+	if channel.Status.NameSuffix != "" {
+		desired = channel.DeepCopy()
+		desired.Spec.Name = channel.SlackName()
+	}
+	if !channel.Spec.TruncateLongFields {
+		channel.Status.TruncatedFields = nil
+		return desired
+	}
+
+	desired, truncatedFields := slackv1alpha1.TruncateLongFields(desired)
+	channel.Status.TruncatedFields = truncatedFields
+	return desired
+}
+
+// planMembership compares the desired members of the channel with its actual members, users with a
+// UserOffboarding or forbidden by a ChannelPolicy are reported as member errors. Only the members of the
+// ChannelPolicies are managed for channels which don't manage members.
+func (r *ChannelReconciler) planMembership(ctx context.Context, deadline context.Context, channel *slackv1alpha1.Channel) (*slack.MembershipPlan, error) {
+	if !channel.ManagesMembers() {
+		return r.planPolicyMembership(deadline, channel)
+	}
+
+	users, skipped, err := r.members(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := r.SlackService.PlanMembership(deadline, channel.Status.ID, users, channel.Spec.EmailAliases, r.protectedUsers(channel))
+	if err != nil {
+		return nil, err
+	}
+	plan.MemberErrors = append(skipped, plan.MemberErrors...)
+
+	return plan, nil
+}
+
+// protectedUsers returns the users of the operator and of the channel which must never be removed, users of the
+// channel forbidden by a ChannelPolicy aren't protected
+func (r *ChannelReconciler) protectedUsers(channel *slackv1alpha1.Channel) []string {
+	protected := append([]string{}, r.ProtectedUsers...)
+	for _, user := range channel.Spec.ProtectedUsers {
+		if !channel.Status.Policy.Forbids(user) {
+			protected = append(protected, user)
+		}
+	}
+	return protected
+}
+
+// manageSuccess updates the status of the channel and requeues it for the next expiring temporary user
+func (r *ChannelReconciler) manageSuccess(channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+	observeConvergence(channel)
+	channel.Status.ObservedGeneration = channel.Generation
+	channel.Status.FailedReconciles = 0
+	channel.Status.Drift = nil
+	channel.Status.LastHandledReconcileAt = channel.Annotations[slackv1alpha1.ReconcileAtAnnotation]
+	r.recordHistory(channel)
+	result, err := reconcilerUtil.ManageSuccess(r.Client, channel)
+	if err != nil {
+		return result, err
+	}
+	return r.requeueForExpiration(channel)
+}
+
+// recordDrift records the fields of the slack channel which differ from the spec, they are kept until the channel
+// is synced successfully
+func recordDrift(channel *slackv1alpha1.Channel, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	outOfSync(channel, "")
+	channel.Status.Drift.Fields = fields
+}
+
+// visibility names the kind of a channel in messages
+func visibility(private bool) string {
+	if private {
+		return "private"
+	}
+	return "public"
+}
+
+// recordChanges logs the fields of the slack channel which were changed to match the spec and records them as events
+// on the channel
+func (r *ChannelReconciler) recordChanges(channel *slackv1alpha1.Channel, changes []slack.FieldChange) {
+	for _, change := range changes {
+		r.Log.Info("Updated field of the slack channel", "channel", channel.Namespace+"/"+channel.Name,
+			"field", change.Field, "old", change.Old, "new", change.New)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(channel, corev1.EventTypeNormal, "FieldUpdated", "Changed the %s of the slack channel from %q to %q",
+				change.Field, change.Old, change.New)
+		}
+	}
+}
+
+// outOfSync marks the slack channel as out of sync for the reason, keeping the time it first went out of sync
+func outOfSync(channel *slackv1alpha1.Channel, reason string) {
+	if channel.Status.ID == "" {
+		return
+	}
+	if channel.Status.Drift == nil {
+		channel.Status.Drift = &slackv1alpha1.ChannelDrift{Since: metav1.Now()}
+	}
+	if reason != "" {
+		channel.Status.Drift.Reason = reason
+	}
+}
+
+// recordHistory appends the changes made to the slack channel since the last status update to the status history,
+// keeping the latest AuditHistory.Size changes
+func (r *ChannelReconciler) recordHistory(channel *slackv1alpha1.Channel) {
+	if r.AuditHistory == nil || channel.Status.ID == "" {
+		return
 	}
 
-	return reconcilerUtil.ManageSuccess(r.Client, channel)
+	history := channel.Status.History
+	for _, event := range r.AuditHistory.Drain(channel.Status.ID) {
+		history = append(history, slackv1alpha1.ChannelAction{
+			Time:   metav1.NewTime(event.Time),
+			Action: string(event.Action),
+			Users:  event.Users,
+			Value:  event.Value,
+		})
+	}
+	if len(history) > r.AuditHistory.Size {
+		history = history[len(history)-r.AuditHistory.Size:]
+	}
+	channel.Status.History = history
 }
 
-func (r *ChannelReconciler) finalizeChannel(req ctrl.Request, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+// requeueForExpiration requeues the channel when the next temporary user expires or a member schedule starts or
+// ends, so members are added and removed in time.
+// Channels with SCIM groups are requeued periodically to pick up changes of the groups, spread over the period so
+// they don't all call the APIs at once.
+func (r *ChannelReconciler) requeueForExpiration(channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+	requeueAfter := time.Duration(0)
+	if len(channel.Spec.SCIMGroups) > 0 {
+		requeueAfter = resync.Spread(channel.Namespace+"/"+channel.Name, groupResyncPeriod)
+	}
+
+	next := membership.NextExpiration(channel.Status.TemporaryUsers, time.Now())
+	if next != nil && (requeueAfter == 0 || time.Until(*next) < requeueAfter) {
+		requeueAfter = time.Until(*next)
+	}
+	next = membership.NextScheduleChange(channel.Spec.MemberSchedules, time.Now())
+	if next != nil && (requeueAfter == 0 || time.Until(*next) < requeueAfter) {
+		requeueAfter = time.Until(*next)
+	}
+	if channel.Status.MembershipSync != nil && (requeueAfter == 0 || membershipSyncContinuePeriod < requeueAfter) {
+		requeueAfter = membershipSyncContinuePeriod
+	}
+	if len(channel.Status.DeferredActions) > 0 {
+		opens := r.MaintenanceWindows.NextOpen(time.Now())
+		if requeueAfter == 0 || time.Until(opens) < requeueAfter {
+			requeueAfter = time.Until(opens)
+		}
+	}
+
+	if requeueAfter == 0 {
+		return reconcilerUtil.DoNotRequeue()
+	}
+	return reconcilerUtil.RequeueAfter(requeueAfter)
+}
+
+// getGroupMembers returns the sorted emails of the members of the SCIM groups of the channel
+func (r *ChannelReconciler) getGroupMembers(channel *slackv1alpha1.Channel) ([]string, error) {
+	if len(channel.Spec.SCIMGroups) == 0 {
+		return nil, nil
+	}
+	if r.SCIMClient == nil {
+		return nil, fmt.Errorf("SCIM groups can't be used because SCIM isn't configured for the operator")
+	}
+
+	members := map[string]bool{}
+	for _, group := range channel.Spec.SCIMGroups {
+		emails, err := r.SCIMClient.GetGroupMemberEmails(group)
+		if err != nil {
+			return nil, err
+		}
+		for _, email := range emails {
+			members[email] = true
+		}
+	}
+
+	emails := []string{}
+	for email := range members {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	return emails, nil
+}
+
+// retentionDrifted checks whether the retention of the slack channel differs from the spec, it isn't checked when
+// the spec has no retention
+func (r *ChannelReconciler) retentionDrifted(channel *slackv1alpha1.Channel) (bool, error) {
+	if channel.Spec.Retention == nil {
+		return false, nil
+	}
+	days, err := r.SlackService.GetRetention(channel.Status.ID)
+	if err != nil {
+		return false, err
+	}
+	return days != channel.Spec.Retention.DesiredDays(), nil
+}
+
+// sectionDrifted checks whether the section recorded for the slack channel differs from the spec, it isn't checked
+// for channels which never had a section
+func (r *ChannelReconciler) sectionDrifted(channel *slackv1alpha1.Channel) (bool, error) {
+	if channel.Spec.Section == "" && channel.Status.Section == "" {
+		return false, nil
+	}
+	section, err := r.SlackService.GetSection(channel.Status.ID)
+	if err != nil {
+		return false, err
+	}
+	return section != channel.Spec.Section, nil
+}
+
+// reconcileCanvas creates or updates the channel canvas when its markdown changed, the new canvas ID and hash
+// are set on the status and true is returned when the canvas was written
+func (r *ChannelReconciler) reconcileCanvas(ctx context.Context, channel *slackv1alpha1.Channel) (bool, error) {
+	if channel.Spec.Canvas == nil {
+		return false, nil
+	}
+
+	markdown, err := r.getCanvasMarkdown(ctx, channel)
+	if err != nil {
+		return false, err
+	}
+
+	hash := sha256.Sum256([]byte(markdown))
+	canvasHash := hex.EncodeToString(hash[:])
+	if channel.Status.CanvasID != "" && channel.Status.CanvasHash == canvasHash {
+		return false, nil
+	}
+
+	if channel.Status.CanvasID == "" {
+		r.Log.Info("Creating channel canvas", "channelID", channel.Status.ID)
+		canvasID, err := r.SlackService.CreateChannelCanvas(channel.Status.ID, markdown)
+		if err != nil {
+			return false, err
+		}
+		channel.Status.CanvasID = canvasID
+	} else {
+		r.Log.Info("Updating channel canvas", "channelID", channel.Status.ID, "canvasID", channel.Status.CanvasID)
+		err = r.SlackService.EditCanvas(channel.Status.CanvasID, markdown)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	channel.Status.CanvasHash = canvasHash
+	return true, nil
+}
+
+// getCanvasMarkdown reads the canvas markdown from its source
+func (r *ChannelReconciler) getCanvasMarkdown(ctx context.Context, channel *slackv1alpha1.Channel) (string, error) {
+	canvas := channel.Spec.Canvas
+	if canvas.ConfigMapRef == nil {
+		return canvas.Markdown, nil
+	}
+
+	return r.getConfigMapValue(ctx, channel.Namespace, *canvas.ConfigMapRef)
+}
+
+// getConfigMapValue reads a key of a ConfigMap in the namespace
+func (r *ChannelReconciler) getConfigMapValue(ctx context.Context, namespace string, ref slackv1alpha1.KeyReference) (string, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, key, configMap); err != nil {
+		return "", fmt.Errorf("Error fetching ConfigMap %s: %v", key, err)
+	}
+
+	value, found := configMap.Data[ref.Key]
+	if !found {
+		return "", fmt.Errorf("ConfigMap %s has no key %s", key, ref.Key)
+	}
+
+	return value, nil
+}
+
+// renderTemplates renders a templated topic and description into the spec of the channel, so they are applied and
+// checked for drift like any other topic and description. The stored spec keeps the templates, the spec of the
+// channel must not be written back once they are rendered. Channels with an on-call schedule and no topic get the
+// DefaultOnCallTopic, or the DefaultSourceTopic when they have a topic source.
+func (r *ChannelReconciler) renderTemplates(ctx context.Context, channel *slackv1alpha1.Channel) error {
+	if channel.Spec.TopicFrom != nil && channel.Spec.Topic == "" {
+		channel.Spec.Topic = slackv1alpha1.DefaultSourceTopic
+	}
+	if channel.Spec.OnCall != nil && channel.Spec.Topic == "" {
+		channel.Spec.Topic = slackv1alpha1.DefaultOnCallTopic
+	}
+	if !templating.IsTemplate(channel.Spec.Topic) && !templating.IsTemplate(channel.Spec.Description) {
+		return nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: channel.Namespace}, namespace); err != nil {
+		return fmt.Errorf("Error fetching namespace %s: %v", channel.Namespace, err)
+	}
+
+	values := map[string]string{}
+	for _, value := range channel.Spec.TemplateValues {
+		data, err := r.getConfigMapValue(ctx, channel.Namespace, value.ConfigMapKeyRef)
+		if err != nil {
+			return err
+		}
+		values[value.Name] = data
+	}
+
+	topicSource, err := r.getTopicSource(ctx, channel)
+	if err != nil {
+		return err
+	}
+
+	data := templating.Data{
+		Name:            channel.Name,
+		Namespace:       channel.Namespace,
+		Labels:          channel.Labels,
+		Annotations:     channel.Annotations,
+		NamespaceLabels: namespace.Labels,
+		Values:          values,
+		Env:             templating.Environment(os.Environ()),
+		OnCall:          channel.Status.OnCall.Mentions(),
+		TopicSource:     topicSource,
+		Now:             time.Now(),
+	}
+
+	topic, err := templating.Render("topic", channel.Spec.Topic, data)
+	if err != nil {
+		return err
+	}
+	description, err := templating.Render("description", channel.Spec.Description, data)
+	if err != nil {
+		return err
+	}
+
+	channel.Spec.Topic, channel.Spec.Description = topic, description
+	return nil
+}
+
+// channelsOf maps a ConfigMap to the Channels whose canvas, template values or topic source are read from it
+func (r *ChannelReconciler) channelsOf(obj client.Object) []reconcile.Request {
+	channelList := &slackv1alpha1.ChannelList{}
+	if err := r.List(context.Background(), channelList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Unable to list channels", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, channel := range channelList.Items {
+		if usesConfigMap(&channel, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}})
+		}
+	}
+
+	return requests
+}
+
+// usesConfigMap checks whether the canvas, a template value or the topic source of the channel is read from the
+// ConfigMap
+func usesConfigMap(channel *slackv1alpha1.Channel, name string) bool {
+	canvas := channel.Spec.Canvas
+	if canvas != nil && canvas.ConfigMapRef != nil && canvas.ConfigMapRef.Name == name {
+		return true
+	}
+	for _, value := range channel.Spec.TemplateValues {
+		if value.ConfigMapKeyRef.Name == name {
+			return true
+		}
+	}
+	source := channel.Spec.TopicFrom
+	return source != nil && source.ConfigMapKeyRef != nil && source.ConfigMapKeyRef.Name == name
+}
+
+// cloneChannel copies the prototype of a channel created with cloneFrom, it does nothing once the clone completed
+func (r *ChannelReconciler) cloneChannel(ctx context.Context, channel *slackv1alpha1.Channel) error {
+	source := channel.Spec.CloneFrom
+	if source == nil || channel.Status.Clone == nil || channel.Status.Clone.CompletedAt != nil {
+		return nil
+	}
+	if (source.ID == "") == (source.Name == "") {
+		return fmt.Errorf("Exactly one of cloneFrom.id and cloneFrom.name must be set")
+	}
+
+	sourceID := source.ID
+	if sourceID == "" {
+		prototype, err := r.SlackService.GetChannelByName(source.Name)
+		if err != nil {
+			return fmt.Errorf("Error fetching prototype channel %s: %v", source.Name, err)
+		}
+		sourceID = prototype.ID
+	}
+
+	clone, err := slack.CloneChannel(r.SlackService, sourceID, channel.Status.ID)
+	if err != nil {
+		return err
+	}
+	r.Log.Info("Cloned prototype channel", "channelID", channel.Status.ID, "source", sourceID,
+		"bookmarks", clone.Bookmarks, "pins", clone.Pins, "members", len(clone.Members))
+
+	channelPatchBase := client.MergeFrom(channel.DeepCopy())
+	channel.Status.Clone = &slackv1alpha1.CloneStatus{
+		Source:      sourceID,
+		Topic:       clone.Topic,
+		Description: clone.Description,
+		Members:     clone.Members,
+		CompletedAt: &metav1.Time{Time: time.Now()},
+	}
+	if err := r.patchStatus(ctx, channel, channelPatchBase); err != nil {
+		return err
+	}
+
+	useCloneDefaults(channel)
+	return nil
+}
+
+// useCloneDefaults sets the topic and description the spec leaves empty to those of the prototype of the channel
+func useCloneDefaults(channel *slackv1alpha1.Channel) {
+	clone := channel.Status.Clone
+	if clone == nil || clone.CompletedAt == nil {
+		return
+	}
+	if channel.Spec.Topic == "" {
+		channel.Spec.Topic = clone.Topic
+	}
+	if channel.Spec.Description == "" {
+		channel.Spec.Description = clone.Description
+	}
+}
+
+// patchStatus patches the status of the channel and keeps its spec, which may hold rendered templates
+func (r *ChannelReconciler) patchStatus(ctx context.Context, channel *slackv1alpha1.Channel, patch client.Patch) error {
+	spec := channel.Spec.DeepCopy()
+	err := r.Status().Patch(ctx, channel, patch)
+	channel.Spec = *spec
+	return err
+}
+
+// getTopicSource returns the text of the topic source of the channel, URLs are fetched by the TopicSourceReconciler
+func (r *ChannelReconciler) getTopicSource(ctx context.Context, channel *slackv1alpha1.Channel) (string, error) {
+	source := channel.Spec.TopicFrom
+	if source == nil {
+		return "", nil
+	}
+	if (source.URL == "") == (source.ConfigMapKeyRef == nil) {
+		return "", fmt.Errorf("Exactly one of topicFrom.url and topicFrom.configMapKeyRef must be set")
+	}
+
+	if source.ConfigMapKeyRef != nil {
+		return r.getConfigMapValue(ctx, channel.Namespace, *source.ConfigMapKeyRef)
+	}
+	if status := channel.Status.TopicSource; status != nil && status.URL == source.URL {
+		return status.Text, nil
+	}
+	return "", nil
+}
+
+// templatedChannelsOf maps a Namespace to its Channels with a templated topic or description
+func (r *ChannelReconciler) templatedChannelsOf(obj client.Object) []reconcile.Request {
+	channelList := &slackv1alpha1.ChannelList{}
+	if err := r.List(context.Background(), channelList, client.InNamespace(obj.GetName())); err != nil {
+		r.Log.Error(err, "Unable to list channels", "namespace", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, channel := range channelList.Items {
+		if templating.IsTemplate(channel.Spec.Topic) || templating.IsTemplate(channel.Spec.Description) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}})
+		}
+	}
+
+	return requests
+}
+
+func (r *ChannelReconciler) finalizeChannel(ctx context.Context, req ctrl.Request, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
 	if channel == nil {
 		return reconcilerUtil.DoNotRequeue()
 	}
@@ -217,30 +1541,110 @@ func (r *ChannelReconciler) finalizeChannel(req ctrl.Request, channel *slackv1al
 	channelID := channel.Status.ID
 	log := r.Log.WithValues("channelID", channelID)
 
-	err := error(nil)
-	log.Info("Archiving channel is disabled")
+	if channel.Spec.DefaultForNewMembers && channelID != "" {
+		log.Info("Removing channel from the default channels")
+		if _, err := slack.UpdateDefaultChannels(r.SlackService, nil, []string{channelID}); err != nil {
+			return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		}
+	}
 
-	if err != nil && err.Error() != "channel_not_found" && err.Error() != "already_archived" {
-		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+	if canary := channel.Status.Canary; canary != nil && canary.ID != "" && r.CanaryService != nil {
+		log.Info("Archiving canary channel", "canaryID", canary.ID)
+		err := r.CanaryService.ArchiveChannel(canary.ID)
+		if err != nil && !goerrors.Is(err, slack.ErrChannelNotFound) && !goerrors.Is(err, slack.ErrAlreadyArchived) {
+			return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		}
 	}
 
-	// Base object for patch, which patches using the merge-patch strategy with the given object as base.
-	channelPatchBase := client.MergeFrom(channel.DeepCopy())
+	err := error(nil)
+	if channel.Spec.DeletionPolicy == slackv1alpha1.DeletionArchive && channelID != "" {
+		// Messages are exported before the channel is archived
+		var pending bool
+		pending, err = r.exportsPending(ctx, channel)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, channel, err, false)
+		}
+		if pending {
+			log.Info("Waiting for the messages to be exported before archiving the channel")
+			return reconcilerUtil.RequeueAfter(exportWaitPeriod)
+		}
 
-	finalizerUtil.DeleteFinalizer(channel, channelFinalizer)
-	log.V(1).Info("Finalizer removed for channel")
+		if now := time.Now(); !r.MaintenanceWindows.Open(now) {
+			opens := r.MaintenanceWindows.NextOpen(now)
+			log.Info("Archiving the channel waits for the next maintenance window", "opens", opens)
+			return reconcilerUtil.RequeueAfter(opens.Sub(now))
+		}
+
+		log.Info("Archiving channel")
+		err = r.SlackService.ArchiveChannel(channelID)
+	} else {
+		log.Info("Retaining channel")
+	}
 
-	err = r.Client.Patch(context.Background(), channel, channelPatchBase)
+	if err != nil && !goerrors.Is(err, slack.ErrChannelNotFound) && !goerrors.Is(err, slack.ErrAlreadyArchived) {
+		return reconcilerUtil.ManageError(r.Client, channel, err, false)
+	}
+
+	err = r.applyFinalizer(ctx, channel, false)
 	if err != nil {
 		return reconcilerUtil.ManageError(r.Client, channel, err, false)
 	}
+	log.V(1).Info("Finalizer removed for channel")
 
 	return reconcilerUtil.DoNotRequeue()
 }
 
+// applyFinalizer adds or removes the finalizer of the channel with server-side apply, finalizers of other tools are
+// kept. Finalizers added with a merge patch by earlier versions of the operator aren't owned by the apply, they are
+// removed with a patch.
+func (r *ChannelReconciler) applyFinalizer(ctx context.Context, channel *slackv1alpha1.Channel, add bool) error {
+	fields := map[string]interface{}{}
+	if add {
+		fields["metadata"] = map[string]interface{}{"finalizers": []interface{}{channelFinalizer}}
+	}
+	if err := pkgutil.Apply(ctx, r.Client, channel, finalizerFieldManager, fields, false); err != nil {
+		return err
+	}
+	if add || !finalizerUtil.HasFinalizer(channel, channelFinalizer) {
+		return nil
+	}
+
+	patchBase := client.MergeFromWithOptions(channel.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	finalizerUtil.DeleteFinalizer(channel, channelFinalizer)
+	return r.Client.Patch(ctx, channel, patchBase)
+}
+
+// exportsPending checks whether a ConversationExport still has to export the messages of the deleted Channel
+func (r *ChannelReconciler) exportsPending(ctx context.Context, channel *slackv1alpha1.Channel) (bool, error) {
+	exports := &slackv1alpha1.ConversationExportList{}
+	if err := r.List(ctx, exports); err != nil {
+		return false, err
+	}
+	for i := range exports.Items {
+		export := &exports.Items[i]
+		if pkgutil.ChannelReferenceKey(export.Spec.Channel, export.Namespace) == client.ObjectKeyFromObject(channel) &&
+			export.DeletionTimestamp == nil && export.PendingBeforeArchive(channel) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // SetupWithManager - Controller-Manager binding configuration
 func (r *ChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&slackv1alpha1.Channel{}).
+	r.driftScans = resync.NewGate(r.MaxConcurrentReconciles / 2)
+	registerDeadLetterGauge(mgr.GetClient())
+	registerChannelStateGauge(mgr.GetClient(), r.Shard)
+
+	blder := ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.Channel{}, builder.WithPredicates(r.Shard.Predicate())).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.channelsOf)).
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.templatedChannelsOf)).
+		Watches(&source.Kind{Type: &slackv1alpha1.ChannelPolicy{}}, handler.EnqueueRequestsFromMapFunc(r.channelsOfPolicy))
+	if r.ExternalChanges != nil {
+		blder = blder.Watches(&source.Channel{Source: r.ExternalChanges}, &handler.EnqueueRequestForObject{})
+	}
+	return blder.
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
@@ -2,13 +2,13 @@ package controllers
 
 import (
 	"context"
-	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
 	"github.com/stakater/slack-operator/pkg/slack/mock"
 	slackMock "github.com/stakater/slack-operator/pkg/slack/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -32,8 +32,11 @@ var _ = Describe("ChannelController", func() {
 				channel := util.GetChannel(channelName, ns)
 
 				Expect(channel.Status.ID).To(Equal(slackMock.PublicConversationID))
-				Expect(len(channel.Status.Conditions)).To(Equal(1))
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+				Expect(channel.Status.Phase).To(Equal(slackv1alpha1.ChannelReady))
+				Expect(channel.Status.Conditions[1].Type).To(Equal(slackv1alpha1.ReadyCondition))
+				Expect(channel.Status.Conditions[1].Status).To(Equal(metav1.ConditionTrue))
 			})
 		})
 
@@ -43,7 +46,7 @@ var _ = Describe("ChannelController", func() {
 				channel := util.GetChannel(channelName, ns)
 
 				Expect(channel.Status.ID).To(Equal(slackMock.PrivateConversationID))
-				Expect(len(channel.Status.Conditions)).To(Equal(1))
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
 			})
 		})
@@ -56,7 +59,7 @@ var _ = Describe("ChannelController", func() {
 				channel := util.GetChannel(channelName, ns)
 
 				Expect(channel.Spec.Description).To(Equal(description))
-				Expect(len(channel.Status.Conditions)).To(Equal(1))
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
 			})
 		})
@@ -69,7 +72,7 @@ var _ = Describe("ChannelController", func() {
 				channel := util.GetChannel(channelName, ns)
 
 				Expect(channel.Spec.Topic).To(Equal(topic))
-				Expect(len(channel.Status.Conditions)).To(Equal(1))
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
 			})
 		})
@@ -80,18 +83,18 @@ var _ = Describe("ChannelController", func() {
 				_ = util.CreateChannel(channelName, true, "", "", []string{mock.ExistingUserEmail}, ns)
 				channel := util.GetChannel(channelName, ns)
 
-				Expect(len(channel.Status.Conditions)).To(Equal(1))
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
 			})
 
-			It("should set error condition when user does not exists", func() {
+			It("should report member error when user does not exists", func() {
 				emailList := []string{"nonexistent@slack.com"}
 				_ = util.CreateChannel(channelName, true, "", "", emailList, ns)
 				channel := util.GetChannel(channelName, ns)
 
-				Expect(len(channel.Status.Conditions)).To(Equal(1))
-				Expect(channel.Status.Conditions[0].Reason).To(Equal("Failed"))
-				Expect(channel.Status.Conditions[0].Message).To(Equal(fmt.Sprintf("Error fetching user by Email %s", emailList[0])))
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
+				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
+				Expect(channel.Status.MemberErrors).To(Equal([]slackv1alpha1.MemberError{{User: emailList[0], Reason: "NotFound"}}))
 			})
 		})
 	})
@@ -121,7 +124,7 @@ var _ = Describe("ChannelController", func() {
 
 				Expect(updatedChannel.Spec.Name).To(Equal(newName))
 
-				Expect(len(channel.Status.Conditions)).To(Equal(1))
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
 			})
 		})
@@ -134,7 +137,7 @@ var _ = Describe("ChannelController", func() {
 				channel := util.GetChannel(channelName, ns)
 
 				Expect(channel.Status.ID).ToNot(BeEmpty())
-				Expect(len(channel.Status.Conditions)).To(Equal(1))
+				Expect(len(channel.Status.Conditions)).To(Equal(2))
 				Expect(channel.Status.Conditions[0].Reason).To(Equal("Successful"))
 
 				util.DeleteChannel(channelName, ns)
@@ -145,5 +148,35 @@ var _ = Describe("ChannelController", func() {
 				Expect(err).To(HaveOccurred())
 			})
 		})
+
+		Context("When archiving the channel on slack fails", func() {
+			It("should keep the finalizer and report the error", func() {
+				channel := util.CreateSlackChannelObject(channelName, false, "", "", []string{mock.ExistingUserEmail}, ns)
+				channel.Spec.DeletionPolicy = slackv1alpha1.DeletionArchive
+				Expect(k8sClient.Create(ctx, channel)).To(Succeed())
+
+				req := reconcile.Request{NamespacedName: types.NamespacedName{Name: channelName, Namespace: ns}}
+				_, err := r.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+
+				channel = util.GetChannel(channelName, ns)
+				channel.Status.ID = mock.ArchiveFailingConversationID
+				Expect(k8sClient.Status().Update(ctx, channel)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, channel)).To(Succeed())
+
+				_, _ = r.Reconcile(context.Background(), req)
+
+				channel = util.GetChannel(channelName, ns)
+				Expect(channel.Finalizers).ToNot(BeEmpty())
+				var messages []string
+				for _, condition := range channel.Status.Conditions {
+					messages = append(messages, condition.Message)
+				}
+				Expect(messages).To(ContainElement(ContainSubstring("restricted_action")))
+
+				channel.Status.ID = slackMock.PublicConversationID
+				Expect(k8sClient.Status().Update(ctx, channel)).To(Succeed())
+			})
+		})
 	})
 })
@@ -0,0 +1,108 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+// deadLettered counts the Channels which were dead-lettered
+var deadLettered = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "slack_operator_dead_lettered_channels_total",
+	Help: "Number of times Channels stopped being reconciled after failing too many reconciles in a row",
+})
+
+func init() {
+	metrics.Registry.MustRegister(deadLettered)
+}
+
+// registerDeadLetterGauge exposes the number of Channels which are dead-lettered, counted from the cache on scrape
+func registerDeadLetterGauge(reader client.Reader) {
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "slack_operator_dead_lettered_channels",
+		Help: "Number of Channels which aren't reconciled because they failed too many reconciles in a row",
+	}, func() float64 {
+		channels := &slackv1alpha1.ChannelList{}
+		if err := reader.List(context.Background(), channels); err != nil {
+			return 0
+		}
+		count := 0
+		for _, channel := range channels.Items {
+			if channel.Status.DeadLetter != nil {
+				count++
+			}
+		}
+		return float64(count)
+	})
+	if err := metrics.Registry.Register(gauge); err != nil {
+		if _, registered := err.(prometheus.AlreadyRegisteredError); !registered {
+			panic(err)
+		}
+	}
+}
+
+// isDeadLettered checks whether the channel is dead-lettered and neither its spec nor the reconcile-at annotation
+// changed since, a released channel is reconciled again with its failures reset
+func isDeadLettered(channel *slackv1alpha1.Channel) bool {
+	deadLetter := channel.Status.DeadLetter
+	if deadLetter == nil {
+		return false
+	}
+	if channel.Generation == deadLetter.Generation && channel.Annotations[slackv1alpha1.ReconcileAtAnnotation] == deadLetter.ReconcileAt {
+		return true
+	}
+
+	channel.Status.DeadLetter = nil
+	channel.Status.FailedReconciles = 0
+	return false
+}
+
+// deadLetter stops reconciling the channel after too many failed reconciles in a row, until its spec or the
+// reconcile-at annotation changes
+func (r *ChannelReconciler) deadLetter(ctx context.Context, channel *slackv1alpha1.Channel, err error) (ctrl.Result, error) {
+	r.Log.Info("Channel failed too many reconciles, stopping to reconcile it", "channel", channel.Namespace+"/"+channel.Name,
+		"failures", channel.Status.FailedReconciles, "error", err.Error())
+
+	channel.Status.DeadLetter = &slackv1alpha1.DeadLetterStatus{
+		Since:       metav1.Now(),
+		Error:       err.Error(),
+		Generation:  channel.Generation,
+		ReconcileAt: channel.Annotations[slackv1alpha1.ReconcileAtAnnotation],
+	}
+	deadLettered.Inc()
+	if r.Recorder != nil {
+		r.Recorder.Eventf(channel, corev1.EventTypeWarning, "DeadLettered", "Channel failed %d reconciles in a row and isn't reconciled anymore: %v",
+			channel.Status.FailedReconciles, err)
+	}
+
+	return r.hold(ctx, channel, metav1.Condition{
+		Type:   "Failed",
+		Reason: "TooManyFailures",
+		Message: fmt.Sprintf("The channel failed %d reconciles in a row and isn't reconciled until its spec or the %s annotation changes: %v",
+			channel.Status.FailedReconciles, slackv1alpha1.ReconcileAtAnnotation, err),
+	}, 0)
+}
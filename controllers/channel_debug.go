@@ -0,0 +1,62 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+// ChannelClaim is a Channel claiming a slack channel name
+type ChannelClaim struct {
+	// Channel is the namespace and name of the Channel
+	Channel string `json:"channel"`
+	// ID is the ID of its slack channel
+	ID string `json:"id,omitempty"`
+	// Phase is the phase of the Channel
+	Phase slackv1alpha1.ChannelPhase `json:"phase,omitempty"`
+	// Owner is set for the Channel which manages the slack channel, the others are held with a NameConflict
+	Owner bool `json:"owner"`
+}
+
+// NameIndex returns the Channels in the cache by the normalized name of their slack channel, the owner first
+func (r *ChannelReconciler) NameIndex(ctx context.Context) (interface{}, error) {
+	channels := &slackv1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		return nil, err
+	}
+
+	index := map[string][]ChannelClaim{}
+	for i := range channels.Items {
+		channel := &channels.Items[i]
+		name := slackv1alpha1.NormalizeChannelName(channel.Spec.Name)
+		index[name] = append(index[name], ChannelClaim{
+			Channel: channel.Namespace + "/" + channel.Name,
+			ID:      channel.Status.ID,
+			Phase:   channel.Status.Phase,
+			Owner:   channel.DeletionTimestamp == nil && slackv1alpha1.FindNameConflict(channel, channels.Items) == nil,
+		})
+	}
+	for _, claims := range index {
+		sort.SliceStable(claims, func(i, j int) bool {
+			return claims[i].Owner && !claims[j].Owner
+		})
+	}
+	return index, nil
+}
@@ -0,0 +1,72 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// externalChanges counts the changes of managed channels which were made outside the operator
+var externalChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "slack_operator_external_channel_changes_total",
+	Help: "Number of changes of managed Slack channels made outside the operator, by audit log action",
+}, []string{"action"})
+
+func init() {
+	metrics.Registry.MustRegister(externalChanges)
+}
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// ChangedInSlack handles an audit log entry of a slack channel changed outside the operator, an event is recorded
+// on the Channels of the slack channel and they are reconciled right away
+func (r *ChannelReconciler) ChangedInSlack(ctx context.Context, entry slack.AuditLogEntry) {
+	channelList := &slackv1alpha1.ChannelList{}
+	if err := r.List(ctx, channelList); err != nil {
+		r.Log.Error(err, "Unable to list channels")
+		return
+	}
+
+	for i := range channelList.Items {
+		channel := &channelList.Items[i]
+		if channel.Status.ID != entry.Entity.Channel.ID || !r.Shard.Owns(channel) {
+			continue
+		}
+
+		r.Log.Info("Channel was changed outside the operator", "channel", channel.Namespace+"/"+channel.Name,
+			"action", entry.Action, "actor", entry.Actor.User.ID)
+		externalChanges.WithLabelValues(entry.Action).Inc()
+		if r.Recorder != nil {
+			r.Recorder.Eventf(channel, corev1.EventTypeWarning, "ChangedInSlack", "Slack channel was changed outside the operator: %s by %s",
+				entry.Action, entry.Actor.User.ID)
+		}
+
+		select {
+		case r.ExternalChanges <- event.GenericEvent{Object: channel}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
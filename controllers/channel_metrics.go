@@ -0,0 +1,143 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/shard"
+)
+
+// Channel states of the slack_operator_channels gauge, every Channel is counted in one of them
+const (
+	stateReady    = "Ready"
+	stateDrifted  = "Drifted"
+	stateFailed   = "Failed"
+	stateArchived = "Archived"
+	stateHeld     = "Held"
+	statePending  = "Pending"
+)
+
+var channelStates = []string{stateReady, stateDrifted, stateFailed, stateArchived, stateHeld, statePending}
+
+// convergence observes how long Channels took to converge after their spec changed
+var convergence = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Name:    "slack_operator_channel_convergence_seconds",
+	Help:    "Time from a change of a Channel's spec until the slack channel matched it",
+	Buckets: prometheus.ExponentialBuckets(1, 2, 13),
+})
+
+func init() {
+	metrics.Registry.MustRegister(convergence)
+}
+
+// channelStateCollector counts the Channels of the shard by state from the cache on scrape
+type channelStateCollector struct {
+	reader client.Reader
+	shard  *shard.Shard
+	desc   *prometheus.Desc
+}
+
+// registerChannelStateGauge exposes the number of Channels the shard reconciles by state
+func registerChannelStateGauge(reader client.Reader, channelShard *shard.Shard) {
+	collector := &channelStateCollector{
+		reader: reader,
+		shard:  channelShard,
+		desc: prometheus.NewDesc("slack_operator_channels",
+			"Number of Channels by state: Ready, Drifted, Failed, Archived, Held or Pending", []string{"state"}, nil),
+	}
+	if err := metrics.Registry.Register(collector); err != nil {
+		if _, registered := err.(prometheus.AlreadyRegisteredError); !registered {
+			panic(err)
+		}
+	}
+}
+
+// Describe describes the gauge
+func (c *channelStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect counts the Channels by state, nothing is reported if they can't be listed
+func (c *channelStateCollector) Collect(ch chan<- prometheus.Metric) {
+	channels := &slackv1alpha1.ChannelList{}
+	if err := c.reader.List(context.Background(), channels); err != nil {
+		return
+	}
+
+	counts := map[string]int{}
+	for i := range channels.Items {
+		if c.shard.Owns(&channels.Items[i]) {
+			counts[channelState(&channels.Items[i])]++
+		}
+	}
+	for _, state := range channelStates {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[state]), state)
+	}
+}
+
+// channelState returns the state the channel is counted in
+func channelState(channel *slackv1alpha1.Channel) string {
+	switch {
+	case channel.Status.Phase == slackv1alpha1.ChannelFailed:
+		return stateFailed
+	case meta.IsStatusConditionTrue(channel.Status.Conditions, "Archived"):
+		return stateArchived
+	case channel.Status.Phase == slackv1alpha1.ChannelHeld:
+		return stateHeld
+	case channel.Status.Drift != nil:
+		return stateDrifted
+	case channel.Status.Phase == slackv1alpha1.ChannelReady:
+		return stateReady
+	}
+	return statePending
+}
+
+// observeConvergence observes the time since the spec of a channel changed, when the reconcile which is about to
+// succeed is the first one of its generation
+func observeConvergence(channel *slackv1alpha1.Channel) {
+	if channel.Status.ObservedGeneration == channel.Generation {
+		return
+	}
+	convergence.Observe(time.Since(specChangedAt(channel)).Seconds())
+}
+
+// specChangedAt returns the last time a field manager of the spec changed the channel, or its creation time
+func specChangedAt(channel *slackv1alpha1.Channel) time.Time {
+	changedAt := channel.CreationTimestamp.Time
+	for _, entry := range channel.ManagedFields {
+		if entry.Time == nil || !entry.Time.After(changedAt) || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, ownsSpec := fields["f:spec"]; ownsSpec {
+			changedAt = entry.Time.Time
+		}
+	}
+	return changedAt
+}
@@ -0,0 +1,137 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// applyChannelPolicies records the defaults of the ChannelPolicies applying to the channel on its status and appends
+// their topic suffixes to the topic of the spec, which isn't stored
+func (r *ChannelReconciler) applyChannelPolicies(ctx context.Context, channel *slackv1alpha1.Channel) error {
+	policies := &slackv1alpha1.ChannelPolicyList{}
+	if err := r.List(ctx, policies); err != nil {
+		return err
+	}
+
+	channel.Status.Policy = slackv1alpha1.ChannelPolicyDefaults(channel, policies.Items)
+	if channel.Status.Policy != nil {
+		channel.Spec.Topic = channel.Status.Policy.AppendTopicSuffixes(channel.Spec.Topic)
+	}
+	return nil
+}
+
+// missingPolicyBookmarks returns the bookmarks of the ChannelPolicies whose link the slack channel has no bookmark of
+func (r *ChannelReconciler) missingPolicyBookmarks(channel *slackv1alpha1.Channel) ([]slackv1alpha1.PolicyBookmark, error) {
+	if channel.Status.Policy == nil || len(channel.Status.Policy.Bookmarks) == 0 {
+		return nil, nil
+	}
+
+	bookmarks, err := r.SlackService.ListBookmarks(channel.Status.ID)
+	if err != nil {
+		return nil, err
+	}
+	links := map[string]bool{}
+	for _, bookmark := range bookmarks {
+		links[bookmark.Link] = true
+	}
+
+	var missing []slackv1alpha1.PolicyBookmark
+	for _, bookmark := range channel.Status.Policy.Bookmarks {
+		if !links[bookmark.Link] {
+			missing = append(missing, bookmark)
+		}
+	}
+	return missing, nil
+}
+
+// addPolicyBookmarks adds the missing bookmarks of the ChannelPolicies to the slack channel
+func (r *ChannelReconciler) addPolicyBookmarks(channel *slackv1alpha1.Channel, missing []slackv1alpha1.PolicyBookmark) error {
+	for _, bookmark := range missing {
+		err := r.SlackService.AddBookmark(channel.Status.ID, slack.Bookmark{Title: bookmark.Title, Link: bookmark.Link, Type: "link"})
+		if err != nil {
+			return err
+		}
+		r.recordChanges(channel, []slack.FieldChange{{Field: "bookmarks", New: bookmark.Title + " " + bookmark.Link}})
+	}
+	return nil
+}
+
+// planPolicyMembership plans the invites of the mandatory members and the removal of the forbidden members of the
+// ChannelPolicies for channels which don't manage their members otherwise, the operator's protected users are kept
+func (r *ChannelReconciler) planPolicyMembership(ctx context.Context, channel *slackv1alpha1.Channel) (*slack.MembershipPlan, error) {
+	policy := channel.Status.Policy
+	if policy == nil || (len(policy.Members) == 0 && len(policy.ForbiddenMembers) == 0) {
+		return &slack.MembershipPlan{}, nil
+	}
+
+	protected := map[string]bool{}
+	for _, user := range r.ProtectedUsers {
+		protected[strings.ToLower(user)] = true
+	}
+	var forbidden []string
+	for _, user := range policy.ForbiddenMembers {
+		if !protected[strings.ToLower(user)] {
+			forbidden = append(forbidden, user)
+		}
+	}
+	return r.SlackService.PlanPolicyMembership(ctx, channel.Status.ID, policy.Members, forbidden, channel.Spec.EmailAliases)
+}
+
+// channelsOfPolicy maps a ChannelPolicy to the Channels it applies to or applied to before
+func (r *ChannelReconciler) channelsOfPolicy(obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*slackv1alpha1.ChannelPolicy)
+	if !ok {
+		return nil
+	}
+
+	channels := &slackv1alpha1.ChannelList{}
+	if err := r.List(context.Background(), channels); err != nil {
+		r.Log.Error(err, "Error listing Channels")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range channels.Items {
+		channel := &channels.Items[i]
+		if r.Shard.Owns(channel) && (policy.AppliesTo(channel) || appliedPolicy(channel, policy.Name)) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}})
+		}
+	}
+	return requests
+}
+
+// appliedPolicy checks whether the policy applied to the channel when it was last reconciled
+func appliedPolicy(channel *slackv1alpha1.Channel, name string) bool {
+	if channel.Status.Policy == nil {
+		return false
+	}
+	for _, policy := range channel.Status.Policy.Policies {
+		if policy == name {
+			return true
+		}
+	}
+	return false
+}
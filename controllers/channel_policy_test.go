@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	"github.com/stakater/slack-operator/pkg/slack/mockservice"
+)
+
+var _ = Describe("ChannelPolicies", func() {
+
+	var service *mockservice.Service
+
+	newPolicyReconciler := func(objects ...client.Object) *ChannelReconciler {
+		policyScheme := runtime.NewScheme()
+		Expect(slackv1alpha1.AddToScheme(policyScheme)).To(Succeed())
+
+		service = &mockservice.Service{}
+		return &ChannelReconciler{
+			Client:       fake.NewClientBuilder().WithScheme(policyScheme).WithObjects(objects...).Build(),
+			Log:          log.WithName("ChannelPolicies"),
+			SlackService: service,
+		}
+	}
+
+	newPolicy := func(name string, spec slackv1alpha1.ChannelPolicySpec) *slackv1alpha1.ChannelPolicy {
+		return &slackv1alpha1.ChannelPolicy{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+	}
+
+	newPolicyChannel := func(namespace string, users ...string) *slackv1alpha1.Channel {
+		return &slackv1alpha1.Channel{
+			ObjectMeta: metav1.ObjectMeta{Name: "ops", Namespace: namespace},
+			Spec:       slackv1alpha1.ChannelSpec{Name: "ops", Topic: "Ops", Users: users},
+			Status:     slackv1alpha1.ChannelStatus{ID: "C0123"},
+		}
+	}
+
+	Describe("Applying the policies to a Channel", func() {
+		Context("With a topic suffix", func() {
+			It("should append the suffix once to the topic of the Channels it applies to", func() {
+				r := newPolicyReconciler(newPolicy("security", slackv1alpha1.ChannelPolicySpec{
+					Namespaces:  []string{"team-*"},
+					TopicSuffix: "| security: #sec",
+				}))
+
+				channel := newPolicyChannel("team-a")
+				Expect(r.applyChannelPolicies(context.Background(), channel)).To(Succeed())
+				Expect(channel.Spec.Topic).To(Equal("Ops | security: #sec"))
+				Expect(channel.Status.Policy.Policies).To(Equal([]string{"security"}))
+
+				Expect(r.applyChannelPolicies(context.Background(), channel)).To(Succeed())
+				Expect(channel.Spec.Topic).To(Equal("Ops | security: #sec"))
+
+				other := newPolicyChannel("platform")
+				Expect(r.applyChannelPolicies(context.Background(), other)).To(Succeed())
+				Expect(other.Spec.Topic).To(Equal("Ops"))
+				Expect(other.Status.Policy).To(BeNil())
+			})
+		})
+
+		Context("When the policy changes", func() {
+			It("should change the applied hash", func() {
+				r := newPolicyReconciler(newPolicy("security", slackv1alpha1.ChannelPolicySpec{
+					ForbiddenMembers: []string{"mallory@example.com"},
+				}))
+				channel := newPolicyChannel("team-a", "alice@example.com")
+				before, err := r.appliedHash(context.Background(), channel)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(r.applyChannelPolicies(context.Background(), channel)).To(Succeed())
+				after, err := r.appliedHash(context.Background(), channel)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(after).NotTo(Equal(before))
+			})
+		})
+	})
+
+	Describe("Planning the members of a Channel", func() {
+		Context("With forbidden members listed by a Channel which manages its members", func() {
+			It("should leave them out and not protect them", func() {
+				r := newPolicyReconciler(newPolicy("security", slackv1alpha1.ChannelPolicySpec{
+					ForbiddenMembers: []string{"mallory@example.com"},
+				}))
+				channel := newPolicyChannel("team-a", "alice@example.com", "Mallory@example.com")
+				channel.Spec.ProtectedUsers = []string{"mallory@example.com"}
+				Expect(r.applyChannelPolicies(context.Background(), channel)).To(Succeed())
+
+				plan, err := r.planMembership(context.Background(), context.Background(), channel)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.MemberErrors).To(ContainElement(slackv1alpha1.MemberError{User: "Mallory@example.com", Reason: slack.MemberForbidden}))
+
+				calls := service.Calls("PlanMembership")
+				Expect(calls).To(HaveLen(1))
+				Expect(calls[0].Args[1]).To(Equal([]string{"alice@example.com"}))
+				Expect(calls[0].Args[3]).To(BeEmpty())
+			})
+		})
+
+		Context("With mandatory members and a Channel which doesn't manage its members", func() {
+			It("should only plan the members of the policies", func() {
+				r := newPolicyReconciler(newPolicy("compliance", slackv1alpha1.ChannelPolicySpec{
+					Members:          []string{"compliance-bot@example.com"},
+					ForbiddenMembers: []string{"mallory@example.com", "admin@example.com"},
+				}))
+				r.ProtectedUsers = []string{"Admin@example.com"}
+				channel := newPolicyChannel("team-a")
+				Expect(r.applyChannelPolicies(context.Background(), channel)).To(Succeed())
+
+				_, err := r.planMembership(context.Background(), context.Background(), channel)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(service.Calls("PlanMembership")).To(BeEmpty())
+
+				calls := service.Calls("PlanPolicyMembership")
+				Expect(calls).To(HaveLen(1))
+				Expect(calls[0].Args[1]).To(Equal([]string{"compliance-bot@example.com"}))
+				Expect(calls[0].Args[2]).To(Equal([]string{"mallory@example.com"}))
+			})
+		})
+
+		Context("Without policies and a Channel which doesn't manage its members", func() {
+			It("should not change any members", func() {
+				r := newPolicyReconciler()
+				channel := newPolicyChannel("team-a")
+				Expect(r.applyChannelPolicies(context.Background(), channel)).To(Succeed())
+
+				plan, err := r.planMembership(context.Background(), context.Background(), channel)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Changed()).To(BeFalse())
+				Expect(service.Calls()).To(BeEmpty())
+			})
+		})
+	})
+})
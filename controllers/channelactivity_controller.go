@@ -0,0 +1,312 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/maintenance"
+	"github.com/stakater/slack-operator/pkg/shard"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// activityWindow is the period the recent messages of a channel are counted for
+const activityWindow = 7 * 24 * time.Hour
+
+// ChannelActivityReconciler records the activity of the slack channels of Channels and archives those which were
+// inactive for longer than their autoArchiveAfterInactivity or ArchivePolicy allows, after posting a warning to them
+type ChannelActivityReconciler struct {
+	client.Client
+	Log          logr.Logger
+	SlackService slack.Service
+
+	// ActivityPeriod is how often the activity in the status of Channels is refreshed, 0 disables it
+	ActivityPeriod time.Duration
+
+	// MaintenanceWindows restricts archiving inactive channels to maintenance windows, nil allows it at any time
+	MaintenanceWindows *maintenance.Windows
+
+	// Shard is the part of the Channels this deployment reconciles, nil to reconcile all of them
+	Shard *shard.Shard
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=archivepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels/status,verbs=get;update;patch
+
+// Reconcile refreshes the activity of the slack channel of a Channel, warns the channel once it's about to be
+// archived for inactivity and archives it once the warning period passed without activity
+func (r *ChannelActivityReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("channel", req.NamespacedName)
+
+	channel := &slackv1alpha1.Channel{}
+	err := r.Get(ctx, req.NamespacedName, channel)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	if !r.Shard.Owns(channel) || channel.GetDeletionTimestamp() != nil || channel.Status.ID == "" {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	var activityResult ctrl.Result
+	if r.ActivityPeriod > 0 {
+		activityResult, err = r.updateActivity(ctx, channel)
+		if err != nil {
+			return reconcilerUtil.RequeueWithError(err)
+		}
+	}
+
+	policies := &slackv1alpha1.ArchivePolicyList{}
+	if err := r.List(ctx, policies); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	autoArchive := slackv1alpha1.AutoArchiveFor(channel, policies.Items)
+	if autoArchive == nil {
+		if err := r.patchAutoArchive(ctx, channel, nil); err != nil {
+			return reconcilerUtil.RequeueWithError(err)
+		}
+		return activityResult, nil
+	}
+
+	result, err := r.checkInactivity(ctx, channel, autoArchive, log)
+	if err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	if activityResult.RequeueAfter > 0 && (result.RequeueAfter == 0 || activityResult.RequeueAfter < result.RequeueAfter) {
+		return activityResult, nil
+	}
+	return result, nil
+}
+
+// updateActivity refreshes the activity in the status of the Channel once it's older than the ActivityPeriod
+func (r *ChannelActivityReconciler) updateActivity(ctx context.Context, channel *slackv1alpha1.Channel) (ctrl.Result, error) {
+	now := time.Now()
+	if activity := channel.Status.Activity; activity != nil {
+		if next := activity.UpdatedAt.Add(r.ActivityPeriod); now.Before(next) {
+			return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+		}
+	}
+
+	activity, err := r.SlackService.ChannelActivity(channel.Status.ID, now.Add(-activityWindow))
+	if err != nil {
+		// Deleted channels are handled by the Channel controller
+		if goerrors.Is(err, slack.ErrChannelNotFound) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	patchBase := client.MergeFrom(channel.DeepCopy())
+	channel.Status.Activity = &slackv1alpha1.ChannelActivity{
+		Members:          activity.Members,
+		MessagesLastWeek: activity.Messages,
+		Sampled:          activity.Sampled,
+		UpdatedAt:        metav1.Time{Time: now},
+	}
+	if !activity.LastMessage.IsZero() {
+		channel.Status.Activity.LastMessageTime = &metav1.Time{Time: activity.LastMessage}
+	}
+	if err := r.Status().Patch(ctx, channel, patchBase); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.ActivityPeriod}, nil
+}
+
+// checkInactivity warns or archives the slack channel depending on its latest activity and records it in the
+// status of the Channel
+func (r *ChannelActivityReconciler) checkInactivity(ctx context.Context, channel *slackv1alpha1.Channel, autoArchive *slackv1alpha1.AutoArchive, log logr.Logger) (ctrl.Result, error) {
+	existingChannel, err := r.SlackService.GetChannel(channel.Status.ID)
+	if err != nil {
+		// Deleted channels are handled by the Channel controller
+		if goerrors.Is(err, slack.ErrChannelNotFound) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now()
+	state := channel.Status.AutoArchive.DeepCopy()
+	if state == nil {
+		state = &slackv1alpha1.AutoArchiveStatus{}
+	}
+
+	// Archived channels are checked until they are unarchived, inactivity is counted again from then on
+	if existingChannel.IsArchived {
+		return reconcilerUtil.RequeueAfter(archivedCheckPeriod)
+	}
+	if state.ArchivedAt != nil {
+		log.Info("Channel was unarchived after it was archived for inactivity")
+		state = &slackv1alpha1.AutoArchiveStatus{ActiveSince: &metav1.Time{Time: now}}
+	}
+
+	latest, err := r.SlackService.LatestActivity(channel.Status.ID, state.WarningTimestamp)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	activeSince := channel.CreationTimestamp.Time
+	if state.ActiveSince != nil && state.ActiveSince.After(activeSince) {
+		activeSince = state.ActiveSince.Time
+	}
+	if latest.After(activeSince) {
+		activeSince = latest
+	}
+
+	if state.WarnedAt != nil && activeSince.After(state.WarnedAt.Time) {
+		log.Info("Channel became active again after it was warned")
+		state.WarnedAt = nil
+		state.WarningTimestamp = ""
+	}
+
+	archiveAt := activeSince.Add(autoArchive.Inactivity)
+	if state.WarnedAt == nil {
+		warnAt := archiveAt.Add(-autoArchive.WarningBefore)
+		if now.Before(warnAt) {
+			if err := r.patchAutoArchive(ctx, channel, state); err != nil {
+				return ctrl.Result{}, err
+			}
+			return reconcilerUtil.RequeueAfter(warnAt.Sub(now))
+		}
+
+		// Channels warned late, e.g. when the policy was just created, still get the whole warning period
+		archiveAt = now.Add(autoArchive.WarningBefore)
+		text := warningText(autoArchive, activeSince, archiveAt)
+		timestamp, err := r.SlackService.PostMessage(channel.Status.ID, slackapi.MsgOptionText(text, false))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("Warned inactive channel", "archiveAt", archiveAt)
+
+		state.WarnedAt = &metav1.Time{Time: now}
+		state.WarningTimestamp = timestamp
+		if err := r.patchAutoArchive(ctx, channel, state); err != nil {
+			return ctrl.Result{}, err
+		}
+		return reconcilerUtil.RequeueAfter(archiveAt.Sub(now))
+	}
+
+	if warned := state.WarnedAt.Add(autoArchive.WarningBefore); warned.After(archiveAt) {
+		archiveAt = warned
+	}
+	if now.Before(archiveAt) {
+		if err := r.patchAutoArchive(ctx, channel, state); err != nil {
+			return ctrl.Result{}, err
+		}
+		return reconcilerUtil.RequeueAfter(archiveAt.Sub(now))
+	}
+
+	if !r.MaintenanceWindows.Open(now) {
+		if err := r.patchAutoArchive(ctx, channel, state); err != nil {
+			return ctrl.Result{}, err
+		}
+		opens := r.MaintenanceWindows.NextOpen(now)
+		log.Info("Archiving the inactive channel waits for the next maintenance window", "opens", opens)
+		return reconcilerUtil.RequeueAfter(opens.Sub(now))
+	}
+
+	// archivedAt is recorded first, so that the Channel controller doesn't unarchive the channel again
+	state.ArchivedAt = &metav1.Time{Time: now}
+	if err := r.patchAutoArchive(ctx, channel, state); err != nil {
+		return ctrl.Result{}, err
+	}
+	err = r.SlackService.ArchiveChannel(channel.Status.ID)
+	if err != nil && !goerrors.Is(err, slack.ErrAlreadyArchived) {
+		archived := state.DeepCopy()
+		archived.ArchivedAt = nil
+		if patchErr := r.patchAutoArchive(ctx, channel, archived); patchErr != nil {
+			log.Error(patchErr, "Unable to reset the archive time")
+		}
+		return ctrl.Result{}, err
+	}
+	log.Info("Archived inactive channel", "inactiveSince", activeSince)
+
+	return reconcilerUtil.RequeueAfter(archivedCheckPeriod)
+}
+
+// patchAutoArchive patches the auto-archiving status of the Channel when it changed
+func (r *ChannelActivityReconciler) patchAutoArchive(ctx context.Context, channel *slackv1alpha1.Channel, state *slackv1alpha1.AutoArchiveStatus) error {
+	if equality.Semantic.DeepEqual(channel.Status.AutoArchive, state) {
+		return nil
+	}
+	patchBase := client.MergeFrom(channel.DeepCopy())
+	channel.Status.AutoArchive = state
+	return r.Status().Patch(ctx, channel, patchBase)
+}
+
+// warningText is the message posted to an inactive channel before it's archived
+func warningText(autoArchive *slackv1alpha1.AutoArchive, activeSince time.Time, archiveAt time.Time) string {
+	if autoArchive.WarningMessage != "" {
+		return autoArchive.WarningMessage
+	}
+	return fmt.Sprintf("This channel has been inactive since %s, it will be archived on %s unless a message is posted to it.",
+		activeSince.Format("January 2, 2006"), archiveAt.Format("January 2, 2006"))
+}
+
+// channelsOf maps an ArchivePolicy to the Channels it applies to
+func (r *ChannelActivityReconciler) channelsOf(obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*slackv1alpha1.ArchivePolicy)
+	if !ok {
+		return nil
+	}
+
+	channels := &slackv1alpha1.ChannelList{}
+	if err := r.List(context.Background(), channels); err != nil {
+		r.Log.Error(err, "Error listing Channels")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range channels.Items {
+		channel := &channels.Items[i]
+		if policy.AppliesTo(channel) || channel.Status.AutoArchive != nil {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration, it's a second controller of Channels next to the
+// ChannelReconciler which only reacts to spec changes
+func (r *ChannelActivityReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("channel-activity").
+		For(&slackv1alpha1.Channel{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}, r.Shard.Predicate())).
+		Watches(&source.Kind{Type: &slackv1alpha1.ArchivePolicy{}}, handler.EnqueueRequestsFromMapFunc(r.channelsOf)).
+		Complete(r)
+}
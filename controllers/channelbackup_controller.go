@@ -0,0 +1,126 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/backup"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// ChannelBackupReconciler reconciles a ChannelBackup object
+type ChannelBackupReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+
+// Reconcile loop for the ChannelBackup resource, it backs up the Channels once every period
+func (r *ChannelBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("channelbackup", req.NamespacedName)
+
+	channelBackup := &slackv1alpha1.ChannelBackup{}
+	err := r.Get(ctx, req.NamespacedName, channelBackup)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if channelBackup.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	destination := channelBackup.Spec.Destination
+	if (destination.ConfigMap == nil) == (destination.Path == "") {
+		return reconcilerUtil.ManageError(r.Client, channelBackup,
+			fmt.Errorf("Exactly one of configMap and path must be set as destination"), false)
+	}
+
+	period := channelBackup.Spec.Period.Duration
+	if period <= 0 {
+		return reconcilerUtil.ManageError(r.Client, channelBackup, fmt.Errorf("The period must be positive"), false)
+	}
+
+	now := time.Now()
+	if last := channelBackup.Status.LastBackupTime; last != nil && now.Before(last.Add(period)) {
+		return reconcilerUtil.RequeueAfter(last.Add(period).Sub(now))
+	}
+
+	snapshot, err := backup.Export(ctx, r.Client, r.SlackService, channelBackup.Includes)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, channelBackup, err, true)
+	}
+	data, err := snapshot.Marshal()
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, channelBackup, err, false)
+	}
+
+	location, err := r.write(ctx, &destination, snapshot.Time.Time, data)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, channelBackup, err, true)
+	}
+	log.Info("Backed up Channels", "channels", len(snapshot.Channels), "location", location)
+
+	channelBackup.Status.LastBackupTime = &metav1.Time{Time: snapshot.Time.Time}
+	channelBackup.Status.Channels = int32(len(snapshot.Channels))
+	channelBackup.Status.Location = location
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, channelBackup)
+	if err != nil {
+		return result, err
+	}
+	return reconcilerUtil.RequeueAfter(period)
+}
+
+// write writes the backup to its destination and returns where it was written to
+func (r *ChannelBackupReconciler) write(ctx context.Context, destination *slackv1alpha1.BackupDestination, at time.Time, data []byte) (string, error) {
+	if destination.ConfigMap != nil {
+		ref := destination.ConfigMap
+		if err := backup.WriteConfigMap(ctx, r.Client, ref.Namespace, ref.Name, data); err != nil {
+			return "", err
+		}
+		return "configmap/" + ref.Namespace + "/" + ref.Name, nil
+	}
+	return backup.WriteFile(destination.Path, int(destination.Keep), at, data)
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *ChannelBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.ChannelBackup{}).
+		Complete(r)
+}
@@ -0,0 +1,200 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	slackapi "github.com/slack-go/slack"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+// ChannelMergeReconciler reconciles a ChannelMerge object
+type ChannelMergeReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelmerges,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelmerges/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels/status,verbs=get;update;patch
+
+// Reconcile loop for the ChannelMerge resource, it merges the source channel into the target channel once. Every step
+// can be retried, the redirect message is only posted once and the merge is done once the source is archived.
+func (r *ChannelMergeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("channelmerge", req.NamespacedName)
+
+	merge := &slackv1alpha1.ChannelMerge{}
+	err := r.Get(ctx, req.NamespacedName, merge)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if merge.GetDeletionTimestamp() != nil || merge.Status.CompletedAt != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	for _, channel := range []slackv1alpha1.MergeChannel{merge.Spec.Source, merge.Spec.Target} {
+		if err := checkMergeAccess(channel, merge.Namespace); err != nil {
+			return reconcilerUtil.ManageError(r.Client, merge, err, false)
+		}
+	}
+
+	sourceID, err := r.channelID(ctx, merge.Spec.Source, merge.Namespace)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, merge, fmt.Errorf("Invalid source: %v", err), true)
+	}
+	targetID, err := r.channelID(ctx, merge.Spec.Target, merge.Namespace)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, merge, fmt.Errorf("Invalid target: %v", err), true)
+	}
+	if sourceID == targetID {
+		return reconcilerUtil.ManageError(r.Client, merge, fmt.Errorf("Channel %s can't be merged into itself", sourceID), false)
+	}
+	merge.Status.SourceID = sourceID
+	merge.Status.TargetID = targetID
+
+	result, err := slack.MergeChannel(r.SlackService, sourceID, targetID, merge.Spec.CopyPins)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, merge, err, true)
+	}
+	merge.Status.MembersInvited = len(result.Members)
+	merge.Status.PinsCopied = result.Pins
+	log.Info("Moved members to the target channel", "source", sourceID, "target", targetID, "members", len(result.Members), "pins", result.Pins)
+
+	// The timestamp is recorded right away, so that a failed archive doesn't post the redirect again
+	if merge.Status.RedirectTimestamp == "" {
+		timestamp, err := r.SlackService.PostMessage(sourceID, slackapi.MsgOptionText(merge.RedirectText(targetID), false))
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, merge, err, true)
+		}
+		merge.Status.RedirectTimestamp = timestamp
+		if err := r.Status().Update(ctx, merge); err != nil {
+			return reconcilerUtil.RequeueWithError(err)
+		}
+	}
+
+	// mergedInto is recorded first, so that the Channel controller doesn't unarchive the source again
+	if merge.Spec.Source.ChannelRef != nil {
+		if err := r.recordMerge(ctx, pkgutil.ChannelReferenceKey(*merge.Spec.Source.ChannelRef, merge.Namespace), targetID); err != nil {
+			return reconcilerUtil.ManageError(r.Client, merge, err, true)
+		}
+	}
+	err = r.SlackService.ArchiveChannel(sourceID)
+	if err != nil && !goerrors.Is(err, slack.ErrAlreadyArchived) {
+		return reconcilerUtil.ManageError(r.Client, merge, err, true)
+	}
+	log.Info("Merged channel", "source", sourceID, "target", targetID)
+
+	merge.Status.CompletedAt = &metav1.Time{Time: time.Now()}
+	if _, err := reconcilerUtil.ManageSuccess(r.Client, merge); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// channelID returns the ID of a channel of the merge, exactly one of channelRef and id must be set
+func (r *ChannelMergeReconciler) channelID(ctx context.Context, channel slackv1alpha1.MergeChannel, namespace string) (string, error) {
+	if (channel.ChannelRef == nil) == (channel.ID == "") {
+		return "", fmt.Errorf("exactly one of channelRef and id must be set")
+	}
+	if channel.ID != "" {
+		return channel.ID, nil
+	}
+	return pkgutil.GetChannelID(ctx, r.Client, pkgutil.ChannelReferenceKey(*channel.ChannelRef, namespace))
+}
+
+// checkMergeAccess checks that a merge in the namespace may merge the channel, merges of namespaces which aren't
+// trusted are limited to the Channels of their namespace
+func checkMergeAccess(channel slackv1alpha1.MergeChannel, namespace string) error {
+	if channel.ID != "" && !slackv1alpha1.IsNamespaceTrusted(namespace) {
+		return fmt.Errorf("Only ChannelMerges in trusted namespaces may merge slack channels by ID")
+	}
+	if channel.ChannelRef != nil {
+		return pkgutil.CheckNamespaceAccess(namespace, pkgutil.ChannelReferenceKey(*channel.ChannelRef, namespace).Namespace)
+	}
+	return nil
+}
+
+// recordMerge records the target in the status of the source Channel
+func (r *ChannelMergeReconciler) recordMerge(ctx context.Context, key types.NamespacedName, targetID string) error {
+	channel := &slackv1alpha1.Channel{}
+	if err := r.Get(ctx, key, channel); err != nil {
+		return fmt.Errorf("Error fetching Channel %s: %v", key, err)
+	}
+	if channel.Status.MergedInto == targetID {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(channel.DeepCopy())
+	channel.Status.MergedInto = targetID
+	return r.Status().Patch(ctx, channel, patchBase)
+}
+
+// mergesOf maps a Channel to the pending ChannelMerges of its slack channel
+func (r *ChannelMergeReconciler) mergesOf(obj client.Object) []reconcile.Request {
+	merges := &slackv1alpha1.ChannelMergeList{}
+	if err := r.List(context.Background(), merges); err != nil {
+		r.Log.Error(err, "Error listing ChannelMerges")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, merge := range merges.Items {
+		if merge.Status.CompletedAt != nil {
+			continue
+		}
+		for _, channel := range []slackv1alpha1.MergeChannel{merge.Spec.Source, merge.Spec.Target} {
+			if channel.ChannelRef != nil && pkgutil.ChannelReferenceKey(*channel.ChannelRef, merge.Namespace) == client.ObjectKeyFromObject(obj) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: merge.Namespace, Name: merge.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *ChannelMergeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.ChannelMerge{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Channel{}}, handler.EnqueueRequestsFromMapFunc(r.mergesOf)).
+		Complete(r)
+}
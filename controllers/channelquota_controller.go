@@ -0,0 +1,128 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+// ChannelQuotaReconciler reconciles a ChannelQuota object
+type ChannelQuotaReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelquotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelquotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+
+// Reconcile loop for the ChannelQuota resource, it reports the Channels of the namespace against the quota. The
+// quota is enforced by the Channel webhook, Channels created before the quota are only reported.
+func (r *ChannelQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("channelquota", req.NamespacedName)
+
+	quota := &slackv1alpha1.ChannelQuota{}
+	err := r.Get(ctx, req.NamespacedName, quota)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if quota.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	channels := &slackv1alpha1.ChannelList{}
+	if err := r.List(ctx, channels, client.InNamespace(quota.Namespace)); err != nil {
+		return reconcilerUtil.ManageError(r.Client, quota, err, true)
+	}
+
+	quota.Status.Used = int32(len(channels.Items))
+	quota.Status.Violations = nil
+	for _, channel := range channels.Items {
+		if !quota.AllowsName(channel.Spec.Name) {
+			quota.Status.Violations = append(quota.Status.Violations, channel.Name)
+		}
+	}
+
+	var problems []string
+	if quota.Spec.MaxChannels != nil && quota.Status.Used > *quota.Spec.MaxChannels {
+		problems = append(problems, fmt.Sprintf("%d Channels exceed the maximum of %d", quota.Status.Used, *quota.Spec.MaxChannels))
+	}
+	if len(quota.Status.Violations) > 0 {
+		problems = append(problems, fmt.Sprintf("Channels %s don't use an allowed prefix", strings.Join(quota.Status.Violations, ", ")))
+	}
+
+	if len(problems) == 0 {
+		return reconcilerUtil.ManageSuccess(r.Client, quota)
+	}
+
+	log.Info("Namespace exceeds ChannelQuota", "used", quota.Status.Used, "violations", quota.Status.Violations)
+	quota.SetReconcileStatus([]metav1.Condition{{
+		Type:               "Exceeded",
+		Status:             metav1.ConditionTrue,
+		Reason:             "QuotaExceeded",
+		Message:            strings.Join(problems, ", "),
+		LastTransitionTime: metav1.Now(),
+	}})
+	if err := r.Status().Update(ctx, quota); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// quotasOf maps a Channel to the ChannelQuotas of its namespace
+func (r *ChannelQuotaReconciler) quotasOf(obj client.Object) []reconcile.Request {
+	quotas := &slackv1alpha1.ChannelQuotaList{}
+	if err := r.List(context.Background(), quotas, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Error listing ChannelQuotas", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, quota := range quotas.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: quota.Namespace, Name: quota.Name}})
+	}
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *ChannelQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.ChannelQuota{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Channel{}}, handler.EnqueueRequestsFromMapFunc(r.quotasOf)).
+		Complete(r)
+}
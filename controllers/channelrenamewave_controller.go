@@ -0,0 +1,317 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+const (
+	defaultRenameInterval = time.Minute
+
+	// renameWaveFieldManager is the field manager applying the new names to Channels
+	renameWaveFieldManager = "slack-operator-rename-wave"
+)
+
+// ChannelRenameWaveReconciler reconciles a ChannelRenameWave object
+type ChannelRenameWaveReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelrenamewaves,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channelrenamewaves/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch;update;patch
+
+// Reconcile loop for the ChannelRenameWave resource. The channels are selected once, then a batch of them gets the
+// new name in its Channel spec every interval and the ChannelReconciler renames them in Slack.
+func (r *ChannelRenameWaveReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("channelrenamewave", req.NamespacedName)
+
+	wave := &slackv1alpha1.ChannelRenameWave{}
+	err := r.Get(ctx, req.NamespacedName, wave)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if wave.GetDeletionTimestamp() != nil || wave.Status.CompletedAt != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if wave.Spec.Prefix == nil && wave.Spec.Suffix == nil {
+		return reconcilerUtil.ManageError(r.Client, wave, fmt.Errorf("At least one of prefix and suffix must be set"), false)
+	}
+
+	if wave.Status.Channels == nil {
+		if err := r.selectChannels(ctx, wave); err != nil {
+			return reconcilerUtil.ManageError(r.Client, wave, err, false)
+		}
+		log.Info("Selected channels to rename", "channels", len(wave.Status.Channels))
+	}
+
+	if err := r.refresh(ctx, wave); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	interval := defaultRenameInterval
+	if wave.Spec.Interval != nil {
+		interval = wave.Spec.Interval.Duration
+	}
+	now := time.Now()
+	wait := time.Duration(0)
+	if last := wave.Status.LastBatchTime; last != nil {
+		wait = last.Add(interval).Sub(now)
+	}
+
+	if wait <= 0 && pending(wave) > 0 {
+		r.startBatch(ctx, wave)
+		wave.Status.LastBatchTime = &metav1.Time{Time: now}
+		wait = interval
+	}
+
+	wave.Status.Renamed, wave.Status.Failed = 0, 0
+	for _, channel := range wave.Status.Channels {
+		switch channel.State {
+		case slackv1alpha1.RenameRenamed:
+			wave.Status.Renamed++
+		case slackv1alpha1.RenameFailed:
+			wave.Status.Failed++
+		}
+	}
+	done := wave.Status.Renamed+wave.Status.Failed == len(wave.Status.Channels)
+	if done {
+		wave.Status.CompletedAt = &metav1.Time{Time: now}
+		log.Info("Completed rename wave", "renamed", wave.Status.Renamed, "failed", wave.Status.Failed)
+	}
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, wave)
+	if err != nil || done {
+		return result, err
+	}
+	if pending(wave) == 0 {
+		// The remaining channels are waiting for the ChannelReconciler, the wave is triggered by their updates
+		return reconcilerUtil.RequeueAfter(interval)
+	}
+	return reconcilerUtil.RequeueAfter(wait)
+}
+
+// selectChannels records the selected Channels whose names have the prefix or suffix of the wave
+func (r *ChannelRenameWaveReconciler) selectChannels(ctx context.Context, wave *slackv1alpha1.ChannelRenameWave) error {
+	selector, err := metav1.LabelSelectorAsSelector(&wave.Spec.ChannelSelector)
+	if err != nil {
+		return err
+	}
+
+	namespaces := wave.Spec.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{wave.Namespace}
+	}
+
+	selected := []slackv1alpha1.RenameWaveChannel{}
+	for _, namespace := range namespaces {
+		if err := pkgutil.CheckNamespaceAccess(wave.Namespace, namespace); err != nil {
+			return err
+		}
+		channelList := &slackv1alpha1.ChannelList{}
+		err = r.List(ctx, channelList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+		if err != nil {
+			return err
+		}
+		for _, channel := range channelList.Items {
+			if channel.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if to, matches := wave.Rename(channel.Spec.Name); matches {
+				selected = append(selected, slackv1alpha1.RenameWaveChannel{
+					Channel: channel.Namespace + "/" + channel.Name,
+					From:    slackv1alpha1.NormalizeChannelName(channel.Spec.Name),
+					To:      to,
+					State:   slackv1alpha1.RenamePending,
+				})
+			}
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Channel < selected[j].Channel
+	})
+	wave.Status.Channels = selected
+	return nil
+}
+
+// refresh marks the updated channels which the ChannelReconciler reconciled as renamed
+func (r *ChannelRenameWaveReconciler) refresh(ctx context.Context, wave *slackv1alpha1.ChannelRenameWave) error {
+	for i := range wave.Status.Channels {
+		entry := &wave.Status.Channels[i]
+		if entry.State != slackv1alpha1.RenameUpdated {
+			continue
+		}
+
+		channel := &slackv1alpha1.Channel{}
+		if err := r.Get(ctx, channelKey(entry.Channel), channel); err != nil {
+			if errors.IsNotFound(err) {
+				entry.State, entry.Error = slackv1alpha1.RenameFailed, "The Channel was deleted"
+				continue
+			}
+			return err
+		}
+
+		if channel.Status.ObservedGeneration >= entry.Generation {
+			entry.State, entry.Error = slackv1alpha1.RenameRenamed, ""
+			continue
+		}
+		// The ChannelReconciler retries failed renames, the error is shown until it succeeds
+		entry.Error = ""
+		for _, condition := range channel.Status.Conditions {
+			if condition.Type == "ReconcileError" {
+				entry.Error = condition.Message
+			}
+		}
+	}
+	return nil
+}
+
+// startBatch sets the new names of the next batch of pending channels in their specs
+func (r *ChannelRenameWaveReconciler) startBatch(ctx context.Context, wave *slackv1alpha1.ChannelRenameWave) {
+	batchSize := wave.Spec.BatchSize
+	if batchSize < 1 {
+		batchSize = 10
+	}
+
+	for i := range wave.Status.Channels {
+		entry := &wave.Status.Channels[i]
+		if entry.State != slackv1alpha1.RenamePending {
+			continue
+		}
+		if batchSize == 0 {
+			return
+		}
+		batchSize--
+
+		generation, err := r.renameChannel(ctx, entry, wave.Spec.ForceConflicts)
+		if err != nil {
+			r.Log.Info("Unable to rename channel", "channel", entry.Channel, "error", err.Error())
+			entry.State, entry.Error = slackv1alpha1.RenameFailed, err.Error()
+			continue
+		}
+		entry.State, entry.Generation = slackv1alpha1.RenameUpdated, generation
+	}
+}
+
+// renameChannel applies the new name to the spec of the Channel and returns the generation of the Channel with it,
+// names managed by another tool are only changed with force
+func (r *ChannelRenameWaveReconciler) renameChannel(ctx context.Context, entry *slackv1alpha1.RenameWaveChannel, force bool) (int64, error) {
+	channel := &slackv1alpha1.Channel{}
+	if err := r.Get(ctx, channelKey(entry.Channel), channel); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, fmt.Errorf("The Channel was deleted")
+		}
+		return 0, err
+	}
+
+	name := slackv1alpha1.NormalizeChannelName(channel.Spec.Name)
+	if name == entry.To {
+		return channel.Generation, nil
+	}
+	if name != entry.From {
+		return 0, fmt.Errorf("The name of the Channel was changed to %s", name)
+	}
+
+	fields := map[string]interface{}{"spec": map[string]interface{}{"name": entry.To}}
+	if err := pkgutil.Apply(ctx, r.Client, channel, renameWaveFieldManager, fields, force); err != nil {
+		if pkgutil.IsApplyConflict(err) {
+			return 0, fmt.Errorf("The name is managed by another tool, set forceConflicts to rename it anyway: %v", err)
+		}
+		return 0, err
+	}
+	return channel.Generation, nil
+}
+
+// channelKey parses a <namespace>/<name> key of a Channel
+func channelKey(key string) types.NamespacedName {
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) != 2 {
+		return types.NamespacedName{Name: key}
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+}
+
+// pending returns the number of channels of the wave which are waiting for their batch
+func pending(wave *slackv1alpha1.ChannelRenameWave) int {
+	count := 0
+	for _, channel := range wave.Status.Channels {
+		if channel.State == slackv1alpha1.RenamePending {
+			count++
+		}
+	}
+	return count
+}
+
+// wavesOf maps a Channel to the running ChannelRenameWaves which rename it
+func (r *ChannelRenameWaveReconciler) wavesOf(obj client.Object) []reconcile.Request {
+	waves := &slackv1alpha1.ChannelRenameWaveList{}
+	if err := r.List(context.Background(), waves); err != nil {
+		r.Log.Error(err, "Error listing ChannelRenameWaves")
+		return nil
+	}
+
+	key := client.ObjectKeyFromObject(obj).String()
+	var requests []reconcile.Request
+	for _, wave := range waves.Items {
+		if wave.Status.CompletedAt != nil {
+			continue
+		}
+		for _, channel := range wave.Status.Channels {
+			if channel.Channel == key && channel.State == slackv1alpha1.RenameUpdated {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: wave.Namespace, Name: wave.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *ChannelRenameWaveReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.ChannelRenameWave{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Channel{}}, handler.EnqueueRequestsFromMapFunc(r.wavesOf)).
+		Complete(r)
+}
@@ -0,0 +1,141 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/history"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+// ConversationExportReconciler reconciles a ConversationExport object
+type ConversationExportReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=conversationexports,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=conversationexports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+
+// Reconcile loop for the ConversationExport resource, it exports the messages posted since the previous export
+func (r *ConversationExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("conversationexport", req.NamespacedName)
+
+	export := &slackv1alpha1.ConversationExport{}
+	err := r.Get(ctx, req.NamespacedName, export)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if export.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	channelKey := pkgutil.ChannelReferenceKey(export.Spec.Channel, export.Namespace)
+	channel := &slackv1alpha1.Channel{}
+	if err := r.Get(ctx, channelKey, channel); err != nil {
+		return reconcilerUtil.ManageError(r.Client, export, err, true)
+	}
+
+	now := time.Now()
+	if last := export.Status.LastExportTime; last != nil && !export.PendingBeforeArchive(channel) {
+		if export.Spec.Period == nil {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		if next := last.Add(export.Spec.Period.Duration); now.Before(next) {
+			return reconcilerUtil.RequeueAfter(next.Sub(now))
+		}
+	}
+
+	channelID, err := pkgutil.GetChannelID(ctx, r.Client, channelKey)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, export, err, true)
+	}
+
+	messages, err := history.Collect(r.SlackService, channelID, export.Status.Latest)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, export, err, true)
+	}
+
+	if len(messages) > 0 {
+		dir := filepath.Join(export.Spec.Path, channelKey.Namespace, channelKey.Name)
+		file, err := history.Write(dir, export.Spec.Format, messages)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, export, err, true)
+		}
+		log.Info("Exported messages", "messages", len(messages), "file", file)
+
+		export.Status.Latest = messages[len(messages)-1].Timestamp
+		export.Status.Messages += int64(len(messages))
+		export.Status.LastFile = file
+	}
+	export.Status.LastExportTime = &metav1.Time{Time: now}
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, export)
+	if err != nil || export.Spec.Period == nil {
+		return result, err
+	}
+	return reconcilerUtil.RequeueAfter(export.Spec.Period.Duration)
+}
+
+// exportsOf maps a Channel to the ConversationExports of its messages
+func (r *ConversationExportReconciler) exportsOf(obj client.Object) []reconcile.Request {
+	exports := &slackv1alpha1.ConversationExportList{}
+	if err := r.List(context.Background(), exports); err != nil {
+		r.Log.Error(err, "Error listing ConversationExports")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, export := range exports.Items {
+		if pkgutil.ChannelReferenceKey(export.Spec.Channel, export.Namespace) == client.ObjectKeyFromObject(obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: export.Namespace, Name: export.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *ConversationExportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.ConversationExport{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Channel{}}, handler.EnqueueRequestsFromMapFunc(r.exportsOf)).
+		Complete(r)
+}
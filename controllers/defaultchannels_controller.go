@@ -0,0 +1,103 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// DefaultChannelsReconciler keeps the slack channels of the Channels with defaultForNewMembers in the default
+// channels of the workspace. Default channels which no Channel manages are left as they are.
+type DefaultChannelsReconciler struct {
+	client.Client
+	Log          logr.Logger
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+
+// Reconcile syncs the default channels of the workspace with all Channels, whichever Channel changed
+func (r *DefaultChannelsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	channels := &slackv1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	var add, remove []string
+	for _, channel := range channels.Items {
+		if channel.Status.ID == "" {
+			continue
+		}
+		if channel.Spec.DefaultForNewMembers && channel.GetDeletionTimestamp() == nil {
+			add = append(add, channel.Status.ID)
+		} else {
+			remove = append(remove, channel.Status.ID)
+		}
+	}
+
+	changed, err := slack.UpdateDefaultChannels(r.SlackService, add, remove)
+	if err != nil {
+		r.Log.Error(err, "Unable to update the default channels")
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	if changed {
+		r.Log.Info("Updated the default channels", "channels", len(add))
+	}
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// defaultChannelChanged passes the events of Channels which may change the default channels
+var defaultChannelChanged = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return e.Object.(*slackv1alpha1.Channel).Spec.DefaultForNewMembers
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldChannel := e.ObjectOld.(*slackv1alpha1.Channel)
+		newChannel := e.ObjectNew.(*slackv1alpha1.Channel)
+		if oldChannel.Spec.DefaultForNewMembers != newChannel.Spec.DefaultForNewMembers {
+			return true
+		}
+		return newChannel.Spec.DefaultForNewMembers && (oldChannel.Status.ID != newChannel.Status.ID ||
+			oldChannel.GetDeletionTimestamp() == nil && newChannel.GetDeletionTimestamp() != nil)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// SetupWithManager - Controller-Manager binding configuration, it's another controller of Channels next to the
+// ChannelReconciler which only reacts to changes of defaultForNewMembers
+func (r *DefaultChannelsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("default-channels").
+		For(&slackv1alpha1.Channel{}, builder.WithPredicates(defaultChannelChanged)).
+		Complete(r)
+}
@@ -0,0 +1,121 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+// DriftReportReconciler reconciles a DriftReport object
+type DriftReportReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=driftreports,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=driftreports/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+
+// Reconcile loop for the DriftReport resource, it lists the Channels whose status reports drift
+func (r *DriftReportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	report := &slackv1alpha1.DriftReport{}
+	err := r.Get(ctx, req.NamespacedName, report)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if report.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	channels := &slackv1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		return reconcilerUtil.ManageError(r.Client, report, err, true)
+	}
+
+	report.Status.Channels = driftedChannels(report, channels.Items)
+	report.Status.OutOfSync = int32(len(report.Status.Channels))
+
+	return reconcilerUtil.ManageSuccess(r.Client, report)
+}
+
+// driftedChannels returns the reported channels which are out of sync, the longest out of sync first
+func driftedChannels(report *slackv1alpha1.DriftReport, channels []slackv1alpha1.Channel) []slackv1alpha1.DriftedChannel {
+	var drifted []slackv1alpha1.DriftedChannel
+	for _, channel := range channels {
+		if channel.Status.Drift == nil || !report.Reports(channel.Namespace) {
+			continue
+		}
+		drifted = append(drifted, slackv1alpha1.DriftedChannel{
+			Namespace:    channel.Namespace,
+			Name:         channel.Name,
+			ID:           channel.Status.ID,
+			ChannelDrift: *channel.Status.Drift,
+		})
+	}
+
+	sort.SliceStable(drifted, func(i, j int) bool {
+		if !drifted[i].Since.Equal(&drifted[j].Since) {
+			return drifted[i].Since.Before(&drifted[j].Since)
+		}
+		return drifted[i].Namespace+"/"+drifted[i].Name < drifted[j].Namespace+"/"+drifted[j].Name
+	})
+	return drifted
+}
+
+// reportsOf maps a Channel to the DriftReports reporting its namespace
+func (r *DriftReportReconciler) reportsOf(obj client.Object) []reconcile.Request {
+	reports := &slackv1alpha1.DriftReportList{}
+	if err := r.List(context.Background(), reports); err != nil {
+		r.Log.Error(err, "Error listing DriftReports")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, report := range reports.Items {
+		if report.Reports(obj.GetNamespace()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: report.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *DriftReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.DriftReport{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Channel{}}, handler.EnqueueRequestsFromMapFunc(r.reportsOf)).
+		Complete(r)
+}
@@ -0,0 +1,309 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/emoji"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+var (
+	emojiFinalizer string = "slack.stakater.com/emoji"
+
+	// emojiResyncPeriod is how often the workspace is checked for emoji removed outside of the operator
+	emojiResyncPeriod = time.Hour
+)
+
+// EmojiReconciler reconciles an Emoji object
+type EmojiReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// emojiSource is the resolved image source of an emoji
+type emojiSource struct {
+	url   string
+	image []byte
+	hash  string
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=emojis,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=emojis/status,verbs=get;update;patch
+
+// Reconcile loop for the Emoji resource, it syncs the custom emoji of the workspace with the spec
+func (r *EmojiReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("emoji", req.NamespacedName)
+
+	emojiInstance := &slackv1alpha1.Emoji{}
+	err := r.Get(ctx, req.NamespacedName, emojiInstance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Emoji is marked for deletion
+	if emojiInstance.GetDeletionTimestamp() != nil {
+		if finalizerUtil.HasFinalizer(emojiInstance, emojiFinalizer) {
+			return r.finalizeEmoji(ctx, emojiInstance)
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(emojiInstance, emojiFinalizer) {
+		log.Info("Adding finalizer for emoji " + req.Name)
+
+		emojiPatchBase := client.MergeFrom(emojiInstance.DeepCopy())
+		finalizerUtil.AddFinalizer(emojiInstance, emojiFinalizer)
+
+		err := r.Client.Patch(ctx, emojiInstance, emojiPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, emojiInstance, err, true)
+		}
+	}
+
+	sources, err := r.getSources(ctx, emojiInstance)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, emojiInstance, err, true)
+	}
+
+	existing, err := r.SlackService.ListEmoji()
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, emojiInstance, err, true)
+	}
+
+	desired := map[string]string{}
+	for name, emojiSource := range sources {
+		desired[name] = emojiSource.hash
+	}
+
+	managed := map[string]string{}
+	for _, managedEmoji := range emojiInstance.Status.Emojis {
+		managed[managedEmoji.Name] = managedEmoji.SourceHash
+	}
+
+	plan := emoji.NewPlan(desired, managed, existing)
+	if plan.Empty() && len(plan.Conflicts) == 0 {
+		return reconcilerUtil.RequeueAfter(emojiResyncPeriod)
+	}
+
+	log.Info("Syncing emoji", "add", plan.Add, "remove", plan.Remove, "rename", plan.Rename, "conflicts", plan.Conflicts)
+
+	// The managed emoji are updated after every change so partial failures are tracked correctly
+	var errorlist []error
+	for _, name := range plan.Remove {
+		if err := r.SlackService.RemoveEmoji(name); err != nil {
+			errorlist = append(errorlist, err)
+			continue
+		}
+		delete(managed, name)
+	}
+
+	for _, rename := range plan.Rename {
+		if err := r.SlackService.RenameEmoji(rename.From, rename.To); err != nil {
+			errorlist = append(errorlist, err)
+			continue
+		}
+		managed[rename.To] = managed[rename.From]
+		delete(managed, rename.From)
+	}
+
+	for _, name := range plan.Add {
+		if _, found := managed[name]; found && managed[name] != desired[name] {
+			// Replaced emoji whose removal failed
+			continue
+		}
+
+		emojiSource := sources[name]
+		if emojiSource.image != nil {
+			err = r.SlackService.UploadEmoji(name, emojiSource.image)
+		} else {
+			err = r.SlackService.AddEmoji(name, emojiSource.url)
+		}
+		if err != nil {
+			errorlist = append(errorlist, fmt.Errorf("Error adding emoji %s: %v", name, err))
+			continue
+		}
+		managed[name] = emojiSource.hash
+	}
+
+	emojiInstance.Status.Emojis = managedEmojiList(managed)
+
+	if len(errorlist) > 0 {
+		return reconcilerUtil.ManageError(r.Client, emojiInstance, pkgutil.MapErrorListToError(errorlist), true)
+	}
+
+	// Emoji whose names are taken aren't retried, they are added once the other emoji is gone
+	if len(plan.Conflicts) > 0 {
+		return r.nameTaken(ctx, emojiInstance, plan.Conflicts)
+	}
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, emojiInstance)
+	if err != nil {
+		return result, err
+	}
+	return reconcilerUtil.RequeueAfter(emojiResyncPeriod)
+}
+
+// nameTaken reports the emoji whose names are taken by emoji the operator doesn't manage
+func (r *EmojiReconciler) nameTaken(ctx context.Context, emojiInstance *slackv1alpha1.Emoji, conflicts []string) (ctrl.Result, error) {
+	emojiInstance.SetReconcileStatus([]metav1.Condition{{
+		Type:               "NameConflict",
+		Status:             metav1.ConditionTrue,
+		Reason:             "NameTaken",
+		Message:            fmt.Sprintf("Emoji %s already exist in the workspace and aren't managed by this Emoji, remove or rename them in Slack", strings.Join(conflicts, ", ")),
+		LastTransitionTime: metav1.Now(),
+	}})
+	if err := r.Status().Update(ctx, emojiInstance); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	return reconcilerUtil.RequeueAfter(emojiResyncPeriod)
+}
+
+// getSources resolves the image sources of all emoji in the spec
+func (r *EmojiReconciler) getSources(ctx context.Context, emojiInstance *slackv1alpha1.Emoji) (map[string]emojiSource, error) {
+	sources := map[string]emojiSource{}
+
+	for _, image := range emojiInstance.Images() {
+		if _, found := sources[image.Name]; found {
+			return nil, fmt.Errorf("Emoji %s is listed more than once", image.Name)
+		}
+
+		if (image.URL == "") == (image.ConfigMapRef == nil) {
+			return nil, fmt.Errorf("Exactly one of url and configMapRef must be set for emoji %s", image.Name)
+		}
+
+		if image.URL != "" {
+			hash := sha256.Sum256([]byte(image.URL))
+			sources[image.Name] = emojiSource{url: image.URL, hash: hex.EncodeToString(hash[:])}
+			continue
+		}
+
+		key := types.NamespacedName{Namespace: emojiInstance.Namespace, Name: image.ConfigMapRef.Name}
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			return nil, fmt.Errorf("Error fetching ConfigMap %s: %v", key, err)
+		}
+
+		data, found := configMap.BinaryData[image.ConfigMapRef.Key]
+		if !found {
+			return nil, fmt.Errorf("ConfigMap %s has no binary key %s", key, image.ConfigMapRef.Key)
+		}
+
+		hash := sha256.Sum256(data)
+		sources[image.Name] = emojiSource{image: data, hash: hex.EncodeToString(hash[:])}
+	}
+
+	return sources, nil
+}
+
+func (r *EmojiReconciler) finalizeEmoji(ctx context.Context, emojiInstance *slackv1alpha1.Emoji) (ctrl.Result, error) {
+	log := r.Log.WithValues("emoji", emojiInstance.Namespace+"/"+emojiInstance.Name)
+
+	var errorlist []error
+	var remaining []slackv1alpha1.ManagedEmoji
+	for _, managedEmoji := range emojiInstance.Status.Emojis {
+		log.Info("Removing emoji", "name", managedEmoji.Name)
+		if err := r.SlackService.RemoveEmoji(managedEmoji.Name); err != nil {
+			errorlist = append(errorlist, err)
+			remaining = append(remaining, managedEmoji)
+		}
+	}
+
+	if len(errorlist) > 0 {
+		emojiInstance.Status.Emojis = remaining
+		return reconcilerUtil.ManageError(r.Client, emojiInstance, pkgutil.MapErrorListToError(errorlist), true)
+	}
+
+	emojiPatchBase := client.MergeFrom(emojiInstance.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(emojiInstance, emojiFinalizer)
+	log.V(1).Info("Finalizer removed for emoji")
+
+	err := r.Client.Patch(ctx, emojiInstance, emojiPatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, emojiInstance, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+func managedEmojiList(managed map[string]string) []slackv1alpha1.ManagedEmoji {
+	list := make([]slackv1alpha1.ManagedEmoji, 0, len(managed))
+	for name, hash := range managed {
+		list = append(list, slackv1alpha1.ManagedEmoji{Name: name, SourceHash: hash})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// emojisOf maps a ConfigMap to the Emojis reading images from it
+func (r *EmojiReconciler) emojisOf(obj client.Object) []reconcile.Request {
+	emojiList := &slackv1alpha1.EmojiList{}
+	if err := r.List(context.Background(), emojiList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Unable to list emojis", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, emojiInstance := range emojiList.Items {
+		for _, image := range emojiInstance.Images() {
+			if image.ConfigMapRef != nil && image.ConfigMapRef.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: emojiInstance.Namespace, Name: emojiInstance.Name}})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *EmojiReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.Emoji{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.emojisOf)).
+		Complete(r)
+}
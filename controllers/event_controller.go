@@ -0,0 +1,272 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/events"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+// EventReconciler forwards Kubernetes Events matching an EventRoute to its slack channel
+type EventReconciler struct {
+	client.Client
+	Log          logr.Logger
+	SlackService slack.Service
+
+	startTime time.Time
+	lock      sync.Mutex
+	routes    map[types.UID]*routeState
+}
+
+// routeState holds the rate limiter and recently posted events of a route
+type routeState struct {
+	maxEventsPerMinute int32
+	limiter            flowcontrol.RateLimiter
+	posted             map[string]time.Time
+}
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// Reconcile posts the event to the channels of all matching routes
+func (r *EventReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("event", req.NamespacedName)
+
+	k8sEvent := &corev1.Event{}
+	err := r.Get(ctx, req.NamespacedName, k8sEvent)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Events that happened before the operator started have either been forwarded already or are stale
+	if events.Timestamp(k8sEvent).Before(r.startTime) {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	routeList := &slackv1alpha1.EventRouteList{}
+	err = r.List(ctx, routeList)
+	if err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	r.pruneRouteStates(routeList.Items)
+
+	namespaceLabels, err := r.getNamespaceLabels(ctx, k8sEvent.Namespace, routeList.Items)
+	if err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	var errorlist []error
+	for i := range routeList.Items {
+		route := &routeList.Items[i]
+		if route.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		matches, err := events.Matches(route, k8sEvent, namespaceLabels)
+		if err != nil || !matches {
+			continue
+		}
+
+		err = r.forward(ctx, route, k8sEvent)
+		if err != nil {
+			log.Error(err, "Error forwarding event", "eventroute", route.Namespace+"/"+route.Name)
+			errorlist = append(errorlist, err)
+		}
+	}
+
+	if len(errorlist) > 0 {
+		return reconcilerUtil.RequeueWithError(pkgutil.MapErrorListToError(errorlist))
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+func (r *EventReconciler) forward(ctx context.Context, route *slackv1alpha1.EventRoute, k8sEvent *corev1.Event) error {
+	log := r.Log.WithValues("eventroute", route.Namespace+"/"+route.Name)
+
+	key := events.DeduplicationKey(k8sEvent)
+	window := time.Duration(route.Spec.DeduplicationWindowSeconds) * time.Second
+
+	state := r.getRouteState(route)
+
+	r.lock.Lock()
+	lastPosted, found := state.posted[key]
+	r.lock.Unlock()
+	if found && time.Since(lastPosted) < window {
+		log.V(1).Info("Skipping duplicate event", "reason", k8sEvent.Reason)
+		return nil
+	}
+
+	if !state.limiter.TryAccept() {
+		log.Info("Rate limit reached, dropping event", "reason", k8sEvent.Reason)
+		return nil
+	}
+
+	channelKey := pkgutil.ChannelReferenceKey(route.Spec.Channel, route.Namespace)
+	if err := pkgutil.CheckNamespaceAccess(route.Namespace, channelKey.Namespace); err != nil {
+		return err
+	}
+	channelID, err := pkgutil.GetChannelID(ctx, r.Client, channelKey)
+	if err != nil {
+		return err
+	}
+
+	blocks, text, err := r.render(ctx, route, k8sEvent)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.SlackService.PostMessage(channelID,
+		slackapi.MsgOptionText(text, false),
+		slackapi.MsgOptionBlocks(blocks...))
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	state.posted[key] = now
+	for postedKey, postedAt := range state.posted {
+		if now.Sub(postedAt) >= window {
+			delete(state.posted, postedKey)
+		}
+	}
+
+	return nil
+}
+
+// render renders the event with the route's template, falling back to the default template
+func (r *EventReconciler) render(ctx context.Context, route *slackv1alpha1.EventRoute, k8sEvent *corev1.Event) ([]slackapi.Block, string, error) {
+	if route.Spec.TemplateRef == nil {
+		blocks, err := events.Render(k8sEvent)
+		return blocks, events.FallbackText(k8sEvent), err
+	}
+
+	tmpl, err := pkgutil.GetNotificationTemplate(ctx, r.Client, pkgutil.NotificationTemplateReferenceKey(*route.Spec.TemplateRef, route.Namespace))
+	if err != nil {
+		return nil, "", err
+	}
+
+	blocks, text, err := tmpl.Render(k8sEvent)
+	if err != nil {
+		return nil, "", err
+	}
+	if text == "" {
+		text = events.FallbackText(k8sEvent)
+	}
+
+	return blocks, text, nil
+}
+
+// getRouteState returns the state of the route, recreating the rate limiter when the route's limit changed
+func (r *EventReconciler) getRouteState(route *slackv1alpha1.EventRoute) *routeState {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	maxEventsPerMinute := route.Spec.MaxEventsPerMinute
+	if maxEventsPerMinute < 1 {
+		maxEventsPerMinute = 1
+	}
+
+	state, found := r.routes[route.UID]
+	if !found {
+		state = &routeState{posted: map[string]time.Time{}}
+		r.routes[route.UID] = state
+	}
+
+	if state.limiter == nil || state.maxEventsPerMinute != maxEventsPerMinute {
+		state.maxEventsPerMinute = maxEventsPerMinute
+		state.limiter = flowcontrol.NewTokenBucketRateLimiter(float32(maxEventsPerMinute)/60, int(maxEventsPerMinute))
+	}
+
+	return state
+}
+
+// pruneRouteStates drops the state of routes which were deleted
+func (r *EventReconciler) pruneRouteStates(routes []slackv1alpha1.EventRoute) {
+	live := map[types.UID]bool{}
+	for _, route := range routes {
+		live[route.UID] = true
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for uid := range r.routes {
+		if !live[uid] {
+			delete(r.routes, uid)
+		}
+	}
+}
+
+// getNamespaceLabels fetches the labels of the namespace, only when a route filters on them
+func (r *EventReconciler) getNamespaceLabels(ctx context.Context, name string, routes []slackv1alpha1.EventRoute) (map[string]string, error) {
+	needed := false
+	for _, route := range routes {
+		if route.Spec.NamespaceSelector != nil {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: name}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return namespace.Labels, nil
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *EventReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.startTime = time.Now()
+	r.routes = map[types.UID]*routeState{}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Event{}).
+		WithEventFilter(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool { return false },
+		}).
+		Complete(r)
+}
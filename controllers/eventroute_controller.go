@@ -0,0 +1,99 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+// EventRouteReconciler reconciles an EventRoute object
+type EventRouteReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=eventroutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=eventroutes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=notificationtemplates,verbs=get;list;watch
+
+// Reconcile loop for the EventRoute resource, it validates the route while the forwarding is done by the EventReconciler
+func (r *EventRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("eventroute", req.NamespacedName)
+
+	route := &slackv1alpha1.EventRoute{}
+	err := r.Get(ctx, req.NamespacedName, route)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if route.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if route.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(route.Spec.NamespaceSelector); err != nil {
+			return reconcilerUtil.ManageError(r.Client, route, err, false)
+		}
+	}
+
+	// Routes of namespaces which aren't trusted are limited to their own namespace
+	channelKey := pkgutil.ChannelReferenceKey(route.Spec.Channel, route.Namespace)
+	for _, namespace := range append([]string{channelKey.Namespace}, route.Spec.Namespaces...) {
+		if err := pkgutil.CheckNamespaceAccess(route.Namespace, namespace); err != nil {
+			return reconcilerUtil.ManageError(r.Client, route, err, false)
+		}
+	}
+
+	// The channel has to exist before events can be routed to it
+	_, err = pkgutil.GetChannelID(ctx, r.Client, channelKey)
+	if err != nil {
+		log.Error(err, "Unable to resolve channel of the route")
+		return reconcilerUtil.ManageError(r.Client, route, err, true)
+	}
+
+	if route.Spec.TemplateRef != nil {
+		_, err = pkgutil.GetNotificationTemplate(ctx, r.Client, pkgutil.NotificationTemplateReferenceKey(*route.Spec.TemplateRef, route.Namespace))
+		if err != nil {
+			log.Error(err, "Unable to resolve template of the route")
+			return reconcilerUtil.ManageError(r.Client, route, err, true)
+		}
+	}
+
+	return reconcilerUtil.ManageSuccess(r.Client, route)
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *EventRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.EventRoute{}).
+		Complete(r)
+}
@@ -0,0 +1,245 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+var (
+	fileUploadFinalizer string = "slack.stakater.com/fileupload"
+)
+
+// FileUploadReconciler reconciles a FileUpload object
+type FileUploadReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=fileuploads,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=fileuploads/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile loop for the FileUpload resource, it uploads the file again whenever its content changes
+func (r *FileUploadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("fileupload", req.NamespacedName)
+
+	upload := &slackv1alpha1.FileUpload{}
+	err := r.Get(ctx, req.NamespacedName, upload)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// FileUpload is marked for deletion
+	if upload.GetDeletionTimestamp() != nil {
+		if finalizerUtil.HasFinalizer(upload, fileUploadFinalizer) {
+			return r.finalizeFileUpload(ctx, upload)
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(upload, fileUploadFinalizer) {
+		log.Info("Adding finalizer for fileupload " + req.Name)
+
+		uploadPatchBase := client.MergeFrom(upload.DeepCopy())
+		finalizerUtil.AddFinalizer(upload, fileUploadFinalizer)
+
+		err := r.Client.Patch(ctx, upload, uploadPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, upload, err, true)
+		}
+	}
+
+	content, err := r.getContent(ctx, upload)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, upload, err, true)
+	}
+
+	channelID, err := pkgutil.GetChannelID(ctx, r.Client, pkgutil.ChannelReferenceKey(upload.Spec.Channel, upload.Namespace))
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, upload, err, true)
+	}
+
+	hash := contentHash(channelID, upload.Spec.Filename, upload.Spec.Title, content)
+	if upload.Status.FileID != "" && upload.Status.ContentHash == hash {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	log.Info("Uploading file", "channelID", channelID, "filename", upload.Spec.Filename)
+	fileID, err := r.SlackService.UploadFile(channelID, upload.Spec.Filename, upload.Spec.Title, content)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, upload, err, true)
+	}
+
+	// Slack files can't be edited, the previous upload is replaced by the new one
+	if upload.Status.FileID != "" {
+		log.Info("Deleting previous upload", "fileID", upload.Status.FileID)
+		if err := r.SlackService.DeleteFile(upload.Status.FileID); err != nil {
+			log.Error(err, "Error deleting previous upload", "fileID", upload.Status.FileID)
+		}
+	}
+
+	upload.Status.FileID = fileID
+	upload.Status.ChannelID = channelID
+	upload.Status.ContentHash = hash
+
+	return reconcilerUtil.ManageSuccess(r.Client, upload)
+}
+
+// getContent reads the file content from its source
+func (r *FileUploadReconciler) getContent(ctx context.Context, upload *slackv1alpha1.FileUpload) ([]byte, error) {
+	fileSource := upload.Spec.Source
+
+	switch {
+	case fileSource.ConfigMapRef != nil:
+		key := types.NamespacedName{Namespace: upload.Namespace, Name: fileSource.ConfigMapRef.Name}
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			return nil, fmt.Errorf("Error fetching ConfigMap %s: %v", key, err)
+		}
+		if data, found := configMap.Data[fileSource.ConfigMapRef.Key]; found {
+			return []byte(data), nil
+		}
+		if data, found := configMap.BinaryData[fileSource.ConfigMapRef.Key]; found {
+			return data, nil
+		}
+		return nil, fmt.Errorf("ConfigMap %s has no key %s", key, fileSource.ConfigMapRef.Key)
+
+	case fileSource.SecretRef != nil:
+		key := types.NamespacedName{Namespace: upload.Namespace, Name: fileSource.SecretRef.Name}
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, key, secret); err != nil {
+			return nil, fmt.Errorf("Error fetching Secret %s: %v", key, err)
+		}
+		// Secrets are only shared when their owner opted in, FileUploads can't read any Secret of the namespace
+		if secret.Labels[slackv1alpha1.FileUploadSecretLabel] != "true" {
+			return nil, fmt.Errorf("Secret %s isn't labeled %s=true", key, slackv1alpha1.FileUploadSecretLabel)
+		}
+		if data, found := secret.Data[fileSource.SecretRef.Key]; found {
+			return data, nil
+		}
+		return nil, fmt.Errorf("Secret %s has no key %s", key, fileSource.SecretRef.Key)
+
+	case fileSource.Content != "":
+		return []byte(fileSource.Content), nil
+	}
+
+	return nil, fmt.Errorf("One of source.content, source.configMapRef or source.secretRef must be set")
+}
+
+func (r *FileUploadReconciler) finalizeFileUpload(ctx context.Context, upload *slackv1alpha1.FileUpload) (ctrl.Result, error) {
+	log := r.Log.WithValues("fileID", upload.Status.FileID)
+
+	if upload.Status.FileID != "" {
+		log.Info("Deleting file")
+		if err := r.SlackService.DeleteFile(upload.Status.FileID); err != nil {
+			return reconcilerUtil.ManageError(r.Client, upload, err, true)
+		}
+	}
+
+	uploadPatchBase := client.MergeFrom(upload.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(upload, fileUploadFinalizer)
+	log.V(1).Info("Finalizer removed for fileupload")
+
+	err := r.Client.Patch(ctx, upload, uploadPatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, upload, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// contentHash identifies an upload of content with the given details
+func contentHash(channelID string, filename string, title string, content []byte) string {
+	hash := sha256.New()
+	for _, field := range []string{channelID, filename, title} {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+	hash.Write(content)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// uploadsOf maps a ConfigMap or Secret to the FileUploads reading from it
+func (r *FileUploadReconciler) uploadsOf(isSecret bool) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		uploadList := &slackv1alpha1.FileUploadList{}
+		if err := r.List(context.Background(), uploadList, client.InNamespace(obj.GetNamespace())); err != nil {
+			r.Log.Error(err, "Unable to list fileuploads", "namespace", obj.GetNamespace())
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, upload := range uploadList.Items {
+			ref := upload.Spec.Source.ConfigMapRef
+			if isSecret {
+				ref = upload.Spec.Source.SecretRef
+			}
+			if ref != nil && ref.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: upload.Namespace, Name: upload.Name}})
+			}
+		}
+
+		return requests
+	}
+}
+
+// fileUploadSecret filters the Secrets which opted in to being uploaded
+var fileUploadSecret = predicate.NewPredicateFuncs(func(obj client.Object) bool {
+	return obj.GetLabels()[slackv1alpha1.FileUploadSecretLabel] == "true"
+})
+
+// SetupWithManager - Controller-Manager binding configuration. Only the metadata of the labeled Secrets is watched,
+// so the contents of the Secrets of the cluster aren't cached.
+func (r *FileUploadReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.FileUpload{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.uploadsOf(false))).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.uploadsOf(true)),
+			builder.OnlyMetadata, builder.WithPredicates(fileUploadSecret)).
+		Complete(r)
+}
@@ -0,0 +1,273 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+var (
+	guestUserFinalizer string = "slack.stakater.com/guestuser"
+)
+
+// GuestUserReconciler reconciles a GuestUser object
+type GuestUserReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=guestusers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=guestusers/status,verbs=get;update;patch
+
+// Reconcile loop for the GuestUser resource, it invites the guest and deactivates them once expired or deleted
+func (r *GuestUserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("guestuser", req.NamespacedName)
+
+	guest := &slackv1alpha1.GuestUser{}
+	err := r.Get(ctx, req.NamespacedName, guest)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// GuestUser is marked for deletion
+	if guest.GetDeletionTimestamp() != nil {
+		if finalizerUtil.HasFinalizer(guest, guestUserFinalizer) {
+			return r.finalizeGuestUser(ctx, guest)
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(guest, guestUserFinalizer) {
+		log.Info("Adding finalizer for guest user " + req.Name)
+
+		guestPatchBase := client.MergeFrom(guest.DeepCopy())
+		finalizerUtil.AddFinalizer(guest, guestUserFinalizer)
+
+		err := r.Client.Patch(ctx, guest, guestPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, guest, err, true)
+		}
+	}
+
+	if guest.Status.State == slackv1alpha1.InviteStateExpired {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if guest.Spec.Type == slackv1alpha1.SingleChannelGuest && len(guest.Spec.Channels) > 1 {
+		return reconcilerUtil.ManageError(r.Client, guest, fmt.Errorf("Single-channel guests can only be invited to one channel"), false)
+	}
+
+	if guest.Status.TeamID == "" {
+		guest.Status.TeamID = guest.Spec.TeamID
+		if guest.Status.TeamID == "" {
+			guest.Status.TeamID, err = r.SlackService.GetTeamID()
+			if err != nil {
+				return reconcilerUtil.ManageError(r.Client, guest, err, true)
+			}
+		}
+	}
+
+	user, err := r.SlackService.LookupUserByEmail(guest.Spec.Email)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, guest, err, true)
+	}
+
+	// Full members are never expired or deactivated, the email may belong to someone who isn't the guest
+	if user != nil && !isGuest(user) {
+		log.Info("User of the email isn't a guest, leaving them alone", "userID", user.ID)
+		return r.notAGuest(ctx, guest, user)
+	}
+
+	expiration := guest.Expiration()
+	if expiration != nil && !expiration.After(time.Now()) {
+		if user != nil {
+			log.Info("Deactivating expired guest", "userID", user.ID)
+			if err := r.SlackService.RemoveUserFromWorkspace(guest.Status.TeamID, user.ID); err != nil {
+				return reconcilerUtil.ManageError(r.Client, guest, err, true)
+			}
+		}
+		guest.Status.State = slackv1alpha1.InviteStateExpired
+		return reconcilerUtil.ManageSuccess(r.Client, guest)
+	}
+
+	if user != nil {
+		guest.Status.State = slackv1alpha1.InviteStateJoined
+		guest.Status.UserID = user.ID
+
+		if expiration != nil && !expiration.Equal(guest.Status.ExpiresAt) {
+			log.Info("Updating expiration of guest", "userID", user.ID, "expiresAt", expiration)
+			if err := r.SlackService.SetUserExpiration(guest.Status.TeamID, user.ID, expiration.Unix()); err != nil {
+				return reconcilerUtil.ManageError(r.Client, guest, err, true)
+			}
+		}
+		guest.Status.ExpiresAt = expiration
+	} else if guest.Status.State == "" {
+		invite, err := r.buildInvite(ctx, guest, expiration)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, guest, err, true)
+		}
+
+		log.Info("Inviting guest to workspace", "teamID", invite.TeamID)
+		err = r.SlackService.InviteUserToWorkspace(*invite)
+		if err != nil && !goerrors.Is(err, slack.ErrAlreadyInvited) {
+			return reconcilerUtil.ManageError(r.Client, guest, err, false)
+		}
+
+		now := metav1.Now()
+		guest.Status.State = slackv1alpha1.InviteStateInvited
+		guest.Status.InvitedAt = &now
+		guest.Status.ExpiresAt = expiration
+	}
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, guest)
+	if err != nil {
+		return result, err
+	}
+
+	return r.requeueGuest(guest, expiration)
+}
+
+// isGuest tells whether the user is a multi-channel or single-channel guest
+func isGuest(user *slackapi.User) bool {
+	return user.IsRestricted || user.IsUltraRestricted
+}
+
+// notAGuest holds the GuestUser whose email belongs to a full member of the workspace, the member is neither expired
+// nor deactivated
+func (r *GuestUserReconciler) notAGuest(ctx context.Context, guest *slackv1alpha1.GuestUser, user *slackapi.User) (ctrl.Result, error) {
+	guest.SetReconcileStatus([]metav1.Condition{{
+		Type:               "NotAGuest",
+		Status:             metav1.ConditionTrue,
+		Reason:             "NotAGuest",
+		Message:            fmt.Sprintf("The user %s of the email %s is a full member of the workspace, only guests are expired and deactivated", user.ID, guest.Spec.Email),
+		LastTransitionTime: metav1.Now(),
+	}})
+	if err := r.Status().Update(ctx, guest); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// requeueGuest requeues pending invitations to check for the guest joining, and expiring guests to deactivate them
+func (r *GuestUserReconciler) requeueGuest(guest *slackv1alpha1.GuestUser, expiration *metav1.Time) (ctrl.Result, error) {
+	requeueAfter := time.Duration(0)
+	if guest.Status.State == slackv1alpha1.InviteStateInvited {
+		requeueAfter = inviteCheckPeriod
+	}
+
+	if expiration != nil {
+		untilExpiration := time.Until(expiration.Time)
+		if requeueAfter == 0 || untilExpiration < requeueAfter {
+			requeueAfter = untilExpiration
+		}
+	}
+
+	if requeueAfter == 0 {
+		return reconcilerUtil.DoNotRequeue()
+	}
+	return reconcilerUtil.RequeueAfter(requeueAfter)
+}
+
+// buildInvite resolves the channels of the guest into a workspace invitation
+func (r *GuestUserReconciler) buildInvite(ctx context.Context, guest *slackv1alpha1.GuestUser, expiration *metav1.Time) (*slack.WorkspaceInvite, error) {
+	invite := &slack.WorkspaceInvite{
+		TeamID:          guest.Status.TeamID,
+		Email:           guest.Spec.Email,
+		RealName:        guest.Spec.RealName,
+		CustomMessage:   guest.Spec.CustomMessage,
+		Restricted:      guest.Spec.Type == slackv1alpha1.MultiChannelGuest,
+		UltraRestricted: guest.Spec.Type == slackv1alpha1.SingleChannelGuest,
+	}
+
+	if expiration != nil {
+		invite.GuestExpiration = expiration.Unix()
+	}
+
+	for _, ref := range guest.Spec.Channels {
+		channelID, err := pkgutil.GetChannelID(ctx, r.Client, ref.Key())
+		if err != nil {
+			return nil, err
+		}
+		invite.ChannelIDs = append(invite.ChannelIDs, channelID)
+	}
+
+	return invite, nil
+}
+
+func (r *GuestUserReconciler) finalizeGuestUser(ctx context.Context, guest *slackv1alpha1.GuestUser) (ctrl.Result, error) {
+	log := r.Log.WithValues("email", guest.Spec.Email)
+
+	// Only guests this GuestUser invited or saw join are deactivated
+	invited := guest.Status.State == slackv1alpha1.InviteStateInvited || guest.Status.State == slackv1alpha1.InviteStateJoined
+	if guest.Status.TeamID != "" && invited {
+		user, err := r.SlackService.LookupUserByEmail(guest.Spec.Email)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, guest, err, true)
+		}
+
+		if user != nil && isGuest(user) {
+			log.Info("Deactivating guest", "userID", user.ID)
+			if err := r.SlackService.RemoveUserFromWorkspace(guest.Status.TeamID, user.ID); err != nil {
+				return reconcilerUtil.ManageError(r.Client, guest, err, true)
+			}
+		} else if user != nil {
+			log.Info("User of the email isn't a guest, leaving them alone", "userID", user.ID)
+		}
+	}
+
+	guestPatchBase := client.MergeFrom(guest.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(guest, guestUserFinalizer)
+	log.V(1).Info("Finalizer removed for guest user")
+
+	err := r.Client.Patch(ctx, guest, guestPatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, guest, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *GuestUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.GuestUser{}).
+		Complete(r)
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	slackapi "github.com/slack-go/slack"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack/mockservice"
+)
+
+var _ = Describe("GuestUserController", func() {
+
+	var service *mockservice.Service
+
+	newGuestReconciler := func(guest *slackv1alpha1.GuestUser, user *slackapi.User) *GuestUserReconciler {
+		guestScheme := runtime.NewScheme()
+		Expect(slackv1alpha1.AddToScheme(guestScheme)).To(Succeed())
+
+		service = &mockservice.Service{
+			LookupUserByEmailFunc: func(string) (*slackapi.User, error) {
+				return user, nil
+			},
+		}
+		return &GuestUserReconciler{
+			Client:       fake.NewClientBuilder().WithScheme(guestScheme).WithObjects(guest).Build(),
+			Log:          log.WithName("GuestUser"),
+			Scheme:       guestScheme,
+			SlackService: service,
+		}
+	}
+
+	newGuest := func(state slackv1alpha1.InviteState, expiresAt time.Time) *slackv1alpha1.GuestUser {
+		expiration := metav1.NewTime(expiresAt)
+		return &slackv1alpha1.GuestUser{
+			ObjectMeta: metav1.ObjectMeta{Name: "contractor", Finalizers: []string{guestUserFinalizer}},
+			Spec: slackv1alpha1.GuestUserSpec{
+				Email:     "contractor@example.com",
+				Type:      slackv1alpha1.MultiChannelGuest,
+				ExpiresAt: &expiration,
+				TeamID:    "T0123",
+			},
+			Status: slackv1alpha1.GuestUserStatus{State: state, TeamID: "T0123"},
+		}
+	}
+
+	reconcile := func(r *GuestUserReconciler) *slackv1alpha1.GuestUser {
+		key := types.NamespacedName{Name: "contractor"}
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		guest := &slackv1alpha1.GuestUser{}
+		Expect(r.Get(context.Background(), key, guest)).To(Succeed())
+		return guest
+	}
+
+	member := &slackapi.User{ID: "U0123"}
+	guestAccount := &slackapi.User{ID: "U0456", IsRestricted: true}
+
+	Describe("Expiring a guest", func() {
+		Context("When the email belongs to a full member", func() {
+			It("should neither deactivate the member nor set their expiration", func() {
+				r := newGuestReconciler(newGuest("", time.Now().Add(-time.Hour)), member)
+
+				guest := reconcile(r)
+				Expect(service.Calls("RemoveUserFromWorkspace", "SetUserExpiration")).To(BeEmpty())
+				Expect(guest.Status.State).To(BeEmpty())
+				Expect(guest.Status.Conditions).To(HaveLen(1))
+				Expect(guest.Status.Conditions[0].Type).To(Equal("NotAGuest"))
+
+				r = newGuestReconciler(newGuest("", time.Now().Add(time.Hour)), member)
+				reconcile(r)
+				Expect(service.Calls("RemoveUserFromWorkspace", "SetUserExpiration")).To(BeEmpty())
+			})
+		})
+
+		Context("When the email belongs to a guest", func() {
+			It("should deactivate the guest once expired", func() {
+				r := newGuestReconciler(newGuest(slackv1alpha1.InviteStateJoined, time.Now().Add(-time.Hour)), guestAccount)
+
+				guest := reconcile(r)
+				Expect(guest.Status.State).To(Equal(slackv1alpha1.InviteStateExpired))
+				calls := service.Calls("RemoveUserFromWorkspace")
+				Expect(calls).To(HaveLen(1))
+				Expect(calls[0].Args).To(Equal([]interface{}{"T0123", "U0456"}))
+			})
+		})
+	})
+
+	Describe("Deleting a GuestUser", func() {
+		deleting := func(state slackv1alpha1.InviteState) *slackv1alpha1.GuestUser {
+			guest := newGuest(state, time.Now().Add(time.Hour))
+			now := metav1.Now()
+			guest.DeletionTimestamp = &now
+			return guest
+		}
+
+		It("should deactivate the guest it invited", func() {
+			r := newGuestReconciler(deleting(slackv1alpha1.InviteStateJoined), guestAccount)
+			guest := reconcile(r)
+			Expect(service.Calls("RemoveUserFromWorkspace")).To(HaveLen(1))
+			Expect(guest.Finalizers).To(BeEmpty())
+		})
+
+		It("should not deactivate a full member", func() {
+			r := newGuestReconciler(deleting(slackv1alpha1.InviteStateJoined), member)
+			guest := reconcile(r)
+			Expect(service.Calls("RemoveUserFromWorkspace")).To(BeEmpty())
+			Expect(guest.Finalizers).To(BeEmpty())
+		})
+
+		It("should not deactivate a guest it didn't invite", func() {
+			r := newGuestReconciler(deleting(""), guestAccount)
+			guest := reconcile(r)
+			Expect(service.Calls("RemoveUserFromWorkspace", "LookupUserByEmail")).To(BeEmpty())
+			Expect(guest.Finalizers).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,205 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+// MessageReconciler reconciles a Message object
+type MessageReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=messages,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=messages/status,verbs=get;update;patch
+
+// Reconcile loop for the Message resource, it posts the message and updates it when the spec changes
+func (r *MessageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("message", req.NamespacedName)
+
+	message := &slackv1alpha1.Message{}
+	err := r.Get(ctx, req.NamespacedName, message)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if message.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if message.Status.Timestamp != "" && message.Status.ObservedGeneration == message.Generation {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	err = validateMessage(message)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, message, err, false)
+	}
+
+	channelID, err := pkgutil.GetChannelID(ctx, r.Client, pkgutil.ChannelReferenceKey(message.Spec.Channel, message.Namespace))
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, message, err, true)
+	}
+
+	threadTimestamp, err := r.getThreadTimestamp(ctx, message, channelID)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, message, err, true)
+	}
+
+	options, err := pkgutil.MessageOptions(ctx, r.Client, message.Spec.Text, message.Spec.TemplateRef, message.Namespace, message)
+	if err != nil {
+		log.Error(err, "Unable to render message")
+		return reconcilerUtil.ManageError(r.Client, message, err, true)
+	}
+
+	// Messages moved to another channel are posted again, otherwise the posted message is updated in place
+	if message.Status.Timestamp != "" && message.Status.ChannelID == channelID && message.Status.ThreadTimestamp == threadTimestamp {
+		log.Info("Updating message", "ts", message.Status.Timestamp)
+		err = r.SlackService.UpdateMessage(channelID, message.Status.Timestamp, options...)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, message, err, true)
+		}
+	} else {
+		if threadTimestamp != "" {
+			options = append(options, slackapi.MsgOptionTS(threadTimestamp))
+			if message.Spec.ReplyBroadcast {
+				options = append(options, slackapi.MsgOptionBroadcast())
+			}
+		}
+
+		log.Info("Posting message", "channelID", channelID, "threadTs", threadTimestamp)
+		timestamp, err := r.SlackService.PostMessage(channelID, options...)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, message, err, true)
+		}
+
+		message.Status.ChannelID = channelID
+		message.Status.Timestamp = timestamp
+		message.Status.ThreadTimestamp = threadTimestamp
+	}
+
+	message.Status.ObservedGeneration = message.Generation
+
+	return reconcilerUtil.ManageSuccess(r.Client, message)
+}
+
+// getThreadTimestamp resolves the timestamp of the thread the message replies to, empty when it isn't a reply
+func (r *MessageReconciler) getThreadTimestamp(ctx context.Context, message *slackv1alpha1.Message, channelID string) (string, error) {
+	threadOf := message.Spec.ThreadOf
+	if threadOf == nil {
+		return "", nil
+	}
+
+	if threadOf.Timestamp != "" {
+		return threadOf.Timestamp, nil
+	}
+
+	key := types.NamespacedName{Namespace: message.Namespace, Name: threadOf.Message}
+	parent := &slackv1alpha1.Message{}
+	if err := r.Get(ctx, key, parent); err != nil {
+		return "", fmt.Errorf("Error fetching parent Message %s: %v", key, err)
+	}
+
+	if parent.Status.Timestamp == "" {
+		return "", fmt.Errorf("Parent Message %s has not been posted yet", key)
+	}
+
+	if parent.Status.ChannelID != channelID {
+		return "", fmt.Errorf("Parent Message %s was posted to a different channel", key)
+	}
+
+	// Slack threads are only one level deep, replies to a reply go to the same thread
+	if parent.Status.ThreadTimestamp != "" {
+		return parent.Status.ThreadTimestamp, nil
+	}
+
+	return parent.Status.Timestamp, nil
+}
+
+func validateMessage(message *slackv1alpha1.Message) error {
+	if message.Spec.Text == "" && message.Spec.TemplateRef == nil {
+		return fmt.Errorf("Either text or templateRef must be set")
+	}
+
+	threadOf := message.Spec.ThreadOf
+	if threadOf == nil {
+		if message.Spec.ReplyBroadcast {
+			return fmt.Errorf("replyBroadcast can only be set together with threadOf")
+		}
+		return nil
+	}
+
+	if (threadOf.Message == "") == (threadOf.Timestamp == "") {
+		return fmt.Errorf("Exactly one of threadOf.message and threadOf.ts must be set")
+	}
+
+	if threadOf.Message == message.Name {
+		return fmt.Errorf("Message can not be a reply to itself")
+	}
+
+	return nil
+}
+
+// repliesOf maps a Message to the Messages replying to it, so replies are posted once their parent is
+func (r *MessageReconciler) repliesOf(obj client.Object) []reconcile.Request {
+	messageList := &slackv1alpha1.MessageList{}
+	if err := r.List(context.Background(), messageList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Unable to list messages", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, message := range messageList.Items {
+		if message.Spec.ThreadOf != nil && message.Spec.ThreadOf.Message == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: message.Namespace, Name: message.Name}})
+		}
+	}
+
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *MessageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.Message{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Message{}}, handler.EnqueueRequestsFromMapFunc(r.repliesOf)).
+		Complete(r)
+}
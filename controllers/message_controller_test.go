@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+var _ = Describe("MessageController", func() {
+
+	var message *slackv1alpha1.Message
+
+	BeforeEach(func() {
+		message = &slackv1alpha1.Message{
+			ObjectMeta: metav1.ObjectMeta{Name: "reply", Namespace: ns},
+			Spec: slackv1alpha1.MessageSpec{
+				Channel: slackv1alpha1.ChannelReference{Name: "incidents"},
+				Text:    "Mitigation deployed",
+			},
+		}
+	})
+
+	Describe("Validating Message resource", func() {
+		Context("With threadOf referencing a message", func() {
+			It("should be valid", func() {
+				message.Spec.ThreadOf = &slackv1alpha1.ThreadReference{Message: "incident"}
+				message.Spec.ReplyBroadcast = true
+
+				Expect(validateMessage(message)).To(Succeed())
+			})
+		})
+
+		Context("With both threadOf.message and threadOf.ts", func() {
+			It("should be invalid", func() {
+				message.Spec.ThreadOf = &slackv1alpha1.ThreadReference{Message: "incident", Timestamp: "1503435956.000247"}
+
+				Expect(validateMessage(message)).NotTo(Succeed())
+			})
+		})
+
+		Context("With replyBroadcast but no threadOf", func() {
+			It("should be invalid", func() {
+				message.Spec.ReplyBroadcast = true
+
+				Expect(validateMessage(message)).NotTo(Succeed())
+			})
+		})
+
+		Context("With threadOf referencing itself", func() {
+			It("should be invalid", func() {
+				message.Spec.ThreadOf = &slackv1alpha1.ThreadReference{Message: "reply"}
+
+				Expect(validateMessage(message)).NotTo(Succeed())
+			})
+		})
+	})
+})
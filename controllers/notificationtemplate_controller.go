@@ -0,0 +1,101 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/blockkit"
+)
+
+// NotificationTemplateReconciler reconciles a NotificationTemplate object
+type NotificationTemplateReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=notificationtemplates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=notificationtemplates/status,verbs=get;update;patch
+
+// Reconcile loop for the NotificationTemplate resource, it validates the template and test-renders it when requested
+func (r *NotificationTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("notificationtemplate", req.NamespacedName)
+
+	notificationTemplate := &slackv1alpha1.NotificationTemplate{}
+	err := r.Get(ctx, req.NamespacedName, notificationTemplate)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if notificationTemplate.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	tmpl, err := blockkit.Compile(req.NamespacedName.String(), notificationTemplate.Spec.Blocks, notificationTemplate.Spec.Text)
+	if err != nil {
+		log.Error(err, "Invalid template")
+		return reconcilerUtil.ManageError(r.Client, notificationTemplate, err, false)
+	}
+
+	notificationTemplate.Status.TestRender = nil
+	if data, found := notificationTemplate.Annotations[slackv1alpha1.TestRenderAnnotation]; found {
+		notificationTemplate.Status.TestRender = testRender(tmpl, data)
+	}
+
+	return reconcilerUtil.ManageSuccess(r.Client, notificationTemplate)
+}
+
+// testRender renders the template with the JSON data, errors are reported in the result
+func testRender(tmpl *blockkit.Template, data string) *slackv1alpha1.TestRenderStatus {
+	var values interface{}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return &slackv1alpha1.TestRenderStatus{Error: fmt.Sprintf("Invalid test render data: %v", err)}
+	}
+
+	blocks, text, err := tmpl.Render(values)
+	if err != nil {
+		return &slackv1alpha1.TestRenderStatus{Error: err.Error()}
+	}
+
+	rendered, err := json.Marshal(blocks)
+	if err != nil {
+		return &slackv1alpha1.TestRenderStatus{Error: err.Error()}
+	}
+
+	return &slackv1alpha1.TestRenderStatus{Blocks: string(rendered), Text: text}
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *NotificationTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.NotificationTemplate{}).
+		Complete(r)
+}
@@ -0,0 +1,156 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/oncall"
+	"github.com/stakater/slack-operator/pkg/shard"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// OnCallTopicReconciler polls the PagerDuty and Opsgenie schedules of Channels and records who is on call in their
+// status, the ChannelReconciler renders them into the topic
+type OnCallTopicReconciler struct {
+	client.Client
+	Log          logr.Logger
+	SlackService slack.Service
+
+	// Schedules looks up who is on call for a schedule
+	Schedules *oncall.Client
+
+	// OnCallPeriod is how often the schedules are polled
+	OnCallPeriod time.Duration
+
+	// Shard is the part of the Channels this deployment reconciles, nil to reconcile all of them
+	Shard *shard.Shard
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list
+
+// Reconcile refreshes the on-call engineers of the schedule of a Channel once they are older than the OnCallPeriod
+func (r *OnCallTopicReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("channel", req.NamespacedName)
+
+	channel := &slackv1alpha1.Channel{}
+	err := r.Get(ctx, req.NamespacedName, channel)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	if !r.Shard.Owns(channel) || channel.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if channel.Spec.OnCall == nil {
+		if channel.Status.OnCall != nil {
+			patchBase := client.MergeFrom(channel.DeepCopy())
+			channel.Status.OnCall = nil
+			if err := r.Status().Patch(ctx, channel, patchBase); err != nil {
+				return reconcilerUtil.RequeueWithError(err)
+			}
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	ref := channel.Spec.OnCall.ScheduleRef
+	schedule := fmt.Sprintf("%s/%s", ref.Provider, ref.ID)
+	now := time.Now()
+	if status := channel.Status.OnCall; status != nil && status.Schedule == schedule {
+		if next := status.UpdatedAt.Add(r.OnCallPeriod); now.Before(next) {
+			return reconcilerUtil.RequeueAfter(next.Sub(now))
+		}
+	}
+
+	token, err := r.getToken(ctx, channel.Namespace, ref.TokenSecretRef)
+	if err != nil {
+		log.Error(err, "Unable to read the on-call API token")
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	emails, err := r.Schedules.OnCall(string(ref.Provider), ref.ID, token)
+	if err != nil {
+		log.Error(err, "Unable to read the on-call engineers", "schedule", schedule)
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	status := &slackv1alpha1.OnCallStatus{Schedule: schedule, UpdatedAt: metav1.Time{Time: now}}
+	for _, email := range emails {
+		user, err := r.SlackService.LookupUserByEmail(email)
+		if err != nil {
+			return reconcilerUtil.RequeueWithError(err)
+		}
+		if user == nil {
+			status.UnknownEmails = append(status.UnknownEmails, email)
+			continue
+		}
+		status.Users = append(status.Users, user.ID)
+	}
+
+	patchBase := client.MergeFrom(channel.DeepCopy())
+	channel.Status.OnCall = status
+	if err := r.Status().Patch(ctx, channel, patchBase); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	log.V(1).Info("Refreshed on-call engineers", "schedule", schedule, "users", status.Users)
+
+	return reconcilerUtil.RequeueAfter(r.OnCallPeriod)
+}
+
+// getToken reads the API token of the on-call provider from a Secret in the namespace
+func (r *OnCallTopicReconciler) getToken(ctx context.Context, namespace string, ref slackv1alpha1.KeyReference) (string, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return "", fmt.Errorf("Error fetching Secret %s: %v", key, err)
+	}
+
+	token, found := secret.Data[ref.Key]
+	if !found || len(token) == 0 {
+		return "", fmt.Errorf("Secret %s has no key %s", key, ref.Key)
+	}
+
+	return string(token), nil
+}
+
+// SetupWithManager - Controller-Manager binding configuration, it's a second controller of Channels next to the
+// ChannelReconciler which only reacts to spec changes
+func (r *OnCallTopicReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("oncall-topic").
+		For(&slackv1alpha1.Channel{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}, r.Shard.Predicate())).
+		Complete(r)
+}
@@ -0,0 +1,150 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+var (
+	reminderFinalizer string = "slack.stakater.com/reminder"
+)
+
+// ReminderReconciler reconciles a Reminder object
+type ReminderReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=reminders,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=reminders/status,verbs=get;update;patch
+
+// Reconcile loop for the Reminder resource, reminders can't be edited so they are replaced when the spec changes
+func (r *ReminderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("reminder", req.NamespacedName)
+
+	reminder := &slackv1alpha1.Reminder{}
+	err := r.Get(ctx, req.NamespacedName, reminder)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// Reminder is marked for deletion
+	if reminder.GetDeletionTimestamp() != nil {
+		if finalizerUtil.HasFinalizer(reminder, reminderFinalizer) {
+			return r.finalizeReminder(ctx, reminder)
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(reminder, reminderFinalizer) {
+		log.Info("Adding finalizer for reminder " + req.Name)
+
+		reminderPatchBase := client.MergeFrom(reminder.DeepCopy())
+		finalizerUtil.AddFinalizer(reminder, reminderFinalizer)
+
+		err := r.Client.Patch(ctx, reminder, reminderPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, reminder, err, true)
+		}
+	}
+
+	if (reminder.Spec.Channel == nil) == (reminder.Spec.User == "") {
+		return reconcilerUtil.ManageError(r.Client, reminder, fmt.Errorf("Exactly one of channel and user must be set"), false)
+	}
+
+	channelID := ""
+	if reminder.Spec.Channel != nil {
+		channelID, err = pkgutil.GetChannelID(ctx, r.Client, pkgutil.ChannelReferenceKey(*reminder.Spec.Channel, reminder.Namespace))
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, reminder, err, true)
+		}
+	}
+
+	hash := contentHash(channelID, reminder.Spec.User, reminder.Spec.Time, []byte(reminder.Spec.Text))
+	if reminder.Status.ReminderID != "" && reminder.Status.SpecHash == hash {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if reminder.Status.ReminderID != "" {
+		log.Info("Deleting outdated reminder", "reminderID", reminder.Status.ReminderID)
+		if err := r.SlackService.DeleteReminder(reminder.Status.ReminderID); err != nil {
+			return reconcilerUtil.ManageError(r.Client, reminder, err, true)
+		}
+		reminder.Status.ReminderID = ""
+	}
+
+	log.Info("Adding reminder", "channelID", channelID, "user", reminder.Spec.User)
+	reminderID, err := r.SlackService.AddReminder(channelID, reminder.Spec.User, reminder.Spec.Text, reminder.Spec.Time)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, reminder, err, true)
+	}
+
+	reminder.Status.ReminderID = reminderID
+	reminder.Status.SpecHash = hash
+
+	return reconcilerUtil.ManageSuccess(r.Client, reminder)
+}
+
+func (r *ReminderReconciler) finalizeReminder(ctx context.Context, reminder *slackv1alpha1.Reminder) (ctrl.Result, error) {
+	log := r.Log.WithValues("reminderID", reminder.Status.ReminderID)
+
+	if reminder.Status.ReminderID != "" {
+		log.Info("Deleting reminder")
+		if err := r.SlackService.DeleteReminder(reminder.Status.ReminderID); err != nil {
+			return reconcilerUtil.ManageError(r.Client, reminder, err, true)
+		}
+	}
+
+	reminderPatchBase := client.MergeFrom(reminder.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(reminder, reminderFinalizer)
+	log.V(1).Info("Finalizer removed for reminder")
+
+	err := r.Client.Patch(ctx, reminder, reminderPatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, reminder, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *ReminderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.Reminder{}).
+		Complete(r)
+}
@@ -0,0 +1,227 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/scim"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+var (
+	scimGroupFinalizer string = "slack.stakater.com/scimgroup"
+)
+
+// SCIMGroupReconciler reconciles a SCIMGroup object
+type SCIMGroupReconciler struct {
+	client.Client
+	Log        logr.Logger
+	Scheme     *runtime.Scheme
+	SCIMClient *scim.Client
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=scimgroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=scimgroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=useroffboardings,verbs=get;list;watch
+
+// Reconcile loop for the SCIMGroup resource, it provisions the group and keeps its members in sync
+func (r *SCIMGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("scimgroup", req.NamespacedName)
+
+	group := &slackv1alpha1.SCIMGroup{}
+	err := r.Get(ctx, req.NamespacedName, group)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if r.SCIMClient == nil {
+		return reconcilerUtil.ManageError(r.Client, group, fmt.Errorf("SCIM isn't configured for the operator"), false)
+	}
+
+	// SCIMGroup is marked for deletion
+	if group.GetDeletionTimestamp() != nil {
+		if finalizerUtil.HasFinalizer(group, scimGroupFinalizer) {
+			return r.finalizeSCIMGroup(ctx, group)
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(group, scimGroupFinalizer) {
+		log.Info("Adding finalizer for SCIM group " + req.Name)
+
+		groupPatchBase := client.MergeFrom(group.DeepCopy())
+		finalizerUtil.AddFinalizer(group, scimGroupFinalizer)
+
+		err := r.Client.Patch(ctx, group, groupPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, group, err, true)
+		}
+	}
+
+	userIDs, memberErrors, err := r.resolveMembers(ctx, group)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, group, err, true)
+	}
+	group.Status.MemberErrors = memberErrors
+
+	if group.Status.GroupID == "" {
+		existing, err := r.SCIMClient.GetGroupByName(group.Spec.DisplayName)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, group, err, true)
+		}
+
+		if existing == nil {
+			log.Info("Provisioning SCIM group", "displayName", group.Spec.DisplayName)
+			existing, err = r.SCIMClient.CreateGroup(group.Spec.DisplayName, userIDs)
+			if err != nil {
+				return reconcilerUtil.ManageError(r.Client, group, err, true)
+			}
+		}
+		group.Status.GroupID = existing.ID
+	}
+
+	existing, err := r.SCIMClient.GetGroup(group.Status.GroupID)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, group, err, true)
+	}
+
+	add, remove := diffGroupMembers(existing.Members, userIDs)
+	if err := r.SCIMClient.UpdateGroupMembers(existing.ID, add, remove); err != nil {
+		return reconcilerUtil.ManageError(r.Client, group, err, true)
+	}
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, group)
+	if err != nil {
+		return result, err
+	}
+	return reconcilerUtil.RequeueAfter(groupResyncPeriod)
+}
+
+// resolveMembers returns the SCIM user IDs of the members, provisioning missing users if enabled. Users with a
+// UserOffboarding are left out.
+func (r *SCIMGroupReconciler) resolveMembers(ctx context.Context, group *slackv1alpha1.SCIMGroup) ([]string, []slackv1alpha1.MemberError, error) {
+	var userIDs []string
+	var memberErrors []slackv1alpha1.MemberError
+
+	offboarded, err := pkgutil.GetOffboardedEmails(ctx, r.Client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, email := range group.Spec.Members {
+		if offboarded[strings.ToLower(email)] {
+			memberErrors = append(memberErrors, slackv1alpha1.MemberError{User: email, Reason: slack.MemberOffboarded})
+			continue
+		}
+
+		user, err := r.SCIMClient.GetUserByEmail(email)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if user == nil && group.Spec.ProvisionUsers {
+			r.Log.Info("Provisioning user", "email", email)
+			user, err = r.SCIMClient.CreateUser(email, "")
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if user == nil {
+			memberErrors = append(memberErrors, slackv1alpha1.MemberError{User: email, Reason: slack.MemberNotFound})
+		} else if !user.Active {
+			memberErrors = append(memberErrors, slackv1alpha1.MemberError{User: email, Reason: slack.MemberDeactivated})
+		} else {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+
+	return userIDs, memberErrors, nil
+}
+
+// diffGroupMembers returns the user IDs to add to and remove from the group
+func diffGroupMembers(members []scim.Member, userIDs []string) ([]string, []string) {
+	current := map[string]bool{}
+	for _, member := range members {
+		current[member.Value] = true
+	}
+
+	var add []string
+	desired := map[string]bool{}
+	for _, id := range userIDs {
+		desired[id] = true
+		if !current[id] {
+			add = append(add, id)
+		}
+	}
+
+	var remove []string
+	for _, member := range members {
+		if !desired[member.Value] {
+			remove = append(remove, member.Value)
+		}
+	}
+
+	return add, remove
+}
+
+func (r *SCIMGroupReconciler) finalizeSCIMGroup(ctx context.Context, group *slackv1alpha1.SCIMGroup) (ctrl.Result, error) {
+	log := r.Log.WithValues("groupID", group.Status.GroupID)
+
+	if group.Status.GroupID != "" {
+		log.Info("Deleting SCIM group")
+		if err := r.SCIMClient.DeleteGroup(group.Status.GroupID); err != nil {
+			return reconcilerUtil.ManageError(r.Client, group, err, true)
+		}
+	}
+
+	groupPatchBase := client.MergeFrom(group.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(group, scimGroupFinalizer)
+	log.V(1).Info("Finalizer removed for SCIM group")
+
+	err := r.Client.Patch(ctx, group, groupPatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, group, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *SCIMGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.SCIMGroup{}).
+		Complete(r)
+}
@@ -0,0 +1,225 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	finalizerUtil "github.com/stakater/operator-utils/util/finalizer"
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+var (
+	slackAppFinalizer string = "slack.stakater.com/slackapp"
+)
+
+// SlackAppReconciler reconciles a SlackApp object
+type SlackAppReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=slackapps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=slackapps/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+
+// Reconcile loop for the SlackApp resource, it creates the app from its manifest and applies manifest changes
+func (r *SlackAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("slackapp", req.NamespacedName)
+
+	app := &slackv1alpha1.SlackApp{}
+	err := r.Get(ctx, req.NamespacedName, app)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	// SlackApp is marked for deletion
+	if app.GetDeletionTimestamp() != nil {
+		if finalizerUtil.HasFinalizer(app, slackAppFinalizer) {
+			return r.finalizeSlackApp(ctx, app)
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Add finalizer if it doesn't exist
+	if !finalizerUtil.HasFinalizer(app, slackAppFinalizer) {
+		log.Info("Adding finalizer for slackapp " + req.Name)
+
+		appPatchBase := client.MergeFrom(app.DeepCopy())
+		finalizerUtil.AddFinalizer(app, slackAppFinalizer)
+
+		err := r.Client.Patch(ctx, app, appPatchBase)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, app, err, true)
+		}
+	}
+
+	manifest := app.Spec.Manifest.Raw
+	if len(manifest) == 0 {
+		return reconcilerUtil.ManageError(r.Client, app, fmt.Errorf("Manifest can not be empty"), false)
+	}
+	hash := sha256.Sum256(manifest)
+	manifestHash := hex.EncodeToString(hash[:])
+
+	if app.Status.AppID != "" && app.Status.ManifestHash == manifestHash {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	configToken, err := r.getConfigToken(ctx, app)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, app, err, true)
+	}
+
+	if app.Status.AppID == "" {
+		log.Info("Creating app from manifest")
+		credentials, err := r.SlackService.CreateApp(configToken, manifest)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, app, err, false)
+		}
+
+		// The app ID is recorded right away so a failure below doesn't create the app twice
+		appPatchBase := client.MergeFrom(app.DeepCopy())
+		app.Status.AppID = credentials.AppID
+		app.Status.ClientID = credentials.ClientID
+		app.Status.OAuthAuthorizeURL = credentials.OAuthAuthorizeURL
+		app.Status.ManifestHash = manifestHash
+
+		err = r.Status().Patch(ctx, app, appPatchBase)
+		if err != nil {
+			log.Error(err, "Failed to update SlackApp status")
+			return reconcilerUtil.ManageError(r.Client, app, err, true)
+		}
+
+		if app.Spec.CredentialsSecretName != "" {
+			err = r.writeCredentials(ctx, app, credentials)
+			if err != nil {
+				log.Error(err, "Failed to write app credentials")
+				return reconcilerUtil.ManageError(r.Client, app, err, false)
+			}
+		}
+
+		return reconcilerUtil.ManageSuccess(r.Client, app)
+	}
+
+	log.Info("Updating app manifest", "appID", app.Status.AppID)
+	err = r.SlackService.UpdateApp(configToken, app.Status.AppID, manifest)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, app, err, false)
+	}
+
+	app.Status.ManifestHash = manifestHash
+
+	return reconcilerUtil.ManageSuccess(r.Client, app)
+}
+
+// getConfigToken reads the app configuration token from its Secret
+func (r *SlackAppReconciler) getConfigToken(ctx context.Context, app *slackv1alpha1.SlackApp) (string, error) {
+	ref := app.Spec.ConfigTokenSecretRef
+	key := types.NamespacedName{Namespace: app.Namespace, Name: ref.Name}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return "", fmt.Errorf("Error fetching Secret %s: %v", key, err)
+	}
+
+	token, found := secret.Data[ref.Key]
+	if !found || len(token) == 0 {
+		return "", fmt.Errorf("Secret %s has no key %s", key, ref.Key)
+	}
+
+	return string(token), nil
+}
+
+// writeCredentials creates the credentials Secret owned by the SlackApp
+func (r *SlackAppReconciler) writeCredentials(ctx context.Context, app *slackv1alpha1.SlackApp, credentials *slack.AppCredentials) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app.Spec.CredentialsSecretName,
+			Namespace: app.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.StringData = map[string]string{
+			"appId":             credentials.AppID,
+			"clientId":          credentials.ClientID,
+			"clientSecret":      credentials.ClientSecret,
+			"verificationToken": credentials.VerificationToken,
+			"signingSecret":     credentials.SigningSecret,
+		}
+		return controllerutil.SetControllerReference(app, secret, r.Scheme)
+	})
+
+	return err
+}
+
+func (r *SlackAppReconciler) finalizeSlackApp(ctx context.Context, app *slackv1alpha1.SlackApp) (ctrl.Result, error) {
+	log := r.Log.WithValues("appID", app.Status.AppID)
+
+	if app.Status.AppID != "" && app.Spec.DeletionPolicy == slackv1alpha1.AppDeletionPolicyDelete {
+		configToken, err := r.getConfigToken(ctx, app)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, app, err, true)
+		}
+
+		log.Info("Deleting app")
+		if err := r.SlackService.DeleteApp(configToken, app.Status.AppID); err != nil {
+			return reconcilerUtil.ManageError(r.Client, app, err, true)
+		}
+	}
+
+	appPatchBase := client.MergeFrom(app.DeepCopy())
+
+	finalizerUtil.DeleteFinalizer(app, slackAppFinalizer)
+	log.V(1).Info("Finalizer removed for slackapp")
+
+	err := r.Client.Patch(ctx, app, appPatchBase)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, app, err, false)
+	}
+
+	return reconcilerUtil.DoNotRequeue()
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *SlackAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.SlackApp{}).
+		Owns(&corev1.Secret{}, builder.OnlyMetadata).
+		Complete(r)
+}
@@ -0,0 +1,120 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/shard"
+	"github.com/stakater/slack-operator/pkg/templating"
+)
+
+// defaultTopicRefreshInterval is how often topic URLs are fetched when the Channel doesn't set an interval
+var defaultTopicRefreshInterval = 5 * time.Minute
+
+// TopicSourceReconciler fetches the topic URLs of Channels and records the text in their status, the
+// ChannelReconciler renders it into the topic
+type TopicSourceReconciler struct {
+	client.Client
+	Log logr.Logger
+
+	// HTTPClient fetches the topic URLs
+	HTTPClient *http.Client
+
+	// Shard is the part of the Channels this deployment reconciles, nil to reconcile all of them
+	Shard *shard.Shard
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels/status,verbs=get;update;patch
+
+// Reconcile fetches the topic URL of a Channel once the text in its status is older than the refresh interval
+func (r *TopicSourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("channel", req.NamespacedName)
+
+	channel := &slackv1alpha1.Channel{}
+	err := r.Get(ctx, req.NamespacedName, channel)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+	if !r.Shard.Owns(channel) || channel.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	// Texts of ConfigMaps are read by the ChannelReconciler, which watches them
+	source := channel.Spec.TopicFrom
+	if source == nil || source.URL == "" {
+		if channel.Status.TopicSource != nil {
+			patchBase := client.MergeFrom(channel.DeepCopy())
+			channel.Status.TopicSource = nil
+			if err := r.Status().Patch(ctx, channel, patchBase); err != nil {
+				return reconcilerUtil.RequeueWithError(err)
+			}
+		}
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	interval := defaultTopicRefreshInterval
+	if source.RefreshInterval != nil && source.RefreshInterval.Duration > 0 {
+		interval = source.RefreshInterval.Duration
+	}
+
+	now := time.Now()
+	if status := channel.Status.TopicSource; status != nil && status.URL == source.URL {
+		if next := status.UpdatedAt.Add(interval); now.Before(next) {
+			return reconcilerUtil.RequeueAfter(next.Sub(now))
+		}
+	}
+
+	text, err := templating.Fetch(r.HTTPClient, source.URL)
+	if err != nil {
+		log.Error(err, "Unable to fetch the topic source", "url", source.URL)
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	patchBase := client.MergeFrom(channel.DeepCopy())
+	channel.Status.TopicSource = &slackv1alpha1.TopicSourceStatus{URL: source.URL, Text: text, UpdatedAt: metav1.Time{Time: now}}
+	if err := r.Status().Patch(ctx, channel, patchBase); err != nil {
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	return reconcilerUtil.RequeueAfter(interval)
+}
+
+// SetupWithManager - Controller-Manager binding configuration, it's a second controller of Channels next to the
+// ChannelReconciler which only reacts to spec changes
+func (r *TopicSourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("topic-source").
+		For(&slackv1alpha1.Channel{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}, r.Shard.Predicate())).
+		Complete(r)
+}
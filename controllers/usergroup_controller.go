@@ -0,0 +1,168 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+// userGroupResyncPeriod is how often the default channels of usergroups are checked for changes made outside of
+// the operator
+var userGroupResyncPeriod = time.Hour
+
+// UserGroupReconciler reconciles a UserGroup object
+type UserGroupReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=usergroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=usergroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+
+// Reconcile loop for the UserGroup resource, it syncs the default channels of the usergroup and adds it to the
+// workspaces of the spec
+func (r *UserGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("usergroup", req.NamespacedName)
+
+	group := &slackv1alpha1.UserGroup{}
+	err := r.Get(ctx, req.NamespacedName, group)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if group.GetDeletionTimestamp() != nil {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	desired := append([]string{}, group.Spec.DefaultChannelIDs...)
+	for _, ref := range group.Spec.DefaultChannels {
+		channelID, err := pkgutil.GetChannelID(ctx, r.Client, ref.Key())
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, group, err, true)
+		}
+		desired = append(desired, channelID)
+	}
+
+	current, err := r.SlackService.ListUsergroupChannels(group.Spec.ID)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, group, err, true)
+	}
+
+	add, remove := diffIDs(current, desired)
+	if len(add) > 0 {
+		log.Info("Adding default channels to usergroup", "channelIDs", add)
+		if err := r.SlackService.AddUsergroupChannels(group.Spec.ID, add); err != nil {
+			return reconcilerUtil.ManageError(r.Client, group, err, true)
+		}
+	}
+	if len(remove) > 0 {
+		log.Info("Removing default channels from usergroup", "channelIDs", remove)
+		if err := r.SlackService.RemoveUsergroupChannels(group.Spec.ID, remove); err != nil {
+			return reconcilerUtil.ManageError(r.Client, group, err, true)
+		}
+	}
+	group.Status.DefaultChannelIDs = desired
+
+	// Workspaces can't be listed, so only those which weren't added before are added
+	teams, _ := diffIDs(group.Status.Teams, group.Spec.Teams)
+	if len(teams) > 0 {
+		log.Info("Adding usergroup to workspaces", "teamIDs", teams)
+		if err := r.SlackService.AddUsergroupTeams(group.Spec.ID, teams); err != nil {
+			return reconcilerUtil.ManageError(r.Client, group, err, true)
+		}
+		group.Status.Teams = append(group.Status.Teams, teams...)
+	}
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, group)
+	if err != nil {
+		return result, err
+	}
+	return reconcilerUtil.RequeueAfter(userGroupResyncPeriod)
+}
+
+// diffIDs returns the desired IDs which are missing from the current IDs and the current IDs which aren't desired
+func diffIDs(current []string, desired []string) ([]string, []string) {
+	currentSet := map[string]bool{}
+	for _, id := range current {
+		currentSet[id] = true
+	}
+	desiredSet := map[string]bool{}
+	var add []string
+	for _, id := range desired {
+		if !currentSet[id] && !desiredSet[id] {
+			add = append(add, id)
+		}
+		desiredSet[id] = true
+	}
+	var remove []string
+	for _, id := range current {
+		if !desiredSet[id] {
+			remove = append(remove, id)
+		}
+	}
+	return add, remove
+}
+
+// userGroupsOf maps a Channel to the UserGroups it's a default channel of
+func (r *UserGroupReconciler) userGroupsOf(obj client.Object) []reconcile.Request {
+	groups := &slackv1alpha1.UserGroupList{}
+	if err := r.List(context.Background(), groups); err != nil {
+		r.Log.Error(err, "Error listing UserGroups")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, group := range groups.Items {
+		for _, ref := range group.Spec.DefaultChannels {
+			if ref.Key() == client.ObjectKeyFromObject(obj) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: group.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *UserGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.UserGroup{}).
+		Watches(&source.Kind{Type: &slackv1alpha1.Channel{}}, handler.EnqueueRequestsFromMapFunc(r.userGroupsOf)).
+		Complete(r)
+}
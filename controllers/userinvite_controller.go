@@ -0,0 +1,147 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	goerrors "errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+var (
+	// inviteCheckPeriod is how often pending invitations are checked for the user joining
+	inviteCheckPeriod = time.Hour
+)
+
+// UserInviteReconciler reconciles a UserInvite object
+type UserInviteReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=userinvites,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=userinvites/status,verbs=get;update;patch
+
+// Reconcile loop for the UserInvite resource, it invites the user once and tracks whether they joined
+func (r *UserInviteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("userinvite", req.NamespacedName)
+
+	invite := &slackv1alpha1.UserInvite{}
+	err := r.Get(ctx, req.NamespacedName, invite)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if invite.GetDeletionTimestamp() != nil || invite.Status.State == slackv1alpha1.InviteStateJoined {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	user, err := r.SlackService.LookupUserByEmail(invite.Spec.Email)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, invite, err, true)
+	}
+
+	if user != nil {
+		log.Info("User joined the workspace", "userID", user.ID)
+		invite.Status.State = slackv1alpha1.InviteStateJoined
+		invite.Status.UserID = user.ID
+		return reconcilerUtil.ManageSuccess(r.Client, invite)
+	}
+
+	if invite.Status.State == slackv1alpha1.InviteStateInvited {
+		return reconcilerUtil.RequeueAfter(inviteCheckPeriod)
+	}
+
+	workspaceInvite, err := r.buildInvite(ctx, invite)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, invite, err, true)
+	}
+
+	log.Info("Inviting user to workspace", "teamID", workspaceInvite.TeamID)
+	err = r.SlackService.InviteUserToWorkspace(*workspaceInvite)
+	if err != nil && !goerrors.Is(err, slack.ErrAlreadyInvited) {
+		return reconcilerUtil.ManageError(r.Client, invite, err, false)
+	}
+
+	now := metav1.Now()
+	invite.Status.State = slackv1alpha1.InviteStateInvited
+	invite.Status.InvitedAt = &now
+
+	result, err := reconcilerUtil.ManageSuccess(r.Client, invite)
+	if err != nil {
+		return result, err
+	}
+	return reconcilerUtil.RequeueAfter(inviteCheckPeriod)
+}
+
+// buildInvite resolves the channels and workspace of the invitation
+func (r *UserInviteReconciler) buildInvite(ctx context.Context, invite *slackv1alpha1.UserInvite) (*slack.WorkspaceInvite, error) {
+	workspaceInvite := &slack.WorkspaceInvite{
+		TeamID:          invite.Spec.TeamID,
+		Email:           invite.Spec.Email,
+		RealName:        invite.Spec.RealName,
+		CustomMessage:   invite.Spec.CustomMessage,
+		Restricted:      invite.Spec.GuestType == slackv1alpha1.MultiChannelGuest,
+		UltraRestricted: invite.Spec.GuestType == slackv1alpha1.SingleChannelGuest,
+	}
+
+	if invite.Spec.GuestExpiration != nil {
+		workspaceInvite.GuestExpiration = invite.Spec.GuestExpiration.Unix()
+	}
+
+	if workspaceInvite.TeamID == "" {
+		teamID, err := r.SlackService.GetTeamID()
+		if err != nil {
+			return nil, err
+		}
+		workspaceInvite.TeamID = teamID
+	}
+
+	for _, ref := range invite.Spec.Channels {
+		channelID, err := pkgutil.GetChannelID(ctx, r.Client, ref.Key())
+		if err != nil {
+			return nil, err
+		}
+		workspaceInvite.ChannelIDs = append(workspaceInvite.ChannelIDs, channelID)
+	}
+
+	return workspaceInvite, nil
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *UserInviteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.UserInvite{}).
+		Complete(r)
+}
@@ -0,0 +1,212 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reconcilerUtil "github.com/stakater/operator-utils/util/reconciler"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/scim"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// UserOffboardingReconciler reconciles a UserOffboarding object
+type UserOffboardingReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	SlackService slack.Service
+	SCIMClient   *scim.Client
+}
+
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=useroffboardings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=useroffboardings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=channels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slack.stakater.com,resources=scimgroups,verbs=get;list;watch
+
+// Reconcile loop for the UserOffboarding resource, it removes the user from every managed channel and SCIM group
+// and optionally deactivates them. Failed removals are retried until all of them succeeded.
+func (r *UserOffboardingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("useroffboarding", req.NamespacedName)
+
+	offboarding := &slackv1alpha1.UserOffboarding{}
+	err := r.Get(ctx, req.NamespacedName, offboarding)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcilerUtil.DoNotRequeue()
+		}
+		return reconcilerUtil.RequeueWithError(err)
+	}
+
+	if offboarding.GetDeletionTimestamp() != nil || offboarding.Status.Completed {
+		return reconcilerUtil.DoNotRequeue()
+	}
+
+	if offboarding.Spec.Deactivate && r.SCIMClient == nil {
+		return reconcilerUtil.ManageError(r.Client, offboarding, fmt.Errorf("Users can't be deactivated because SCIM isn't configured for the operator"), false)
+	}
+
+	user, err := r.SlackService.LookupUserByEmail(offboarding.Spec.Email)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, offboarding, err, true)
+	}
+	var userID string
+	if user != nil {
+		userID = user.ID
+	}
+
+	failed := false
+
+	offboarding.Status.Channels, err = r.offboardChannels(ctx, userID)
+	if err != nil {
+		return reconcilerUtil.ManageError(r.Client, offboarding, err, true)
+	}
+
+	var scimUser *scim.User
+	if r.SCIMClient != nil {
+		scimUser, err = r.SCIMClient.GetUserByEmail(offboarding.Spec.Email)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, offboarding, err, true)
+		}
+
+		offboarding.Status.Groups, err = r.offboardGroups(ctx, scimUser)
+		if err != nil {
+			return reconcilerUtil.ManageError(r.Client, offboarding, err, true)
+		}
+	}
+
+	for _, report := range append(offboarding.Status.Channels, offboarding.Status.Groups...) {
+		if report.Result == slackv1alpha1.OffboardingFailed {
+			failed = true
+		}
+	}
+
+	if !failed && offboarding.Spec.Deactivate && scimUser != nil && !offboarding.Status.Deactivated {
+		log.Info("Deactivating user", "userID", scimUser.ID)
+		if err := r.SCIMClient.DeactivateUser(scimUser.ID); err != nil {
+			return reconcilerUtil.ManageError(r.Client, offboarding, err, true)
+		}
+		offboarding.Status.Deactivated = true
+	}
+
+	if failed {
+		return reconcilerUtil.ManageError(r.Client, offboarding, fmt.Errorf("The user couldn't be removed from all channels and groups"), true)
+	}
+
+	log.Info("User offboarded", "channels", len(offboarding.Status.Channels), "groups", len(offboarding.Status.Groups))
+	offboarding.Status.Completed = true
+	return reconcilerUtil.ManageSuccess(r.Client, offboarding)
+}
+
+// offboardChannels removes the user from all managed channels
+func (r *UserOffboardingReconciler) offboardChannels(ctx context.Context, userID string) ([]slackv1alpha1.OffboardingReport, error) {
+	channels := &slackv1alpha1.ChannelList{}
+	if err := r.List(ctx, channels); err != nil {
+		return nil, err
+	}
+
+	var reports []slackv1alpha1.OffboardingReport
+	for _, channel := range channels.Items {
+		if channel.Status.ID == "" {
+			continue
+		}
+
+		report := slackv1alpha1.OffboardingReport{
+			Name:   types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}.String(),
+			Result: slackv1alpha1.OffboardingNotMember,
+		}
+
+		if userID != "" {
+			removed, err := r.SlackService.KickUser(channel.Status.ID, userID)
+			if err != nil {
+				report.Result = slackv1alpha1.OffboardingFailed
+				report.Error = err.Error()
+			} else if removed {
+				report.Result = slackv1alpha1.OffboardingRemoved
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// offboardGroups removes the user from all managed SCIM groups
+func (r *UserOffboardingReconciler) offboardGroups(ctx context.Context, user *scim.User) ([]slackv1alpha1.OffboardingReport, error) {
+	groups := &slackv1alpha1.SCIMGroupList{}
+	if err := r.List(ctx, groups); err != nil {
+		return nil, err
+	}
+
+	var reports []slackv1alpha1.OffboardingReport
+	for _, group := range groups.Items {
+		if group.Status.GroupID == "" {
+			continue
+		}
+
+		report := slackv1alpha1.OffboardingReport{
+			Name:   types.NamespacedName{Namespace: group.Namespace, Name: group.Name}.String(),
+			Result: slackv1alpha1.OffboardingNotMember,
+		}
+
+		if user != nil {
+			removed, err := r.removeGroupMember(group.Status.GroupID, user.ID)
+			if err != nil {
+				report.Result = slackv1alpha1.OffboardingFailed
+				report.Error = err.Error()
+			} else if removed {
+				report.Result = slackv1alpha1.OffboardingRemoved
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// removeGroupMember removes the user from the SCIM group, false is returned if the user wasn't a member
+func (r *UserOffboardingReconciler) removeGroupMember(groupID string, userID string) (bool, error) {
+	group, err := r.SCIMClient.GetGroup(groupID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, member := range group.Members {
+		if member.Value == userID {
+			return true, r.SCIMClient.UpdateGroupMembers(groupID, nil, []string{userID})
+		}
+	}
+	return false, nil
+}
+
+// SetupWithManager - Controller-Manager binding configuration
+func (r *UserOffboardingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slackv1alpha1.UserOffboarding{}).
+		Complete(r)
+}
@@ -17,11 +17,19 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
+	// Time zones of member schedules are embedded, the base image may lack the tz database
+	_ "time/tzdata"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -30,13 +38,27 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slackv1beta1 "github.com/stakater/slack-operator/api/v1beta1"
 	"github.com/stakater/slack-operator/controllers"
+	"github.com/stakater/slack-operator/pkg/alertmanager"
 	config "github.com/stakater/slack-operator/pkg/config"
+	"github.com/stakater/slack-operator/pkg/debug"
+	"github.com/stakater/slack-operator/pkg/maintenance"
+	"github.com/stakater/slack-operator/pkg/oncall"
+	"github.com/stakater/slack-operator/pkg/opa"
+	"github.com/stakater/slack-operator/pkg/redact"
+	"github.com/stakater/slack-operator/pkg/scim"
+	"github.com/stakater/slack-operator/pkg/shard"
 	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+	"github.com/stakater/slack-operator/pkg/vault"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,19 +71,148 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(slackv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(slackv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaderElectionNamespace string
+	var leaseDuration, renewDeadline, retryPeriod time.Duration
+	var releaseLeaderOnCancel bool
+	var pprofAddr string
+	var debugAddr string
+	var shardIndex, totalShards int
+	var shardLabel string
+	var watchNamespaces, watchSelector string
 	var probeAddr string
+	var alertmanagerAddr string
+	var protectedUsers string
+	var botAllowlist string
+	var minChannelUsers int
+	var userSnapshotPeriod time.Duration
+	var userKey, canaryUserKey string
+	var maxConcurrentReconciles int
+	var maxFailedReconciles int
+	var maxMemberChanges int
+	var tokenReloadPeriod time.Duration
+	var authCheckPeriod time.Duration
+	var vaultConfig vault.Config
+	var transportConfig slack.TransportConfig
+	var piiRedaction string
+	var allowedNamespaces string
+	var trustedNamespaces string
+	var privateChannelNamespaces, privateChannelSelector string
+	var opaURL, opaFailurePolicy string
+	var opaTimeout time.Duration
+	var memberEnforcementNamespaces, memberEnforcementSelector string
+	var maintenanceWindowsSpec string
+	var auditChannelID string
+	var notificationChannelID string
+	var auditLogsPeriod time.Duration
+	var activityPeriod time.Duration
+	var onCallPeriod time.Duration
+	var auditHistorySize int
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&alertmanagerAddr, "alertmanager-bind-address", "", "The address the Alertmanager webhook receiver binds to. "+
+		"The receiver is disabled when left empty.")
+	flag.StringVar(&protectedUsers, "protected-users", "", "Comma separated emails or user IDs which are never removed from channels, "+
+		"e.g. workspace admins and integrations that appear as users.")
+	flag.StringVar(&botAllowlist, "bot-allowlist", "", "Comma separated user IDs, usernames or app IDs of integrations which are "+
+		"never removed from channels. Bot, app and workflow users are always kept.")
+	flag.IntVar(&minChannelUsers, "min-channel-users", 0, "The minimum number of members a Channel must have, "+
+		"counting its users, temporary users, member schedules and the members of SCIMGroups of its SCIM groups. "+
+		"Membership isn't enforced for channels without users.")
+	flag.DurationVar(&userSnapshotPeriod, "user-snapshot-period", 15*time.Minute, "How often the users of the workspace are listed "+
+		"to look up channel members without a users.info call each. The snapshot is disabled when set to 0.")
+	flag.StringVar(&userKey, "user-key", string(slack.UserKeyEmail), "What the users listed by Channels are matched with slack "+
+		"users by: email, id or username. Matching by username needs the users snapshot.")
+	flag.StringVar(&canaryUserKey, "canary-user-key", "", "What users are matched by in the canary workspace, defaults to --user-key.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1, "The number of Channels reconciled in parallel. "+
+		"Slack API calls of all reconciles share one budget per rate limit tier.")
+	flag.IntVar(&maxMemberChanges, "max-member-changes", 500, "The number of users a reconcile invites to and removes from a "+
+		"channel, larger membership changes continue on the next reconciles. Set to 0 to make all changes at once.")
+	flag.IntVar(&maxFailedReconciles, "max-failed-reconciles", 10, "The number of reconciles in a row a Channel may fail "+
+		"before it isn't reconciled anymore until its spec or reconcile-at annotation changes. Set to 0 to retry forever.")
+	flag.DurationVar(&tokenReloadPeriod, "token-reload-period", time.Minute, "How often the Slack token is read from the operator "+
+		"secret, so rotated tokens are used without a restart. Reloading is disabled when set to 0.")
+	flag.DurationVar(&authCheckPeriod, "auth-check-period", 5*time.Minute, "How often auth.test verifies the Slack token for the "+
+		"readiness probe. The token is only verified at startup when set to 0.")
+	flag.StringVar(&vaultConfig.Address, "vault-address", "", "The address of the Vault server to read the Slack tokens from "+
+		"instead of the operator secret, e.g. https://vault.example.com:8200.")
+	flag.StringVar(&vaultConfig.AuthMount, "vault-auth-mount", "kubernetes", "The mount path of the Vault Kubernetes auth method.")
+	flag.StringVar(&vaultConfig.Role, "vault-role", "slack-operator", "The Vault role the operator's service account logs in as.")
+	flag.StringVar(&vaultConfig.SecretPath, "vault-secret-path", "secret/data/slack-operator", "The path of the Vault secret with "+
+		"the APIToken and the optional SCIMToken keys.")
+	flag.StringVar(&transportConfig.ProxyURL, "proxy-url", "", "The proxy for requests to Slack. HTTPS_PROXY and NO_PROXY apply when it's empty.")
+	flag.StringVar(&transportConfig.CABundle, "ca-bundle", "", "A PEM file of CA certificates trusted for requests to Slack, "+
+		"in addition to the system roots, e.g. of a TLS intercepting proxy.")
+	flag.StringVar(&transportConfig.MinTLSVersion, "tls-min-version", "1.2", "The lowest TLS version accepted for requests to Slack.")
+	flag.StringVar(&allowedNamespaces, "allowed-namespaces", "", "Comma separated namespaces, or patterns like team-*, which may "+
+		"create Channels. Channels in other namespaces are rejected and not reconciled. Every namespace is allowed when empty.")
+	flag.StringVar(&trustedNamespaces, "trusted-namespaces", "", "Comma separated namespaces, or patterns like platform-*, "+
+		"whose resources may reach the Channels and events of other namespaces. Resources of every other namespace are "+
+		"limited to their own namespace.")
+	flag.StringVar(&privateChannelNamespaces, "private-channel-namespaces", "", "Comma separated namespaces, or patterns like "+
+		"team-*, which may create private channels. Private channels are allowed everywhere unless this or the selector is set.")
+	flag.StringVar(&privateChannelSelector, "private-channel-selector", "", "Label selector of Channels which may create private channels.")
+	flag.StringVar(&opaURL, "opa-url", "", "The URL of an Open Policy Agent policy document, e.g. "+
+		"http://opa:8181/v1/data/slack/channel/deny, whose messages reject Channels in the validating webhook. "+
+		"No custom rules are evaluated when empty.")
+	flag.DurationVar(&opaTimeout, "opa-timeout", 5*time.Second, "How long the webhook waits for the Open Policy Agent.")
+	flag.StringVar(&opaFailurePolicy, "opa-failure-policy", opa.Fail, "Whether Channels are rejected (Fail) or admitted "+
+		"(Ignore) when the Open Policy Agent can't be queried.")
+	flag.StringVar(&memberEnforcementNamespaces, "member-enforcement-namespaces", "", "Comma separated namespaces, or patterns "+
+		"like team-*, whose Channels may manage members and remove unlisted ones. Allowed everywhere unless this or the selector is set.")
+	flag.StringVar(&memberEnforcementSelector, "member-enforcement-selector", "", "Label selector of Channels which may manage members.")
+	flag.StringVar(&maintenanceWindowsSpec, "maintenance-windows", "", "Weekly windows separated by ; as \"[days] HH:MM-HH:MM "+
+		"[time zone]\", e.g. \"Sat,Sun 02:00-06:00 Europe/Berlin\". Removing members, renaming and archiving channels wait for "+
+		"a window, other changes are applied right away. Disruptive changes are applied at any time unless this is set.")
+	flag.StringVar(&auditChannelID, "audit-channel", "", "The ID of a Slack channel every change the operator makes to "+
+		"channels is posted to. Changes are always written to the audit log.")
+	flag.IntVar(&auditHistorySize, "audit-history-size", 20, "The number of latest changes kept in the history of a "+
+		"Channel's status. 0 keeps no history.")
+	flag.DurationVar(&auditLogsPeriod, "audit-logs-period", 0, "How often the Enterprise Grid Audit Logs API is polled for "+
+		"changes of managed channels made outside the operator, which are reconciled right away. Needs a user token with "+
+		"the auditlogs:read scope, 0 disables polling.")
+	flag.DurationVar(&activityPeriod, "activity-period", time.Hour, "How often the member count and recent messages "+
+		"in the status.activity of Channels are refreshed, 0 disables it.")
+	flag.DurationVar(&onCallPeriod, "oncall-period", 5*time.Minute, "How often the PagerDuty and Opsgenie schedules of "+
+		"Channels with spec.onCall are polled for who is on call.")
+	flag.StringVar(&notificationChannelID, "notification-channel", "", "The ID of a Slack channel the operator posts its own "+
+		"critical problems to, e.g. token failures, sustained rate limiting and the expiry of the webhook certificate.")
+	flag.StringVar(&piiRedaction, "pii-redaction", string(redact.Hash), "How emails are redacted from logs: off, mask (j***@example.com) "+
+		"or hash, which keeps log lines of a user correlated. Tokens are always redacted.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionNamespace, "leader-election-namespace", "", "The namespace of the leader election lease, "+
+		"defaults to the namespace of the operator.")
+	flag.DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second, "How long replicas wait before taking "+
+		"over the lease of a leader which stopped renewing it.")
+	flag.DurationVar(&renewDeadline, "leader-election-renew-deadline", 10*time.Second, "How long the leader retries renewing "+
+		"the lease before it gives up leadership. Must be shorter than the lease duration.")
+	flag.DurationVar(&retryPeriod, "leader-election-retry-period", 2*time.Second, "How often replicas try to acquire or "+
+		"renew the lease.")
+	flag.BoolVar(&releaseLeaderOnCancel, "leader-election-release-on-cancel", false, "Release the lease when the leader "+
+		"shuts down, so another replica takes over right away instead of after the lease duration.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "", "The address the pprof profiles are served on below /debug/pprof/, "+
+		"e.g. 127.0.0.1:6060. Profiling is disabled when empty.")
+	flag.StringVar(&debugAddr, "debug-bind-address", "", "The address the in-memory state, i.e. the "+
+		"Channels by slack channel name, the users snapshot with redacted emails and the work queues, is served on below "+
+		"/debug/, e.g. 127.0.0.1:6061. Disabled when empty.")
+	flag.IntVar(&shardIndex, "shard", 0, "The shard of the Channels this deployment reconciles, from 0 to --total-shards - 1. "+
+		"Shard 0 also reconciles the resources which aren't sharded.")
+	flag.IntVar(&totalShards, "total-shards", 1, "The number of deployments the Channels are sharded between.")
+	flag.StringVar(&shardLabel, "shard-label", "", "The label of Channels, e.g. the workspace, whose value assigns them to a "+
+		"shard. Channels are assigned by their namespace and name when empty.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated namespaces the operator watches, overrides "+
+		"the WATCH_NAMESPACE environment variable. All namespaces are watched when both are empty.")
+	flag.StringVar(&watchSelector, "watch-selector", "", "Label selector of the Channels the operator reconciles, e.g. "+
+		"team=payments for a team's own install next to a central one excluding them with team!=payments.")
 
 	opts := zap.Options{
 		Development: true,
@@ -69,9 +220,27 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	redactionMode, err := redact.ParseMode(piiRedaction)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	ctrl.SetLogger(redact.NewLogger(zap.New(zap.UseFlagOptions(&opts)), redactionMode))
 
-	watchNamespace, err := getWatchNamespace()
+	var channelSelector labels.Selector
+	if watchSelector != "" {
+		if channelSelector, err = labels.Parse(watchSelector); err != nil {
+			setupLog.Error(err, "invalid watch selector")
+			os.Exit(1)
+		}
+	}
+	channelShard, err := shard.New(shardIndex, totalShards, shardLabel, channelSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid sharding")
+		os.Exit(1)
+	}
+
+	watchNamespace, err := getWatchNamespace(watchNamespaces)
 	if err != nil {
 		setupLog.Info("Unable to fetch WatchNamespace, the manager will watch and manage resources in all Namespaces")
 	}
@@ -82,8 +251,19 @@ func main() {
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "957ea167.stakater.com",
+		LeaderElectionID:       channelShard.LeaderElectionID("957ea167.stakater.com"),
 		Namespace:              watchNamespace, // namespaced-scope when the value is not an empty string
+
+		// Only controllers and leader-only runnables wait for the lease, webhooks and receivers are served by every replica
+		LeaderElectionNamespace:       leaderElectionNamespace,
+		LeaseDuration:                 &leaseDuration,
+		RenewDeadline:                 &renewDeadline,
+		RetryPeriod:                   &retryPeriod,
+		LeaderElectionReleaseOnCancel: releaseLeaderOnCancel,
+
+		// Secrets are read from the API server, the controllers only watch their metadata, so the Secrets of the
+		// cluster aren't cached
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}},
 	}
 
 	// Add support for MultiNamespace set in WATCH_NAMESPACE (e.g ns1,ns2)
@@ -101,23 +281,515 @@ func main() {
 		os.Exit(1)
 	}
 
-	slackAPIToken := config.ReadSlackTokenSecret(mgr.GetAPIReader())
+	// The tokens are read from Vault instead of the operator secret when a Vault address is set
+	readToken := func(ctx context.Context) (string, error) {
+		return config.LoadSlackToken(ctx, mgr.GetAPIReader())
+	}
+	var tokenSecret runtime.Object = config.SlackTokenSecret()
+	var slackAPIToken, scimToken, userToken, canaryToken, alertmanagerToken string
+	var poolTokens []string
+	if vaultConfig.Address != "" {
+		vaultClient := vault.New(vaultConfig, http.DefaultClient, ctrl.Log.WithName("service").WithName("Vault"))
+		readToken = func(ctx context.Context) (string, error) {
+			values, err := vaultClient.ReadSecret(ctx)
+			return values[config.SlackAPITokenSecretKey], err
+		}
+		tokenSecret = nil
+
+		values, err := vaultClient.ReadSecret(context.Background())
+		if err != nil || values[config.SlackAPITokenSecretKey] == "" {
+			setupLog.Error(err, "Could not read API token from Vault", "path", vaultConfig.SecretPath, "secretKey", config.SlackAPITokenSecretKey)
+			os.Exit(1)
+		}
+		slackAPIToken = values[config.SlackAPITokenSecretKey]
+		scimToken = values[config.SlackSCIMTokenSecretKey]
+		userToken = values[config.SlackUserTokenSecretKey]
+		poolTokens = config.SplitTokens(values[config.SlackPoolTokensSecretKey])
+		canaryToken = values[config.SlackCanaryTokenSecretKey]
+		alertmanagerToken = values[config.SlackAlertmanagerTokenSecretKey]
+	} else {
+		slackAPIToken = config.ReadSlackTokenSecret(mgr.GetAPIReader())
+		scimToken = config.ReadSCIMTokenSecret(mgr.GetAPIReader())
+		userToken = config.ReadUserTokenSecret(mgr.GetAPIReader())
+		poolTokens = config.ReadPoolTokensSecret(mgr.GetAPIReader())
+		canaryToken = config.ReadCanaryTokenSecret(mgr.GetAPIReader())
+		alertmanagerToken = config.ReadAlertmanagerTokenSecret(mgr.GetAPIReader())
+	}
+
+	transport, err := slack.NewTransport(transportConfig)
+	if err != nil {
+		setupLog.Error(err, "invalid Slack transport configuration")
+		os.Exit(1)
+	}
+
+	// Changes are logged to the audit logger, and kept for the Channel history and posted to the audit channel if enabled
+	auditors := slack.Auditors{&slack.AuditLog{Log: ctrl.Log.WithName("audit")}, slack.AuditMetrics{}}
+	var auditHistory *slack.AuditHistory
+	if auditHistorySize > 0 {
+		auditHistory = slack.NewAuditHistory(auditHistorySize)
+		auditors = append(auditors, auditHistory)
+	}
+	var auditChannel *slack.AuditChannel
+	if auditChannelID != "" {
+		auditChannel = &slack.AuditChannel{ChannelID: auditChannelID}
+		auditors = append(auditors, auditChannel)
+	}
 
-	if err = (&controllers.ChannelReconciler{
+	if canaryUserKey == "" {
+		canaryUserKey = userKey
+	}
+	userMatchKey, err := slack.ParseUserKey(userKey)
+	if err != nil {
+		setupLog.Error(err, "invalid --user-key")
+		os.Exit(1)
+	}
+	canaryMatchKey, err := slack.ParseUserKey(canaryUserKey)
+	if err != nil {
+		setupLog.Error(err, "invalid --canary-user-key")
+		os.Exit(1)
+	}
+	if userSnapshotPeriod == 0 && (userMatchKey == slack.UserKeyUsername || canaryMatchKey == slack.UserKeyUsername) {
+		setupLog.Error(fmt.Errorf("users can't be matched by username with --user-snapshot-period=0"), "invalid user key")
+		os.Exit(1)
+	}
+
+	slackService := slack.New(slackAPIToken, ctrl.Log.WithName("service").WithName("Slack"),
+		slack.WithAuditor(auditors),
+		slack.WithTransport(transport),
+		slack.WithUserToken(userToken),
+		slack.WithTokenPool(poolTokens),
+		slack.WithBotAllowlist(splitList(botAllowlist)),
+		slack.WithUserKey(userMatchKey),
+		slack.WithMinChannelUsers(minChannelUsers))
+
+	if auditChannel != nil {
+		auditChannel.Service = slackService
+	}
+	slack.RegisterRateLimitGauge(slackService)
+
+	// Token problems are reported at startup and keep the operator unready instead of failing every reconcile
+	if err := slackService.CheckAuth(); err != nil {
+		setupLog.Error(err, "Slack token failed validation")
+	}
+
+	var canaryService slack.Service
+	var canarySlackService *slack.SlackService
+	if canaryToken != "" {
+		setupLog.Info("Canary token found, canary Channels are reconciled against the canary workspace first")
+		canarySlackService = slack.New(canaryToken, ctrl.Log.WithName("service").WithName("CanarySlack"),
+			slack.WithTransport(transport),
+			slack.WithBotAllowlist(splitList(botAllowlist)),
+			slack.WithUserKey(canaryMatchKey))
+		canaryService = canarySlackService
+	}
+
+	var scimClient *scim.Client
+	if scimToken != "" {
+		setupLog.Info("SCIM token found, enabling SCIM provisioning")
+		scimClient = scim.NewWithURL(scimToken, scim.APIURL, &http.Client{Transport: transport}, ctrl.Log.WithName("service").WithName("SCIM"))
+	}
+	slackv1alpha1.MinChannelUsers = minChannelUsers
+	slackv1alpha1.AllowedNamespaces = splitList(allowedNamespaces)
+	slackv1alpha1.TrustedNamespaces = splitList(trustedNamespaces)
+	if slackv1alpha1.PrivateChannelPolicy, err = featurePolicy(privateChannelNamespaces, privateChannelSelector); err != nil {
+		setupLog.Error(err, "invalid private channel policy")
+		os.Exit(1)
+	}
+	if slackv1alpha1.MemberEnforcementPolicy, err = featurePolicy(memberEnforcementNamespaces, memberEnforcementSelector); err != nil {
+		setupLog.Error(err, "invalid member enforcement policy")
+		os.Exit(1)
+	}
+	maintenanceWindows, err := maintenance.Parse(maintenanceWindowsSpec)
+	if err != nil {
+		setupLog.Error(err, "invalid maintenance windows")
+		os.Exit(1)
+	}
+	slackv1alpha1.ChannelReader = mgr.GetClient()
+	if opaURL != "" {
+		admissionPolicy, err := opa.New(opaURL, opaTimeout, opaFailurePolicy, ctrl.Log.WithName("opa"))
+		if err != nil {
+			setupLog.Error(err, "invalid admission policy")
+			os.Exit(1)
+		}
+		slackv1alpha1.AdmissionPolicy = admissionPolicy
+	}
+
+	// Every shard reconciles its part of the Channels, the other resources are only reconciled by the primary shard
+	primary := channelShard.PrimaryOnly(mgr)
+
+	channelReconciler := &controllers.ChannelReconciler{
+		Client:         pkgutil.NewApplyClient(mgr.GetClient()),
+		Log:            ctrl.Log.WithName("controllers").WithName("Channel"),
+		Scheme:         mgr.GetScheme(),
+		SlackService:   slackService,
+		ProtectedUsers: splitList(protectedUsers),
+		SCIMClient:     scimClient,
+		Recorder:       mgr.GetEventRecorderFor("slack-operator"),
+
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		MaxFailedReconciles:     maxFailedReconciles,
+		MaxMemberChanges:        maxMemberChanges,
+		MaintenanceWindows:      maintenanceWindows,
+		CanaryService:           canaryService,
+		AuditHistory:            auditHistory,
+		Shard:                   channelShard,
+	}
+	if err = (&controllers.ChannelBackupReconciler{
 		Client:       mgr.GetClient(),
-		Log:          ctrl.Log.WithName("controllers").WithName("Channel"),
+		Log:          ctrl.Log.WithName("controllers").WithName("ChannelBackup"),
 		Scheme:       mgr.GetScheme(),
-		SlackService: slack.New(slackAPIToken, ctrl.Log.WithName("service").WithName("Slack")),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChannelBackup")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ConversationExportReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("ConversationExport"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ConversationExport")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ChannelMergeReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("ChannelMerge"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChannelMerge")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ChannelRenameWaveReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ChannelRenameWave"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChannelRenameWave")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ChannelActivityReconciler{
+		Client:         mgr.GetClient(),
+		Log:            ctrl.Log.WithName("controllers").WithName("ChannelActivity"),
+		SlackService:   slackService,
+		ActivityPeriod: activityPeriod,
+
+		MaintenanceWindows: maintenanceWindows,
+		Shard:              channelShard,
 	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChannelActivity")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.OnCallTopicReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("OnCallTopic"),
+		SlackService: slackService,
+		Schedules:    oncall.New(),
+		OnCallPeriod: onCallPeriod,
+		Shard:        channelShard,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OnCallTopic")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.TopicSourceReconciler{
+		Client:     mgr.GetClient(),
+		Log:        ctrl.Log.WithName("controllers").WithName("TopicSource"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		Shard:      channelShard,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TopicSource")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.DefaultChannelsReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("DefaultChannels"),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DefaultChannels")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.BarrierReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Barrier"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Barrier")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.UserGroupReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("UserGroup"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "UserGroup")
+		os.Exit(1)
+	}
+
+	if auditLogsPeriod > 0 {
+		channelReconciler.ExternalChanges = make(chan event.GenericEvent)
+	}
+	if err = channelReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Channel")
 		os.Exit(1)
 	}
 
+	if err = (&controllers.EventRouteReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("EventRoute"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EventRoute")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.EventReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Event"),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Event")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.NotificationTemplateReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("NotificationTemplate"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NotificationTemplate")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.BroadcastReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Broadcast"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Broadcast")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.MessageReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Message"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Message")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.FileUploadReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("FileUpload"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FileUpload")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ReminderReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Reminder"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Reminder")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.EmojiReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Emoji"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Emoji")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.SlackAppReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("SlackApp"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SlackApp")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.UserInviteReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("UserInvite"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "UserInvite")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.GuestUserReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("GuestUser"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GuestUser")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.SCIMGroupReconciler{
+		Client:     mgr.GetClient(),
+		Log:        ctrl.Log.WithName("controllers").WithName("SCIMGroup"),
+		Scheme:     mgr.GetScheme(),
+		SCIMClient: scimClient,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SCIMGroup")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.UserOffboardingReconciler{
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("UserOffboarding"),
+		Scheme:       mgr.GetScheme(),
+		SlackService: slackService,
+		SCIMClient:   scimClient,
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "UserOffboarding")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ChannelQuotaReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("ChannelQuota"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChannelQuota")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.DriftReportReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("DriftReport"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(primary); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DriftReport")
+		os.Exit(1)
+	}
+
+	if auditLogsPeriod > 0 {
+		if userToken == "" {
+			setupLog.Error(nil, "polling the audit logs needs a user token with the auditlogs:read scope", "secretKey", config.SlackUserTokenSecretKey)
+			os.Exit(1)
+		}
+		if err = mgr.Add(slack.NewAuditLogPoller(slackService, auditLogsPeriod, channelReconciler.ChangedInSlack)); err != nil {
+			setupLog.Error(err, "unable to add audit logs poller")
+			os.Exit(1)
+		}
+	}
+
+	if userSnapshotPeriod > 0 {
+		if err = mgr.Add(slack.NewUserSnapshotRefresher(slackService, userSnapshotPeriod)); err != nil {
+			setupLog.Error(err, "unable to add users snapshot refresher")
+			os.Exit(1)
+		}
+	}
+	// The canary workspace only needs its users snapshot to look up users by username
+	if canarySlackService != nil && canaryMatchKey == slack.UserKeyUsername {
+		if err = mgr.Add(slack.NewUserSnapshotRefresher(canarySlackService, userSnapshotPeriod)); err != nil {
+			setupLog.Error(err, "unable to add canary users snapshot refresher")
+			os.Exit(1)
+		}
+	}
+
+	if tokenReloadPeriod > 0 {
+		reloader := slack.NewTokenReloader(slackService, readToken, tokenReloadPeriod,
+			mgr.GetEventRecorderFor("slack-operator"), tokenSecret)
+		if err = mgr.Add(reloader); err != nil {
+			setupLog.Error(err, "unable to add token reloader")
+			os.Exit(1)
+		}
+	}
+
+	// Rotating tokens are refreshed by the leader, the other replicas reload them from the secret
+	if vaultConfig.Address == "" {
+		tokenStore := &config.SecretTokenStore{Reader: mgr.GetAPIReader(), Writer: mgr.GetClient()}
+		if err = primary.Add(slack.NewTokenRotator(slackService, tokenStore)); err != nil {
+			setupLog.Error(err, "unable to add token rotator")
+			os.Exit(1)
+		}
+	}
+
+	if pprofAddr != "" {
+		profilingServer := debug.NewServer(pprofAddr, ctrl.Log.WithName("pprof"))
+		profilingServer.HandleProfiles()
+		if err = mgr.Add(profilingServer); err != nil {
+			setupLog.Error(err, "unable to set up profiling server")
+			os.Exit(1)
+		}
+	}
+
+	if debugAddr != "" {
+		debugServer := debug.NewServer(debugAddr, ctrl.Log.WithName("debug"))
+		debugServer.HandleState("channels", channelReconciler.NameIndex)
+		debugServer.HandleState("users", debug.Users(slackService.SnapshotUsers, redactionMode))
+		debugServer.HandleState("queues", debug.Queues(metrics.Registry))
+		if err = mgr.Add(debugServer); err != nil {
+			setupLog.Error(err, "unable to set up debug server")
+			os.Exit(1)
+		}
+	}
+
+	if alertmanagerAddr != "" {
+		if alertmanagerToken == "" {
+			setupLog.Error(nil, "Alertmanager receiver requires a bearer token", "secretKey", config.SlackAlertmanagerTokenSecretKey)
+			os.Exit(1)
+		}
+		receiver := alertmanager.NewReceiver(alertmanagerAddr, alertmanagerToken, mgr.GetClient(), slackService, ctrl.Log.WithName("alertmanager"))
+		if err = mgr.Add(receiver); err != nil {
+			setupLog.Error(err, "unable to set up Alertmanager receiver")
+			os.Exit(1)
+		}
+	}
+
+	if notificationChannelID != "" {
+		webhookCertFile := ""
+		if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+			webhookCertFile = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs", "tls.crt")
+		}
+		if err = primary.Add(slack.NewOperatorNotifier(slackService, notificationChannelID, webhookCertFile)); err != nil {
+			setupLog.Error(err, "unable to add operator notifier")
+			os.Exit(1)
+		}
+	}
+
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&slackv1alpha1.Channel{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Channel")
 			os.Exit(1)
 		}
+		if err = (&slackv1beta1.Channel{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Channel")
+			os.Exit(1)
+		}
+		if err = (&slackv1alpha1.NotificationTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NotificationTemplate")
+			os.Exit(1)
+		}
 	}
 
 	// Add health endpoints
@@ -129,6 +801,28 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("slack-auth", slackService.AuthCheck); err != nil {
+		setupLog.Error(err, "unable to set up Slack auth check")
+		os.Exit(1)
+	}
+	if authCheckPeriod > 0 {
+		if err := mgr.Add(slack.NewAuthChecker(slackService, authCheckPeriod)); err != nil {
+			setupLog.Error(err, "unable to set up Slack auth checker")
+			os.Exit(1)
+		}
+	}
+	// An expired webhook certificate marks the operator unready and restarts it, so the renewed certificate is mounted
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		certCheck := slack.CertificateCheck(filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs", "tls.crt"))
+		if err := mgr.AddReadyzCheck("webhook-cert", certCheck); err != nil {
+			setupLog.Error(err, "unable to set up webhook certificate check")
+			os.Exit(1)
+		}
+		if err := mgr.AddHealthzCheck("webhook-cert", certCheck); err != nil {
+			setupLog.Error(err, "unable to set up webhook certificate check")
+			os.Exit(1)
+		}
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -139,7 +833,12 @@ func main() {
 	}
 }
 
-func getWatchNamespace() (string, error) {
+func getWatchNamespace(namespaces string) (string, error) {
+	// The --watch-namespaces flag takes precedence over the environment variable
+	if namespaces != "" {
+		return strings.Join(splitList(namespaces), ","), nil
+	}
+
 	// WatchNamespaceEnvVar is the constant for env variable WATCH_NAMESPACE
 	// which specifies the Namespace to watch.
 	// An empty value means the operator is running with cluster scope.
@@ -151,3 +850,32 @@ func getWatchNamespace() (string, error) {
 	}
 	return ns, nil
 }
+
+// splitList splits a comma separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// featurePolicy creates the policy of a feature from its flags, features without namespaces or selector are allowed
+// everywhere
+func featurePolicy(namespaces string, selector string) (*slackv1alpha1.FeaturePolicy, error) {
+	if namespaces == "" && selector == "" {
+		return nil, nil
+	}
+
+	policy := &slackv1alpha1.FeaturePolicy{Namespaces: splitList(namespaces)}
+	if selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return nil, err
+		}
+		policy.Selector = parsed
+	}
+	return policy, nil
+}
@@ -0,0 +1,281 @@
+package alertmanager
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	slackapi "github.com/slack-go/slack"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slack "github.com/stakater/slack-operator/pkg/slack"
+	pkgutil "github.com/stakater/slack-operator/pkg/util"
+)
+
+const (
+	// ChannelRefKey is the alert label or annotation referencing the Channel CR to post to,
+	// either as "<namespace>/<name>" or "<name>" in which case the alert's namespace label is used
+	ChannelRefKey string = "slack_channel"
+	// TemplateRefKey is the alert label or annotation referencing the NotificationTemplate CR used to render the alerts,
+	// in the same format as ChannelRefKey, the default template is used when it is missing
+	TemplateRefKey string = "slack_template"
+	// NamespaceLabel is the alert label used to default the namespace of the Channel CR
+	NamespaceLabel string = "namespace"
+
+	// Path is the HTTP path the receiver serves Alertmanager webhooks on
+	Path string = "/alertmanager"
+)
+
+// Receiver is an Alertmanager compatible webhook receiver that posts alerts to managed channels
+type Receiver struct {
+	addr         string
+	token        string
+	client       client.Reader
+	slackService slack.Service
+	log          logr.Logger
+}
+
+// NewReceiver creates a new Receiver serving on addr, requests must carry token as bearer token
+func NewReceiver(addr string, token string, k8sReader client.Reader, slackService slack.Service, logger logr.Logger) *Receiver {
+	return &Receiver{
+		addr:         addr,
+		token:        token,
+		client:       k8sReader,
+		slackService: slackService,
+		log:          logger,
+	}
+}
+
+// Start runs the receiver's HTTP server until the context is cancelled
+func (r *Receiver) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, r)
+
+	server := &http.Server{
+		Addr:    r.addr,
+		Handler: mux,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		r.log.Info("Starting Alertmanager receiver", "addr", r.addr, "path", Path)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		return err
+	}
+}
+
+// NeedLeaderElection allows every replica to receive alerts
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP handles a webhook notification from Alertmanager
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !r.authorized(req) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	data := &Data{}
+	if err := json.NewDecoder(req.Body).Decode(data); err != nil {
+		http.Error(w, fmt.Sprintf("Unable to decode alerts: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	log := r.log.WithValues("receiver", data.Receiver, "groupKey", data.GroupKey)
+
+	posted, errs := r.Notify(req.Context(), data)
+	for _, err := range errs {
+		log.Error(err, "Error posting alerts to Slack")
+	}
+
+	// Alertmanager resends the whole notification on errors, only ask for it when nothing was posted
+	// so the messages already posted aren't duplicated
+	if posted == 0 && len(errs) > 0 {
+		http.Error(w, pkgutil.MapErrorListToError(errs).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// authorized checks the bearer token of the request in constant time
+func (r *Receiver) authorized(req *http.Request) bool {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return r.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(r.token)) == 1
+}
+
+// Notify posts the alerts in data to the channels they reference, one message per channel,
+// and returns the number of messages posted
+func (r *Receiver) Notify(ctx context.Context, data *Data) (int, []error) {
+	var errorlist []error
+
+	groups := map[groupKey]*Data{}
+	for _, alert := range data.Alerts {
+		channel, err := channelRef(alert)
+		if err != nil {
+			errorlist = append(errorlist, err)
+			continue
+		}
+
+		tmpl, err := templateRef(alert)
+		if err != nil {
+			errorlist = append(errorlist, err)
+			continue
+		}
+
+		key := groupKey{channel: channel, template: tmpl}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &Data{
+				Version:           data.Version,
+				GroupKey:          data.GroupKey,
+				Receiver:          data.Receiver,
+				Status:            StatusResolved,
+				GroupLabels:       data.GroupLabels,
+				CommonLabels:      data.CommonLabels,
+				CommonAnnotations: data.CommonAnnotations,
+				ExternalURL:       data.ExternalURL,
+			}
+			groups[key] = group
+		}
+
+		group.Alerts = append(group.Alerts, alert)
+		if alert.Status == StatusFiring {
+			group.Status = StatusFiring
+		}
+	}
+
+	// Post in a stable order so retries by Alertmanager behave predictably
+	keys := make([]groupKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].channel != keys[j].channel {
+			return keys[i].channel.String() < keys[j].channel.String()
+		}
+		return keys[i].template.String() < keys[j].template.String()
+	})
+
+	posted := 0
+	for _, key := range keys {
+		if err := r.post(ctx, key, groups[key]); err != nil {
+			errorlist = append(errorlist, err)
+			continue
+		}
+		posted++
+	}
+
+	return posted, errorlist
+}
+
+// groupKey groups the alerts posted as a single message
+type groupKey struct {
+	channel  types.NamespacedName
+	template types.NamespacedName
+}
+
+func (r *Receiver) post(ctx context.Context, key groupKey, data *Data) error {
+	channelID, err := pkgutil.GetChannelID(ctx, r.client, key.channel)
+	if err != nil {
+		return err
+	}
+
+	blocks, text, err := r.render(ctx, key.template, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.slackService.PostMessage(channelID,
+		slackapi.MsgOptionText(text, false),
+		slackapi.MsgOptionBlocks(blocks...))
+	return err
+}
+
+// render renders the alerts with the referenced template, or the default template when there is none
+func (r *Receiver) render(ctx context.Context, templateKey types.NamespacedName, data *Data) ([]slackapi.Block, string, error) {
+	if templateKey.Name == "" {
+		blocks, err := Render(data)
+		return blocks, FallbackText(data), err
+	}
+
+	tmpl, err := pkgutil.GetNotificationTemplate(ctx, r.client, templateKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	blocks, text, err := tmpl.Render(data)
+	if err != nil {
+		return nil, "", err
+	}
+	if text == "" {
+		text = FallbackText(data)
+	}
+
+	return blocks, text, nil
+}
+
+// channelRef extracts the Channel CR referenced by the alert's label or annotation
+func channelRef(alert Alert) (types.NamespacedName, error) {
+	key, err := reference(alert, ChannelRefKey)
+	if err != nil {
+		return key, err
+	}
+	if key.Name == "" {
+		return key, fmt.Errorf("Alert %s has no %s label or annotation", alert.Labels["alertname"], ChannelRefKey)
+	}
+	return key, nil
+}
+
+// templateRef extracts the optional NotificationTemplate CR referenced by the alert's label or annotation
+func templateRef(alert Alert) (types.NamespacedName, error) {
+	return reference(alert, TemplateRefKey)
+}
+
+// reference parses the resource referenced by the alert's label or annotation, an empty name is returned when it is missing
+func reference(alert Alert, refKey string) (types.NamespacedName, error) {
+	ref, ok := alert.Labels[refKey]
+	if !ok {
+		ref = alert.Annotations[refKey]
+	}
+	if ref == "" {
+		return types.NamespacedName{}, nil
+	}
+
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) == 2 {
+		return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+	}
+
+	namespace := alert.Labels[NamespaceLabel]
+	if namespace == "" {
+		return types.NamespacedName{}, fmt.Errorf("Alert %s references %s %s without a namespace", alert.Labels["alertname"], refKey, ref)
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: ref}, nil
+}
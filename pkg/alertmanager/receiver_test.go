@@ -0,0 +1,196 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack"
+	"github.com/stakater/slack-operator/pkg/slack/mock"
+)
+
+var log = zap.New()
+
+const testToken = "s3cr3t"
+
+func newTestReceiver(objects ...client.Object) *Receiver {
+	scheme := runtime.NewScheme()
+	_ = slackv1alpha1.AddToScheme(scheme)
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...)
+
+	return NewReceiver("", testToken, builder.Build(), slack.NewMockService(log), log)
+}
+
+func newChannel(namespace string, name string, id string) *slackv1alpha1.Channel {
+	return &slackv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status:     slackv1alpha1.ChannelStatus{ID: id},
+	}
+}
+
+func newAlert(labels KV) Alert {
+	return Alert{
+		Status:      StatusFiring,
+		Labels:      labels,
+		Annotations: KV{"summary": "Something is on fire"},
+	}
+}
+
+func TestChannelRef_shouldUseAlertNamespace_whenRefHasNoNamespace(t *testing.T) {
+	key, err := channelRef(newAlert(KV{ChannelRefKey: "alerts", NamespaceLabel: "team-a"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "team-a/alerts", key.String())
+}
+
+func TestChannelRef_shouldUseAnnotation_whenLabelIsMissing(t *testing.T) {
+	alert := newAlert(KV{})
+	alert.Annotations[ChannelRefKey] = "team-b/alerts"
+
+	key, err := channelRef(alert)
+	assert.NoError(t, err)
+	assert.Equal(t, "team-b/alerts", key.String())
+}
+
+func TestChannelRef_shouldThrowError_whenNamespaceCannotBeResolved(t *testing.T) {
+	_, err := channelRef(newAlert(KV{ChannelRefKey: "alerts"}))
+	assert.Error(t, err)
+}
+
+func TestRender_shouldRenderBlockPerAlert(t *testing.T) {
+	data := &Data{
+		Status:       StatusFiring,
+		CommonLabels: KV{"alertname": "KubePodCrashLooping"},
+		Alerts: []Alert{
+			newAlert(KV{"alertname": "KubePodCrashLooping"}),
+			newAlert(KV{"alertname": "KubePodCrashLooping"}),
+		},
+	}
+
+	blocks, err := Render(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(blocks))
+}
+
+func TestReceiver_Notify_shouldPostToReferencedChannel(t *testing.T) {
+	r := newTestReceiver(newChannel("team-a", "alerts", mock.PublicConversationID))
+
+	_, errs := r.Notify(context.Background(), &Data{
+		Status: StatusFiring,
+		Alerts: []Alert{newAlert(KV{ChannelRefKey: "alerts", NamespaceLabel: "team-a"})},
+	})
+	assert.Equal(t, 0, len(errs))
+}
+
+func TestReceiver_Notify_shouldThrowError_whenChannelIsNotCreated(t *testing.T) {
+	r := newTestReceiver(newChannel("team-a", "alerts", ""))
+
+	_, errs := r.Notify(context.Background(), &Data{
+		Status: StatusFiring,
+		Alerts: []Alert{newAlert(KV{ChannelRefKey: "team-a/alerts"})},
+	})
+	assert.Equal(t, 1, len(errs))
+}
+
+func TestReceiver_Notify_shouldRenderWithReferencedTemplate(t *testing.T) {
+	r := newTestReceiver(newChannel("team-a", "alerts", mock.PublicConversationID), &slackv1alpha1.NotificationTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "short", Namespace: "team-a"},
+		Spec: slackv1alpha1.NotificationTemplateSpec{
+			Blocks: `[{"type": "section", "text": {"type": "mrkdwn", "text": {{ .Status | json }}}}]`,
+		},
+	})
+
+	_, errs := r.Notify(context.Background(), &Data{
+		Status: StatusFiring,
+		Alerts: []Alert{newAlert(KV{ChannelRefKey: "team-a/alerts", TemplateRefKey: "team-a/short"})},
+	})
+	assert.Equal(t, 0, len(errs))
+}
+
+func TestReceiver_Notify_shouldThrowError_whenTemplateIsMissing(t *testing.T) {
+	r := newTestReceiver(newChannel("team-a", "alerts", mock.PublicConversationID))
+
+	_, errs := r.Notify(context.Background(), &Data{
+		Status: StatusFiring,
+		Alerts: []Alert{newAlert(KV{ChannelRefKey: "team-a/alerts", TemplateRefKey: "team-a/missing"})},
+	})
+	assert.Equal(t, 1, len(errs))
+}
+
+func TestReceiver_ServeHTTP_shouldRejectNonPostRequests(t *testing.T) {
+	r := newTestReceiver()
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func newRequest(token string, data *Data) *http.Request {
+	body, _ := json.Marshal(data)
+	req := httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestReceiver_ServeHTTP_shouldReturnOK_whenAlertsArePosted(t *testing.T) {
+	r := newTestReceiver(newChannel("team-a", "alerts", mock.PublicConversationID))
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, newRequest(testToken, &Data{
+		Status: StatusFiring,
+		Alerts: []Alert{newAlert(KV{ChannelRefKey: "team-a/alerts"})},
+	}))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestReceiver_ServeHTTP_shouldRejectRequests_whenTokenIsWrong(t *testing.T) {
+	r := newTestReceiver(newChannel("team-a", "alerts", mock.PublicConversationID))
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, newRequest("wrong", &Data{
+		Status: StatusFiring,
+		Alerts: []Alert{newAlert(KV{ChannelRefKey: "team-a/alerts"})},
+	}))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestReceiver_ServeHTTP_shouldReturnOK_whenSomeGroupsFail(t *testing.T) {
+	r := newTestReceiver(newChannel("team-a", "alerts", mock.PublicConversationID), newChannel("team-b", "alerts", ""))
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, newRequest(testToken, &Data{
+		Status: StatusFiring,
+		Alerts: []Alert{
+			newAlert(KV{ChannelRefKey: "team-a/alerts"}),
+			newAlert(KV{ChannelRefKey: "team-b/alerts"}),
+		},
+	}))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestReceiver_ServeHTTP_shouldReturnError_whenNoGroupIsPosted(t *testing.T) {
+	r := newTestReceiver(newChannel("team-a", "alerts", ""))
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, newRequest(testToken, &Data{
+		Status: StatusFiring,
+		Alerts: []Alert{newAlert(KV{ChannelRefKey: "team-a/alerts"})},
+	}))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
@@ -0,0 +1,53 @@
+package alertmanager
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/slack-go/slack"
+
+	"github.com/stakater/slack-operator/pkg/blockkit"
+)
+
+// DefaultTemplate is the Block Kit template used to render alert notifications
+const DefaultTemplate = `[
+	{
+		"type": "header",
+		"text": {
+			"type": "plain_text",
+			"text": {{ printf "[%s] %s" (upper .Status) (index .CommonLabels "alertname") | json }}
+		}
+	}
+	{{- range .Alerts }},
+	{
+		"type": "section",
+		"text": {
+			"type": "mrkdwn",
+			"text": {{ printf "*%s* %s\n%s" (upper .Status) (or .Annotations.summary .Labels.alertname) .Annotations.description | json }}
+		}
+	}
+	{{- if .GeneratorURL }},
+	{
+		"type": "context",
+		"elements": [
+			{
+				"type": "mrkdwn",
+				"text": {{ printf "<%s|Source>" .GeneratorURL | json }}
+			}
+		]
+	}
+	{{- end }}
+	{{- end }}
+]`
+
+var defaultTemplate = template.Must(blockkit.Parse("alertmanager", DefaultTemplate))
+
+// Render renders the alert group into slack blocks using the default template
+func Render(data *Data) ([]slack.Block, error) {
+	return blockkit.Render(defaultTemplate, data)
+}
+
+// FallbackText returns the plain text shown in notifications for clients that can't render blocks
+func FallbackText(data *Data) string {
+	return fmt.Sprintf("[%s] %s (%d alerts)", data.Status, data.CommonLabels["alertname"], len(data.Alerts))
+}
@@ -0,0 +1,58 @@
+package alertmanager
+
+import "time"
+
+const (
+	// StatusFiring is the status of an alert or alert group that is currently firing
+	StatusFiring string = "firing"
+	// StatusResolved is the status of an alert or alert group that has been resolved
+	StatusResolved string = "resolved"
+)
+
+// KV is a set of key/value string pairs, used for labels and annotations
+type KV map[string]string
+
+// Alert holds one alert for notification templates
+type Alert struct {
+	Status       string    `json:"status"`
+	Labels       KV        `json:"labels"`
+	Annotations  KV        `json:"annotations"`
+	StartsAt     time.Time `json:"startsAt"`
+	EndsAt       time.Time `json:"endsAt"`
+	GeneratorURL string    `json:"generatorURL"`
+	Fingerprint  string    `json:"fingerprint"`
+}
+
+// Data is the payload sent by Alertmanager to webhook receivers
+type Data struct {
+	Version           string  `json:"version"`
+	GroupKey          string  `json:"groupKey"`
+	TruncatedAlerts   int     `json:"truncatedAlerts"`
+	Receiver          string  `json:"receiver"`
+	Status            string  `json:"status"`
+	Alerts            []Alert `json:"alerts"`
+	GroupLabels       KV      `json:"groupLabels"`
+	CommonLabels      KV      `json:"commonLabels"`
+	CommonAnnotations KV      `json:"commonAnnotations"`
+	ExternalURL       string  `json:"externalURL"`
+}
+
+// Firing returns the subset of alerts that are firing
+func (d *Data) Firing() []Alert {
+	return d.filterByStatus(StatusFiring)
+}
+
+// Resolved returns the subset of alerts that are resolved
+func (d *Data) Resolved() []Alert {
+	return d.filterByStatus(StatusResolved)
+}
+
+func (d *Data) filterByStatus(status string) []Alert {
+	alerts := []Alert{}
+	for _, alert := range d.Alerts {
+		if alert.Status == status {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
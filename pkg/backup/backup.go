@@ -0,0 +1,176 @@
+// Package backup exports the managed channels with their Slack state and restores their Channels, for disaster
+// recovery of the channels managed as code
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack"
+)
+
+// Backup is a backup of the managed channels
+type Backup struct {
+	// Time the backup was taken at
+	Time metav1.Time `json:"time"`
+
+	// Channels sorted by namespace and name
+	Channels []Channel `json:"channels"`
+}
+
+// Channel is the backup of a Channel and of the state of its slack channel
+type Channel struct {
+	Namespace string                    `json:"namespace"`
+	Name      string                    `json:"name"`
+	Labels    map[string]string         `json:"labels,omitempty"`
+	Spec      slackv1alpha1.ChannelSpec `json:"spec"`
+
+	// ID of the slack channel, empty if it wasn't created yet
+	ID string `json:"id,omitempty"`
+
+	// Members of the slack channel as resolved by the operator
+	Members []slack.ChannelMember `json:"members,omitempty"`
+
+	Bookmarks []slack.Bookmark `json:"bookmarks,omitempty"`
+	Pins      []slack.Pin      `json:"pins,omitempty"`
+}
+
+// Export backs up the Channels of the namespaces accepted by include. The members, bookmarks and pins of their
+// slack channels are fetched from Slack, channels the bot can't see anymore are backed up without them.
+func Export(ctx context.Context, c client.Client, service slack.Service, include func(namespace string) bool) (*Backup, error) {
+	channels := &slackv1alpha1.ChannelList{}
+	if err := c.List(ctx, channels); err != nil {
+		return nil, err
+	}
+
+	backup := &Backup{Time: metav1.NewTime(time.Now().UTC()), Channels: []Channel{}}
+	for _, channel := range channels.Items {
+		if channel.DeletionTimestamp != nil || !include(channel.Namespace) {
+			continue
+		}
+
+		backedUp := Channel{
+			Namespace: channel.Namespace,
+			Name:      channel.Name,
+			Labels:    channel.Labels,
+			Spec:      channel.Spec,
+			ID:        channel.Status.ID,
+		}
+		if backedUp.ID != "" {
+			if err := exportSlackState(service, &backedUp); err != nil {
+				return nil, fmt.Errorf("Error backing up channel %s/%s: %v", channel.Namespace, channel.Name, err)
+			}
+		}
+		backup.Channels = append(backup.Channels, backedUp)
+	}
+
+	sort.Slice(backup.Channels, func(i, j int) bool {
+		if backup.Channels[i].Namespace != backup.Channels[j].Namespace {
+			return backup.Channels[i].Namespace < backup.Channels[j].Namespace
+		}
+		return backup.Channels[i].Name < backup.Channels[j].Name
+	})
+	return backup, nil
+}
+
+// exportSlackState adds the members, bookmarks and pins of the slack channel to the backup of the channel
+func exportSlackState(service slack.Service, channel *Channel) error {
+	members, err := service.ListMembers(channel.ID)
+	if errors.Is(err, slack.ErrChannelNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	channel.Members = members
+
+	if channel.Bookmarks, err = service.ListBookmarks(channel.ID); err != nil {
+		return err
+	}
+	channel.Pins, err = service.ListPins(channel.ID)
+	return err
+}
+
+// Marshal serializes the backup as YAML
+func (b *Backup) Marshal() ([]byte, error) {
+	return yaml.Marshal(b)
+}
+
+// Parse deserializes a backup from YAML
+func Parse(data []byte) (*Backup, error) {
+	backup := &Backup{}
+	if err := yaml.UnmarshalStrict(data, backup); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+// Restore results of a Channel
+const (
+	Created = "Created"
+	Exists  = "Exists"
+)
+
+// Result is the outcome of restoring a Channel
+type Result struct {
+	Namespace string
+	Name      string
+	// Action is Created or Exists
+	Action string
+}
+
+// Restore recreates the Channels of the backup which don't exist. The ID of the slack channel is restored in the
+// status, so the operator manages the same slack channel again instead of creating a new one. Nothing is changed
+// with dryRun.
+func Restore(ctx context.Context, c client.Client, backup *Backup, dryRun bool) ([]Result, error) {
+	var results []Result
+	for _, backedUp := range backup.Channels {
+		result := Result{Namespace: backedUp.Namespace, Name: backedUp.Name, Action: Created}
+
+		existing := &slackv1alpha1.Channel{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: backedUp.Namespace, Name: backedUp.Name}, existing)
+		if err == nil {
+			result.Action = Exists
+			results = append(results, result)
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return results, err
+		}
+
+		if !dryRun {
+			if err := restoreChannel(ctx, c, backedUp); err != nil {
+				return results, fmt.Errorf("Error restoring channel %s/%s: %v", backedUp.Namespace, backedUp.Name, err)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// restoreChannel creates the Channel and restores the ID of its slack channel
+func restoreChannel(ctx context.Context, c client.Client, backedUp Channel) error {
+	channel := &slackv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Namespace: backedUp.Namespace, Name: backedUp.Name, Labels: backedUp.Labels},
+		Spec:       backedUp.Spec,
+	}
+	if err := c.Create(ctx, channel); err != nil {
+		return err
+	}
+
+	if backedUp.ID == "" {
+		return nil
+	}
+	channel.Status.ID = backedUp.ID
+	return c.Status().Update(ctx, channel)
+}
@@ -0,0 +1,132 @@
+package backup
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack"
+	"github.com/stakater/slack-operator/pkg/slack/mockservice"
+)
+
+func newClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = slackv1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func channel(namespace string, name string, id string) *slackv1alpha1.Channel {
+	return &slackv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       slackv1alpha1.ChannelSpec{Name: name, Users: []string{"jane@example.com"}},
+		Status:     slackv1alpha1.ChannelStatus{ID: id},
+	}
+}
+
+func TestExport_shouldBackUpChannelsWithTheirSlackState(t *testing.T) {
+	c := newClient(channel("team-b", "alerts", "C2"), channel("team-a", "alerts", "C1"),
+		channel("team-a", "pending", ""), channel("other", "alerts", "C3"))
+	service := &mockservice.Service{
+		ListMembersFunc: func(channelID string) ([]slack.ChannelMember, error) {
+			if channelID == "C2" {
+				return nil, slack.ErrChannelNotFound
+			}
+			return []slack.ChannelMember{{ID: "U1", Email: "jane@example.com", Kind: slack.MemberKindMember}}, nil
+		},
+		ListBookmarksFunc: func(string) ([]slack.Bookmark, error) {
+			return []slack.Bookmark{{Title: "Runbook", Link: "https://example.com", Type: "link"}}, nil
+		},
+		ListPinsFunc: func(string) ([]slack.Pin, error) {
+			return []slack.Pin{{Type: "message", Timestamp: "1.000001", Text: "Read the runbook"}}, nil
+		},
+	}
+
+	backup, err := Export(context.Background(), c, service, func(namespace string) bool {
+		return namespace != "other"
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, backup.Channels, 3)
+	assert.Equal(t, "team-a/alerts", backup.Channels[0].Namespace+"/"+backup.Channels[0].Name)
+	assert.Equal(t, "C1", backup.Channels[0].ID)
+	assert.Equal(t, "U1", backup.Channels[0].Members[0].ID)
+	assert.Equal(t, "Runbook", backup.Channels[0].Bookmarks[0].Title)
+	assert.Equal(t, "Read the runbook", backup.Channels[0].Pins[0].Text)
+	assert.Empty(t, backup.Channels[1].ID)
+	assert.Empty(t, backup.Channels[1].Members)
+	assert.Equal(t, "C2", backup.Channels[2].ID)
+	assert.Empty(t, backup.Channels[2].Members)
+	assert.Equal(t, []string{"C1", "C2"}, calledChannels(service.Calls("ListMembers")))
+}
+
+func calledChannels(calls []mockservice.Call) []string {
+	var channelIDs []string
+	for _, call := range calls {
+		channelIDs = append(channelIDs, call.Args[0].(string))
+	}
+	return channelIDs
+}
+
+func TestRestore_shouldRecreateMissingChannels(t *testing.T) {
+	c := newClient(channel("team-a", "alerts", "C1"))
+	backup := &Backup{Channels: []Channel{
+		{Namespace: "team-a", Name: "alerts", Spec: slackv1alpha1.ChannelSpec{Name: "changed"}, ID: "C1"},
+		{Namespace: "team-a", Name: "deploys", Spec: slackv1alpha1.ChannelSpec{Name: "deploys"}, ID: "C2"},
+	}}
+
+	data, err := backup.Marshal()
+	assert.NoError(t, err)
+	parsed, err := Parse(data)
+	assert.NoError(t, err)
+
+	results, err := Restore(context.Background(), c, parsed, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Result{
+		{Namespace: "team-a", Name: "alerts", Action: Exists},
+		{Namespace: "team-a", Name: "deploys", Action: Created},
+	}, results)
+
+	restored := &slackv1alpha1.Channel{}
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "deploys"}, restored))
+	assert.Equal(t, "deploys", restored.Spec.Name)
+	assert.Equal(t, "C2", restored.Status.ID)
+
+	existing := &slackv1alpha1.Channel{}
+	assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "alerts"}, existing))
+	assert.Equal(t, "alerts", existing.Spec.Name)
+}
+
+func TestWriteFile_shouldKeepTheLatestBackups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	var files []string
+	for i := 0; i < 4; i++ {
+		file, err := WriteFile(dir, 2, start.Add(time.Duration(i)*time.Hour), []byte("channels: []"))
+		assert.NoError(t, err)
+		files = append(files, filepath.Base(file))
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, files[2:], names)
+	assert.Equal(t, "channels-20210601T030000Z.yaml", files[3])
+}
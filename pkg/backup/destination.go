@@ -0,0 +1,76 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ConfigMapKey is the key of the ConfigMap holding the backup
+const ConfigMapKey = "channels.yaml"
+
+// filePrefix and fileSuffix surround the time in the names of backup files
+const (
+	filePrefix = "channels-"
+	fileSuffix = ".yaml"
+)
+
+// WriteConfigMap writes the backup to the ConfigMap, which is created if it doesn't exist
+func WriteConfigMap(ctx context.Context, c client.Client, namespace string, name string, data []byte) error {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, configMap, func() error {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[ConfigMapKey] = string(data)
+		return nil
+	})
+	return err
+}
+
+// WriteFile writes the backup taken at the time to a new file in the directory and deletes the oldest backups
+// beyond keep, at least the new backup is kept. The path of the file is returned.
+func WriteFile(dir string, keep int, at time.Time, data []byte) (string, error) {
+	if keep < 1 {
+		keep = 1
+	}
+	file := filepath.Join(dir, filePrefix+at.UTC().Format("20060102T150405Z")+fileSuffix)
+
+	// The backup is renamed into place, so a partially written file is never taken for a backup
+	temp := file + ".tmp"
+	if err := ioutil.WriteFile(temp, data, 0600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(temp, file); err != nil {
+		return "", err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return file, err
+	}
+	var backups []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), filePrefix) && strings.HasSuffix(entry.Name(), fileSuffix) {
+			backups = append(backups, entry.Name())
+		}
+	}
+	// The names sort by time, the newest last
+	sort.Strings(backups)
+	for i := 0; i < len(backups)-keep; i++ {
+		if err := os.Remove(filepath.Join(dir, backups[i])); err != nil {
+			return file, fmt.Errorf("Error deleting old backup %s: %v", backups[i], err)
+		}
+	}
+	return file, nil
+}
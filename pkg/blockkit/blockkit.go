@@ -0,0 +1,108 @@
+package blockkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/slack-go/slack"
+)
+
+// unsafeFuncs are the sprig functions left out of templates, templates are written by tenants and mustn't read the
+// environment of the operator or resolve hosts from its network
+var unsafeFuncs = []string{"env", "expandenv", "getHostByName"}
+
+// funcMap contains the helper functions available to Block Kit templates, the sprig functions and json
+var funcMap = newFuncMap()
+
+func newFuncMap() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	for _, name := range unsafeFuncs {
+		delete(funcs, name)
+	}
+	funcs["json"] = toJSON
+	return funcs
+}
+
+// Template is a Block Kit template with an optional fallback text template
+type Template struct {
+	blocks *template.Template
+	text   *template.Template
+}
+
+// Compile parses the blocks and text templates, text may be empty
+func Compile(name string, blocks string, text string) (*Template, error) {
+	blocksTemplate, err := Parse(name, blocks)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid blocks template: %v", err)
+	}
+
+	compiled := &Template{blocks: blocksTemplate}
+	if text != "" {
+		compiled.text, err = Parse(name+"-text", text)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid text template: %v", err)
+		}
+	}
+
+	return compiled, nil
+}
+
+// Render renders the blocks and fallback text, the text is empty when the template has none
+func (t *Template) Render(data interface{}) ([]slack.Block, string, error) {
+	blocks, err := Render(t.blocks, data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if t.text == nil {
+		return blocks, "", nil
+	}
+
+	text, err := RenderText(t.text, data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return blocks, text, nil
+}
+
+// Parse parses a Block Kit template, the template must render to a JSON array of blocks
+func Parse(name string, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=zero").Funcs(funcMap).Parse(text)
+}
+
+// Render executes the template with the given data and decodes the result into slack blocks
+func Render(tmpl *template.Template, data interface{}) ([]slack.Block, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	var blocks slack.Blocks
+	if err := json.Unmarshal(buf.Bytes(), &blocks); err != nil {
+		return nil, fmt.Errorf("Template %s did not render valid Block Kit JSON: %v", tmpl.Name(), err)
+	}
+
+	return blocks.BlockSet, nil
+}
+
+// RenderText executes a plain text template
+func RenderText(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// toJSON encodes a value as JSON so it can be safely embedded in a template
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
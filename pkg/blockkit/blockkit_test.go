@@ -0,0 +1,49 @@
+package blockkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sectionTemplate = `[{"type": "section", "text": {"type": "mrkdwn", "text": {{ .Message | default "none" | trunc 5 | json }}}}]`
+
+func TestCompile_shouldThrowError_whenTemplateIsInvalid(t *testing.T) {
+	_, err := Compile("invalid", `[{{ .Message }`, "")
+	assert.Error(t, err)
+
+	_, err = Compile("invalid", sectionTemplate, `{{ if }}`)
+	assert.Error(t, err)
+}
+
+func TestTemplate_Render_shouldRenderBlocksAndText(t *testing.T) {
+	tmpl, err := Compile("section", sectionTemplate, `{{ .Message | upper }}`)
+	assert.NoError(t, err)
+
+	blocks, text, err := tmpl.Render(map[string]string{"Message": "something broke"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(blocks))
+	assert.Equal(t, "SOMETHING BROKE", text)
+}
+
+func TestTemplate_Render_shouldThrowError_whenOutputIsNotBlockKitJSON(t *testing.T) {
+	tmpl, err := Compile("text", `not json`, "")
+	assert.NoError(t, err)
+
+	_, _, err = tmpl.Render(nil)
+	assert.Error(t, err)
+}
+
+func TestParse_shouldProvideSprigFunctions(t *testing.T) {
+	tmpl, err := Parse("text", `{{ .Reason | default "Unknown" | lower }} {{ list "a" "b" | join "," }}`)
+	assert.NoError(t, err)
+
+	text, err := RenderText(tmpl, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "unknown a,b", text)
+}
+
+func TestParse_shouldRejectEnvironmentFunctions(t *testing.T) {
+	_, err := Parse("text", `{{ env "HOME" }}`)
+	assert.Error(t, err)
+}
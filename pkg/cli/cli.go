@@ -0,0 +1,121 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cli has the helpers shared by the command line tools of the operator, kubectl-slack and slackctl
+package cli
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slack "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// TokenEnv is the environment variable the tools read the bot token from when no token flag is set
+const TokenEnv = "SLACK_API_TOKEN"
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(slackv1alpha1.AddToScheme(scheme))
+}
+
+// KubeOptions are the flags selecting the cluster and the namespace
+type KubeOptions struct {
+	Kubeconfig string
+	Context    string
+	Namespace  string
+}
+
+// Client creates a client of the cluster of the kubeconfig, the namespace of its context is used unless one was
+// given with -n
+func (o *KubeOptions) Client() (client.Client, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.Kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: o.Context})
+
+	if o.Namespace == "" {
+		namespace, _, err := clientConfig.Namespace()
+		if err != nil {
+			return nil, err
+		}
+		o.Namespace = namespace
+	}
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(restConfig, client.Options{Scheme: scheme})
+}
+
+// ParseInterspersed parses the flags between the positional arguments, which are returned
+func ParseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// SlackToken returns the token of the flag, or of the TokenEnv environment variable when the flag is empty
+func SlackToken(token string) string {
+	if token != "" {
+		return token
+	}
+	return os.Getenv(TokenEnv)
+}
+
+// NewSlackService creates a SlackService with the token which doesn't log, the tools report errors themselves
+func NewSlackService(token string) *slack.SlackService {
+	return slack.New(token, zap.New(zap.WriteTo(io.Discard)))
+}
+
+// RequestReconcile annotates the Channel with the current time, so the operator reconciles it right away
+func RequestReconcile(ctx context.Context, c client.Client, key types.NamespacedName) error {
+	channel := &slackv1alpha1.Channel{}
+	if err := c.Get(ctx, key, channel); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(channel.DeepCopy())
+	if channel.Annotations == nil {
+		channel.Annotations = map[string]string{}
+	}
+	channel.Annotations[slackv1alpha1.ReconcileAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	return c.Patch(ctx, channel, patch)
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+func TestParseInterspersed_shouldParseFlagsBetweenArguments(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	namespace := flags.String("n", "", "")
+	dryRun := flags.Bool("dry-run", false, "")
+
+	args, err := ParseInterspersed(flags, []string{"channels", "-n", "team-a", "sync", "ops", "--dry-run"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"channels", "sync", "ops"}, args)
+	assert.Equal(t, "team-a", *namespace)
+	assert.True(t, *dryRun)
+}
+
+func TestRequestReconcile_shouldAnnotateChannel(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&slackv1alpha1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: "ops", Namespace: "team-a"},
+	}).Build()
+	key := types.NamespacedName{Namespace: "team-a", Name: "ops"}
+
+	assert.NoError(t, RequestReconcile(context.Background(), c, key))
+
+	channel := &slackv1alpha1.Channel{}
+	assert.NoError(t, c.Get(context.Background(), key, channel))
+	assert.NotEmpty(t, channel.Annotations[slackv1alpha1.ReconcileAtAnnotation])
+}
@@ -1,16 +1,22 @@
 package config
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
 	util "github.com/stakater/operator-utils/util"
 	secretsUtil "github.com/stakater/operator-utils/util/secrets"
 	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/stakater/slack-operator/pkg/slack"
 )
 
 const (
@@ -18,6 +24,24 @@ const (
 
 	SlackDefaultSecretName string = "slack-secret"
 	SlackAPITokenSecretKey string = "APIToken"
+	// SlackSCIMTokenSecretKey is the optional key of an admin user token for the SCIM API
+	SlackSCIMTokenSecretKey string = "SCIMToken"
+	// SlackUserTokenSecretKey is the optional key of a user token for Web API methods bot tokens can't call
+	SlackUserTokenSecretKey string = "UserToken"
+	// SlackPoolTokensSecretKey is the optional key of comma or newline separated bot tokens of further apps
+	SlackPoolTokensSecretKey string = "PoolTokens"
+	// SlackCanaryTokenSecretKey is the optional key of a bot token of the canary workspace, Channels with the canary
+	// label are reconciled against it before the production workspace
+	SlackCanaryTokenSecretKey string = "CanaryToken"
+	// SlackAlertmanagerTokenSecretKey is the key of the bearer token Alertmanager authenticates to the receiver with,
+	// required when the receiver is enabled
+	SlackAlertmanagerTokenSecretKey string = "AlertmanagerToken"
+
+	// Optional keys of rotating tokens, the refreshed APIToken and RefreshToken are written back to the secret
+	SlackRefreshTokenSecretKey   string = "RefreshToken"
+	SlackTokenExpiresAtSecretKey string = "TokenExpiresAt"
+	SlackClientIDSecretKey       string = "ClientID"
+	SlackClientSecretSecretKey   string = "ClientSecret"
 )
 
 var (
@@ -85,7 +109,7 @@ func getConfigSecretName() string {
 	return configSecretName
 }
 
-func ReadSlackTokenSecret(k8sReader client.Reader) string {
+func getOperatorNamespace() string {
 	operatorNamespace, _ := os.LookupEnv("OPERATOR_NAMESPACE")
 	if len(operatorNamespace) == 0 {
 		operatorNamespaceTemp, err := util.GetOperatorNamespace()
@@ -95,6 +119,11 @@ func ReadSlackTokenSecret(k8sReader client.Reader) string {
 		}
 		operatorNamespace = operatorNamespaceTemp
 	}
+	return operatorNamespace
+}
+
+func ReadSlackTokenSecret(k8sReader client.Reader) string {
+	operatorNamespace := getOperatorNamespace()
 
 	token, err := secretsUtil.LoadSecretData(k8sReader, SlackSecretName, operatorNamespace, SlackAPITokenSecretKey)
 	if err != nil {
@@ -104,3 +133,112 @@ func ReadSlackTokenSecret(k8sReader client.Reader) string {
 
 	return token
 }
+
+// SlackTokenSecret returns a reference to the operator secret holding the Slack token
+func SlackTokenSecret() *corev1.Secret {
+	secret := &corev1.Secret{}
+	secret.Name = SlackSecretName
+	secret.Namespace = getOperatorNamespace()
+	return secret
+}
+
+// LoadSlackToken reads the Slack token from the operator secret
+func LoadSlackToken(ctx context.Context, k8sReader client.Reader) (string, error) {
+	secret := &corev1.Secret{}
+	err := k8sReader.Get(ctx, types.NamespacedName{Name: SlackSecretName, Namespace: getOperatorNamespace()}, secret)
+	if err != nil {
+		return "", err
+	}
+	return string(secret.Data[SlackAPITokenSecretKey]), nil
+}
+
+// SecretTokenStore stores the rotating Slack token in the operator secret
+type SecretTokenStore struct {
+	// Reader reads the secret bypassing the cache, which may not cover the operator namespace
+	Reader client.Reader
+	Writer client.Writer
+}
+
+// Load returns the rotating token of the operator secret, or nil when the secret has no refresh token
+func (s *SecretTokenStore) Load(ctx context.Context) (*slack.RotatingToken, error) {
+	secret := &corev1.Secret{}
+	err := s.Reader.Get(ctx, types.NamespacedName{Name: SlackSecretName, Namespace: getOperatorNamespace()}, secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret.Data[SlackRefreshTokenSecretKey]) == 0 {
+		return nil, nil
+	}
+
+	// Tokens without an expiry are refreshed right away
+	expiresAt, _ := time.Parse(time.RFC3339, string(secret.Data[SlackTokenExpiresAtSecretKey]))
+
+	return &slack.RotatingToken{
+		AccessToken:  string(secret.Data[SlackAPITokenSecretKey]),
+		RefreshToken: string(secret.Data[SlackRefreshTokenSecretKey]),
+		ExpiresAt:    expiresAt,
+		ClientID:     string(secret.Data[SlackClientIDSecretKey]),
+		ClientSecret: string(secret.Data[SlackClientSecretSecretKey]),
+	}, nil
+}
+
+// Save writes the access token, refresh token and expiry to the operator secret
+func (s *SecretTokenStore) Save(ctx context.Context, token *slack.RotatingToken) error {
+	secret := &corev1.Secret{}
+	err := s.Reader.Get(ctx, types.NamespacedName{Name: SlackSecretName, Namespace: getOperatorNamespace()}, secret)
+	if err != nil {
+		return err
+	}
+
+	secret.Data[SlackAPITokenSecretKey] = []byte(token.AccessToken)
+	secret.Data[SlackRefreshTokenSecretKey] = []byte(token.RefreshToken)
+	secret.Data[SlackTokenExpiresAtSecretKey] = []byte(token.ExpiresAt.UTC().Format(time.RFC3339))
+	return s.Writer.Update(ctx, secret)
+}
+
+// ReadSCIMTokenSecret returns the SCIM token of the operator secret, or an empty string when SCIM isn't configured
+func ReadSCIMTokenSecret(k8sReader client.Reader) string {
+	return readOptionalSecretKey(k8sReader, SlackSCIMTokenSecretKey)
+}
+
+// ReadUserTokenSecret returns the user token of the operator secret, or an empty string when there is none
+func ReadUserTokenSecret(k8sReader client.Reader) string {
+	return readOptionalSecretKey(k8sReader, SlackUserTokenSecretKey)
+}
+
+// ReadCanaryTokenSecret returns the bot token of the canary workspace of the operator secret, or an empty string when
+// there is none
+func ReadCanaryTokenSecret(k8sReader client.Reader) string {
+	return readOptionalSecretKey(k8sReader, SlackCanaryTokenSecretKey)
+}
+
+// ReadAlertmanagerTokenSecret returns the bearer token of the Alertmanager receiver of the operator secret, or an
+// empty string when there is none
+func ReadAlertmanagerTokenSecret(k8sReader client.Reader) string {
+	return readOptionalSecretKey(k8sReader, SlackAlertmanagerTokenSecretKey)
+}
+
+// ReadPoolTokensSecret returns the bot tokens of the token pool in the operator secret
+func ReadPoolTokensSecret(k8sReader client.Reader) []string {
+	return SplitTokens(readOptionalSecretKey(k8sReader, SlackPoolTokensSecretKey))
+}
+
+// SplitTokens splits comma or newline separated tokens
+func SplitTokens(tokens string) []string {
+	return strings.FieldsFunc(tokens, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ' '
+	})
+}
+
+func readOptionalSecretKey(k8sReader client.Reader, key string) string {
+	operatorNamespace := getOperatorNamespace()
+
+	secret := &corev1.Secret{}
+	err := k8sReader.Get(context.TODO(), types.NamespacedName{Name: SlackSecretName, Namespace: operatorNamespace}, secret)
+	if err != nil {
+		setupLog.Error(err, "Could not read secret", "secretName", SlackSecretName)
+		os.Exit(1)
+	}
+
+	return string(secret.Data[key])
+}
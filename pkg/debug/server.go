@@ -0,0 +1,100 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Server serves the pprof profiles or the in-memory state of the operator, every replica serves its own
+type Server struct {
+	addr     string
+	log      logr.Logger
+	profiles bool
+	state    map[string]StateFunc
+}
+
+// NewServer creates a new Server serving on addr
+func NewServer(addr string, logger logr.Logger) *Server {
+	return &Server{addr: addr, log: logger, state: map[string]StateFunc{}}
+}
+
+// HandleProfiles serves the pprof profiles below /debug/pprof/
+func (s *Server) HandleProfiles() {
+	s.profiles = true
+}
+
+// HandleState serves the state returned by the function as JSON below /debug/<name>
+func (s *Server) HandleState(name string, state StateFunc) {
+	s.state[name] = state
+}
+
+// Handler returns the handler of the profiles, if they are served, and of the states
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	if s.profiles {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	for name, state := range s.state {
+		mux.Handle("/debug/"+name, s.stateHandler(state))
+	}
+	return mux
+}
+
+// stateHandler serves the state as indented JSON
+func (s *Server) stateHandler(state StateFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value, err := state(r.Context())
+		if err != nil {
+			s.log.Error(err, "Error getting debug state", "path", r.URL.Path)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(value); err != nil {
+			s.log.Error(err, "Error writing debug state", "path", r.URL.Path)
+		}
+	})
+}
+
+// Start runs the HTTP server until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:    s.addr,
+		Handler: s.Handler(),
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		s.log.Info("Starting debug server", "addr", s.addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		return err
+	}
+}
+
+// NeedLeaderElection profiles every replica, not only the leader
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
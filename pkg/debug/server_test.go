@@ -0,0 +1,66 @@
+package debug
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/stakater/slack-operator/pkg/redact"
+)
+
+func TestServer_shouldServeProfiles(t *testing.T) {
+	profilingServer := NewServer(":0", zap.New())
+	profilingServer.HandleProfiles()
+	server := httptest.NewServer(profilingServer.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/debug/pprof/goroutine?debug=1")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "goroutine profile")
+}
+
+func TestServer_shouldServeStates(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	depth := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "workqueue_depth"}, []string{"name"})
+	registry.MustRegister(depth)
+	depth.WithLabelValues("channel").Set(3)
+
+	debugServer := NewServer(":0", zap.New())
+	debugServer.HandleState("queues", Queues(registry))
+	debugServer.HandleState("users", Users(func() []slack.User {
+		return []slack.User{{ID: "U0123", Name: "jane", Profile: slack.UserProfile{Email: "jane@example.com"}}}
+	}, redact.Mask))
+	server := httptest.NewServer(debugServer.Handler())
+	defer server.Close()
+
+	queues := map[string]Queue{}
+	getJSON(t, server.URL+"/debug/queues", &queues)
+	assert.Equal(t, map[string]Queue{"channel": {Depth: 3}}, queues)
+
+	users := []User{}
+	getJSON(t, server.URL+"/debug/users", &users)
+	assert.Equal(t, []User{{ID: "U0123", Name: "jane", Email: "j***@example.com"}}, users)
+
+	resp, err := http.Get(server.URL + "/debug/pprof/")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func getJSON(t *testing.T, url string, value interface{}) {
+	resp, err := http.Get(url)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(value))
+}
@@ -0,0 +1,100 @@
+package debug
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/slack-go/slack"
+
+	"github.com/stakater/slack-operator/pkg/redact"
+)
+
+// StateFunc returns a part of the in-memory state of the operator, it must be serializable as JSON
+type StateFunc func(ctx context.Context) (interface{}, error)
+
+// Queue is the state of the work queue of a controller
+type Queue struct {
+	// Depth is the number of items waiting to be reconciled
+	Depth float64 `json:"depth"`
+	// Adds is the number of items added since the start
+	Adds float64 `json:"adds"`
+	// UnfinishedWorkSeconds is how long the items being reconciled have been reconciled for in total
+	UnfinishedWorkSeconds float64 `json:"unfinishedWorkSeconds"`
+	// LongestRunningProcessorSeconds is how long the longest running reconcile has been running for, it grows
+	// while a reconcile is stuck
+	LongestRunningProcessorSeconds float64 `json:"longestRunningProcessorSeconds"`
+}
+
+// queueMetrics are the work queue metrics of client-go which make up a Queue
+var queueMetrics = map[string]func(queue *Queue, value float64){
+	"workqueue_depth":                             func(queue *Queue, value float64) { queue.Depth = value },
+	"workqueue_adds_total":                        func(queue *Queue, value float64) { queue.Adds = value },
+	"workqueue_unfinished_work_seconds":           func(queue *Queue, value float64) { queue.UnfinishedWorkSeconds = value },
+	"workqueue_longest_running_processor_seconds": func(queue *Queue, value float64) { queue.LongestRunningProcessorSeconds = value },
+}
+
+// Queues returns the work queues of the controllers by name from their metrics
+func Queues(gatherer prometheus.Gatherer) StateFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		families, err := gatherer.Gather()
+		if err != nil {
+			return nil, err
+		}
+
+		queues := map[string]*Queue{}
+		for _, family := range families {
+			set, found := queueMetrics[family.GetName()]
+			if !found {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				name := ""
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "name" {
+						name = label.GetValue()
+					}
+				}
+				if queues[name] == nil {
+					queues[name] = &Queue{}
+				}
+				switch {
+				case metric.GetGauge() != nil:
+					set(queues[name], metric.GetGauge().GetValue())
+				case metric.GetCounter() != nil:
+					set(queues[name], metric.GetCounter().GetValue())
+				}
+			}
+		}
+		return queues, nil
+	}
+}
+
+// User is a user of the users snapshot with the email redacted
+type User struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	IsBot   bool   `json:"isBot,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+	Guest   bool   `json:"guest,omitempty"`
+}
+
+// Users returns the users of the snapshot with their emails redacted like in the logs, an empty list if the users
+// weren't listed yet
+func Users(users func() []slack.User, mode redact.Mode) StateFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		snapshot := users()
+		redacted := make([]User, 0, len(snapshot))
+		for _, user := range snapshot {
+			redacted = append(redacted, User{
+				ID:      user.ID,
+				Name:    user.Name,
+				Email:   redact.String(user.Profile.Email, mode),
+				IsBot:   user.IsBot,
+				Deleted: user.Deleted,
+				Guest:   user.IsRestricted || user.IsUltraRestricted,
+			})
+		}
+		return redacted, nil
+	}
+}
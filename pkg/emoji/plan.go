@@ -0,0 +1,95 @@
+package emoji
+
+import (
+	"sort"
+)
+
+// Rename of a managed emoji
+type Rename struct {
+	From string
+	To   string
+}
+
+// Plan lists the changes needed to bring the workspace emoji in line with the desired emoji
+type Plan struct {
+	// Emoji to add, replaced emoji are listed in Remove as well
+	Add []string
+	// Emoji to remove
+	Remove []string
+	// Emoji to rename, the image stays the same
+	Rename []Rename
+	// Desired emoji whose name is taken by an emoji the operator doesn't manage, e.g. one added by hand or by another
+	// Emoji resource. They are neither added nor adopted.
+	Conflicts []string
+}
+
+// Empty checks if there is nothing to change, conflicts can't be changed
+func (p Plan) Empty() bool {
+	return len(p.Add) == 0 && len(p.Remove) == 0 && len(p.Rename) == 0
+}
+
+// NewPlan compares the desired emoji with the emoji managed so far, both mapping emoji names to a hash of their
+// image source, and the emoji present in the workspace. Managed emoji which disappear while another emoji with the
+// same source appears are renamed instead of removed and added again. Unmanaged emoji of the workspace are never
+// replaced, desired emoji with their names are listed as conflicts
+func NewPlan(desired map[string]string, managed map[string]string, existing map[string]string) Plan {
+	plan := Plan{}
+
+	var added []string
+	for name, hash := range desired {
+		managedHash, isManaged := managed[name]
+		_, exists := existing[name]
+
+		switch {
+		case !isManaged && exists:
+			plan.Conflicts = append(plan.Conflicts, name)
+		case !isManaged:
+			added = append(added, name)
+		case managedHash != hash:
+			plan.Remove = append(plan.Remove, name)
+			plan.Add = append(plan.Add, name)
+		case !exists:
+			// Removed outside of the operator
+			plan.Add = append(plan.Add, name)
+		}
+	}
+	sort.Strings(added)
+
+	var removed []string
+	for name := range managed {
+		if _, found := desired[name]; !found {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	renamedTo := map[string]bool{}
+	for _, name := range removed {
+		renamed := false
+		if _, exists := existing[name]; exists {
+			for _, newName := range added {
+				if !renamedTo[newName] && desired[newName] == managed[name] {
+					plan.Rename = append(plan.Rename, Rename{From: name, To: newName})
+					renamedTo[newName] = true
+					renamed = true
+					break
+				}
+			}
+		}
+		if !renamed {
+			plan.Remove = append(plan.Remove, name)
+		}
+	}
+
+	for _, name := range added {
+		if !renamedTo[name] {
+			plan.Add = append(plan.Add, name)
+		}
+	}
+
+	sort.Strings(plan.Add)
+	sort.Strings(plan.Remove)
+	sort.Strings(plan.Conflicts)
+
+	return plan
+}
@@ -0,0 +1,60 @@
+package emoji
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPlan_shouldAddNewEmoji(t *testing.T) {
+	plan := NewPlan(map[string]string{"shipit": "a"}, map[string]string{}, map[string]string{})
+
+	assert.Equal(t, []string{"shipit"}, plan.Add)
+	assert.Empty(t, plan.Remove)
+	assert.Empty(t, plan.Rename)
+}
+
+func TestNewPlan_shouldBeEmpty_whenNothingChanged(t *testing.T) {
+	plan := NewPlan(map[string]string{"shipit": "a"}, map[string]string{"shipit": "a"}, map[string]string{"shipit": "url"})
+
+	assert.True(t, plan.Empty())
+}
+
+func TestNewPlan_shouldReportConflict_whenNameIsTakenByUnmanagedEmoji(t *testing.T) {
+	plan := NewPlan(map[string]string{"shipit": "a", "partyparrot": "b"}, map[string]string{"parrot": "b"},
+		map[string]string{"shipit": "url", "parrot": "url", "partyparrot": "url"})
+
+	assert.Equal(t, []string{"partyparrot", "shipit"}, plan.Conflicts)
+	assert.Empty(t, plan.Add)
+	assert.Empty(t, plan.Rename)
+	assert.Equal(t, []string{"parrot"}, plan.Remove)
+}
+
+func TestNewPlan_shouldAddAgain_whenEmojiWasRemovedFromWorkspace(t *testing.T) {
+	plan := NewPlan(map[string]string{"shipit": "a"}, map[string]string{"shipit": "a"}, map[string]string{})
+
+	assert.Equal(t, []string{"shipit"}, plan.Add)
+	assert.Empty(t, plan.Remove)
+}
+
+func TestNewPlan_shouldReplace_whenSourceChanged(t *testing.T) {
+	plan := NewPlan(map[string]string{"shipit": "b"}, map[string]string{"shipit": "a"}, map[string]string{"shipit": "url"})
+
+	assert.Equal(t, []string{"shipit"}, plan.Add)
+	assert.Equal(t, []string{"shipit"}, plan.Remove)
+}
+
+func TestNewPlan_shouldRename_whenNameChangedWithSameSource(t *testing.T) {
+	plan := NewPlan(map[string]string{"ship-it": "a"}, map[string]string{"shipit": "a"}, map[string]string{"shipit": "url"})
+
+	assert.Empty(t, plan.Add)
+	assert.Empty(t, plan.Remove)
+	assert.Equal(t, []Rename{{From: "shipit", To: "ship-it"}}, plan.Rename)
+}
+
+func TestNewPlan_shouldRemove_whenEmojiIsNoLongerDesired(t *testing.T) {
+	plan := NewPlan(map[string]string{}, map[string]string{"shipit": "a"}, map[string]string{"shipit": "url"})
+
+	assert.Empty(t, plan.Add)
+	assert.Equal(t, []string{"shipit"}, plan.Remove)
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+	"fmt"
+	"path"
+	"text/template"
+	"time"
+
+	"github.com/slack-go/slack"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/blockkit"
+)
+
+// DefaultTemplate is the Block Kit template used to render forwarded events
+const DefaultTemplate = `[
+	{
+		"type": "section",
+		"text": {
+			"type": "mrkdwn",
+			"text": {{ printf "*%s* %s ` + "`%s/%s/%s`" + `\n%s" .Type .Reason .InvolvedObject.Kind .InvolvedObject.Namespace .InvolvedObject.Name .Message | json }}
+		}
+	},
+	{
+		"type": "context",
+		"elements": [
+			{
+				"type": "mrkdwn",
+				"text": {{ printf "%s | count: %d" .Source.Component .Count | json }}
+			}
+		]
+	}
+]`
+
+var defaultTemplate = template.Must(blockkit.Parse("event", DefaultTemplate))
+
+// Render renders the event into slack blocks using the default template
+func Render(event *corev1.Event) ([]slack.Block, error) {
+	return blockkit.Render(defaultTemplate, event)
+}
+
+// FallbackText returns the plain text shown in notifications for clients that can't render blocks
+func FallbackText(event *corev1.Event) string {
+	return fmt.Sprintf("%s %s %s/%s: %s", event.Type, event.Reason, event.InvolvedObject.Kind, event.InvolvedObject.Name, event.Message)
+}
+
+// Timestamp returns the last time the event occurred
+func Timestamp(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// Matches checks whether the event passes all filters of the route, namespaceLabels are the labels of the event's namespace
+func Matches(route *slackv1alpha1.EventRoute, event *corev1.Event, namespaceLabels map[string]string) (bool, error) {
+	spec := route.Spec
+
+	// Routes forward the events of their own namespace unless they list namespaces, only routes of trusted namespaces
+	// forward the events of other namespaces
+	namespaces := spec.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{route.Namespace}
+	}
+	if !matchesNamespace(namespaces, event.Namespace) {
+		return false, nil
+	}
+	if event.Namespace != route.Namespace && !slackv1alpha1.IsNamespaceTrusted(route.Namespace) {
+		return false, nil
+	}
+
+	if len(spec.Reasons) > 0 && !contains(spec.Reasons, event.Reason) {
+		return false, nil
+	}
+
+	if len(spec.InvolvedObjectKinds) > 0 && !contains(spec.InvolvedObjectKinds, event.InvolvedObject.Kind) {
+		return false, nil
+	}
+
+	if len(spec.Types) > 0 {
+		found := false
+		for _, eventType := range spec.Types {
+			if string(eventType) == event.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	if spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector)
+		if err != nil {
+			return false, err
+		}
+		if !selector.Matches(labels.Set(namespaceLabels)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// DeduplicationKey identifies repeats of the same event
+func DeduplicationKey(event *corev1.Event) string {
+	return fmt.Sprintf("%s/%s/%s", event.InvolvedObject.UID, event.Reason, event.Message)
+}
+
+// matchesNamespace checks whether the namespace matches one of the names or patterns like team-*
+func matchesNamespace(patterns []string, namespace string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
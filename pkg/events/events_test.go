@@ -0,0 +1,93 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+func newEvent() *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-123.16b", Namespace: "team-a"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: "team-a",
+			Name:      "api-123",
+			UID:       "2f7c1b2e",
+		},
+		Reason:  "BackOff",
+		Message: "Back-off restarting failed container",
+		Type:    corev1.EventTypeWarning,
+		Count:   3,
+	}
+}
+
+func TestMatches_shouldMatch_whenRouteHasNoFilters(t *testing.T) {
+	matches, err := Matches(newRoute(slackv1alpha1.EventRouteSpec{}), newEvent(), nil)
+	assert.NoError(t, err)
+	assert.True(t, matches)
+}
+
+func newRoute(spec slackv1alpha1.EventRouteSpec) *slackv1alpha1.EventRoute {
+	return &slackv1alpha1.EventRoute{ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "team-a"}, Spec: spec}
+}
+
+func TestMatches_shouldOnlyMatchEventsOfTheRoutesNamespace_whenNamespaceIsNotTrusted(t *testing.T) {
+	event := newEvent()
+	event.Namespace = "kube-system"
+
+	matches, err := Matches(newRoute(slackv1alpha1.EventRouteSpec{}), event, nil)
+	assert.NoError(t, err)
+	assert.False(t, matches)
+
+	route := newRoute(slackv1alpha1.EventRouteSpec{Namespaces: []string{"*"}})
+	matches, err = Matches(route, event, nil)
+	assert.NoError(t, err)
+	assert.False(t, matches)
+
+	slackv1alpha1.TrustedNamespaces = []string{"team-*"}
+	defer func() { slackv1alpha1.TrustedNamespaces = nil }()
+	matches, err = Matches(route, event, nil)
+	assert.NoError(t, err)
+	assert.True(t, matches)
+}
+
+func TestMatches_shouldNotMatch_whenReasonIsNotListed(t *testing.T) {
+	route := newRoute(slackv1alpha1.EventRouteSpec{Reasons: []string{"FailedScheduling"}})
+
+	matches, err := Matches(route, newEvent(), nil)
+	assert.NoError(t, err)
+	assert.False(t, matches)
+}
+
+func TestMatches_shouldNotMatch_whenTypeIsNotListed(t *testing.T) {
+	route := newRoute(slackv1alpha1.EventRouteSpec{Types: []slackv1alpha1.EventType{"Normal"}})
+
+	matches, err := Matches(route, newEvent(), nil)
+	assert.NoError(t, err)
+	assert.False(t, matches)
+}
+
+func TestMatches_shouldMatchNamespaceSelector_whenNamespaceLabelsMatch(t *testing.T) {
+	route := newRoute(slackv1alpha1.EventRouteSpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+	})
+
+	matches, err := Matches(route, newEvent(), map[string]string{"team": "a"})
+	assert.NoError(t, err)
+	assert.True(t, matches)
+
+	matches, err = Matches(route, newEvent(), map[string]string{"team": "b"})
+	assert.NoError(t, err)
+	assert.False(t, matches)
+}
+
+func TestRender_shouldRenderEvent(t *testing.T) {
+	blocks, err := Render(newEvent())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(blocks))
+}
@@ -0,0 +1,74 @@
+// Package history exports the messages of slack channels to archive files, for compliance retention
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	goslack "github.com/slack-go/slack"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack"
+)
+
+// Collect returns the messages of the channel posted after the oldest timestamp, oldest first
+func Collect(service slack.Service, channelID string, oldest string) ([]goslack.Message, error) {
+	var messages []goslack.Message
+	cursor := ""
+	for {
+		page, next, err := service.ConversationHistory(channelID, oldest, cursor)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, page...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	// Slack lists the newest messages first
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// Write writes the messages, oldest first, to a new file in the directory, which is created if it doesn't exist.
+// The file is named after the timestamps of the first and last message, its path is returned.
+func Write(dir string, format slackv1alpha1.ExportFormat, messages []goslack.Message) (string, error) {
+	var data []byte
+	extension := ".ndjson"
+	if format == slackv1alpha1.ExportJSON {
+		extension = ".json"
+		encoded, err := json.MarshalIndent(messages, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		data = encoded
+	} else {
+		buffer := &bytes.Buffer{}
+		encoder := json.NewEncoder(buffer)
+		for _, message := range messages {
+			if err := encoder.Encode(message); err != nil {
+				return "", err
+			}
+		}
+		data = buffer.Bytes()
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, "messages-"+messages[0].Timestamp+"-"+messages[len(messages)-1].Timestamp+extension)
+
+	// The archive is renamed into place, so a partially written file is never taken for an archive
+	temp := file + ".tmp"
+	if err := ioutil.WriteFile(temp, data, 0600); err != nil {
+		return "", err
+	}
+	return file, os.Rename(temp, file)
+}
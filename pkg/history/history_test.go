@@ -0,0 +1,74 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	goslack "github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack"
+	"github.com/stakater/slack-operator/pkg/slack/fake"
+)
+
+func TestCollect_shouldReturnMessagesAfterOldestInOrder(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	channel := server.AddChannel(fake.Channel{Name: "project", Members: []string{server.BotUserID}})
+
+	service := slack.New("token", zap.New(), slack.WithAPIURL(server.URL()))
+	var timestamps []string
+	for _, text := range []string{"first", "second", "third"} {
+		timestamp, err := service.PostMessage(channel.ID, goslack.MsgOptionText(text, false))
+		assert.NoError(t, err)
+		timestamps = append(timestamps, timestamp)
+	}
+
+	messages, err := Collect(service, channel.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, texts(messages))
+
+	messages, err = Collect(service, channel.ID, timestamps[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"second", "third"}, texts(messages))
+}
+
+func texts(messages []goslack.Message) []string {
+	var texts []string
+	for _, message := range messages {
+		texts = append(texts, message.Text)
+	}
+	return texts
+}
+
+func TestWrite_shouldWriteNDJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	messages := []goslack.Message{
+		{Msg: goslack.Msg{Timestamp: "1.000001", Text: "first"}},
+		{Msg: goslack.Msg{Timestamp: "2.000001", Text: "second"}},
+	}
+	file, err := Write(dir+"/team/project", slackv1alpha1.ExportNDJSON, messages)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(file, "/team/project/messages-1.000001-2.000001.ndjson"))
+
+	opened, err := os.Open(file)
+	assert.NoError(t, err)
+	defer opened.Close()
+	var written []goslack.Message
+	scanner := bufio.NewScanner(opened)
+	for scanner.Scan() {
+		message := goslack.Message{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &message))
+		written = append(written, message)
+	}
+	assert.Equal(t, []string{"first", "second"}, texts(written))
+}
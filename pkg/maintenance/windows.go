@@ -0,0 +1,124 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/membership"
+)
+
+var weekdays = []slackv1alpha1.Weekday{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// Windows are the weekly maintenance windows disruptive actions are restricted to, like removing members, renaming
+// and archiving channels. A nil Windows is always open.
+type Windows struct {
+	// schedules holds a member schedule per window, so every window has its own time zone
+	schedules []slackv1alpha1.MemberSchedule
+}
+
+// Parse parses windows separated by ";", each as "[days] HH:MM-HH:MM [time zone]", e.g.
+// "Sat,Sun 02:00-06:00 Europe/Berlin; Mon-Fri 22:00-02:00". Days default to every day and the time zone to UTC,
+// windows ending before they start end on the next day. An empty spec returns nil.
+func Parse(spec string) (*Windows, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	windows := &Windows{}
+	for i, text := range strings.Split(spec, ";") {
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		schedule := slackv1alpha1.MemberSchedule{Name: fmt.Sprintf("maintenance-%d", i), Users: []string{"maintenance"}}
+		window := slackv1alpha1.ScheduleWindow{}
+
+		if !strings.Contains(fields[0], ":") {
+			days, err := parseDays(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid maintenance window %q: %v", strings.TrimSpace(text), err)
+			}
+			window.Days = days
+			fields = fields[1:]
+		}
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("Invalid maintenance window %q, expected \"[days] HH:MM-HH:MM [time zone]\"", strings.TrimSpace(text))
+		}
+		clock := strings.SplitN(fields[0], "-", 2)
+		if len(clock) != 2 {
+			return nil, fmt.Errorf("Invalid maintenance window %q, expected HH:MM-HH:MM", strings.TrimSpace(text))
+		}
+		window.Start, window.End = clock[0], clock[1]
+		if len(fields) == 2 {
+			schedule.TimeZone = fields[1]
+		}
+
+		schedule.Windows = []slackv1alpha1.ScheduleWindow{window}
+		if _, _, err := membership.ScheduledUsers([]slackv1alpha1.MemberSchedule{schedule}, time.Now()); err != nil {
+			return nil, fmt.Errorf("Invalid maintenance window %q: %v", strings.TrimSpace(text), err)
+		}
+		windows.schedules = append(windows.schedules, schedule)
+	}
+
+	if len(windows.schedules) == 0 {
+		return nil, nil
+	}
+	return windows, nil
+}
+
+// Open checks whether a maintenance window is open at now
+func (w *Windows) Open(now time.Time) bool {
+	if w == nil {
+		return true
+	}
+	active, _, _ := membership.ScheduledUsers(w.schedules, now)
+	return len(active) > 0
+}
+
+// NextOpen returns when the next maintenance window opens, now if one is open
+func (w *Windows) NextOpen(now time.Time) time.Time {
+	if w.Open(now) {
+		return now
+	}
+	// While all windows are closed the next change opens one
+	if next := membership.NextScheduleChange(w.schedules, now); next != nil {
+		return *next
+	}
+	return now
+}
+
+// parseDays parses comma separated days and ranges of days, e.g. Mon-Fri,Sun
+func parseDays(text string) ([]slackv1alpha1.Weekday, error) {
+	var days []slackv1alpha1.Weekday
+	for _, part := range strings.Split(text, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := weekdayIndex(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = weekdayIndex(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+		for i := first; ; i = (i + 1) % len(weekdays) {
+			days = append(days, weekdays[i])
+			if i == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func weekdayIndex(day string) (int, error) {
+	for i, weekday := range weekdays {
+		if strings.EqualFold(day, string(weekday)) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%q is not a day, expected Mon, Tue, Wed, Thu, Fri, Sat or Sun", day)
+}
@@ -0,0 +1,53 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 2026-10-16 is a Friday
+var friday = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func TestParse_shouldReturnNil_whenSpecIsEmpty(t *testing.T) {
+	windows, err := Parse(" ")
+	assert.NoError(t, err)
+	assert.Nil(t, windows)
+	assert.True(t, windows.Open(friday))
+	assert.Equal(t, friday, windows.NextOpen(friday))
+}
+
+func TestParse_shouldThrowError_whenWindowIsInvalid(t *testing.T) {
+	for _, spec := range []string{"Someday 02:00-04:00", "02:00", "02:00-04:00 Mars/Olympus", "Mon 02:00-04:00 UTC extra", "25:00-26:00"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestWindows_shouldBeOpenDuringWindow(t *testing.T) {
+	windows, err := Parse("Mon-Fri 11:00-13:00; Sat,Sun 02:00-06:00 Europe/Berlin")
+	assert.NoError(t, err)
+
+	assert.True(t, windows.Open(friday))
+	assert.False(t, windows.Open(friday.Add(2*time.Hour)))
+	// 02:00 in Berlin is 00:00 UTC in summer time
+	assert.True(t, windows.Open(time.Date(2026, 10, 17, 0, 30, 0, 0, time.UTC)))
+}
+
+func TestWindows_shouldReturnNextOpening(t *testing.T) {
+	windows, err := Parse("Sat 22:00-02:00")
+	assert.NoError(t, err)
+
+	assert.Equal(t, time.Date(2026, 10, 17, 22, 0, 0, 0, time.UTC), windows.NextOpen(friday))
+	sundayNight := time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC)
+	assert.True(t, windows.Open(sundayNight))
+	assert.Equal(t, sundayNight, windows.NextOpen(sundayNight))
+}
+
+func TestParseDays_shouldWrapAroundTheWeek(t *testing.T) {
+	windows, err := Parse("Sat-Mon 00:00-24:00")
+	assert.NoError(t, err)
+	assert.True(t, windows.Open(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)))
+	assert.False(t, windows.Open(friday))
+}
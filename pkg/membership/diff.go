@@ -0,0 +1,71 @@
+package membership
+
+// Diff returns the IDs in desired which are missing from actual, and the IDs in actual which aren't desired.
+// Both results keep the order of their input and contain no duplicates.
+func Diff(desired []string, actual []string) ([]string, []string) {
+	diff := NewStreamingDiff(desired)
+	remove := diff.Page(actual)
+	return diff.Missing(), remove
+}
+
+// StreamingDiff compares the desired IDs with the actual IDs page by page, e.g. the members of a large channel, so
+// the actual IDs never have to be held at once. Only the desired IDs and the IDs to remove are kept.
+type StreamingDiff struct {
+	desired    []string
+	desiredSet map[string]bool
+	seen       map[string]bool
+	removed    map[string]bool
+	scanned    int
+}
+
+// NewStreamingDiff creates a diff against the desired IDs
+func NewStreamingDiff(desired []string) *StreamingDiff {
+	return &StreamingDiff{
+		desired:    desired,
+		desiredSet: toSet(desired),
+		seen:       map[string]bool{},
+		removed:    map[string]bool{},
+	}
+}
+
+// Page compares a page of actual IDs and returns the IDs of the page which aren't desired and weren't returned by
+// an earlier page
+func (d *StreamingDiff) Page(actual []string) []string {
+	var remove []string
+	for _, id := range actual {
+		d.scanned++
+		if d.desiredSet[id] {
+			d.seen[id] = true
+		} else if !d.removed[id] {
+			remove = append(remove, id)
+			d.removed[id] = true
+		}
+	}
+	return remove
+}
+
+// Scanned returns the number of actual IDs compared so far
+func (d *StreamingDiff) Scanned() int {
+	return d.scanned
+}
+
+// Missing returns the desired IDs which weren't in any page, in the order of desired and without duplicates
+func (d *StreamingDiff) Missing() []string {
+	var add []string
+	added := map[string]bool{}
+	for _, id := range d.desired {
+		if !d.seen[id] && !added[id] {
+			add = append(add, id)
+			added[id] = true
+		}
+	}
+	return add
+}
+
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, item := range list {
+		set[item] = true
+	}
+	return set
+}
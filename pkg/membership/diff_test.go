@@ -0,0 +1,32 @@
+package membership
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff_shouldReturnOnlyTheDelta(t *testing.T) {
+	add, remove := Diff([]string{"U1", "U2", "U3", "U3"}, []string{"U2", "U4", "U4"})
+
+	assert.Equal(t, []string{"U1", "U3"}, add)
+	assert.Equal(t, []string{"U4"}, remove)
+}
+
+func TestDiff_shouldReturnNothing_whenSetsAreEqual(t *testing.T) {
+	add, remove := Diff([]string{"U1", "U2"}, []string{"U2", "U1"})
+
+	assert.Empty(t, add)
+	assert.Empty(t, remove)
+}
+
+func TestStreamingDiff_shouldCompareActualIDsPageByPage(t *testing.T) {
+	diff := NewStreamingDiff([]string{"U1", "U2", "U3", "U3"})
+
+	assert.Equal(t, []string{"U4"}, diff.Page([]string{"U2", "U4"}))
+	assert.Equal(t, []string{"U5"}, diff.Page([]string{"U4", "U3", "U5"}))
+	assert.Empty(t, diff.Page(nil))
+
+	assert.Equal(t, []string{"U1"}, diff.Missing())
+	assert.Equal(t, 5, diff.Scanned())
+}
@@ -0,0 +1,181 @@
+package membership
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+var weekdays = map[slackv1alpha1.Weekday]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// window is a parsed weekly window of a member schedule, start and end are minutes after midnight
+type window struct {
+	days     map[time.Weekday]bool
+	start    int
+	end      int
+	location *time.Location
+}
+
+// ScheduledUsers returns the names of the member schedules which are active at now along with their users,
+// users of several active schedules are only returned once
+func ScheduledUsers(schedules []slackv1alpha1.MemberSchedule, now time.Time) ([]string, []string, error) {
+	var active, users []string
+	seen := map[string]bool{}
+
+	for _, schedule := range schedules {
+		windows, err := parseWindows(schedule)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !anyActive(windows, now) {
+			continue
+		}
+		active = append(active, schedule.Name)
+		for _, user := range schedule.Users {
+			if !seen[strings.ToLower(user)] {
+				seen[strings.ToLower(user)] = true
+				users = append(users, user)
+			}
+		}
+	}
+
+	return active, users, nil
+}
+
+// NextScheduleChange returns the earliest time after now a member schedule starts or ends, or nil if there are no
+// valid schedules
+func NextScheduleChange(schedules []slackv1alpha1.MemberSchedule, now time.Time) *time.Time {
+	var next *time.Time
+
+	for _, schedule := range schedules {
+		windows, err := parseWindows(schedule)
+		if err != nil {
+			continue
+		}
+		for _, w := range windows {
+			if change := w.next(now); change != nil && (next == nil || change.Before(*next)) {
+				next = change
+			}
+		}
+	}
+
+	return next
+}
+
+func parseWindows(schedule slackv1alpha1.MemberSchedule) ([]window, error) {
+	timeZone := schedule.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid time zone %s of member schedule %s: %v", timeZone, schedule.Name, err)
+	}
+
+	var windows []window
+	for _, spec := range schedule.Windows {
+		w := window{days: map[time.Weekday]bool{}, location: location}
+		for _, day := range spec.Days {
+			weekday, found := weekdays[day]
+			if !found {
+				return nil, fmt.Errorf("Invalid day %s in member schedule %s", day, schedule.Name)
+			}
+			w.days[weekday] = true
+		}
+		if len(w.days) == 0 {
+			for _, weekday := range weekdays {
+				w.days[weekday] = true
+			}
+		}
+
+		if w.start, err = parseClock(spec.Start); err != nil {
+			return nil, fmt.Errorf("Invalid start of member schedule %s: %v", schedule.Name, err)
+		}
+		if w.end, err = parseClock(spec.End); err != nil {
+			return nil, fmt.Errorf("Invalid end of member schedule %s: %v", schedule.Name, err)
+		}
+		if w.start == w.end {
+			return nil, fmt.Errorf("Window of member schedule %s starts when it ends", schedule.Name)
+		}
+
+		windows = append(windows, w)
+	}
+
+	return windows, nil
+}
+
+// parseClock parses HH:MM into minutes after midnight, 24:00 is the end of the day
+func parseClock(clock string) (int, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hours, &minutes); err != nil || len(clock) != 5 {
+		return 0, fmt.Errorf("%q is not HH:MM", clock)
+	}
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes > 0) {
+		return 0, fmt.Errorf("%q is not a time of day", clock)
+	}
+	return hours*60 + minutes, nil
+}
+
+func anyActive(windows []window, now time.Time) bool {
+	for _, w := range windows {
+		if w.active(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// active checks whether now is in the window, windows ending before they start end on the next day
+func (w window) active(now time.Time) bool {
+	local := now.In(w.location)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + 6) % 7
+
+	if w.start < w.end {
+		return w.days[today] && minute >= w.start && minute < w.end
+	}
+	return (w.days[today] && minute >= w.start) || (w.days[yesterday] && minute < w.end)
+}
+
+// next returns the earliest start or end of the window after now
+func (w window) next(now time.Time) *time.Time {
+	local := now.In(w.location)
+	var next *time.Time
+
+	for offset := -1; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, w.location)
+		if !w.days[day.Weekday()] {
+			continue
+		}
+
+		endDay := day
+		if w.end <= w.start {
+			endDay = day.AddDate(0, 0, 1)
+		}
+		for _, change := range []time.Time{at(day, w.start), at(endDay, w.end)} {
+			if change.After(now) && (next == nil || change.Before(*next)) {
+				change := change
+				next = &change
+			}
+		}
+	}
+
+	return next
+}
+
+// at returns the time minutes after midnight of the day, in the location of the day
+func at(day time.Time, minutes int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, day.Location())
+}
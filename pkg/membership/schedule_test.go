@@ -0,0 +1,92 @@
+package membership
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+var followTheSun = []slackv1alpha1.MemberSchedule{
+	{
+		Name:     "apac",
+		Users:    []string{"apac@example.com", "lead@example.com"},
+		TimeZone: "Asia/Singapore",
+		Windows:  []slackv1alpha1.ScheduleWindow{{Days: []slackv1alpha1.Weekday{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "08:00", End: "16:00"}},
+	},
+	{
+		Name:     "emea",
+		Users:    []string{"emea@example.com", "Lead@example.com"},
+		TimeZone: "Europe/London",
+		Windows:  []slackv1alpha1.ScheduleWindow{{Days: []slackv1alpha1.Weekday{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "08:00", End: "16:00"}},
+	},
+	{
+		Name:    "night",
+		Users:   []string{"night@example.com"},
+		Windows: []slackv1alpha1.ScheduleWindow{{Start: "22:00", End: "06:00"}},
+	},
+}
+
+func TestScheduledUsers_shouldReturnActiveSchedules(t *testing.T) {
+	// Monday 03:00 UTC is 11:00 in Singapore
+	active, users, err := ScheduledUsers(followTheSun, time.Date(2026, 10, 12, 3, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apac", "night"}, active)
+	assert.Equal(t, []string{"apac@example.com", "lead@example.com", "night@example.com"}, users)
+
+	// Monday 07:30 UTC is 15:30 in Singapore and 08:30 in London
+	active, users, err = ScheduledUsers(followTheSun, time.Date(2026, 10, 12, 7, 30, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apac", "emea"}, active)
+	assert.Equal(t, []string{"apac@example.com", "lead@example.com", "emea@example.com"}, users)
+
+	// Saturday noon
+	active, users, err = ScheduledUsers(followTheSun, time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Empty(t, active)
+	assert.Empty(t, users)
+}
+
+func TestScheduledUsers_shouldKeepWindowsPastMidnightActiveOnTheNextDay(t *testing.T) {
+	schedules := []slackv1alpha1.MemberSchedule{{
+		Name:    "weekend",
+		Users:   []string{"weekend@example.com"},
+		Windows: []slackv1alpha1.ScheduleWindow{{Days: []slackv1alpha1.Weekday{"Fri"}, Start: "18:00", End: "02:00"}},
+	}}
+
+	active, _, err := ScheduledUsers(schedules, time.Date(2026, 10, 17, 1, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"weekend"}, active)
+
+	active, _, err = ScheduledUsers(schedules, time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Empty(t, active)
+}
+
+func TestScheduledUsers_shouldThrowError_whenScheduleIsInvalid(t *testing.T) {
+	invalid := []slackv1alpha1.MemberSchedule{
+		{Name: "zone", TimeZone: "Mars/Olympus", Windows: []slackv1alpha1.ScheduleWindow{{Start: "08:00", End: "16:00"}}},
+		{Name: "day", Windows: []slackv1alpha1.ScheduleWindow{{Days: []slackv1alpha1.Weekday{"Someday"}, Start: "08:00", End: "16:00"}}},
+		{Name: "clock", Windows: []slackv1alpha1.ScheduleWindow{{Start: "8am", End: "16:00"}}},
+		{Name: "empty", Windows: []slackv1alpha1.ScheduleWindow{{Start: "08:00", End: "08:00"}}},
+	}
+
+	for _, schedule := range invalid {
+		_, _, err := ScheduledUsers([]slackv1alpha1.MemberSchedule{schedule}, time.Now())
+		assert.Error(t, err, schedule.Name)
+	}
+}
+
+func TestNextScheduleChange_shouldReturnEarliestStartOrEnd(t *testing.T) {
+	// Monday 07:30 UTC, apac ends at 08:00 UTC and emea started at 07:00 UTC
+	next := NextScheduleChange(followTheSun, time.Date(2026, 10, 12, 7, 30, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC), next.UTC())
+
+	// Saturday noon, the night window starts at 22:00
+	next = NextScheduleChange(followTheSun, time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 10, 17, 22, 0, 0, 0, time.UTC), next.UTC())
+
+	assert.Nil(t, NextScheduleChange(nil, time.Now()))
+}
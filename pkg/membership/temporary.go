@@ -0,0 +1,59 @@
+package membership
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+// SyncTemporaryUsers resolves the expiration of the temporary users in the spec. Expirations of users with a ttl
+// are kept from the current status, so the ttl counts from the first time the user was seen. Users removed from
+// the spec are dropped.
+func SyncTemporaryUsers(users []slackv1alpha1.TemporaryUser, current []slackv1alpha1.TemporaryUserStatus, now time.Time) ([]slackv1alpha1.TemporaryUserStatus, error) {
+	var statuses []slackv1alpha1.TemporaryUserStatus
+
+	for _, user := range users {
+		if (user.ExpiresAt == nil) == (user.TTL == nil) {
+			return nil, fmt.Errorf("Exactly one of expiresAt and ttl must be set for temporary user %s", user.Email)
+		}
+
+		status := slackv1alpha1.TemporaryUserStatus{Email: user.Email}
+		if user.ExpiresAt != nil {
+			status.ExpiresAt = *user.ExpiresAt
+		} else if existing := find(current, user.Email); existing != nil {
+			status.ExpiresAt = existing.ExpiresAt
+		} else {
+			status.ExpiresAt = metav1.NewTime(now.Add(user.TTL.Duration))
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// NextExpiration returns the earliest expiration after now, or nil if no temporary user expires in the future
+func NextExpiration(statuses []slackv1alpha1.TemporaryUserStatus, now time.Time) *time.Time {
+	var next *time.Time
+
+	for i := range statuses {
+		expiresAt := statuses[i].ExpiresAt.Time
+		if expiresAt.After(now) && (next == nil || expiresAt.Before(*next)) {
+			next = &expiresAt
+		}
+	}
+
+	return next
+}
+
+func find(statuses []slackv1alpha1.TemporaryUserStatus, email string) *slackv1alpha1.TemporaryUserStatus {
+	for i := range statuses {
+		if statuses[i].Email == email {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package membership
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+var now = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestSyncTemporaryUsers_shouldCountTTLFromFirstSeen(t *testing.T) {
+	users := []slackv1alpha1.TemporaryUser{{Email: "jane@example.com", TTL: &metav1.Duration{Duration: time.Hour}}}
+
+	statuses, err := SyncTemporaryUsers(users, nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), statuses[0].ExpiresAt.Time)
+
+	statuses, err = SyncTemporaryUsers(users, statuses, now.Add(30*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), statuses[0].ExpiresAt.Time)
+}
+
+func TestSyncTemporaryUsers_shouldUseExpiresAt(t *testing.T) {
+	expiresAt := metav1.NewTime(now.Add(24 * time.Hour))
+	current := []slackv1alpha1.TemporaryUserStatus{{Email: "jane@example.com", ExpiresAt: metav1.NewTime(now)}}
+
+	statuses, err := SyncTemporaryUsers([]slackv1alpha1.TemporaryUser{{Email: "jane@example.com", ExpiresAt: &expiresAt}}, current, now)
+	assert.NoError(t, err)
+	assert.Equal(t, expiresAt, statuses[0].ExpiresAt)
+}
+
+func TestSyncTemporaryUsers_shouldDropUsersRemovedFromSpec(t *testing.T) {
+	current := []slackv1alpha1.TemporaryUserStatus{{Email: "jane@example.com", ExpiresAt: metav1.NewTime(now)}}
+
+	statuses, err := SyncTemporaryUsers(nil, current, now)
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+}
+
+func TestSyncTemporaryUsers_shouldThrowError_whenExpirationIsAmbiguous(t *testing.T) {
+	_, err := SyncTemporaryUsers([]slackv1alpha1.TemporaryUser{{Email: "jane@example.com"}}, nil, now)
+	assert.Error(t, err)
+}
+
+func TestNextExpiration_shouldIgnorePastExpirations(t *testing.T) {
+	statuses := []slackv1alpha1.TemporaryUserStatus{
+		{Email: "a@example.com", ExpiresAt: metav1.NewTime(now.Add(-time.Hour))},
+		{Email: "b@example.com", ExpiresAt: metav1.NewTime(now.Add(2 * time.Hour))},
+		{Email: "c@example.com", ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+	}
+
+	assert.Equal(t, now.Add(time.Hour), *NextExpiration(statuses, now))
+	assert.Nil(t, NextExpiration(statuses[:1], now))
+}
@@ -0,0 +1,155 @@
+package oncall
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// PagerDuty schedules are read with the oncalls endpoint of the PagerDuty REST API
+	PagerDuty = "PagerDuty"
+
+	// Opsgenie schedules are read with the on-calls endpoint of the Opsgenie schedule API
+	Opsgenie = "Opsgenie"
+
+	// PagerDutyURL is the base URL of the PagerDuty REST API
+	PagerDutyURL = "https://api.pagerduty.com/"
+
+	// OpsgenieURL is the base URL of the Opsgenie API, accounts in the EU use https://api.eu.opsgenie.com/
+	OpsgenieURL = "https://api.opsgenie.com/"
+)
+
+// Client looks up who is currently on call for PagerDuty and Opsgenie schedules
+type Client struct {
+	PagerDutyURL string
+	OpsgenieURL  string
+
+	httpClient *http.Client
+}
+
+// New creates a client for the public PagerDuty and Opsgenie APIs
+func New() *Client {
+	return &Client{
+		PagerDutyURL: PagerDutyURL,
+		OpsgenieURL:  OpsgenieURL,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type pagerDutyOnCalls struct {
+	OnCalls []struct {
+		EscalationLevel int `json:"escalation_level"`
+		User            struct {
+			Email string `json:"email"`
+		} `json:"user"`
+	} `json:"oncalls"`
+}
+
+type opsgenieOnCalls struct {
+	Data struct {
+		OnCallRecipients []string `json:"onCallRecipients"`
+	} `json:"data"`
+}
+
+// OnCall returns the emails of who is currently on call for the schedule of the provider, authenticated with the
+// API token. For PagerDuty only the first escalation level of the schedule is returned.
+func (c *Client) OnCall(provider string, scheduleID string, token string) ([]string, error) {
+	switch provider {
+	case PagerDuty:
+		return c.pagerDutyOnCall(scheduleID, token)
+	case Opsgenie:
+		return c.opsgenieOnCall(scheduleID, token)
+	default:
+		return nil, fmt.Errorf("Unknown on-call provider %s", provider)
+	}
+}
+
+func (c *Client) pagerDutyOnCall(scheduleID string, token string) ([]string, error) {
+	query := url.Values{
+		"schedule_ids[]": {scheduleID},
+		"include[]":      {"users"},
+		"earliest":       {"true"},
+	}
+
+	response := &pagerDutyOnCalls{}
+	err := c.get(c.PagerDutyURL+"oncalls?"+query.Encode(), map[string]string{
+		"Authorization": "Token token=" + token,
+		"Accept":        "application/vnd.pagerduty+json;version=2",
+	}, response)
+	if err != nil {
+		return nil, err
+	}
+
+	level := 0
+	for _, onCall := range response.OnCalls {
+		if level == 0 || onCall.EscalationLevel < level {
+			level = onCall.EscalationLevel
+		}
+	}
+
+	var emails []string
+	for _, onCall := range response.OnCalls {
+		if onCall.EscalationLevel == level && onCall.User.Email != "" {
+			emails = appendUnique(emails, onCall.User.Email)
+		}
+	}
+	return emails, nil
+}
+
+func (c *Client) opsgenieOnCall(scheduleID string, token string) ([]string, error) {
+	query := url.Values{
+		"scheduleIdentifierType": {"id"},
+		"flat":                   {"true"},
+	}
+
+	response := &opsgenieOnCalls{}
+	err := c.get(c.OpsgenieURL+"v2/schedules/"+url.PathEscape(scheduleID)+"/on-calls?"+query.Encode(), map[string]string{
+		"Authorization": "GenieKey " + token,
+	}, response)
+	if err != nil {
+		return nil, err
+	}
+
+	var emails []string
+	for _, recipient := range response.Data.OnCallRecipients {
+		emails = appendUnique(emails, recipient)
+	}
+	return emails, nil
+}
+
+// get sends a GET request with the headers and decodes the JSON response into result
+func (c *Client) get(requestURL string, headers map[string]string, result interface{}) error {
+	request, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("On-call request failed with status %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(response.Body).Decode(result)
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if strings.EqualFold(existing, value) {
+			return values
+		}
+	}
+	return append(values, value)
+}
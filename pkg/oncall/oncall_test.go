@@ -0,0 +1,66 @@
+package oncall
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(handler http.HandlerFunc) (*Client, func()) {
+	server := httptest.NewServer(handler)
+	client := New()
+	client.PagerDutyURL = server.URL + "/"
+	client.OpsgenieURL = server.URL + "/"
+	return client, server.Close
+}
+
+func TestOnCall_shouldReturnFirstEscalationLevel_whenProviderIsPagerDuty(t *testing.T) {
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/oncalls", r.URL.Path)
+		assert.Equal(t, "PABC123", r.URL.Query().Get("schedule_ids[]"))
+		assert.Equal(t, "Token token=secret", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"oncalls": [
+			{"escalation_level": 2, "user": {"email": "manager@example.com"}},
+			{"escalation_level": 1, "user": {"email": "alice@example.com"}},
+			{"escalation_level": 1, "user": {"email": "bob@example.com"}},
+			{"escalation_level": 1, "user": {"email": "Alice@example.com"}}
+		]}`))
+	})
+	defer closeServer()
+
+	emails, err := client.OnCall(PagerDuty, "PABC123", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, emails)
+}
+
+func TestOnCall_shouldReturnRecipients_whenProviderIsOpsgenie(t *testing.T) {
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/schedules/abc-123/on-calls", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("flat"))
+		assert.Equal(t, "GenieKey secret", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"data": {"onCallRecipients": ["carol@example.com"]}}`))
+	})
+	defer closeServer()
+
+	emails, err := client.OnCall(Opsgenie, "abc-123", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"carol@example.com"}, emails)
+}
+
+func TestOnCall_shouldThrowError_whenRequestFails(t *testing.T) {
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	})
+	defer closeServer()
+
+	_, err := client.OnCall(PagerDuty, "PABC123", "wrong")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
+
+func TestOnCall_shouldThrowError_whenProviderIsUnknown(t *testing.T) {
+	_, err := New().OnCall("VictorOps", "schedule", "secret")
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+const (
+	// Fail rejects Channels when the policy can't be evaluated
+	Fail = "Fail"
+
+	// Ignore admits Channels when the policy can't be evaluated
+	Ignore = "Ignore"
+)
+
+// Client evaluates Channels against a policy of an Open Policy Agent with its Data API. The policy document, e.g.
+// http://opa:8181/v1/data/slack/channel/deny, must be a set of messages, every message rejects the Channel.
+type Client struct {
+	policyURL     string
+	failurePolicy string
+	httpClient    *http.Client
+	log           logr.Logger
+}
+
+// New creates a client for the policy document at policyURL, failurePolicy is Fail or Ignore
+func New(policyURL string, timeout time.Duration, failurePolicy string, logger logr.Logger) (*Client, error) {
+	if failurePolicy != Fail && failurePolicy != Ignore {
+		return nil, fmt.Errorf("invalid failure policy %q, expected %s or %s", failurePolicy, Fail, Ignore)
+	}
+	return &Client{
+		policyURL:     policyURL,
+		failurePolicy: failurePolicy,
+		httpClient:    &http.Client{Timeout: timeout},
+		log:           logger,
+	}, nil
+}
+
+// request is the body of a Data API query
+type request struct {
+	Input input `json:"input"`
+}
+
+// input is the document the policy is evaluated against
+type input struct {
+	// Operation is CREATE or UPDATE
+	Operation string `json:"operation"`
+	// Object is the Channel
+	Object *slackv1alpha1.Channel `json:"object"`
+	// OldObject is the Channel before the update
+	OldObject *slackv1alpha1.Channel `json:"oldObject,omitempty"`
+	// Namespace holds the labels of the namespace of the Channel
+	Namespace namespace `json:"namespace"`
+}
+
+type namespace struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// response is the result of a Data API query, the result is missing when the document is undefined
+type response struct {
+	Result []string `json:"result"`
+}
+
+// Violations returns the messages of the policy rejecting the channel, old is nil for new channels. Errors
+// evaluating the policy are returned with the Fail failure policy and admit the channel with Ignore.
+func (c *Client) Violations(ctx context.Context, channel *slackv1alpha1.Channel, old *slackv1alpha1.Channel, namespaceLabels map[string]string) ([]string, error) {
+	query := request{Input: input{
+		Operation: "CREATE",
+		Object:    channel,
+		OldObject: old,
+		Namespace: namespace{Name: channel.Namespace, Labels: namespaceLabels},
+	}}
+	if old != nil {
+		query.Input.Operation = "UPDATE"
+	}
+
+	violations, err := c.query(ctx, query)
+	if err != nil && c.failurePolicy == Ignore {
+		c.log.Error(err, "Error evaluating the admission policy, admitting the Channel", "channel", channel.Namespace+"/"+channel.Name)
+		return nil, nil
+	}
+	return violations, err
+}
+
+func (c *Client) query(ctx context.Context, query request) ([]string, error) {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, c.policyURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying the admission policy: %v", err)
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(httpResponse.Body, 1024))
+		return nil, fmt.Errorf("Admission policy query failed with status %d: %s", httpResponse.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	result := &response{}
+	if err := json.NewDecoder(httpResponse.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("Error decoding the admission policy result, expected a set of messages: %v", err)
+	}
+	return result.Result, nil
+}
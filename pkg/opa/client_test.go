@@ -0,0 +1,71 @@
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+var channel = &slackv1alpha1.Channel{
+	ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "team-a"},
+	Spec:       slackv1alpha1.ChannelSpec{Name: "alerts", Private: true},
+}
+
+func TestClient_Violations_shouldReturnMessagesOfThePolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/data/slack/channel/deny", r.URL.Path)
+		query := &request{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(query))
+		assert.Equal(t, "CREATE", query.Input.Operation)
+		assert.Equal(t, "alerts", query.Input.Object.Spec.Name)
+		assert.Equal(t, map[string]string{"tier": "gold"}, query.Input.Namespace.Labels)
+		_, _ = w.Write([]byte(`{"result": ["private channels require a cost-center label"]}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL+"/v1/data/slack/channel/deny", time.Second, Fail, zap.New())
+	assert.NoError(t, err)
+	violations, err := client.Violations(context.Background(), channel, nil, map[string]string{"tier": "gold"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"private channels require a cost-center label"}, violations)
+}
+
+func TestClient_Violations_shouldAdmitChannels_whenDocumentIsUndefined(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL, time.Second, Fail, zap.New())
+	violations, err := client.Violations(context.Background(), channel, channel, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestClient_Violations_shouldApplyFailurePolicy_whenPolicyCanNotBeQueried(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "policy compile error", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, _ := New(server.URL, time.Second, Fail, zap.New())
+	_, err := client.Violations(context.Background(), channel, nil, nil)
+	assert.EqualError(t, err, "Admission policy query failed with status 500: policy compile error")
+
+	client, _ = New(server.URL, time.Second, Ignore, zap.New())
+	violations, err := client.Violations(context.Background(), channel, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	_, err = New(server.URL, time.Second, "Retry", zap.New())
+	assert.Error(t, err)
+}
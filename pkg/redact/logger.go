@@ -0,0 +1,185 @@
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// Mode is how emails are redacted from logs, tokens are always redacted
+type Mode string
+
+const (
+	// Off logs emails as they are
+	Off Mode = "off"
+	// Mask keeps the first letter and the domain of emails, e.g. j***@example.com
+	Mask Mode = "mask"
+	// Hash replaces the local part of emails with a hash, so log lines of the same user can still be correlated
+	Hash Mode = "hash"
+
+	// Redacted replaces tokens and values of sensitive keys
+	Redacted = "[REDACTED]"
+)
+
+var (
+	email = regexp.MustCompile(`([A-Za-z0-9._%+\-]+)@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+	// token matches Slack tokens, including rotating ones, e.g. xoxb-..., xoxe.xoxp-... or xoxe-1-...
+	token = regexp.MustCompile(`(xoxe\.)?xox[a-z]-[A-Za-z0-9\-]+`)
+
+	// sensitiveKeys are parts of log keys whose values are never logged
+	sensitiveKeys = []string{"token", "secret", "password"}
+)
+
+// ParseMode parses the value of the redaction flag
+func ParseMode(mode string) (Mode, error) {
+	switch Mode(mode) {
+	case Off, Mask, Hash:
+		return Mode(mode), nil
+	}
+	return "", fmt.Errorf("invalid redaction mode %q, expected off, mask or hash", mode)
+}
+
+// logger redacts the messages, values and errors it passes to its base logger
+type logger struct {
+	base logr.Logger
+	mode Mode
+}
+
+// NewLogger wraps base in a logger which redacts tokens and, depending on mode, emails. Values which aren't
+// strings or numbers are logged as JSON to redact their fields.
+func NewLogger(base logr.Logger, mode Mode) logr.Logger {
+	return &logger{base: base, mode: mode}
+}
+
+func (l *logger) Enabled() bool {
+	return l.base.Enabled()
+}
+
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	l.base.Info(l.String(msg), l.values(keysAndValues)...)
+}
+
+func (l *logger) Error(err error, msg string, keysAndValues ...interface{}) {
+	if err != nil {
+		err = &redactedError{err: err, message: l.String(err.Error())}
+	}
+	l.base.Error(err, l.String(msg), l.values(keysAndValues)...)
+}
+
+func (l *logger) V(level int) logr.Logger {
+	return &logger{base: l.base.V(level), mode: l.mode}
+}
+
+func (l *logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &logger{base: l.base.WithValues(l.values(keysAndValues)...), mode: l.mode}
+}
+
+func (l *logger) WithName(name string) logr.Logger {
+	return &logger{base: l.base.WithName(name), mode: l.mode}
+}
+
+// String redacts tokens and emails from s
+func (l *logger) String(s string) string {
+	return String(s, l.mode)
+}
+
+// String redacts tokens and, depending on mode, emails from s
+func String(s string, mode Mode) string {
+	s = token.ReplaceAllString(s, Redacted)
+	switch mode {
+	case Mask:
+		s = email.ReplaceAllStringFunc(s, func(address string) string {
+			parts := email.FindStringSubmatch(address)
+			return parts[1][:1] + "***@" + parts[2]
+		})
+	case Hash:
+		s = email.ReplaceAllStringFunc(s, func(address string) string {
+			parts := email.FindStringSubmatch(address)
+			sum := sha256.Sum256([]byte(strings.ToLower(parts[1])))
+			return hex.EncodeToString(sum[:6]) + "@" + parts[2]
+		})
+	}
+	return s
+}
+
+func (l *logger) values(keysAndValues []interface{}) []interface{} {
+	redacted := make([]interface{}, len(keysAndValues))
+	for i := 0; i < len(keysAndValues); i += 2 {
+		redacted[i] = keysAndValues[i]
+		if i+1 == len(keysAndValues) {
+			break
+		}
+
+		if key, ok := keysAndValues[i].(string); ok && isSensitive(key) {
+			redacted[i+1] = Redacted
+		} else {
+			redacted[i+1] = l.value(keysAndValues[i+1])
+		}
+	}
+	return redacted
+}
+
+func (l *logger) value(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return l.String(v)
+	case []string:
+		strs := make([]string, len(v))
+		for i, s := range v {
+			strs[i] = l.String(s)
+		}
+		return strs
+	case error:
+		return l.String(v.Error())
+	case fmt.Stringer:
+		return l.String(v.String())
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return value
+	case reflect.String:
+		return l.String(reflect.ValueOf(value).String())
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return Redacted
+	}
+	return l.String(string(data))
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedError logs the redacted message of an error, errors.Is and errors.As still see the error
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,63 @@
+package resync
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Spread returns the period shifted by an offset derived from the key, plus a little random jitter, so objects
+// requeued with the same period don't all resync at the same moment. The result is between 90% and 112% of period.
+func Spread(key string, period time.Duration) time.Duration {
+	window := int64(period / 5)
+	if window <= 0 {
+		return period
+	}
+
+	jitter := rand.Int63n(window/10 + 1)
+
+	return period - period/10 + time.Duration(offset(key, window)+jitter)
+}
+
+// offset maps the key to a stable offset within the window
+func offset(key string, window int64) int64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum32()) % window
+}
+
+// Gate limits the number of background drift scans running at once, so that workers remain free for reconciles of
+// spec changes
+type Gate struct {
+	slots chan struct{}
+}
+
+// NewGate creates a gate which lets size scans in at once, at least one
+func NewGate(size int) *Gate {
+	if size < 1 {
+		size = 1
+	}
+	return &Gate{slots: make(chan struct{}, size)}
+}
+
+// TryEnter takes a slot if one is free, Leave must be called once the scan is done. A nil gate lets every scan in.
+func (g *Gate) TryEnter() bool {
+	if g == nil {
+		return true
+	}
+
+	select {
+	case g.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Leave frees the slot taken by TryEnter
+func (g *Gate) Leave() {
+	if g == nil {
+		return
+	}
+	<-g.slots
+}
@@ -0,0 +1,37 @@
+package resync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpread_shouldStayNearThePeriod(t *testing.T) {
+	for _, key := range []string{"default/dev", "default/ops", "team-a/alerts"} {
+		spread := Spread(key, 10*time.Minute)
+		assert.GreaterOrEqual(t, int64(spread), int64(9*time.Minute))
+		assert.LessOrEqual(t, int64(spread), int64(11*time.Minute+12*time.Second))
+	}
+}
+
+func TestSpread_shouldSpreadKeys(t *testing.T) {
+	window := int64(12 * time.Minute)
+
+	assert.Equal(t, offset("default/dev", window), offset("default/dev", window))
+	assert.NotEqual(t, offset("default/dev", window), offset("default/ops", window))
+}
+
+func TestGate_shouldLimitConcurrentScans(t *testing.T) {
+	gate := NewGate(1)
+
+	assert.True(t, gate.TryEnter())
+	assert.False(t, gate.TryEnter())
+
+	gate.Leave()
+	assert.True(t, gate.TryEnter())
+
+	var unlimited *Gate
+	assert.True(t, unlimited.TryEnter())
+	unlimited.Leave()
+}
@@ -0,0 +1,309 @@
+package scim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// APIURL is the base URL of the slack SCIM API, which is available on Business+ and Enterprise Grid plans
+	APIURL = "https://api.slack.com/scim/v1/"
+
+	coreSchema = "urn:scim:schemas:core:1.0"
+)
+
+// Client provisions workspace users and groups with the SCIM API
+type Client struct {
+	log        logr.Logger
+	token      string
+	apiURL     string
+	httpClient *http.Client
+}
+
+// User is a SCIM user
+type User struct {
+	ID          string  `json:"id,omitempty"`
+	UserName    string  `json:"userName"`
+	DisplayName string  `json:"displayName,omitempty"`
+	Emails      []Email `json:"emails,omitempty"`
+	Active      bool    `json:"active"`
+}
+
+// Email is an email of a SCIM user
+type Email struct {
+	Value   string `json:"value"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Group is a SCIM group
+type Group struct {
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Member `json:"members,omitempty"`
+}
+
+// Member is a user in a SCIM group, Operation is only set to remove members when patching a group
+type Member struct {
+	Value     string `json:"value"`
+	Display   string `json:"display,omitempty"`
+	Operation string `json:"operation,omitempty"`
+}
+
+// Error is returned for failed SCIM requests
+type Error struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("SCIM request failed with status %d: %s", e.Code, e.Description)
+}
+
+// IsNotFound checks whether the error is a SCIM error for a missing user or group
+func IsNotFound(err error) bool {
+	scimErr, ok := err.(*Error)
+	return ok && scimErr.Code == http.StatusNotFound
+}
+
+type listResponse struct {
+	TotalResults int               `json:"totalResults"`
+	Resources    []json.RawMessage `json:"Resources"`
+}
+
+// New creates a new SCIM client authenticated with an admin user token
+func New(token string, logger logr.Logger) *Client {
+	return NewWithURL(token, APIURL, http.DefaultClient, logger)
+}
+
+// NewWithURL creates a new SCIM client for the SCIM API at apiURL
+func NewWithURL(token string, apiURL string, httpClient *http.Client, logger logr.Logger) *Client {
+	if !strings.HasSuffix(apiURL, "/") {
+		apiURL += "/"
+	}
+	return &Client{
+		log:        logger,
+		token:      token,
+		apiURL:     apiURL,
+		httpClient: httpClient,
+	}
+}
+
+// GetUserByEmail returns the user with the email, or nil if there is no such user
+func (c *Client) GetUserByEmail(email string) (*User, error) {
+	users := []User{}
+	if err := c.list("Users", fmt.Sprintf("email eq %q", email), &users); err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
+
+// GetUser returns the user with the ID
+func (c *Client) GetUser(id string) (*User, error) {
+	user := &User{}
+	if err := c.do(http.MethodGet, "Users/"+id, nil, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateUser provisions a workspace user with the email as user name and primary email
+func (c *Client) CreateUser(email string, displayName string) (*User, error) {
+	c.log.V(1).Info("Provisioning user", "email", email)
+
+	request := map[string]interface{}{
+		"schemas":     []string{coreSchema},
+		"userName":    strings.Split(email, "@")[0],
+		"displayName": displayName,
+		"emails":      []Email{{Value: email, Primary: true}},
+		"active":      true,
+	}
+
+	user := &User{}
+	if err := c.do(http.MethodPost, "Users", request, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// DeactivateUser deprovisions the user, users which are already gone are ignored
+func (c *Client) DeactivateUser(id string) error {
+	c.log.V(1).Info("Deactivating user", "userID", id)
+
+	err := c.do(http.MethodDelete, "Users/"+id, nil, nil)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// GetGroupByName returns the group with the display name, or nil if there is no such group
+func (c *Client) GetGroupByName(displayName string) (*Group, error) {
+	groups := []Group{}
+	if err := c.list("Groups", fmt.Sprintf("displayName eq %q", displayName), &groups); err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	return &groups[0], nil
+}
+
+// GetGroup returns the group with the ID
+func (c *Client) GetGroup(id string) (*Group, error) {
+	group := &Group{}
+	if err := c.do(http.MethodGet, "Groups/"+id, nil, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// CreateGroup provisions a group with the user IDs as members
+func (c *Client) CreateGroup(displayName string, userIDs []string) (*Group, error) {
+	c.log.V(1).Info("Provisioning group", "displayName", displayName)
+
+	request := map[string]interface{}{
+		"schemas":     []string{coreSchema},
+		"displayName": displayName,
+		"members":     members(userIDs, ""),
+	}
+
+	group := &Group{}
+	if err := c.do(http.MethodPost, "Groups", request, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// UpdateGroupMembers adds and removes members of the group
+func (c *Client) UpdateGroupMembers(id string, add []string, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	c.log.V(1).Info("Updating group members", "groupID", id, "add", len(add), "remove", len(remove))
+
+	request := map[string]interface{}{
+		"schemas": []string{coreSchema},
+		"members": append(members(add, ""), members(remove, "delete")...),
+	}
+
+	return c.do(http.MethodPatch, "Groups/"+id, request, nil)
+}
+
+// DeleteGroup deprovisions the group, groups which are already gone are ignored
+func (c *Client) DeleteGroup(id string) error {
+	c.log.V(1).Info("Deleting group", "groupID", id)
+
+	err := c.do(http.MethodDelete, "Groups/"+id, nil, nil)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// GetGroupMemberEmails returns the primary emails of the active members of the group
+func (c *Client) GetGroupMemberEmails(displayName string) ([]string, error) {
+	group, err := c.GetGroupByName(displayName)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("SCIM group %s not found", displayName)
+	}
+
+	var emails []string
+	for _, member := range group.Members {
+		user, err := c.GetUser(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		if email := user.PrimaryEmail(); user.Active && email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails, nil
+}
+
+// PrimaryEmail returns the primary email of the user, or the first one if none is marked primary
+func (u *User) PrimaryEmail() string {
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+func members(userIDs []string, operation string) []Member {
+	result := []Member{}
+	for _, id := range userIDs {
+		result = append(result, Member{Value: id, Operation: operation})
+	}
+	return result
+}
+
+// list fetches all resources matching the filter into result, which must be a pointer to a slice
+func (c *Client) list(resource string, filter string, result interface{}) error {
+	response := &listResponse{}
+	if err := c.do(http.MethodGet, resource+"?filter="+url.QueryEscape(filter), nil, response); err != nil {
+		return err
+	}
+
+	resources, err := json.Marshal(response.Resources)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resources, result)
+}
+
+// do sends a SCIM request and decodes the response into result unless it is nil
+func (c *Client) do(method string, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequest(method, c.apiURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		scimErr := struct {
+			Errors Error `json:"Errors"`
+		}{}
+		_ = json.NewDecoder(resp.Body).Decode(&scimErr)
+		scimErr.Errors.Code = resp.StatusCode
+		return &scimErr.Errors
+	}
+
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
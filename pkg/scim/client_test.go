@@ -0,0 +1,90 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var log = zap.New()
+
+func newTestClient(t *testing.T) (*Client, *[]map[string]interface{}) {
+	var patches []map[string]interface{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Users", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(`{"id": "U3", "userName": "new", "active": true}`))
+		case r.URL.Query().Get("filter") == `email eq "jane@example.com"`:
+			_, _ = w.Write([]byte(`{"totalResults": 1, "Resources": [{"id": "U1", "userName": "jane", "active": true, "emails": [{"value": "jane@example.com", "primary": true}]}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"totalResults": 0, "Resources": []}`))
+		}
+	})
+	mux.HandleFunc("/Users/U1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "U1", "userName": "jane", "active": true, "emails": [{"value": "jane@example.com", "primary": true}]}`))
+	})
+	mux.HandleFunc("/Users/U2", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "U2", "userName": "john", "active": false, "emails": [{"value": "john@example.com"}]}`))
+	})
+	mux.HandleFunc("/Users/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"Errors": {"description": "User not found", "code": 404}}`))
+	})
+	mux.HandleFunc("/Groups", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"totalResults": 1, "Resources": [{"id": "G1", "displayName": "platform", "members": [{"value": "U1"}, {"value": "U2"}]}]}`))
+	})
+	mux.HandleFunc("/Groups/G1", func(w http.ResponseWriter, r *http.Request) {
+		patch := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&patch)
+		patches = append(patches, patch)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return NewWithURL("xoxp-admin", server.URL, server.Client(), log), &patches
+}
+
+func TestClient_GetUserByEmail_shouldReturnNil_whenUserDoesNotExist(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	user, err := c.GetUserByEmail("jane@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "U1", user.ID)
+
+	user, err = c.GetUserByEmail("nobody@example.com")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+}
+
+func TestClient_GetGroupMemberEmails_shouldSkipInactiveUsers(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	emails, err := c.GetGroupMemberEmails("platform")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jane@example.com"}, emails)
+}
+
+func TestClient_DeactivateUser_shouldIgnoreMissingUsers(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	assert.NoError(t, c.DeactivateUser("missing"))
+	assert.True(t, IsNotFound(c.do(http.MethodGet, "Users/missing", nil, nil)))
+}
+
+func TestClient_UpdateGroupMembers_shouldMarkRemovedMembers(t *testing.T) {
+	c, patches := newTestClient(t)
+
+	assert.NoError(t, c.UpdateGroupMembers("G1", []string{"U3"}, []string{"U2"}))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"value": "U3"},
+		map[string]interface{}{"value": "U2", "operation": "delete"},
+	}, (*patches)[0]["members"])
+}
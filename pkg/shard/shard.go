@@ -0,0 +1,116 @@
+package shard
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Shard is the part of the Channels an operator deployment reconciles, Channels are assigned to shards by the hash of
+// their label named Label, or of their namespace/name when Label is empty. Channels not matching the Selector belong
+// to no shard of the deployment, they are left to another operator install. A nil Shard owns every Channel.
+type Shard struct {
+	// Index of the shard, from 0 to Total-1
+	Index int
+
+	// Total number of shards
+	Total int
+
+	// Label whose value is the sharding key, e.g. the workspace of the Channel
+	Label string
+
+	// Selector of the Channels the deployment reconciles, nil for all of them
+	Selector labels.Selector
+}
+
+// New creates the shard of index out of total shards reconciling the Channels matching the selector, nil when there
+// is only one shard and every Channel is selected
+func New(index int, total int, label string, selector labels.Selector) (*Shard, error) {
+	if total < 1 || index < 0 || index >= total {
+		return nil, fmt.Errorf("Invalid shard %d of %d shards, the shard must be between 0 and the total shards - 1", index, total)
+	}
+	if selector != nil && selector.Empty() {
+		selector = nil
+	}
+	if total == 1 && selector == nil {
+		return nil, nil
+	}
+	return &Shard{Index: index, Total: total, Label: label, Selector: selector}, nil
+}
+
+// Owns checks whether the object belongs to the shard
+func (s *Shard) Owns(obj client.Object) bool {
+	if s == nil {
+		return true
+	}
+	if s.Selector != nil && !s.Selector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	if s.Total == 1 {
+		return true
+	}
+
+	key := obj.GetNamespace() + "/" + obj.GetName()
+	if s.Label != "" {
+		key = obj.GetLabels()[s.Label]
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+	return int(hash.Sum32()%uint32(s.Total)) == s.Index
+}
+
+// Primary checks whether the shard runs the controllers of the resources which aren't sharded, the first shard does
+func (s *Shard) Primary() bool {
+	return s == nil || s.Index == 0
+}
+
+// Predicate filters the events of objects which don't belong to the shard. Objects which move to another shard
+// because their label changed are updated in both shards, the new one picks them up.
+func (s *Shard) Predicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return s.Owns(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return s.Owns(e.ObjectNew) || s.Owns(e.ObjectOld)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return s.Owns(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return s.Owns(e.Object)
+		},
+	}
+}
+
+// LeaderElectionID returns the leader election ID of the shard, every shard elects its own leader
+func (s *Shard) LeaderElectionID(id string) string {
+	if s == nil || s.Total == 1 {
+		return id
+	}
+	return fmt.Sprintf("%s-shard-%d", id, s.Index)
+}
+
+// secondary is a manager of a shard other than the primary one, it drops the runnables added to it
+type secondary struct {
+	manager.Manager
+}
+
+// PrimaryOnly wraps the manager so that controllers and runnables added to it only run in the primary shard, the
+// resources which aren't sharded are reconciled by a single deployment
+func (s *Shard) PrimaryOnly(mgr manager.Manager) manager.Manager {
+	if s.Primary() {
+		return mgr
+	}
+	return &secondary{Manager: mgr}
+}
+
+// Add drops the runnable, the primary shard runs it
+func (m *secondary) Add(manager.Runnable) error {
+	return nil
+}
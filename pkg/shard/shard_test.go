@@ -0,0 +1,100 @@
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+func channel(name string, labels map[string]string) *slackv1alpha1.Channel {
+	return &slackv1alpha1.Channel{ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: name, Labels: labels}}
+}
+
+func TestNew_shouldValidateIndex(t *testing.T) {
+	_, err := New(2, 2, "", nil)
+	assert.Error(t, err)
+	_, err = New(-1, 2, "", nil)
+	assert.Error(t, err)
+	_, err = New(0, 0, "", nil)
+	assert.Error(t, err)
+
+	s, err := New(0, 1, "", labels.Everything())
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+	assert.True(t, s.Owns(channel("a", nil)))
+	assert.True(t, s.Primary())
+	assert.Equal(t, "id", s.LeaderElectionID("id"))
+}
+
+func TestShard_shouldAssignEveryChannelToExactlyOneShard(t *testing.T) {
+	shards := make([]*Shard, 3)
+	for i := range shards {
+		shards[i], _ = New(i, 3, "", nil)
+	}
+
+	counts := make([]int, 3)
+	for i := 0; i < 300; i++ {
+		owners := 0
+		for index, s := range shards {
+			if s.Owns(channel(fmt.Sprintf("channel-%d", i), nil)) {
+				owners++
+				counts[index]++
+			}
+		}
+		assert.Equal(t, 1, owners)
+	}
+	for _, count := range counts {
+		assert.Greater(t, count, 50)
+	}
+	assert.True(t, shards[0].Primary())
+	assert.False(t, shards[1].Primary())
+	assert.Equal(t, "id-shard-1", shards[1].LeaderElectionID("id"))
+}
+
+func TestShard_shouldKeepChannelsOfALabelValueTogether(t *testing.T) {
+	s, _ := New(1, 4, "slack.stakater.com/workspace", nil)
+	workspace := map[string]string{"slack.stakater.com/workspace": "engineering"}
+
+	owns := s.Owns(channel("a", workspace))
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, owns, s.Owns(channel(fmt.Sprintf("channel-%d", i), workspace)))
+	}
+}
+
+func TestShard_Predicate_shouldPassUpdatesOfChannelsMovingBetweenShards(t *testing.T) {
+	s, _ := New(0, 2, "workspace", nil)
+	var owned, other map[string]string
+	for _, value := range []string{"a", "b", "c", "d", "e", "f"} {
+		labels := map[string]string{"workspace": value}
+		if s.Owns(channel("x", labels)) {
+			owned = labels
+		} else {
+			other = labels
+		}
+	}
+
+	predicate := s.Predicate()
+	assert.True(t, predicate.Create(event.CreateEvent{Object: channel("x", owned)}))
+	assert.False(t, predicate.Create(event.CreateEvent{Object: channel("x", other)}))
+	assert.True(t, predicate.Update(event.UpdateEvent{ObjectOld: channel("x", owned), ObjectNew: channel("x", other)}))
+	assert.False(t, predicate.Update(event.UpdateEvent{ObjectOld: channel("x", other), ObjectNew: channel("x", other)}))
+}
+
+func TestShard_shouldOnlyOwnSelectedChannels(t *testing.T) {
+	selector, _ := labels.Parse("team=payments")
+	s, err := New(0, 1, "", selector)
+	assert.NoError(t, err)
+	assert.NotNil(t, s)
+
+	assert.True(t, s.Owns(channel("a", map[string]string{"team": "payments"})))
+	assert.False(t, s.Owns(channel("a", map[string]string{"team": "billing"})))
+	assert.False(t, s.Owns(channel("a", nil)))
+	assert.True(t, s.Primary())
+	assert.Equal(t, "id", s.LeaderElectionID("id"))
+}
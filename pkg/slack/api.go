@@ -0,0 +1,37 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+)
+
+// API is the part of *slack.Client used by the service. Pass an implementation to NewWithClient to record or
+// fake calls, or to wrap them in middleware.
+type API interface {
+	AuthTest() (*slack.AuthTestResponse, error)
+
+	GetConversationInfo(channelID string, includeLocale bool) (*slack.Channel, error)
+	GetConversations(params *slack.GetConversationsParameters) ([]slack.Channel, string, error)
+	GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error)
+	CreateConversation(channelName string, isPrivate bool) (*slack.Channel, error)
+	RenameConversation(channelID, channelName string) (*slack.Channel, error)
+	SetPurposeOfConversation(channelID, purpose string) (*slack.Channel, error)
+	SetTopicOfConversation(channelID, topic string) (*slack.Channel, error)
+	ArchiveConversation(channelID string) error
+	UnArchiveConversation(channelID string) error
+	JoinConversation(channelID string) (*slack.Channel, string, []string, error)
+	InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error)
+	KickUserFromConversationContext(ctx context.Context, channelID string, user string) error
+
+	GetUserInfoContext(ctx context.Context, user string) (*slack.User, error)
+	GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error)
+	GetUsersContext(ctx context.Context) ([]slack.User, error)
+
+	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
+	UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	DeleteFile(fileID string) error
+	GetEmoji() (map[string]string, error)
+}
+
+var _ API = &slack.Client{}
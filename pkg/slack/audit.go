@@ -0,0 +1,144 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/slack-go/slack"
+)
+
+// AuditAction is a mutating action the operator performs on a slack channel
+type AuditAction string
+
+const (
+	AuditCreate         AuditAction = "Create"
+	AuditRename         AuditAction = "Rename"
+	AuditSetTopic       AuditAction = "SetTopic"
+	AuditSetDescription AuditAction = "SetDescription"
+	AuditArchive        AuditAction = "Archive"
+	AuditUnarchive      AuditAction = "Unarchive"
+	AuditInvite         AuditAction = "Invite"
+	AuditKick           AuditAction = "Kick"
+	AuditJoin           AuditAction = "Join"
+)
+
+// AuditEvent is a mutating action which was performed successfully
+type AuditEvent struct {
+	Time      time.Time
+	Action    AuditAction
+	ChannelID string
+	// Users are the IDs of the invited or removed users
+	Users []string
+	// Value is the new name, topic or description
+	Value string
+}
+
+// Auditor records the audit events of the service
+type Auditor interface {
+	Record(event AuditEvent)
+}
+
+// WithAuditor records every mutating action of the service with the auditor
+func WithAuditor(auditor Auditor) Option {
+	return func(s *SlackService) {
+		s.auditor = auditor
+	}
+}
+
+// audit records the action with the auditor of the service, if any
+func (s *SlackService) audit(action AuditAction, channelID string, value string, users ...string) {
+	if s.auditor == nil {
+		return
+	}
+	s.auditor.Record(AuditEvent{Time: time.Now(), Action: action, ChannelID: channelID, Users: users, Value: value})
+}
+
+// Auditors records the events with each of the auditors
+type Auditors []Auditor
+
+// Record records the event with each of the auditors
+func (a Auditors) Record(event AuditEvent) {
+	for _, auditor := range a {
+		auditor.Record(event)
+	}
+}
+
+// AuditLog writes the events as structured log entries, e.g. to a logger named "audit" which is shipped to a SIEM
+type AuditLog struct {
+	Log logr.Logger
+}
+
+// Record logs the event
+func (a *AuditLog) Record(event AuditEvent) {
+	a.Log.Info("Slack channel changed", "action", event.Action, "channelID", event.ChannelID,
+		"users", event.Users, "value", event.Value, "time", event.Time.UTC().Format(time.RFC3339))
+}
+
+// AuditHistory keeps the latest events of each channel until they are drained into the status of its Channel
+type AuditHistory struct {
+	// Size is the maximum number of events kept per channel
+	Size int
+
+	mu     sync.Mutex
+	events map[string][]AuditEvent
+}
+
+// NewAuditHistory creates an AuditHistory which keeps at most size events per channel
+func NewAuditHistory(size int) *AuditHistory {
+	return &AuditHistory{Size: size, events: map[string][]AuditEvent{}}
+}
+
+// Record keeps the event, dropping the oldest event of the channel once Size is reached
+func (h *AuditHistory) Record(event AuditEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := append(h.events[event.ChannelID], event)
+	if len(events) > h.Size {
+		events = events[len(events)-h.Size:]
+	}
+	h.events[event.ChannelID] = events
+}
+
+// Drain returns and forgets the events of the channel
+func (h *AuditHistory) Drain(channelID string) []AuditEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := h.events[channelID]
+	delete(h.events, channelID)
+	return events
+}
+
+// AuditChannel posts the events to a slack channel, e.g. one the compliance team follows
+type AuditChannel struct {
+	Service   *SlackService
+	ChannelID string
+}
+
+// Record posts the event, failures are logged and don't fail the action
+func (a *AuditChannel) Record(event AuditEvent) {
+	_, err := a.Service.PostMessage(a.ChannelID, slack.MsgOptionText(FormatAuditEvent(event), false))
+	if err != nil {
+		a.Service.log.Error(err, "Error posting audit event", "action", event.Action, "channelID", event.ChannelID)
+	}
+}
+
+// FormatAuditEvent formats the event as a single line of mrkdwn
+func FormatAuditEvent(event AuditEvent) string {
+	text := fmt.Sprintf("*%s* <#%s>", event.Action, event.ChannelID)
+	if len(event.Users) > 0 {
+		mentions := make([]string, len(event.Users))
+		for i, userID := range event.Users {
+			mentions[i] = "<@" + userID + ">"
+		}
+		text += " " + strings.Join(mentions, " ")
+	}
+	if event.Value != "" {
+		text += ": " + event.Value
+	}
+	return text
+}
@@ -0,0 +1,57 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stakater/slack-operator/pkg/slack/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditHistory_shouldKeepLatestEventsPerChannel(t *testing.T) {
+	history := NewAuditHistory(2)
+
+	history.Record(AuditEvent{Action: AuditCreate, ChannelID: "C1"})
+	history.Record(AuditEvent{Action: AuditInvite, ChannelID: "C1", Users: []string{"U1"}})
+	history.Record(AuditEvent{Action: AuditKick, ChannelID: "C1", Users: []string{"U2"}})
+	history.Record(AuditEvent{Action: AuditArchive, ChannelID: "C2"})
+
+	events := history.Drain("C1")
+	assert.Len(t, events, 2)
+	assert.Equal(t, AuditInvite, events[0].Action)
+	assert.Equal(t, AuditKick, events[1].Action)
+	assert.Empty(t, history.Drain("C1"))
+	assert.Len(t, history.Drain("C2"), 1)
+}
+
+func TestFormatAuditEvent(t *testing.T) {
+	assert.Equal(t, "*Invite* <#C1> <@U1> <@U2>", FormatAuditEvent(AuditEvent{Action: AuditInvite, ChannelID: "C1", Users: []string{"U1", "U2"}}))
+	assert.Equal(t, "*Rename* <#C1>: new-name", FormatAuditEvent(AuditEvent{Action: AuditRename, ChannelID: "C1", Value: "new-name"}))
+}
+
+func TestSlackService_RenameChannel_shouldAuditRename(t *testing.T) {
+	history := NewAuditHistory(10)
+	s := NewMockService(log)
+	s.auditor = history
+	defer func() { s.auditor = nil }()
+
+	_, err := s.RenameChannel(mock.PublicConversationID, "new-channel")
+
+	assert.NoError(t, err)
+	events := history.Drain(mock.PublicConversationID)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, AuditRename, events[0].Action)
+		assert.Equal(t, "new-channel", events[0].Value)
+	}
+}
+
+func TestAuditMetrics_shouldCountEveryUser(t *testing.T) {
+	invites := testutil.ToFloat64(enforcementActions.WithLabelValues(string(AuditInvite)))
+	renames := testutil.ToFloat64(enforcementActions.WithLabelValues(string(AuditRename)))
+
+	AuditMetrics{}.Record(AuditEvent{Action: AuditInvite, ChannelID: "C1", Users: []string{"U1", "U2"}})
+	AuditMetrics{}.Record(AuditEvent{Action: AuditRename, ChannelID: "C1", Value: "new-name"})
+
+	assert.Equal(t, invites+2, testutil.ToFloat64(enforcementActions.WithLabelValues(string(AuditInvite))))
+	assert.Equal(t, renames+1, testutil.ToFloat64(enforcementActions.WithLabelValues(string(AuditRename))))
+}
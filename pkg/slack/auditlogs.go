@@ -0,0 +1,213 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// AuditLogsURL is the base URL of the Enterprise Grid Audit Logs API
+const AuditLogsURL = "https://api.slack.com/audit/v1/"
+
+// auditLogsPageSize is the number of entries fetched per page of the Audit Logs API
+const auditLogsPageSize = 200
+
+// AuditLogEntry is an entry of the Audit Logs API, only the fields the operator needs are decoded
+type AuditLogEntry struct {
+	ID         string `json:"id"`
+	DateCreate int64  `json:"date_create"`
+	Action     string `json:"action"`
+	Actor      struct {
+		Type string `json:"type"`
+		User struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"user"`
+	} `json:"actor"`
+	Entity struct {
+		Type    string `json:"type"`
+		Channel struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"channel"`
+	} `json:"entity"`
+}
+
+type auditLogsResponse struct {
+	Entries          []AuditLogEntry `json:"entries"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+// AuditLogs lists the entries of the Audit Logs API created at or after oldest, a Unix timestamp, newest first.
+// The API needs an org level user token with the auditlogs:read scope, the user token of the service is used.
+func (s *SlackService) AuditLogs(oldest int64, cursor string) ([]AuditLogEntry, string, error) {
+	query := url.Values{
+		"oldest": {strconv.FormatInt(oldest, 10)},
+		"limit":  {strconv.Itoa(auditLogsPageSize)},
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.auditLogsURL+"logs?"+query.Encode(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.userToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("audit logs returned status %s", resp.Status)
+	}
+
+	logs := &auditLogsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(logs); err != nil {
+		return nil, "", err
+	}
+	return logs.Entries, logs.ResponseMetadata.NextCursor, nil
+}
+
+type userAuthResponse struct {
+	slack.SlackResponse
+	UserID string `json:"user_id"`
+}
+
+// operatorUserIDs returns the users of the tokens of the service, whose actions are the operator's own
+func (s *SlackService) operatorUserIDs() (map[string]bool, error) {
+	users := map[string]bool{}
+
+	auth, err := s.primary().AuthTest()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	users[auth.UserID] = true
+
+	for _, pooled := range s.pool {
+		auth, err := pooled.api.AuthTest()
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		users[auth.UserID] = true
+	}
+
+	if s.userToken != "" {
+		auth := &userAuthResponse{}
+		if err := s.callAPI("auth.test", url.Values{"token": {s.userToken}}, auth); err != nil {
+			return nil, err
+		}
+		users[auth.UserID] = true
+	}
+
+	return users, nil
+}
+
+// AuditLogPoller polls the Audit Logs API for changes of channels which weren't made by the operator
+type AuditLogPoller struct {
+	service *SlackService
+	period  time.Duration
+	handle  func(context.Context, AuditLogEntry)
+
+	// oldest is the timestamp of the next entries to fetch, seen are the entries of that second which were handled
+	oldest int64
+	seen   map[string]bool
+
+	// operatorUsers are the users the operator acts as
+	operatorUsers map[string]bool
+}
+
+// NewAuditLogPoller creates a poller which calls handle for every change of a channel made outside the operator,
+// the Audit Logs API is polled every period
+func NewAuditLogPoller(service *SlackService, period time.Duration, handle func(context.Context, AuditLogEntry)) *AuditLogPoller {
+	return &AuditLogPoller{
+		service: service,
+		period:  period,
+		handle:  handle,
+	}
+}
+
+// Start polls the Audit Logs API every period until the context is cancelled, starting with the entries created
+// after the poller was started. Failed polls are retried from the same timestamp.
+func (p *AuditLogPoller) Start(ctx context.Context) error {
+	p.oldest = time.Now().Unix()
+	p.seen = map[string]bool{}
+
+	ticker := time.NewTicker(p.period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := p.poll(ctx); err != nil {
+			p.service.log.Error(err, "Error polling the Slack audit logs")
+		}
+	}
+}
+
+// poll handles the entries created since the last poll
+func (p *AuditLogPoller) poll(ctx context.Context) error {
+	if p.operatorUsers == nil {
+		users, err := p.service.operatorUserIDs()
+		if err != nil {
+			return err
+		}
+		p.operatorUsers = users
+	}
+
+	var entries []AuditLogEntry
+	cursor := ""
+	for {
+		page, next, err := p.service.AuditLogs(p.oldest, cursor)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, page...)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	// Entries are listed newest first, they are handled in the order they happened. The next poll starts at the
+	// second of the newest entry, as further entries of that second may not be listed yet.
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if p.seen[entry.ID] {
+			continue
+		}
+		if entry.DateCreate > p.oldest {
+			p.oldest = entry.DateCreate
+			p.seen = map[string]bool{}
+		}
+		if entry.DateCreate == p.oldest {
+			p.seen[entry.ID] = true
+		}
+		if entry.Entity.Type != "channel" || entry.Entity.Channel.ID == "" || p.operatorUsers[entry.Actor.User.ID] {
+			continue
+		}
+		p.handle(ctx, entry)
+	}
+
+	return nil
+}
+
+// NeedLeaderElection polls on the leader only, which reconciles the changed channels
+func (p *AuditLogPoller) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,93 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RequiredScopes are the scopes the token needs to manage channels and their members. Features like canvases,
+// reminders or emoji need further scopes, which are reported by MissingScope conditions when they are used.
+var RequiredScopes = []string{
+	"bookmarks:read",
+	"bookmarks:write",
+	"channels:manage",
+	"channels:read",
+	"chat:write",
+	"groups:read",
+	"groups:write",
+	"users:read",
+	"users:read.email",
+}
+
+type authTestResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	Team  string `json:"team"`
+	User  string `json:"user"`
+}
+
+// CheckAuth calls auth.test to verify the token and compares the scopes granted to it, which Slack returns in the
+// X-OAuth-Scopes header, with RequiredScopes. The error names every missing scope, it is kept for AuthCheck.
+func (s *SlackService) CheckAuth() error {
+	err := s.checkAuth()
+
+	s.mu.Lock()
+	s.authErr = err
+	s.mu.Unlock()
+
+	return err
+}
+
+// AuthCheck is a readiness check failing with the error of the last CheckAuth
+func (s *SlackService) AuthCheck(_ *http.Request) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.authErr
+}
+
+func (s *SlackService) checkAuth() error {
+	resp, err := s.httpClient.PostForm(s.apiURL+"auth.test", url.Values{"token": {s.apiToken()}})
+	if err != nil {
+		return fmt.Errorf("auth.test failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth.test returned status %s", resp.Status)
+	}
+
+	auth := &authTestResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(auth); err != nil {
+		return fmt.Errorf("auth.test returned an invalid response: %v", err)
+	}
+	if !auth.OK {
+		return fmt.Errorf("token is invalid: %s", auth.Error)
+	}
+
+	missing := missingScopes(resp.Header.Get("X-OAuth-Scopes"))
+	if len(missing) > 0 {
+		return fmt.Errorf("token of %s in %s is missing the scopes %s", auth.User, auth.Team, strings.Join(missing, ", "))
+	}
+
+	s.log.Info("Slack token verified", "team", auth.Team, "user", auth.User)
+	return nil
+}
+
+// missingScopes returns the required scopes which aren't in the comma separated granted scopes
+func missingScopes(granted string) []string {
+	scopes := map[string]bool{}
+	for _, scope := range strings.Split(granted, ",") {
+		scopes[strings.TrimSpace(scope)] = true
+	}
+
+	var missing []string
+	for _, scope := range RequiredScopes {
+		if !scopes[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
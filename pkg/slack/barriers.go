@@ -0,0 +1,127 @@
+package slack
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// Barrier is an information barrier of Enterprise Grid, the members of the primary usergroup can't reach the
+// members of the barriered usergroups through the restricted subjects
+type Barrier struct {
+	ID                 string
+	PrimaryUsergroup   string
+	BarrieredFrom      []string
+	RestrictedSubjects []string
+}
+
+type usergroupResponse struct {
+	ID string `json:"id"`
+}
+
+type barrierResponse struct {
+	ID                      string              `json:"id"`
+	PrimaryUsergroup        usergroupResponse   `json:"primary_usergroup"`
+	BarrieredFromUsergroups []usergroupResponse `json:"barriered_from_usergroups"`
+	RestrictedSubjects      []string            `json:"restricted_subjects"`
+}
+
+type barrierListResponse struct {
+	slack.SlackResponse
+	Barriers         []barrierResponse `json:"barriers"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+type barrierCreateResponse struct {
+	slack.SlackResponse
+	BarrierID string `json:"barrier_id"`
+}
+
+// ListBarriers returns the information barriers of the organization, following the cursor through all pages.
+// It needs an Enterprise Grid user token with the admin.barriers:read scope.
+func (s *SlackService) ListBarriers() ([]Barrier, error) {
+	var barriers []Barrier
+	var cursor string
+
+	for {
+		values := url.Values{"limit": {"200"}}
+		if cursor != "" {
+			values.Set("cursor", cursor)
+		}
+
+		response := &barrierListResponse{}
+		if err := s.callAPI("admin.barriers.list", values, response); err != nil {
+			s.log.Error(err, "Error listing information barriers")
+			return nil, err
+		}
+		for _, barrier := range response.Barriers {
+			var barrieredFrom []string
+			for _, usergroup := range barrier.BarrieredFromUsergroups {
+				barrieredFrom = append(barrieredFrom, usergroup.ID)
+			}
+			barriers = append(barriers, Barrier{
+				ID:                 barrier.ID,
+				PrimaryUsergroup:   barrier.PrimaryUsergroup.ID,
+				BarrieredFrom:      barrieredFrom,
+				RestrictedSubjects: barrier.RestrictedSubjects,
+			})
+		}
+
+		if response.ResponseMetadata.NextCursor == "" {
+			return barriers, nil
+		}
+		cursor = response.ResponseMetadata.NextCursor
+	}
+}
+
+// CreateBarrier creates the information barrier and returns its ID.
+// It needs an Enterprise Grid user token with the admin.barriers:write scope.
+func (s *SlackService) CreateBarrier(barrier Barrier) (string, error) {
+	log := s.log.WithValues("primaryUsergroup", barrier.PrimaryUsergroup)
+
+	log.V(1).Info("Creating information barrier")
+	response := &barrierCreateResponse{}
+	if err := s.callAPI("admin.barriers.create", barrierValues(barrier), response); err != nil {
+		log.Error(err, "Error creating information barrier")
+		return "", err
+	}
+	return response.BarrierID, nil
+}
+
+// UpdateBarrier replaces the usergroups and restricted subjects of the information barrier with the ID
+func (s *SlackService) UpdateBarrier(barrier Barrier) error {
+	log := s.log.WithValues("barrierID", barrier.ID)
+
+	log.V(1).Info("Updating information barrier")
+	values := barrierValues(barrier)
+	values.Set("barrier_id", barrier.ID)
+	if err := s.callAPI("admin.barriers.update", values, &slack.SlackResponse{}); err != nil {
+		log.Error(err, "Error updating information barrier")
+		return err
+	}
+	return nil
+}
+
+// DeleteBarrier deletes the information barrier
+func (s *SlackService) DeleteBarrier(barrierID string) error {
+	log := s.log.WithValues("barrierID", barrierID)
+
+	log.V(1).Info("Deleting information barrier")
+	if err := s.callAPI("admin.barriers.delete", url.Values{"barrier_id": {barrierID}}, &slack.SlackResponse{}); err != nil {
+		log.Error(err, "Error deleting information barrier")
+		return err
+	}
+	return nil
+}
+
+// barrierValues are the parameters of admin.barriers.create and admin.barriers.update
+func barrierValues(barrier Barrier) url.Values {
+	return url.Values{
+		"primary_usergroup_id":         {barrier.PrimaryUsergroup},
+		"barriered_from_usergroup_ids": {strings.Join(barrier.BarrieredFrom, ",")},
+		"restricted_subjects":          {strings.Join(barrier.RestrictedSubjects, ",")},
+	}
+}
@@ -0,0 +1,145 @@
+package slack
+
+import (
+	"context"
+	"github.com/slack-go/slack"
+)
+
+// ChannelClone is what was copied from a prototype channel
+type ChannelClone struct {
+	Topic       string
+	Description string
+
+	// Members are the user IDs of the members of the prototype, integrations, guests and deactivated users are left
+	// out
+	Members []string
+
+	// Bookmarks and Pins are the numbers of bookmarks and pinned messages which were copied
+	Bookmarks int
+	Pins      int
+}
+
+// CloneChannel copies the bookmarks, pinned messages and members of the source channel to the target channel and
+// returns the topic and description of the source, which the Channel applies. Bookmarks whose link the target
+// already has and the managed-by bookmark are skipped, so a failed clone can be retried. Pinned messages are posted
+// to the target and pinned there, pinned files aren't copied.
+func CloneChannel(service Service, sourceID string, targetID string) (*ChannelClone, error) {
+	source, err := service.GetChannel(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	clone := &ChannelClone{Topic: source.Topic.Value, Description: source.Purpose.Value}
+
+	if clone.Bookmarks, err = cloneBookmarks(service, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	if clone.Pins, err = clonePins(service, sourceID, targetID); err != nil {
+		return nil, err
+	}
+
+	members, err := service.ListMembers(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.Kind == MemberKindMember {
+			clone.Members = append(clone.Members, member.ID)
+		}
+	}
+	if clone.Members, err = inviteMembers(service, targetID, clone.Members); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+// inviteMembers invites the users to the target channel and returns the ones who joined it, users who can't join the
+// channel, like guests, are left out
+func inviteMembers(service Service, targetID string, userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	plan := &MembershipPlan{Invite: userIDs, emails: map[string]string{}}
+	for _, userID := range userIDs {
+		plan.emails[userID] = userID
+	}
+	memberErrors, errs := service.ApplyMembership(context.Background(), targetID, plan)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	failed := map[string]bool{}
+	for _, memberError := range memberErrors {
+		failed[memberError.User] = true
+	}
+	var joined []string
+	for _, userID := range userIDs {
+		if !failed[userID] {
+			joined = append(joined, userID)
+		}
+	}
+	return joined, nil
+}
+
+func cloneBookmarks(service Service, sourceID string, targetID string) (int, error) {
+	bookmarks, err := service.ListBookmarks(sourceID)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := service.ListBookmarks(targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	links := map[string]bool{}
+	for _, bookmark := range existing {
+		links[bookmark.Link] = true
+	}
+
+	copied := 0
+	for _, bookmark := range bookmarks {
+		if bookmark.Title == ManagedByTitle || bookmark.Type != "link" || links[bookmark.Link] {
+			continue
+		}
+		if err := service.AddBookmark(targetID, bookmark); err != nil {
+			return copied, err
+		}
+		links[bookmark.Link] = true
+		copied++
+	}
+	return copied, nil
+}
+
+func clonePins(service Service, sourceID string, targetID string) (int, error) {
+	pins, err := service.ListPins(sourceID)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := service.ListPins(targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	texts := map[string]bool{}
+	for _, pin := range existing {
+		texts[pin.Text] = true
+	}
+
+	copied := 0
+	for _, pin := range pins {
+		if pin.Text == "" || texts[pin.Text] {
+			continue
+		}
+		timestamp, err := service.PostMessage(targetID, slack.MsgOptionText(pin.Text, false))
+		if err != nil {
+			return copied, err
+		}
+		if err := service.AddPin(targetID, timestamp); err != nil {
+			return copied, err
+		}
+		texts[pin.Text] = true
+		copied++
+	}
+	return copied, nil
+}
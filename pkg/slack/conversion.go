@@ -0,0 +1,23 @@
+package slack
+
+import (
+	"net/url"
+
+	"github.com/slack-go/slack"
+)
+
+// ConvertChannel converts the channel to a private or a public channel. It needs an Enterprise Grid user token with
+// the admin.conversations:write scope, other tokens fail with ErrAdminUnavailable.
+func (s *SlackService) ConvertChannel(channelID string, private bool) error {
+	method := "admin.conversations.convertToPublic"
+	if private {
+		method = "admin.conversations.convertToPrivate"
+	}
+
+	s.log.V(1).Info("Converting channel", "channelID", channelID, "private", private)
+	if err := s.callAPI(method, url.Values{"channel_id": {channelID}}, &slack.SlackResponse{}); err != nil {
+		s.log.Error(err, "Error converting channel", "channelID", channelID, "private", private)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package slack
+
+import (
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// Errors of the Slack Web API the callers of the service branch on, match them with errors.Is
+var (
+	ErrChannelNotFound  = errors.New("channel not found")
+	ErrNameTaken        = errors.New("channel name is taken")
+	ErrAlreadyArchived  = errors.New("channel is already archived")
+	ErrChannelArchived  = errors.New("channel is archived")
+	ErrAlreadyInChannel = errors.New("user is already in the channel")
+	ErrNotInChannel     = errors.New("user isn't in the channel")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrUserRestricted   = errors.New("user is a guest who can't join the channel")
+	ErrAlreadyInvited   = errors.New("user is already invited")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrMissingScope     = errors.New("token is missing a scope")
+	ErrBarrierNotFound  = errors.New("information barrier not found")
+	ErrAdminUnavailable = errors.New("admin API isn't available to the token")
+)
+
+// errorCodes maps the error codes returned by Slack to their errors
+var errorCodes = map[string]error{
+	"channel_not_found":  ErrChannelNotFound,
+	"name_taken":         ErrNameTaken,
+	"already_archived":   ErrAlreadyArchived,
+	"is_archived":        ErrChannelArchived,
+	"already_in_channel": ErrAlreadyInChannel,
+	// the bot is always a member of the channels it manages
+	"cant_invite_self":         ErrAlreadyInChannel,
+	"not_in_channel":           ErrNotInChannel,
+	"users_not_found":          ErrUserNotFound,
+	"user_not_found":           ErrUserNotFound,
+	"user_is_restricted":       ErrUserRestricted,
+	"user_is_ultra_restricted": ErrUserRestricted,
+	"ura_max_channels":         ErrUserRestricted,
+	"already_invited":          ErrAlreadyInvited,
+	"ratelimited":              ErrRateLimited,
+	"missing_scope":            ErrMissingScope,
+	"barrier_not_found":        ErrBarrierNotFound,
+	// admin methods need an admin user token of an Enterprise Grid organization
+	"not_allowed_token_type":      ErrAdminUnavailable,
+	"not_an_admin":                ErrAdminUnavailable,
+	"not_an_enterprise":           ErrAdminUnavailable,
+	"team_not_on_enterprise_plan": ErrAdminUnavailable,
+	"feature_not_enabled":         ErrAdminUnavailable,
+}
+
+// APIError is an error response of the Slack Web API, its message is the error code returned by Slack
+type APIError struct {
+	Code string
+	err  error
+}
+
+func (e *APIError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error of the slack client, e.g. a *slack.RateLimitedError with the time to wait
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Is matches the error of the code
+func (e *APIError) Is(target error) bool {
+	return errorCodes[e.Code] == target
+}
+
+// wrapError wraps errors of the Slack Web API with known codes into an APIError, other errors are returned as is
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return err
+	}
+
+	var rateLimited *slack.RateLimitedError
+	if errors.As(err, &rateLimited) {
+		return &APIError{Code: "ratelimited", err: err}
+	}
+
+	if _, known := errorCodes[err.Error()]; known {
+		return &APIError{Code: err.Error(), err: err}
+	}
+	return err
+}
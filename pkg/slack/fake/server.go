@@ -0,0 +1,964 @@
+// Package fake provides an in-memory Slack Web API server implementing the conversations, users and bookmarks
+// methods the operator calls, so reconciles can be tested end to end without a workspace or network
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// DefaultScopes are the scopes auth.test reports for the token, the scopes the operator requires
+var DefaultScopes = []string{
+	"bookmarks:read", "bookmarks:write", "channels:manage", "channels:read", "chat:write",
+	"groups:read", "groups:write", "users:read", "users:read.email",
+}
+
+// Channel is a channel of the fake workspace
+type Channel struct {
+	ID          string
+	Name        string
+	Topic       string
+	Description string
+	Private     bool
+	Archived    bool
+	// Members are the IDs of the members, the bot is added to the channels it creates
+	Members []string
+	// Updated is the time of the last change in milliseconds
+	Updated int64
+	// RetentionDays is the custom retention of the channel, 0 for the workspace retention
+	RetentionDays int
+}
+
+// Barrier is an information barrier of the fake organization
+type Barrier struct {
+	ID                 string
+	PrimaryUsergroup   string
+	BarrieredFrom      []string
+	RestrictedSubjects []string
+}
+
+// User is a user of the fake workspace
+type User struct {
+	ID    string
+	Name  string
+	Email string
+	IsBot bool
+	// Restricted users are guests who can't be invited to channels
+	Restricted bool
+	Deleted    bool
+}
+
+// Bookmark is a bookmark of a channel
+type Bookmark struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+	Type  string `json:"type"`
+}
+
+// Message is a message posted to a channel
+type Message struct {
+	Channel   string
+	Timestamp string
+	Text      string
+}
+
+// Server is an in-memory Slack workspace served over HTTP, its state can be seeded and inspected by tests
+type Server struct {
+	// BotUserID is the user of the token, TeamID the workspace
+	BotUserID string
+	TeamID    string
+	// Scopes are reported by auth.test in the X-OAuth-Scopes header
+	Scopes []string
+
+	mu        sync.Mutex
+	server    *httptest.Server
+	channels  map[string]*Channel
+	users     map[string]*User
+	bookmarks map[string][]Bookmark
+	messages  []Message
+	pins      map[string][]Message
+	defaults  []string
+	barriers  map[string]*Barrier
+	// usergroups are the default channels of the usergroups
+	usergroups map[string][]string
+	nextID     int
+}
+
+// NewServer starts a fake Slack server with the bot user UBOT in the workspace TFAKE, Close stops it
+func NewServer() *Server {
+	s := &Server{
+		BotUserID:  "UBOT",
+		TeamID:     "TFAKE",
+		Scopes:     DefaultScopes,
+		channels:   map[string]*Channel{},
+		users:      map[string]*User{},
+		bookmarks:  map[string][]Bookmark{},
+		barriers:   map[string]*Barrier{},
+		usergroups: map[string][]string{},
+		pins:       map[string][]Message{},
+	}
+	s.users[s.BotUserID] = &User{ID: s.BotUserID, Name: "slack-operator", IsBot: true}
+
+	mux := http.NewServeMux()
+	for method, handler := range map[string]func(*http.Request) (interface{}, string){
+		"auth.test":                                 s.authTest,
+		"conversations.create":                      s.createConversation,
+		"conversations.info":                        s.conversationInfo,
+		"conversations.list":                        s.listConversations,
+		"conversations.members":                     s.conversationMembers,
+		"conversations.rename":                      s.renameConversation,
+		"conversations.setTopic":                    s.setTopic,
+		"conversations.setPurpose":                  s.setPurpose,
+		"conversations.archive":                     s.archiveConversation,
+		"conversations.unarchive":                   s.unarchiveConversation,
+		"conversations.join":                        s.joinConversation,
+		"conversations.invite":                      s.inviteToConversation,
+		"conversations.kick":                        s.kickFromConversation,
+		"users.info":                                s.userInfo,
+		"users.lookupByEmail":                       s.lookupUserByEmail,
+		"users.list":                                s.listUsers,
+		"bookmarks.add":                             s.addBookmark,
+		"bookmarks.list":                            s.listBookmarks,
+		"bookmarks.edit":                            s.editBookmark,
+		"chat.postMessage":                          s.postMessage,
+		"conversations.history":                     s.conversationHistory,
+		"pins.add":                                  s.addPin,
+		"pins.list":                                 s.listPins,
+		"admin.conversations.invite":                s.adminInvite,
+		"admin.conversations.convertToPrivate":      s.convertToPrivate,
+		"admin.conversations.convertToPublic":       s.convertToPublic,
+		"admin.conversations.getCustomRetention":    s.getRetention,
+		"admin.conversations.setCustomRetention":    s.setRetention,
+		"admin.conversations.removeCustomRetention": s.removeRetention,
+		"admin.barriers.create":                     s.createBarrier,
+		"admin.barriers.update":                     s.updateBarrier,
+		"admin.barriers.delete":                     s.deleteBarrier,
+		"admin.barriers.list":                       s.listBarriers,
+		"admin.usergroups.listChannels":             s.listUsergroupChannels,
+		"admin.usergroups.addChannels":              s.addUsergroupChannels,
+		"admin.usergroups.removeChannels":           s.removeUsergroupChannels,
+		"admin.teams.settings.info":                 s.teamSettings,
+		"admin.teams.settings.setDefaultChannels":   s.setDefaultChannels,
+	} {
+		mux.HandleFunc("/"+method, s.handle(handler))
+	}
+	s.server = httptest.NewServer(mux)
+
+	return s
+}
+
+// URL is the API URL of the server, e.g. for slack.OptionAPIURL or the WithAPIURL option of the service
+func (s *Server) URL() string {
+	return s.server.URL + "/"
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddUser adds the user to the workspace, an ID is assigned if it has none
+func (s *Server) AddUser(user User) User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = s.newID("U")
+	}
+	s.users[user.ID] = &user
+	return user
+}
+
+// AddChannel adds the channel to the workspace, an ID is assigned if it has none
+func (s *Server) AddChannel(channel Channel) Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if channel.ID == "" {
+		channel.ID = s.newID("C")
+	}
+	channel.Members = append([]string{}, channel.Members...)
+	s.touch(&channel)
+	s.channels[channel.ID] = &channel
+	return channel
+}
+
+// Channel returns a copy of the channel with the ID
+func (s *Server) Channel(id string) (Channel, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel, found := s.channels[id]
+	if !found {
+		return Channel{}, false
+	}
+	copied := *channel
+	copied.Members = append([]string{}, channel.Members...)
+	return copied, true
+}
+
+// ChannelByName returns a copy of the channel with the name
+func (s *Server) ChannelByName(name string) (Channel, bool) {
+	s.mu.Lock()
+	channel := s.channelByName(name)
+	s.mu.Unlock()
+
+	if channel == nil {
+		return Channel{}, false
+	}
+	return s.Channel(channel.ID)
+}
+
+// UpdateChannel changes the channel like a user in Slack, e.g. to test drift
+func (s *Server) UpdateChannel(id string, update func(*Channel)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if channel, found := s.channels[id]; found {
+		update(channel)
+		s.touch(channel)
+	}
+}
+
+// Bookmarks returns the bookmarks of the channel
+func (s *Server) Bookmarks(channelID string) []Bookmark {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Bookmark{}, s.bookmarks[channelID]...)
+}
+
+// Messages returns the messages posted to all channels in the order they were posted
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message{}, s.messages...)
+}
+
+// DefaultChannels returns the IDs of the channels new members join
+func (s *Server) DefaultChannels() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.defaults...)
+}
+
+// Barriers returns the information barriers
+func (s *Server) Barriers() []Barrier {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var barriers []Barrier
+	for _, barrier := range s.barriers {
+		barriers = append(barriers, *barrier)
+	}
+	return barriers
+}
+
+// UsergroupChannels returns the IDs of the default channels of the usergroup
+func (s *Server) UsergroupChannels(usergroupID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.usergroups[usergroupID]...)
+}
+
+// AddMessage adds a message with the given timestamp, e.g. to backdate it, keeping the messages in timestamp order
+func (s *Server) AddMessage(message Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts, _ := strconv.ParseFloat(message.Timestamp, 64)
+	i := len(s.messages)
+	for i > 0 {
+		if previous, _ := strconv.ParseFloat(s.messages[i-1].Timestamp, 64); previous <= ts {
+			break
+		}
+		i--
+	}
+	s.messages = append(s.messages[:i], append([]Message{message}, s.messages[i:]...)...)
+}
+
+// Pins returns the messages pinned to the channel
+func (s *Server) Pins(channelID string) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message{}, s.pins[channelID]...)
+}
+
+// handle serves a Web API method, handler returns the fields of the response or an error code
+func (s *Server) handle(handler func(*http.Request) (interface{}, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		response, errorCode := handler(r)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "auth.test") {
+			w.Header().Set("X-OAuth-Scopes", strings.Join(s.Scopes, ","))
+		}
+
+		if errorCode != "" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": false, "error": errorCode})
+			return
+		}
+
+		fields := map[string]interface{}{}
+		if response != nil {
+			data, _ := json.Marshal(response)
+			_ = json.Unmarshal(data, &fields)
+		}
+		fields["ok"] = true
+		_ = json.NewEncoder(w).Encode(fields)
+	}
+}
+
+func (s *Server) authTest(*http.Request) (interface{}, string) {
+	return map[string]string{
+		"team":    "fake",
+		"team_id": s.TeamID,
+		"user":    s.users[s.BotUserID].Name,
+		"user_id": s.BotUserID,
+	}, ""
+}
+
+func (s *Server) createConversation(r *http.Request) (interface{}, string) {
+	name := r.FormValue("name")
+	if s.channelByName(name) != nil {
+		return nil, "name_taken"
+	}
+
+	channel := &Channel{
+		ID:      s.newID("C"),
+		Name:    name,
+		Private: r.FormValue("is_private") == "true",
+		Members: []string{s.BotUserID},
+	}
+	s.touch(channel)
+	s.channels[channel.ID] = channel
+	return s.channelResponse(channel), ""
+}
+
+func (s *Server) conversationInfo(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.visibleChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	return s.channelResponse(channel), ""
+}
+
+func (s *Server) listConversations(r *http.Request) (interface{}, string) {
+	types := r.FormValue("types")
+	excludeArchived := r.FormValue("exclude_archived") == "true"
+
+	var ids []string
+	for id, channel := range s.channels {
+		if excludeArchived && channel.Archived {
+			continue
+		}
+		if channel.Private && (!strings.Contains(types, "private_channel") || !contains(channel.Members, s.BotUserID)) {
+			continue
+		}
+		if !channel.Private && types != "" && !strings.Contains(types, "public_channel") {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	page, next := paginate(ids, r)
+	channels := []slack.Channel{}
+	for _, id := range page {
+		channels = append(channels, s.toSlack(s.channels[id]))
+	}
+	return map[string]interface{}{
+		"channels":          channels,
+		"response_metadata": map[string]string{"next_cursor": next},
+	}, ""
+}
+
+func (s *Server) conversationMembers(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.visibleChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	page, next := paginate(channel.Members, r)
+	return map[string]interface{}{
+		"members":           append([]string{}, page...),
+		"response_metadata": map[string]string{"next_cursor": next},
+	}, ""
+}
+
+func (s *Server) renameConversation(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.writableChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	name := r.FormValue("name")
+	if other := s.channelByName(name); other != nil && other.ID != channel.ID {
+		return nil, "name_taken"
+	}
+	channel.Name = name
+	s.touch(channel)
+	return s.channelResponse(channel), ""
+}
+
+func (s *Server) setTopic(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.writableChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	channel.Topic = r.FormValue("topic")
+	s.touch(channel)
+	return s.channelResponse(channel), ""
+}
+
+func (s *Server) setPurpose(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.writableChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	channel.Description = r.FormValue("purpose")
+	s.touch(channel)
+	return s.channelResponse(channel), ""
+}
+
+func (s *Server) archiveConversation(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.visibleChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	if channel.Archived {
+		return nil, "already_archived"
+	}
+	channel.Archived = true
+	s.touch(channel)
+	return nil, ""
+}
+
+func (s *Server) unarchiveConversation(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.visibleChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	if !channel.Archived {
+		return nil, "not_archived"
+	}
+	channel.Archived = false
+	s.touch(channel)
+	return nil, ""
+}
+
+func (s *Server) joinConversation(r *http.Request) (interface{}, string) {
+	channel, found := s.channels[r.FormValue("channel")]
+	if !found || channel.Private {
+		return nil, "channel_not_found"
+	}
+	if !contains(channel.Members, s.BotUserID) {
+		channel.Members = append(channel.Members, s.BotUserID)
+		s.touch(channel)
+	}
+	return s.channelResponse(channel), ""
+}
+
+func (s *Server) inviteToConversation(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.writableChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+
+	// The whole batch fails if one of the users can't be invited, like Slack does
+	userIDs := strings.Split(r.FormValue("users"), ",")
+	for _, userID := range userIDs {
+		user, found := s.users[userID]
+		switch {
+		case !found || user.Deleted:
+			return nil, "user_not_found"
+		case user.Restricted:
+			return nil, "user_is_restricted"
+		case contains(channel.Members, userID):
+			return nil, "already_in_channel"
+		}
+	}
+	channel.Members = append(channel.Members, userIDs...)
+	s.touch(channel)
+	return s.channelResponse(channel), ""
+}
+
+func (s *Server) kickFromConversation(r *http.Request) (interface{}, string) {
+	channel, errorCode := s.writableChannel(r.FormValue("channel"))
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	userID := r.FormValue("user")
+	if !contains(channel.Members, userID) {
+		return nil, "not_in_channel"
+	}
+	channel.Members = remove(channel.Members, userID)
+	s.touch(channel)
+	return nil, ""
+}
+
+func (s *Server) adminInvite(r *http.Request) (interface{}, string) {
+	channel, found := s.channels[r.FormValue("channel_id")]
+	if !found {
+		return nil, "channel_not_found"
+	}
+	for _, userID := range strings.Split(r.FormValue("user_ids"), ",") {
+		if !contains(channel.Members, userID) {
+			channel.Members = append(channel.Members, userID)
+		}
+	}
+	s.touch(channel)
+	return nil, ""
+}
+
+func (s *Server) getRetention(r *http.Request) (interface{}, string) {
+	channel, found := s.channels[r.FormValue("channel_id")]
+	if !found {
+		return nil, "channel_not_found"
+	}
+	return map[string]interface{}{
+		"is_policy_enabled": channel.RetentionDays > 0,
+		"duration_days":     channel.RetentionDays,
+	}, ""
+}
+
+func (s *Server) setRetention(r *http.Request) (interface{}, string) {
+	channel, found := s.channels[r.FormValue("channel_id")]
+	if !found {
+		return nil, "channel_not_found"
+	}
+	days, err := strconv.Atoi(r.FormValue("duration_days"))
+	if err != nil || days < 1 {
+		return nil, "invalid_duration_days"
+	}
+	channel.RetentionDays = days
+	return nil, ""
+}
+
+func (s *Server) convertToPrivate(r *http.Request) (interface{}, string) {
+	return s.convert(r, true)
+}
+
+func (s *Server) convertToPublic(r *http.Request) (interface{}, string) {
+	return s.convert(r, false)
+}
+
+func (s *Server) convert(r *http.Request, private bool) (interface{}, string) {
+	channel, found := s.channels[r.FormValue("channel_id")]
+	if !found {
+		return nil, "channel_not_found"
+	}
+	if channel.Private == private {
+		if private {
+			return nil, "channel_already_private"
+		}
+		return nil, "channel_already_public"
+	}
+	channel.Private = private
+	return nil, ""
+}
+
+func (s *Server) removeRetention(r *http.Request) (interface{}, string) {
+	channel, found := s.channels[r.FormValue("channel_id")]
+	if !found {
+		return nil, "channel_not_found"
+	}
+	channel.RetentionDays = 0
+	return nil, ""
+}
+
+func (s *Server) teamSettings(r *http.Request) (interface{}, string) {
+	if r.FormValue("team_id") != s.TeamID {
+		return nil, "team_not_found"
+	}
+	return map[string]interface{}{
+		"team": map[string]interface{}{"id": s.TeamID, "default_channels": append([]string{}, s.defaults...)},
+	}, ""
+}
+
+func (s *Server) setDefaultChannels(r *http.Request) (interface{}, string) {
+	if r.FormValue("team_id") != s.TeamID {
+		return nil, "team_not_found"
+	}
+	s.defaults = nil
+	for _, channelID := range strings.Split(r.FormValue("channel_ids"), ",") {
+		if channelID == "" {
+			continue
+		}
+		if _, found := s.channels[channelID]; !found {
+			return nil, "channel_not_found"
+		}
+		s.defaults = append(s.defaults, channelID)
+	}
+	return nil, ""
+}
+
+func (s *Server) createBarrier(r *http.Request) (interface{}, string) {
+	barrier := &Barrier{ID: s.newID("B")}
+	if errorCode := setBarrier(barrier, r); errorCode != "" {
+		return nil, errorCode
+	}
+	s.barriers[barrier.ID] = barrier
+	return map[string]string{"barrier_id": barrier.ID}, ""
+}
+
+func (s *Server) updateBarrier(r *http.Request) (interface{}, string) {
+	barrier, found := s.barriers[r.FormValue("barrier_id")]
+	if !found {
+		return nil, "barrier_not_found"
+	}
+	return nil, setBarrier(barrier, r)
+}
+
+func (s *Server) deleteBarrier(r *http.Request) (interface{}, string) {
+	if _, found := s.barriers[r.FormValue("barrier_id")]; !found {
+		return nil, "barrier_not_found"
+	}
+	delete(s.barriers, r.FormValue("barrier_id"))
+	return nil, ""
+}
+
+func (s *Server) listBarriers(r *http.Request) (interface{}, string) {
+	var ids []string
+	for id := range s.barriers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	page, next := paginate(ids, r)
+	barriers := []map[string]interface{}{}
+	for _, id := range page {
+		barrier := s.barriers[id]
+		var barrieredFrom []map[string]string
+		for _, usergroup := range barrier.BarrieredFrom {
+			barrieredFrom = append(barrieredFrom, map[string]string{"id": usergroup})
+		}
+		barriers = append(barriers, map[string]interface{}{
+			"id":                        barrier.ID,
+			"primary_usergroup":         map[string]string{"id": barrier.PrimaryUsergroup},
+			"barriered_from_usergroups": barrieredFrom,
+			"restricted_subjects":       barrier.RestrictedSubjects,
+		})
+	}
+	return map[string]interface{}{
+		"barriers":          barriers,
+		"response_metadata": map[string]string{"next_cursor": next},
+	}, ""
+}
+
+// setBarrier sets the usergroups and restricted subjects of the barrier from the request
+func setBarrier(barrier *Barrier, r *http.Request) string {
+	if r.FormValue("primary_usergroup_id") == "" || r.FormValue("barriered_from_usergroup_ids") == "" {
+		return "invalid_arguments"
+	}
+	barrier.PrimaryUsergroup = r.FormValue("primary_usergroup_id")
+	barrier.BarrieredFrom = strings.Split(r.FormValue("barriered_from_usergroup_ids"), ",")
+	barrier.RestrictedSubjects = strings.Split(r.FormValue("restricted_subjects"), ",")
+	return ""
+}
+
+func (s *Server) listUsergroupChannels(r *http.Request) (interface{}, string) {
+	channels := []map[string]string{}
+	for _, channelID := range s.usergroups[r.FormValue("usergroup_id")] {
+		channels = append(channels, map[string]string{"id": channelID})
+	}
+	return map[string]interface{}{"channels": channels}, ""
+}
+
+func (s *Server) addUsergroupChannels(r *http.Request) (interface{}, string) {
+	usergroupID := r.FormValue("usergroup_id")
+	for _, channelID := range strings.Split(r.FormValue("channel_ids"), ",") {
+		if _, found := s.channels[channelID]; !found {
+			return nil, "channel_not_found"
+		}
+		if !contains(s.usergroups[usergroupID], channelID) {
+			s.usergroups[usergroupID] = append(s.usergroups[usergroupID], channelID)
+		}
+	}
+	return nil, ""
+}
+
+func (s *Server) removeUsergroupChannels(r *http.Request) (interface{}, string) {
+	usergroupID := r.FormValue("usergroup_id")
+	removed := strings.Split(r.FormValue("channel_ids"), ",")
+	var channelIDs []string
+	for _, channelID := range s.usergroups[usergroupID] {
+		if !contains(removed, channelID) {
+			channelIDs = append(channelIDs, channelID)
+		}
+	}
+	s.usergroups[usergroupID] = channelIDs
+	return nil, ""
+}
+
+func (s *Server) userInfo(r *http.Request) (interface{}, string) {
+	user, found := s.users[r.FormValue("user")]
+	if !found {
+		return nil, "user_not_found"
+	}
+	return map[string]interface{}{"user": toSlackUser(user)}, ""
+}
+
+func (s *Server) lookupUserByEmail(r *http.Request) (interface{}, string) {
+	email := r.FormValue("email")
+	for _, user := range s.users {
+		if user.Email != "" && strings.EqualFold(user.Email, email) {
+			return map[string]interface{}{"user": toSlackUser(user)}, ""
+		}
+	}
+	return nil, "users_not_found"
+}
+
+func (s *Server) listUsers(r *http.Request) (interface{}, string) {
+	var ids []string
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	page, next := paginate(ids, r)
+	users := []slack.User{}
+	for _, id := range page {
+		users = append(users, toSlackUser(s.users[id]))
+	}
+	return map[string]interface{}{
+		"members":           users,
+		"response_metadata": map[string]string{"next_cursor": next},
+	}, ""
+}
+
+func (s *Server) addBookmark(r *http.Request) (interface{}, string) {
+	channelID := r.FormValue("channel_id")
+	if _, errorCode := s.visibleChannel(channelID); errorCode != "" {
+		return nil, errorCode
+	}
+	bookmark := Bookmark{ID: s.newID("Bk"), Title: r.FormValue("title"), Link: r.FormValue("link"), Type: r.FormValue("type")}
+	s.bookmarks[channelID] = append(s.bookmarks[channelID], bookmark)
+	return map[string]interface{}{"bookmark": bookmark}, ""
+}
+
+func (s *Server) editBookmark(r *http.Request) (interface{}, string) {
+	channelID := r.FormValue("channel_id")
+	if _, errorCode := s.visibleChannel(channelID); errorCode != "" {
+		return nil, errorCode
+	}
+	bookmarks := s.bookmarks[channelID]
+	for i := range bookmarks {
+		if bookmarks[i].ID != r.FormValue("bookmark_id") {
+			continue
+		}
+		if title := r.FormValue("title"); title != "" {
+			bookmarks[i].Title = title
+		}
+		if link := r.FormValue("link"); link != "" {
+			bookmarks[i].Link = link
+		}
+		return map[string]interface{}{"bookmark": bookmarks[i]}, ""
+	}
+	return nil, "not_found"
+}
+
+func (s *Server) listBookmarks(r *http.Request) (interface{}, string) {
+	channelID := r.FormValue("channel_id")
+	if _, errorCode := s.visibleChannel(channelID); errorCode != "" {
+		return nil, errorCode
+	}
+	return map[string]interface{}{"bookmarks": append([]Bookmark{}, s.bookmarks[channelID]...)}, ""
+}
+
+func (s *Server) postMessage(r *http.Request) (interface{}, string) {
+	channelID := r.FormValue("channel")
+	if _, errorCode := s.writableChannel(channelID); errorCode != "" {
+		return nil, errorCode
+	}
+	message := Message{
+		Channel:   channelID,
+		Timestamp: fmt.Sprintf("%d.%06d", time.Now().Unix(), len(s.messages)),
+		Text:      r.FormValue("text"),
+	}
+	s.messages = append(s.messages, message)
+	return map[string]string{"channel": channelID, "ts": message.Timestamp}, ""
+}
+
+func (s *Server) conversationHistory(r *http.Request) (interface{}, string) {
+	channelID := r.FormValue("channel")
+	if _, errorCode := s.visibleChannel(channelID); errorCode != "" {
+		return nil, errorCode
+	}
+	oldest, _ := strconv.ParseFloat(r.FormValue("oldest"), 64)
+
+	// Messages are listed newest first, like Slack does
+	var timestamps []string
+	texts := map[string]string{}
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		message := s.messages[i]
+		if ts, _ := strconv.ParseFloat(message.Timestamp, 64); message.Channel == channelID && ts > oldest {
+			timestamps = append(timestamps, message.Timestamp)
+			texts[message.Timestamp] = message.Text
+		}
+	}
+
+	page, next := paginate(timestamps, r)
+	messages := []slack.Message{}
+	for _, timestamp := range page {
+		messages = append(messages, slack.Message{Msg: slack.Msg{Type: "message", Timestamp: timestamp, Text: texts[timestamp]}})
+	}
+	return map[string]interface{}{
+		"messages":          messages,
+		"has_more":          next != "",
+		"response_metadata": map[string]string{"next_cursor": next},
+	}, ""
+}
+
+func (s *Server) addPin(r *http.Request) (interface{}, string) {
+	channelID := r.FormValue("channel")
+	if _, errorCode := s.visibleChannel(channelID); errorCode != "" {
+		return nil, errorCode
+	}
+	timestamp := r.FormValue("timestamp")
+	for _, message := range s.messages {
+		if message.Channel == channelID && message.Timestamp == timestamp {
+			s.pins[channelID] = append(s.pins[channelID], message)
+			return nil, ""
+		}
+	}
+	return nil, "message_not_found"
+}
+
+func (s *Server) listPins(r *http.Request) (interface{}, string) {
+	channelID := r.FormValue("channel")
+	if _, errorCode := s.visibleChannel(channelID); errorCode != "" {
+		return nil, errorCode
+	}
+	items := []slack.Item{}
+	for _, message := range s.pins[channelID] {
+		items = append(items, slack.NewMessageItem(channelID, &slack.Message{
+			Msg: slack.Msg{Channel: channelID, Timestamp: message.Timestamp, Text: message.Text},
+		}))
+	}
+	return map[string]interface{}{"items": items}, ""
+}
+
+// visibleChannel returns the channel if the bot can see it, private channels are only visible to their members
+func (s *Server) visibleChannel(id string) (*Channel, string) {
+	channel, found := s.channels[id]
+	if !found || (channel.Private && !contains(channel.Members, s.BotUserID)) {
+		return nil, "channel_not_found"
+	}
+	return channel, ""
+}
+
+// writableChannel returns the channel if the bot can change it, archived channels can't be changed
+func (s *Server) writableChannel(id string) (*Channel, string) {
+	channel, errorCode := s.visibleChannel(id)
+	if errorCode != "" {
+		return nil, errorCode
+	}
+	if channel.Archived {
+		return nil, "is_archived"
+	}
+	return channel, ""
+}
+
+// channelByName returns the channel with the name, which Slack compares case insensitively
+func (s *Server) channelByName(name string) *Channel {
+	for _, channel := range s.channels {
+		if strings.EqualFold(channel.Name, name) {
+			return channel
+		}
+	}
+	return nil
+}
+
+// channelResponse is the channel of a response, with the time it was last updated like conversations.info has
+func (s *Server) channelResponse(channel *Channel) map[string]interface{} {
+	data, _ := json.Marshal(s.toSlack(channel))
+	fields := map[string]interface{}{}
+	_ = json.Unmarshal(data, &fields)
+	fields["updated"] = channel.Updated
+	return map[string]interface{}{"channel": fields}
+}
+
+func (s *Server) toSlack(channel *Channel) slack.Channel {
+	converted := slack.Channel{IsChannel: true, IsMember: contains(channel.Members, s.BotUserID)}
+	converted.ID = channel.ID
+	converted.Name = channel.Name
+	converted.NameNormalized = strings.ToLower(channel.Name)
+	converted.IsPrivate = channel.Private
+	converted.IsArchived = channel.Archived
+	converted.Members = append([]string{}, channel.Members...)
+	converted.NumMembers = len(channel.Members)
+	converted.Topic = slack.Topic{Value: channel.Topic}
+	converted.Purpose = slack.Purpose{Value: channel.Description}
+	return converted
+}
+
+func toSlackUser(user *User) slack.User {
+	return slack.User{
+		ID:           user.ID,
+		Name:         user.Name,
+		Deleted:      user.Deleted,
+		IsBot:        user.IsBot,
+		IsRestricted: user.Restricted,
+		Profile:      slack.UserProfile{Email: user.Email, RealName: user.Name},
+	}
+}
+
+// touch records the change of the channel, updated increases with every change
+func (s *Server) touch(channel *Channel) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if now <= channel.Updated {
+		now = channel.Updated + 1
+	}
+	channel.Updated = now
+}
+
+// newID returns a new ID with the prefix
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s%08d", prefix, s.nextID)
+}
+
+// paginate returns the page of the cursor and limit of the request and the cursor of the next page
+func paginate(items []string, r *http.Request) ([]string, string) {
+	offset, _ := strconv.Atoi(r.FormValue("cursor"))
+	limit, err := strconv.Atoi(r.FormValue("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := offset + limit
+	if end >= len(items) {
+		return items[offset:], ""
+	}
+	return items[offset:end], strconv.Itoa(end)
+}
+
+func contains(list []string, item string) bool {
+	for _, element := range list {
+		if element == item {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(list []string, item string) []string {
+	var result []string
+	for _, element := range list {
+		if element != item {
+			result = append(result, element)
+		}
+	}
+	return result
+}
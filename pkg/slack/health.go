@@ -0,0 +1,61 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AuthChecker calls auth.test every period, so AuthCheck reports tokens which were revoked or lost scopes while the
+// operator is running. The result is cached between the calls, readiness probes don't call Slack.
+type AuthChecker struct {
+	service *SlackService
+	period  time.Duration
+}
+
+// NewAuthChecker creates a checker refreshing the auth check of the service every period
+func NewAuthChecker(service *SlackService, period time.Duration) *AuthChecker {
+	return &AuthChecker{service: service, period: period}
+}
+
+// Start calls auth.test every period until the context is cancelled
+func (c *AuthChecker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := c.service.CheckAuth(); err != nil {
+			c.service.log.Error(err, "Slack token failed validation")
+		}
+	}
+}
+
+// NeedLeaderElection checks the token on every replica, each has its own readiness
+func (c *AuthChecker) NeedLeaderElection() bool {
+	return false
+}
+
+// CertificateCheck returns a health check failing when the PEM certificate in file can't be read or is expired
+func CertificateCheck(file string) func(*http.Request) error {
+	return func(_ *http.Request) error {
+		return checkCertificate(file, time.Now())
+	}
+}
+
+func checkCertificate(file string, now time.Time) error {
+	expiry, err := certificateExpiry(file)
+	if err != nil {
+		return fmt.Errorf("certificate can't be read: %v", err)
+	}
+	if !now.Before(expiry) {
+		return fmt.Errorf("certificate expired at %s", expiry.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package slack
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	// historyPageSize is the number of messages fetched per page of conversations.history
+	historyPageSize = 200
+
+	// activitySampleSize is the number of messages counted at most for the activity of a channel
+	activitySampleSize = 1000
+)
+
+// Activity is how active a slack channel is
+type Activity struct {
+	// Members is the number of members
+	Members int
+
+	// LastMessage is the time of the latest message, zero when there is none
+	LastMessage time.Time
+
+	// Messages is the number of messages posted since the given time
+	Messages int
+
+	// Sampled is set when there were more messages than activitySampleSize, which were not counted
+	Sampled bool
+}
+
+type historyResponse struct {
+	slack.SlackResponse
+	Messages         []slack.Message `json:"messages"`
+	HasMore          bool            `json:"has_more"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+// ConversationHistory returns a page of the messages of the channel posted after the oldest timestamp, newest
+// first, and the cursor of the next page. Threads are exported with their parent message only.
+// It needs the channels:history and groups:history scopes.
+func (s *SlackService) ConversationHistory(channelID string, oldest string, cursor string) ([]slack.Message, string, error) {
+	values := url.Values{
+		"channel": {channelID},
+		"limit":   {strconv.Itoa(historyPageSize)},
+	}
+	if oldest != "" {
+		values.Set("oldest", oldest)
+	}
+	if cursor != "" {
+		values.Set("cursor", cursor)
+	}
+
+	response := &historyResponse{}
+	if err := s.callAPI("conversations.history", values, response); err != nil {
+		s.log.Error(err, "Error fetching conversation history", "channelID", channelID)
+		return nil, "", err
+	}
+	if !response.HasMore {
+		return response.Messages, "", nil
+	}
+	return response.Messages, response.ResponseMetadata.NextCursor, nil
+}
+
+// LatestActivity returns the time of the latest message in the channel other than the message with the ignored
+// timestamp, e.g. the operator's inactivity warning. It returns the zero time when the channel has no messages.
+func (s *SlackService) LatestActivity(channelID string, ignoreTimestamp string) (time.Time, error) {
+	values := url.Values{
+		"channel": {channelID},
+		"limit":   {"2"},
+	}
+
+	response := &historyResponse{}
+	if err := s.callAPI("conversations.history", values, response); err != nil {
+		s.log.Error(err, "Error fetching latest message", "channelID", channelID)
+		return time.Time{}, err
+	}
+	for _, message := range response.Messages {
+		if message.Timestamp != ignoreTimestamp {
+			return parseTimestamp(message.Timestamp), nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// parseTimestamp converts a slack message timestamp, seconds and microseconds, into a time
+func parseTimestamp(timestamp string) time.Time {
+	seconds, micros := timestamp, "0"
+	if i := strings.Index(timestamp, "."); i >= 0 {
+		seconds, micros = timestamp[:i], timestamp[i+1:]
+	}
+	sec, _ := strconv.ParseInt(seconds, 10, 64)
+	usec, _ := strconv.ParseInt(micros, 10, 64)
+	return time.Unix(sec, usec*int64(time.Microsecond))
+}
+
+// ChannelActivity counts the members of the channel and the messages posted since the given time, up to
+// activitySampleSize messages, and looks up the time of its latest message
+func (s *SlackService) ChannelActivity(channelID string, since time.Time) (*Activity, error) {
+	members, err := s.GetUsersInChannel(context.Background(), channelID)
+	if err != nil {
+		s.log.Error(err, "Error fetching channel members", "channelID", channelID)
+		return nil, err
+	}
+	activity := &Activity{Members: len(members)}
+
+	oldest := strconv.FormatInt(since.Unix(), 10)
+	var cursor string
+	for {
+		messages, next, err := s.ConversationHistory(channelID, oldest, cursor)
+		if err != nil {
+			return nil, err
+		}
+		if activity.Messages == 0 && len(messages) > 0 {
+			activity.LastMessage = parseTimestamp(messages[0].Timestamp)
+		}
+		activity.Messages += len(messages)
+
+		if next == "" {
+			break
+		}
+		if activity.Messages >= activitySampleSize {
+			activity.Sampled = true
+			break
+		}
+		cursor = next
+	}
+
+	if activity.Messages == 0 {
+		activity.LastMessage, err = s.LatestActivity(channelID, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return activity, nil
+}
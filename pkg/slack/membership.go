@@ -0,0 +1,489 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/slack-go/slack"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/membership"
+)
+
+// inviteBatchSize is the number of users invited with a single conversations.invite call, the API accepts up to 1000
+var inviteBatchSize = 100
+
+// UserKey is what the users listed by Channels are matched with slack users by
+type UserKey string
+
+const (
+	// UserKeyEmail matches users by the email of their profile, the default
+	UserKeyEmail UserKey = "email"
+	// UserKeyID matches users by their user ID
+	UserKeyID UserKey = "id"
+	// UserKeyUsername matches users by their username, which needs the users snapshot
+	UserKeyUsername UserKey = "username"
+)
+
+// ParseUserKey parses the user key of a flag, i.e. email, id or username
+func ParseUserKey(key string) (UserKey, error) {
+	switch userKey := UserKey(strings.ToLower(key)); userKey {
+	case UserKeyEmail, UserKeyID, UserKeyUsername:
+		return userKey, nil
+	}
+	return "", fmt.Errorf("unknown user key %q, it must be email, id or username", key)
+}
+
+// MembershipPlan is the delta between the desired and the actual members of a channel
+type MembershipPlan struct {
+	// Invite are the IDs of the users to invite
+	Invite []string
+	// Remove are the IDs of the members to remove
+	Remove []string
+	// MemberErrors are the listed users who can't be members of the channel
+	MemberErrors []slackv1alpha1.MemberError
+	// Members is the number of members the channel had when the plan was made
+	Members int
+
+	// emails maps the IDs to invite to their entry in the user list, to report member errors
+	emails map[string]string
+	// lookupFailed is set when listed users couldn't be looked up, they may be members who mustn't be removed
+	lookupFailed bool
+}
+
+// Changed checks whether users have to be invited or removed
+func (p *MembershipPlan) Changed() bool {
+	return len(p.Invite) > 0 || len(p.Remove) > 0
+}
+
+// Split splits the invites and removals of the plan into plans of at most size changes each, invites first. The
+// member errors are kept by the first plan.
+func (p *MembershipPlan) Split(size int) []*MembershipPlan {
+	var parts []*MembershipPlan
+	for _, invite := range chunk(p.Invite, size) {
+		parts = append(parts, &MembershipPlan{Invite: invite, emails: p.emails})
+	}
+	for _, remove := range chunk(p.Remove, size) {
+		parts = append(parts, &MembershipPlan{Remove: remove, emails: p.emails})
+	}
+	if len(parts) == 0 {
+		parts = append(parts, &MembershipPlan{emails: p.emails})
+	}
+	parts[0].MemberErrors = p.MemberErrors
+	parts[0].Members = p.Members
+	return parts
+}
+
+// PlanMembership resolves the listed users and compares them with the members of the channel. Emails without a
+// slack account are retried with their aliases. Users who don't exist, are deactivated or are guests who can't join
+// the channel are reported as member errors. Members who aren't listed are removed unless they are integrations or
+// protected users. Members who hide their email aren't removed while listed emails can't be found, they may be one
+// of them. Users and members who can't be looked up are reported as member errors as well and no members are removed
+// while listed users can't be looked up, only errors which concern the whole channel fail the plan.
+func (s *SlackService) PlanMembership(ctx context.Context, channelID string, userEmails []string, aliases map[string][]string, protectedUsers []string) (*MembershipPlan, error) {
+	log := s.log.WithValues("channelID", channelID)
+
+	plan := &MembershipPlan{emails: map[string]string{}}
+	desired, guests, unresolvedEmails, err := s.resolveMembers(ctx, log, plan, userEmails, aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	// The members are compared page by page, so channels with many members are never held at once
+	diff := membership.NewStreamingDiff(desired)
+	err = s.forEachMembersPage(ctx, channelID, func(page []string) error {
+		// Only members who aren't listed are fetched, to keep integrations and protected users
+		for _, userID := range diff.Page(page) {
+			user, err := s.getUserInfo(ctx, userID)
+			if err != nil && isMemberFailure(err) {
+				// Members who can't be looked up may be integrations or protected users, they are kept
+				log.Error(err, "Error fetching member, keeping it", "userID", userID)
+				plan.MemberErrors = append(plan.MemberErrors, slackv1alpha1.MemberError{User: UserIDPrefix + userID, Reason: MemberLookupFailed})
+				continue
+			} else if err != nil {
+				log.Error(err, "Error fetching user info")
+				return err
+			}
+
+			if unresolvedEmails && user.Profile.Email == "" && !s.isIntegration(user) {
+				log.Info("Keeping member with a hidden email, listed emails weren't found", "userID", userID)
+				continue
+			}
+			if !s.isIntegration(user) && !s.isProtectedMember(user, protectedUsers) {
+				plan.Remove = append(plan.Remove, userID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Error getting users in a conversation")
+		return nil, err
+	}
+	if plan.lookupFailed && len(plan.Remove) > 0 {
+		log.Info("Keeping members who aren't listed, listed users couldn't be looked up", "members", len(plan.Remove))
+		plan.Remove = nil
+	}
+	plan.Members = diff.Scanned()
+	plan.invite(log, diff.Missing(), guests)
+
+	return plan, nil
+}
+
+// PlanPolicyMembership plans the invites of the mandatory users who aren't members of the channel yet and the
+// removal of the forbidden users who are, other members are left alone. Forbidden users who can't be found aren't
+// members of any channel and are skipped.
+func (s *SlackService) PlanPolicyMembership(ctx context.Context, channelID string, mandatory []string, forbidden []string, aliases map[string][]string) (*MembershipPlan, error) {
+	log := s.log.WithValues("channelID", channelID)
+
+	plan := &MembershipPlan{emails: map[string]string{}}
+	desired, guests, _, err := s.resolveMembers(ctx, log, plan, mandatory, aliases)
+	if err != nil {
+		return nil, err
+	}
+
+	forbiddenIDs := map[string]bool{}
+	for _, entry := range forbidden {
+		user, reason, err := s.lookupMember(ctx, entry, aliases[entry])
+		if err != nil && isMemberFailure(err) {
+			log.Error(err, "Error fetching forbidden user, skipping it", "user", entry)
+			plan.MemberErrors = append(plan.MemberErrors, slackv1alpha1.MemberError{User: entry, Reason: MemberLookupFailed})
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("Error fetching user %s: %w", entry, err)
+		}
+		if reason == "" {
+			forbiddenIDs[user.ID] = true
+		}
+	}
+
+	diff := membership.NewStreamingDiff(desired)
+	err = s.forEachMembersPage(ctx, channelID, func(page []string) error {
+		diff.Page(page)
+		for _, userID := range page {
+			if forbiddenIDs[userID] {
+				plan.Remove = append(plan.Remove, userID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Error getting users in a conversation")
+		return nil, err
+	}
+	plan.Members = diff.Scanned()
+	plan.invite(log, diff.Missing(), guests)
+
+	return plan, nil
+}
+
+// resolveMembers looks up the listed users, users who can't be members are added to the member errors of the plan.
+// It returns the IDs of the users along with the guests among them and whether emails weren't found.
+func (s *SlackService) resolveMembers(ctx context.Context, log logr.Logger, plan *MembershipPlan, userEmails []string, aliases map[string][]string) ([]string, map[string]bool, bool, error) {
+	var desired []string
+	// guests are only reported when they'd have to be invited, guests who are members already are kept
+	guests := map[string]bool{}
+	unresolvedEmails := false
+	for _, email := range userEmails {
+		user, reason, err := s.lookupMember(ctx, email, aliases[email])
+		if err != nil && isMemberFailure(err) {
+			log.Error(err, "Error fetching user, skipping it", "email", email)
+			reason = MemberLookupFailed
+			plan.lookupFailed = true
+		} else if err != nil {
+			log.Error(err, fmt.Sprintf("Error fetching user by Email %s", email))
+			return nil, nil, false, fmt.Errorf("Error fetching user by Email %s: %w", email, err)
+		}
+		if reason == MemberNotFound && s.matchesByEmail(email) {
+			unresolvedEmails = true
+		}
+		if reason != "" {
+			log.Info("Skipping user", "email", email, "reason", reason)
+			plan.MemberErrors = append(plan.MemberErrors, slackv1alpha1.MemberError{User: email, Reason: reason})
+			continue
+		}
+
+		desired = append(desired, user.ID)
+		if plan.emails[user.ID] == "" {
+			plan.emails[user.ID] = email
+		}
+		if user.IsRestricted || user.IsUltraRestricted {
+			guests[user.ID] = true
+		}
+	}
+	return desired, guests, unresolvedEmails, nil
+}
+
+// invite plans the invites of the missing users, guests are reported as member errors instead
+func (p *MembershipPlan) invite(log logr.Logger, missing []string, guests map[string]bool) {
+	for _, userID := range missing {
+		if guests[userID] {
+			log.Info("Skipping user", "email", p.emails[userID], "reason", MemberRestricted)
+			p.MemberErrors = append(p.MemberErrors, slackv1alpha1.MemberError{User: p.emails[userID], Reason: MemberRestricted})
+			continue
+		}
+		p.Invite = append(p.Invite, userID)
+	}
+}
+
+// ApplyMembership invites and removes the users of the plan, users are invited in batches of inviteBatchSize.
+// The member errors of the plan are returned along with guests who turned out not to be able to join the channel and
+// users who failed to be invited or removed. The errors returned concern the whole channel, e.g. a rate limit. Once
+// ctx is done the changes left are skipped and its error is returned.
+func (s *SlackService) ApplyMembership(ctx context.Context, channelID string, plan *MembershipPlan) ([]slackv1alpha1.MemberError, []error) {
+	log := s.log.WithValues("channelID", channelID)
+
+	memberErrors := append([]slackv1alpha1.MemberError{}, plan.MemberErrors...)
+	var errorlist []error
+
+	for _, batch := range chunk(plan.Invite, inviteBatchSize) {
+		if ctx.Err() != nil {
+			return memberErrors, append(errorlist, ctx.Err())
+		}
+
+		log.V(1).Info("Inviting users to Slack Channel", "userIDs", batch)
+		_, err := s.primary().InviteUsersToConversationContext(ctx, channelID, batch...)
+		if err == nil {
+			s.audit(AuditInvite, channelID, "", batch...)
+			continue
+		}
+
+		// The whole batch fails if one user can't be invited, so the users are retried one by one to find them
+		log.V(1).Info("Error inviting batch of users, inviting them one by one", "error", err.Error())
+		for _, userID := range batch {
+			if ctx.Err() != nil {
+				return memberErrors, append(errorlist, ctx.Err())
+			}
+			memberError, err := s.inviteUser(ctx, channelID, userID, plan.emails[userID])
+			if memberError != nil {
+				memberErrors = append(memberErrors, *memberError)
+			}
+			if err != nil {
+				errorlist = append(errorlist, err)
+			}
+		}
+	}
+
+	for _, userID := range plan.Remove {
+		if ctx.Err() != nil {
+			return memberErrors, append(errorlist, ctx.Err())
+		}
+		_, err := s.kickUser(ctx, channelID, userID)
+		if err != nil && isMemberFailure(err) {
+			memberErrors = append(memberErrors, slackv1alpha1.MemberError{User: UserIDPrefix + userID, Reason: MemberRemoveFailed})
+		} else if err != nil {
+			errorlist = append(errorlist, err)
+		}
+	}
+
+	return memberErrors, errorlist
+}
+
+// inviteUser invites a single user to the slack channel, a member error is returned for guests who can't join it
+func (s *SlackService) inviteUser(ctx context.Context, channelID string, userID string, email string) (*slackv1alpha1.MemberError, error) {
+	log := s.log.WithValues("channelID", channelID, "userID", userID)
+
+	_, err := s.primary().InviteUsersToConversationContext(ctx, channelID, userID)
+	err = wrapError(err)
+	if errors.Is(err, ErrUserRestricted) {
+		log.Info("Skipping restricted user", "email", email, "error", err.Error())
+		return &slackv1alpha1.MemberError{User: email, Reason: MemberRestricted}, nil
+	} else if err != nil && !errors.Is(err, ErrAlreadyInChannel) {
+		log.Error(err, "Error Inviting user to channel")
+		if isMemberFailure(err) {
+			return &slackv1alpha1.MemberError{User: email, Reason: MemberInviteFailed}, nil
+		}
+		return nil, err
+	} else if err == nil {
+		s.audit(AuditInvite, channelID, "", userID)
+	}
+
+	return nil, nil
+}
+
+// KickUser removes a single user from the slack channel, false is returned if the user wasn't a member
+func (s *SlackService) KickUser(channelID string, userID string) (bool, error) {
+	return s.kickUser(context.Background(), channelID, userID)
+}
+
+// kickUser removes the user like KickUser, the call is cancelled once ctx is done
+func (s *SlackService) kickUser(ctx context.Context, channelID string, userID string) (bool, error) {
+	log := s.log.WithValues("channelID", channelID, "userID", userID)
+
+	log.V(1).Info("Removing user from Slack Channel")
+
+	err := wrapError(s.primary().KickUserFromConversationContext(ctx, channelID, userID))
+	if err != nil {
+		if errors.Is(err, ErrNotInChannel) {
+			return false, nil
+		}
+		log.Error(err, "Error removing user from the conversation")
+		return false, err
+	}
+	s.audit(AuditKick, channelID, "", userID)
+
+	return true, nil
+}
+
+// memberErrorCodes are the error codes of calls for a single user which only concern that user. Any other error, e.g.
+// a rate limit, a 5xx or an unknown code, may concern the whole channel, the token or Slack and fails the call.
+var memberErrorCodes = map[string]bool{
+	"user_not_found":   true,
+	"users_not_found":  true,
+	"user_not_visible": true,
+	"cant_invite":      true,
+	"cant_kick_self":   true,
+}
+
+// isMemberFailure checks whether the error of a call for a single user only concerns that user, e.g.
+// user_not_visible, so the other users are still invited or removed
+func isMemberFailure(err error) bool {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return memberErrorCodes[apiError.Code]
+	}
+	return memberErrorCodes[err.Error()]
+}
+
+// chunk splits the list into chunks of at most size items
+func chunk(list []string, size int) [][]string {
+	var chunks [][]string
+	for len(list) > size {
+		chunks = append(chunks, list[:size])
+		list = list[size:]
+	}
+	if len(list) > 0 {
+		chunks = append(chunks, list)
+	}
+	return chunks
+}
+
+// lookupMember fetches the user by the user key of the service, or by ID for entries with the id: prefix. Emails fall
+// back through their aliases. The reason is set instead when the user can't be a member of any channel.
+func (s *SlackService) lookupMember(ctx context.Context, entry string, aliases []string) (*slack.User, string, error) {
+	var user *slack.User
+	var err error
+	switch {
+	case strings.HasPrefix(entry, UserIDPrefix):
+		user, err = s.getUserInfo(ctx, strings.TrimPrefix(entry, UserIDPrefix))
+	case s.userKey == UserKeyID:
+		user, err = s.getUserInfo(ctx, entry)
+	case s.userKey == UserKeyUsername:
+		user, err = s.getUserByName(entry)
+	default:
+		user, err = s.getUserByEmail(ctx, entry)
+		for i := 0; errors.Is(err, ErrUserNotFound) && i < len(aliases); i++ {
+			user, err = s.getUserByEmail(ctx, aliases[i])
+		}
+	}
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, MemberNotFound, nil
+		}
+		return nil, "", err
+	}
+
+	if user.Deleted {
+		return nil, MemberDeactivated, nil
+	}
+
+	return user, "", nil
+}
+
+// isIntegration checks whether the user is a bot, app or workflow user, or is in the bot allowlist. Integrations
+// are never removed from channels so that membership enforcement doesn't break them.
+func (s *SlackService) isIntegration(user *slack.User) bool {
+	if user.IsBot || user.IsAppUser || user.ID == SlackbotUserID {
+		return true
+	}
+	if user.Profile.BotID != "" || user.Profile.ApiAppID != "" {
+		return true
+	}
+
+	for _, allowed := range s.botAllowlist {
+		if allowed == user.ID || allowed == user.Name || allowed == user.Profile.ApiAppID {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesByEmail checks whether the listed user is looked up by email
+func (s *SlackService) matchesByEmail(entry string) bool {
+	return !strings.HasPrefix(entry, UserIDPrefix) && (s.userKey == "" || s.userKey == UserKeyEmail)
+}
+
+// isProtectedMember checks whether the user is a protected user, protected users are listed by username as well if
+// users are matched by username
+func (s *SlackService) isProtectedMember(user *slack.User, protectedUsers []string) bool {
+	if isProtected(user, protectedUsers) {
+		return true
+	}
+	if s.userKey != UserKeyUsername {
+		return false
+	}
+	for _, protected := range protectedUsers {
+		if strings.TrimPrefix(protected, "@") == user.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// isProtected checks whether the user is listed by email or ID in the protected users
+func isProtected(user *slack.User, protectedUsers []string) bool {
+	for _, protected := range protectedUsers {
+		if protected == user.ID || strings.EqualFold(protected, user.Profile.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// Kinds of channel members reported by ListMembers
+const (
+	MemberKindMember      = "Member"
+	MemberKindGuest       = "Guest"
+	MemberKindIntegration = "Integration"
+	MemberKindDeactivated = "Deactivated"
+)
+
+// ChannelMember is a member of a slack channel
+type ChannelMember struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	// Kind is one of Member, Guest, Integration or Deactivated
+	Kind string `json:"kind"`
+}
+
+// ListMembers returns the members of the channel with their kind, e.g. to audit who has access to it
+func (s *SlackService) ListMembers(channelID string) ([]ChannelMember, error) {
+	userIDs, err := s.GetUsersInChannel(context.Background(), channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]ChannelMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := s.getUserInfo(context.Background(), userID)
+		if err != nil {
+			s.log.Error(err, "Error fetching user info", "userID", userID)
+			return nil, err
+		}
+
+		member := ChannelMember{ID: user.ID, Name: user.Name, Email: user.Profile.Email, Kind: MemberKindMember}
+		switch {
+		case user.Deleted:
+			member.Kind = MemberKindDeactivated
+		case s.isIntegration(user):
+			member.Kind = MemberKindIntegration
+		case user.IsRestricted || user.IsUltraRestricted:
+			member.Kind = MemberKindGuest
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
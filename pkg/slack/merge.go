@@ -0,0 +1,41 @@
+package slack
+
+// ChannelMergeResult is what was moved from the source channel of a merge to the target channel
+type ChannelMergeResult struct {
+	// Members are the user IDs of the members of the source who joined the target, integrations and deactivated
+	// users are left out
+	Members []string
+
+	// Pins is the number of pinned messages which were copied
+	Pins int
+}
+
+// MergeChannel invites the members of the source channel to the target channel and, with copyPins, reposts the
+// messages pinned in the source to the target and pins them there. Members who are already in the target and pins
+// the target already has are skipped, so a failed merge can be retried. The source is left as it is, posting the
+// redirect message and archiving it is up to the caller.
+func MergeChannel(service Service, sourceID string, targetID string, copyPins bool) (*ChannelMergeResult, error) {
+	result := &ChannelMergeResult{}
+
+	members, err := service.ListMembers(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []string
+	for _, member := range members {
+		if member.Kind == MemberKindMember || member.Kind == MemberKindGuest {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+	if result.Members, err = inviteMembers(service, targetID, userIDs); err != nil {
+		return nil, err
+	}
+
+	if copyPins {
+		if result.Pins, err = clonePins(service, sourceID, targetID); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
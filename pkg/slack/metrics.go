@@ -0,0 +1,61 @@
+package slack
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// enforcementActions counts the changes the operator made to slack channels
+var enforcementActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "slack_operator_enforcement_actions_total",
+	Help: "Number of changes the operator made to Slack channels by action, e.g. Invite, Kick or Rename. Invites and kicks count every user.",
+}, []string{"action"})
+
+func init() {
+	metrics.Registry.MustRegister(enforcementActions)
+}
+
+// AuditMetrics counts the audit events as enforcement actions
+type AuditMetrics struct{}
+
+// Record counts the event, events with users count once per user
+func (AuditMetrics) Record(event AuditEvent) {
+	count := 1
+	if len(event.Users) > 0 {
+		count = len(event.Users)
+	}
+	enforcementActions.WithLabelValues(string(event.Action)).Add(float64(count))
+}
+
+// rateLimitCollector reports the rate limit budget of a service on scrape
+type rateLimitCollector struct {
+	service *SlackService
+	desc    *prometheus.Desc
+}
+
+// RegisterRateLimitGauge exposes how many more calls of every called Web API method the service has within the
+// current minute
+func RegisterRateLimitGauge(service *SlackService) {
+	collector := &rateLimitCollector{
+		service: service,
+		desc: prometheus.NewDesc("slack_operator_rate_limit_remaining",
+			"Number of calls of a Slack Web API method the operator's tokens have left within the current minute", []string{"method"}, nil),
+	}
+	if err := metrics.Registry.Register(collector); err != nil {
+		if _, registered := err.(prometheus.AlreadyRegisteredError); !registered {
+			panic(err)
+		}
+	}
+}
+
+// Describe describes the gauge
+func (c *rateLimitCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect reports the budget of every called method
+func (c *rateLimitCollector) Collect(ch chan<- prometheus.Metric) {
+	for method, remaining := range c.service.RateLimitBudget() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(remaining), method)
+	}
+}
@@ -15,7 +15,10 @@ var InvalidChannelName = "#$%^^^&)$#(!($&!#KHLREJOIWQRHQOIWRHQWRIOWQIHEIUWQ BE&#
 var PublicConversationID = "C0EAQDV4Z"
 var PrivateConversationID = "Y7HGFWC6Q"
 var NotFoundConversationID = "-"
+var ArchiveFailingConversationID = "C0ARCHFAIL"
 var NotFoundUserID = "-"
+var NotVisibleUserEmail = "hidden@example.com"
+var InternalErrorUserEmail = "outage@example.com"
 var BotID = "U023BECGF"
 var Description = "My channel Description"
 
@@ -55,6 +58,7 @@ var templateChannelJSON = `
 		"last_set": %d
 	},
 	"num_members": %d,
+	"updated": 1600000000000,
 	"previous_names": [],
 	"priority": 0
 }`
@@ -132,6 +136,91 @@ func kickUserFromConversationSuccessResponse() string {
 	}`
 }
 
+var MessageTimestamp = "1503435956.000247"
+
+func getPostMessageResponse(channelID string) string {
+	return fmt.Sprintf(`{
+		"ok": true,
+		"channel": "%s",
+		"ts": "%s"
+	}`, channelID, MessageTimestamp)
+}
+
+var FileID = "F0123ABCDEF"
+
+var okJSON = `{"ok": true}`
+
+var fileNotFoundJSON = `{"ok": false, "error": "file_not_found"}`
+
+func getUploadURLExternalResponse(uploadURL string) string {
+	return fmt.Sprintf(`{
+		"ok": true,
+		"upload_url": "%s",
+		"file_id": "%s"
+	}`, uploadURL, FileID)
+}
+
+var CanvasID = "F0456CANVAS"
+
+var canvasNotFoundJSON = `{"ok": false, "error": "canvas_not_found"}`
+
+func getCreateCanvasResponse() string {
+	return fmt.Sprintf(`{"ok": true, "canvas_id": "%s"}`, CanvasID)
+}
+
+var ReminderID = "Rm12345678"
+
+var reminderNotFoundJSON = `{"ok": false, "error": "not_found"}`
+
+func getAddReminderResponse() string {
+	return fmt.Sprintf(`{
+		"ok": true,
+		"reminder": {
+			"id": "%s",
+			"creator": "U18888888",
+			"user": "U18888888",
+			"text": "Standup",
+			"recurring": true,
+			"time": 1602288000,
+			"complete_ts": 0
+		}
+	}`, ReminderID)
+}
+
+var ExistingEmoji = "partyparrot"
+var NotFoundEmoji = "-"
+
+var listEmojiJSON = `{
+	"ok": true,
+	"emoji": {
+		"partyparrot": "https://emoji.slack-edge.com/T0123456/partyparrot/1a2b3c.gif",
+		"parrot": "alias:partyparrot"
+	}
+}`
+
+var emojiNotFoundJSON = `{"ok": false, "error": "emoji_not_found"}`
+
+var AppID = "A0123456789"
+var AppClientID = "1234567890.1234567890"
+
+var createAppJSON = fmt.Sprintf(`{
+	"ok": true,
+	"app_id": "%s",
+	"credentials": {
+		"client_id": "%s",
+		"client_secret": "b2c3d4",
+		"verification_token": "c3d4e5",
+		"signing_secret": "d4e5f6"
+	},
+	"oauth_authorize_url": "https://slack.com/oauth/v2/authorize?client_id=%s"
+}`, AppID, AppClientID, AppClientID)
+
+var invalidManifestJSON = `{
+	"ok": false,
+	"error": "invalid_manifest",
+	"errors": [{"message": "must have required property 'display_information'", "pointer": "/"}]
+}`
+
 const ExistingUserEmail = "iamuser@slack.com"
 
 var templateUserJSON = `
@@ -189,6 +278,58 @@ var membersInConversationJSON = `
 	}
 }`
 
+var membersInConversationLastPageJSON = `
+{
+	"ok": true,
+	"members": [
+		"U0G9QF9C6"
+	],
+	"response_metadata": {
+		"next_cursor": ""
+	}
+}`
+
+// SnapshotUserID is the ID of a user returned by users.list only
+var SnapshotUserID = "U0SNAPSHOT"
+
+var listUsersJSON = fmt.Sprintf(`
+{
+	"ok": true,
+	"members": [
+		{
+			"id": "%s",
+			"name": "snapshot",
+			"is_bot": true,
+			"profile": {
+				"email": "snapshot@slack.com"
+			}
+		}
+	],
+	"response_metadata": {
+		"next_cursor": ""
+	}
+}`, SnapshotUserID)
+
+// ManagedByOwner is the Channel in the managed-by bookmark of the public channel
+var ManagedByOwner = "default/bat-channel"
+
+var managedByBookmarksJSON = `
+{
+	"ok": true,
+	"bookmarks": [
+		{
+			"id": "Bk01",
+			"title": "Runbook",
+			"link": "https://example.com/runbook"
+		},
+		{
+			"id": "Bk02",
+			"title": "Managed by slack-operator",
+			"link": "https://github.com/stakater/slack-operator?owner=default%2Fbat-channel"
+		}
+	]
+}`
+
 var userNotFoundJSON = `
 {
     "ok": false,
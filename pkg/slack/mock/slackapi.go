@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/slack-go/slack/slacktest"
 )
@@ -42,9 +43,87 @@ func InitSlackTestServer() *slacktest.Server {
 		func(c slacktest.Customize) {
 			c.Handle("/conversations.members", getMembersInConversationHandler)
 		},
+		func(c slacktest.Customize) {
+			c.Handle("/users.list", listUsersHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/conversations.join", joinConversationHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/bookmarks.list", listBookmarksHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/bookmarks.add", addBookmarkHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.conversations.invite", adminInviteConversationHandler)
+		},
 		func(c slacktest.Customize) {
 			c.Handle("/conversations.kick", kickMemberFromConversationHandler)
 		},
+		func(c slacktest.Customize) {
+			c.Handle("/chat.postMessage", postMessageHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/chat.update", postMessageHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/files.getUploadURLExternal", getUploadURLExternalHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/files.upload", fileUploadHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/files.completeUploadExternal", completeUploadExternalHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/files.delete", deleteFileHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/conversations.canvases.create", createChannelCanvasHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/canvases.edit", editCanvasHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/reminders.add", addReminderHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/reminders.delete", deleteReminderHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/emoji.list", listEmojiHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.emoji.add", emojiHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/emoji.add", uploadEmojiHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.emoji.remove", emojiHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.emoji.rename", emojiHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/apps.manifest.create", createAppHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/apps.manifest.update", appHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/apps.manifest.delete", appHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.users.invite", inviteUserToWorkspaceHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.users.remove", workspaceUserHandler)
+		},
+		func(c slacktest.Customize) {
+			c.Handle("/admin.users.setExpiration", workspaceUserHandler)
+		},
 	)
 
 	return testServer
@@ -109,6 +188,8 @@ func archiveConversationHandler(w http.ResponseWriter, r *http.Request) {
 	response := ""
 	if channelID == NotFoundConversationID {
 		response = getConversationArchiveChannelNotFoundRespose()
+	} else if channelID == ArchiveFailingConversationID {
+		response = `{"ok": false, "error": "restricted_action"}`
 	} else {
 		response = getConversationArchiveResponse()
 	}
@@ -120,13 +201,76 @@ func inviteConversationHandler(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(inviteConversationJSON))
 }
 
+// handle conversations.join, only public channels can be joined
+func joinConversationHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel")
+
+	response := ""
+	if channelID == PublicConversationID {
+		response = publicConversationJSON
+	} else if channelID == PrivateConversationID {
+		response = `{"ok": false, "error": "method_not_supported_for_channel_type"}`
+	} else {
+		response = getConversationNotFoundResponse()
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle bookmarks.list, the public channel is managed by ManagedByOwner
+func listBookmarksHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel_id")
+
+	response := ""
+	if channelID == PublicConversationID {
+		response = managedByBookmarksJSON
+	} else if channelID == NotFoundConversationID {
+		response = getConversationNotFoundResponse()
+	} else {
+		response = `{"ok": true, "bookmarks": []}`
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle bookmarks.add
+func addBookmarkHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel_id")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = getConversationNotFoundResponse()
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle admin.conversations.invite
+func adminInviteConversationHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel_id")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = getConversationNotFoundResponse()
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
 // handle conversations.members
 func getMembersInConversationHandler(w http.ResponseWriter, r *http.Request) {
 	channelID := extractParamValue(r, "channel")
+	cursor := extractParamValue(r, "cursor")
 
 	response := ""
 	if channelID == NotFoundConversationID {
 		response = getConversationNotFoundResponse()
+	} else if cursor != "" {
+		response = membersInConversationLastPageJSON
 	} else {
 		response = getMembersInConversationResponse()
 	}
@@ -151,6 +295,204 @@ func kickMemberFromConversationHandler(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(response))
 }
 
+// handle chat.postMessage and chat.update
+func postMessageHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = getConversationNotFoundResponse()
+	} else {
+		response = getPostMessageResponse(channelID)
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle files.getUploadURLExternal, the content is uploaded to the files.upload handler of this server
+func getUploadURLExternalHandler(w http.ResponseWriter, r *http.Request) {
+	uploadURL := fmt.Sprintf("http://%s%s", r.Host, strings.TrimSuffix(r.URL.Path, "files.getUploadURLExternal")+"files.upload")
+	_, _ = w.Write([]byte(getUploadURLExternalResponse(uploadURL)))
+}
+
+// handle uploads to the URL returned by files.getUploadURLExternal
+func fileUploadHandler(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("OK"))
+}
+
+// handle files.completeUploadExternal
+func completeUploadExternalHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel_id")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = getConversationNotFoundResponse()
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle files.delete
+func deleteFileHandler(w http.ResponseWriter, r *http.Request) {
+	fileID := extractParamValue(r, "file")
+
+	response := ""
+	if fileID == FileID {
+		response = okJSON
+	} else {
+		response = fileNotFoundJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle conversations.canvases.create
+func createChannelCanvasHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel_id")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = getConversationNotFoundResponse()
+	} else {
+		response = getCreateCanvasResponse()
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle canvases.edit
+func editCanvasHandler(w http.ResponseWriter, r *http.Request) {
+	canvasID := extractParamValue(r, "canvas_id")
+
+	response := ""
+	if canvasID == CanvasID {
+		response = okJSON
+	} else {
+		response = canvasNotFoundJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle reminders.add
+func addReminderHandler(w http.ResponseWriter, r *http.Request) {
+	channelID := extractParamValue(r, "channel")
+
+	response := ""
+	if channelID == NotFoundConversationID {
+		response = getConversationNotFoundResponse()
+	} else {
+		response = getAddReminderResponse()
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle reminders.delete
+func deleteReminderHandler(w http.ResponseWriter, r *http.Request) {
+	reminderID := extractParamValue(r, "reminder")
+
+	response := ""
+	if reminderID == ReminderID {
+		response = okJSON
+	} else {
+		response = reminderNotFoundJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle emoji.list
+func listEmojiHandler(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(listEmojiJSON))
+}
+
+// handle admin.emoji.add, admin.emoji.remove and admin.emoji.rename
+func emojiHandler(w http.ResponseWriter, r *http.Request) {
+	name := extractParamValue(r, "name")
+
+	response := ""
+	if name == NotFoundEmoji {
+		response = emojiNotFoundJSON
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle emoji.add
+func uploadEmojiHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil || r.FormValue("name") == "" {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_form_data"}`))
+		return
+	}
+	_, _ = w.Write([]byte(okJSON))
+}
+
+// handle apps.manifest.create
+func createAppHandler(w http.ResponseWriter, r *http.Request) {
+	manifest := extractParamValue(r, "manifest")
+
+	response := ""
+	if manifest == "" || manifest == url.QueryEscape("{}") {
+		response = invalidManifestJSON
+	} else {
+		response = createAppJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle apps.manifest.update and apps.manifest.delete
+func appHandler(w http.ResponseWriter, r *http.Request) {
+	appID := extractParamValue(r, "app_id")
+
+	response := ""
+	if appID == AppID {
+		response = okJSON
+	} else {
+		response = `{"ok": false, "error": "invalid_app_id"}`
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle admin.users.invite
+func inviteUserToWorkspaceHandler(w http.ResponseWriter, r *http.Request) {
+	email := extractParamValue(r, "email")
+
+	response := ""
+	if email == url.QueryEscape(ExistingUserEmail) {
+		response = `{"ok": false, "error": "already_in_team"}`
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle admin.users.remove and admin.users.setExpiration
+func workspaceUserHandler(w http.ResponseWriter, r *http.Request) {
+	userID := extractParamValue(r, "user_id")
+
+	response := ""
+	if userID == NotFoundUserID {
+		response = `{"ok": false, "error": "user_not_found"}`
+	} else {
+		response = okJSON
+	}
+
+	_, _ = w.Write([]byte(response))
+}
+
+// handle users.list
+func listUsersHandler(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(listUsersJSON))
+}
+
 // handle users.lookupByEmail
 func usersLookupByEmailHandler(w http.ResponseWriter, r *http.Request) {
 	email := extractParamValue(r, "email")
@@ -158,6 +500,10 @@ func usersLookupByEmailHandler(w http.ResponseWriter, r *http.Request) {
 	userJSON := ""
 	if email == url.QueryEscape(ExistingUserEmail) {
 		userJSON = fmt.Sprintf(templateUserJSON, ExistingUserEmail)
+	} else if email == url.QueryEscape(NotVisibleUserEmail) {
+		userJSON = `{"ok": false, "error": "user_not_visible"}`
+	} else if email == url.QueryEscape(InternalErrorUserEmail) {
+		userJSON = `{"ok": false, "error": "internal_error"}`
 	} else {
 		userJSON = userNotFoundJSON
 	}
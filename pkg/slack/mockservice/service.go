@@ -0,0 +1,722 @@
+// Package mockservice provides a programmable test double of the slack Service, for unit testing code which
+// uses the service layer without a Slack API
+package mockservice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	slackservice "github.com/stakater/slack-operator/pkg/slack"
+)
+
+// Call is a recorded call of a method of the Service
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Service implements the slack Service. The behavior of each method is programmed with its Func field, e.g.
+// SetTopicFunc, methods whose Func is nil succeed with zero values or the changed channel. All calls are recorded.
+type Service struct {
+	CreateChannelFunc           func(string, bool) (*string, error)
+	SetDescriptionFunc          func(string, string) (*slack.Channel, error)
+	SetTopicFunc                func(string, string) (*slack.Channel, error)
+	RenameChannelFunc           func(string, string) (*slack.Channel, error)
+	ArchiveChannelFunc          func(string) error
+	PlanMembershipFunc          func(string, []string, map[string][]string, []string) (*slackservice.MembershipPlan, error)
+	PlanPolicyMembershipFunc    func(string, []string, []string, map[string][]string) (*slackservice.MembershipPlan, error)
+	ApplyMembershipFunc         func(string, *slackservice.MembershipPlan) ([]slackv1alpha1.MemberError, []error)
+	KickUserFunc                func(string, string) (bool, error)
+	GetChannelFunc              func(string) (*slack.Channel, error)
+	GetUsersInChannelFunc       func(string) ([]string, error)
+	GetChannelCRFromChannelFunc func(*slack.Channel) *slackv1alpha1.Channel
+	SyncChannelMetadataFunc     func(string, *slackv1alpha1.Channel) ([]slackservice.FieldChange, error)
+	IsValidChannelFunc          func(*slackv1alpha1.Channel) error
+	GetChannelByNameFunc        func(string) (*slack.Channel, error)
+	UnArchiveChannelFunc        func(*slack.Channel) error
+	PostMessageFunc             func(string, ...slack.MsgOption) (string, error)
+	UpdateMessageFunc           func(string, string, ...slack.MsgOption) error
+	UploadFileFunc              func(string, string, string, []byte) (string, error)
+	DeleteFileFunc              func(string) error
+	CreateChannelCanvasFunc     func(string, string) (string, error)
+	EditCanvasFunc              func(string, string) error
+	AddReminderFunc             func(string, string, string, string) (string, error)
+	DeleteReminderFunc          func(string) error
+	ListEmojiFunc               func() (map[string]string, error)
+	AddEmojiFunc                func(string, string) error
+	UploadEmojiFunc             func(string, []byte) error
+	RemoveEmojiFunc             func(string) error
+	RenameEmojiFunc             func(string, string) error
+	CreateAppFunc               func(string, []byte) (*slackservice.AppCredentials, error)
+	UpdateAppFunc               func(string, string, []byte) error
+	DeleteAppFunc               func(string, string) error
+	InviteUserToWorkspaceFunc   func(slackservice.WorkspaceInvite) error
+	GetTeamIDFunc               func() (string, error)
+	LookupUserByEmailFunc       func(string) (*slack.User, error)
+	RemoveUserFromWorkspaceFunc func(string, string) error
+	SetUserExpirationFunc       func(string, string, int64) error
+	GetChannelUpdatedFunc       func(string) (int64, error)
+	RejoinChannelFunc           func(string) error
+	GetManagedByFunc            func(string) (string, error)
+	AddManagedByMarkerFunc      func(string, string) error
+	ListMembersFunc             func(string) ([]slackservice.ChannelMember, error)
+	ListBookmarksFunc           func(string) ([]slackservice.Bookmark, error)
+	ListPinsFunc                func(string) ([]slackservice.Pin, error)
+	AddBookmarkFunc             func(string, slackservice.Bookmark) error
+	AddPinFunc                  func(string, string) error
+	ConversationHistoryFunc     func(string, string, string) ([]slack.Message, string, error)
+	LatestActivityFunc          func(string, string) (time.Time, error)
+	ChannelActivityFunc         func(string, time.Time) (*slackservice.Activity, error)
+	GetRetentionFunc            func(string) (int, error)
+	SetRetentionFunc            func(string, int) error
+	ConvertChannelFunc          func(string, bool) error
+	GetDefaultChannelsFunc      func(string) ([]string, error)
+	SetDefaultChannelsFunc      func(string, []string) error
+	ListBarriersFunc            func() ([]slackservice.Barrier, error)
+	CreateBarrierFunc           func(slackservice.Barrier) (string, error)
+	UpdateBarrierFunc           func(slackservice.Barrier) error
+	DeleteBarrierFunc           func(string) error
+	ListUsergroupChannelsFunc   func(string) ([]string, error)
+	AddUsergroupChannelsFunc    func(string, []string) error
+	RemoveUsergroupChannelsFunc func(string, []string) error
+	AddUsergroupTeamsFunc       func(string, []string) error
+	GetSectionFunc              func(string) (string, error)
+	SetSectionFunc              func(string, string, string) error
+
+	mu     sync.Mutex
+	calls  []Call
+	nextID int
+}
+
+var _ slackservice.Service = &Service{}
+
+// Calls returns the recorded calls of the methods, all calls if no method is given, in the order they were made
+func (s *Service) Calls(methods ...string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var calls []Call
+	for _, call := range s.calls {
+		if len(methods) == 0 || contains(methods, call.Method) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Called checks whether the method was called
+func (s *Service) Called(method string) bool {
+	return len(s.Calls(method)) > 0
+}
+
+// Reset forgets the recorded calls
+func (s *Service) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+func (s *Service) record(method string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, Call{Method: method, Args: args})
+}
+
+// newID returns the ID of a created channel
+func (s *Service) newID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	return fmt.Sprintf("C%08d", s.nextID)
+}
+
+func contains(list []string, item string) bool {
+	for _, element := range list {
+		if element == item {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateChannel records the call and calls CreateChannelFunc
+func (s *Service) CreateChannel(name string, isPrivate bool) (*string, error) {
+	s.record("CreateChannel", name, isPrivate)
+	if s.CreateChannelFunc != nil {
+		return s.CreateChannelFunc(name, isPrivate)
+	}
+	id := s.newID()
+	return &id, nil
+}
+
+// SetDescription records the call and calls SetDescriptionFunc
+func (s *Service) SetDescription(channelID string, description string) (*slack.Channel, error) {
+	s.record("SetDescription", channelID, description)
+	if s.SetDescriptionFunc != nil {
+		return s.SetDescriptionFunc(channelID, description)
+	}
+	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: channelID}, Purpose: slack.Purpose{Value: description}}}, nil
+}
+
+// SetTopic records the call and calls SetTopicFunc
+func (s *Service) SetTopic(channelID string, topic string) (*slack.Channel, error) {
+	s.record("SetTopic", channelID, topic)
+	if s.SetTopicFunc != nil {
+		return s.SetTopicFunc(channelID, topic)
+	}
+	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: channelID}, Topic: slack.Topic{Value: topic}}}, nil
+}
+
+// RenameChannel records the call and calls RenameChannelFunc
+func (s *Service) RenameChannel(channelID string, newName string) (*slack.Channel, error) {
+	s.record("RenameChannel", channelID, newName)
+	if s.RenameChannelFunc != nil {
+		return s.RenameChannelFunc(channelID, newName)
+	}
+	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: channelID}, Name: newName}}, nil
+}
+
+// ArchiveChannel records the call and calls ArchiveChannelFunc
+func (s *Service) ArchiveChannel(channelID string) error {
+	s.record("ArchiveChannel", channelID)
+	if s.ArchiveChannelFunc != nil {
+		return s.ArchiveChannelFunc(channelID)
+	}
+	return nil
+}
+
+// PlanMembership records the call and calls PlanMembershipFunc
+func (s *Service) PlanMembership(_ context.Context, channelID string, userEmails []string, aliases map[string][]string, protectedUsers []string) (*slackservice.MembershipPlan, error) {
+	s.record("PlanMembership", channelID, userEmails, aliases, protectedUsers)
+	if s.PlanMembershipFunc != nil {
+		return s.PlanMembershipFunc(channelID, userEmails, aliases, protectedUsers)
+	}
+	return &slackservice.MembershipPlan{}, nil
+}
+
+// PlanPolicyMembership records the call and calls PlanPolicyMembershipFunc
+func (s *Service) PlanPolicyMembership(_ context.Context, channelID string, mandatory []string, forbidden []string, aliases map[string][]string) (*slackservice.MembershipPlan, error) {
+	s.record("PlanPolicyMembership", channelID, mandatory, forbidden, aliases)
+	if s.PlanPolicyMembershipFunc != nil {
+		return s.PlanPolicyMembershipFunc(channelID, mandatory, forbidden, aliases)
+	}
+	return &slackservice.MembershipPlan{}, nil
+}
+
+// ApplyMembership records the call and calls ApplyMembershipFunc
+func (s *Service) ApplyMembership(_ context.Context, channelID string, plan *slackservice.MembershipPlan) ([]slackv1alpha1.MemberError, []error) {
+	s.record("ApplyMembership", channelID, plan)
+	if s.ApplyMembershipFunc != nil {
+		return s.ApplyMembershipFunc(channelID, plan)
+	}
+	return nil, nil
+}
+
+// KickUser records the call and calls KickUserFunc
+func (s *Service) KickUser(channelID string, userID string) (bool, error) {
+	s.record("KickUser", channelID, userID)
+	if s.KickUserFunc != nil {
+		return s.KickUserFunc(channelID, userID)
+	}
+	return false, nil
+}
+
+// GetChannel records the call and calls GetChannelFunc
+func (s *Service) GetChannel(channelID string) (*slack.Channel, error) {
+	s.record("GetChannel", channelID)
+	if s.GetChannelFunc != nil {
+		return s.GetChannelFunc(channelID)
+	}
+	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: channelID}}}, nil
+}
+
+// GetUsersInChannel records the call and calls GetUsersInChannelFunc
+func (s *Service) GetUsersInChannel(_ context.Context, channelID string) ([]string, error) {
+	s.record("GetUsersInChannel", channelID)
+	if s.GetUsersInChannelFunc != nil {
+		return s.GetUsersInChannelFunc(channelID)
+	}
+	return nil, nil
+}
+
+// GetChannelCRFromChannel records the call and calls GetChannelCRFromChannelFunc
+func (s *Service) GetChannelCRFromChannel(existingChannel *slack.Channel) *slackv1alpha1.Channel {
+	s.record("GetChannelCRFromChannel", existingChannel)
+	if s.GetChannelCRFromChannelFunc != nil {
+		return s.GetChannelCRFromChannelFunc(existingChannel)
+	}
+	return &slackv1alpha1.Channel{}
+}
+
+// SyncChannelMetadata records the call and calls SyncChannelMetadataFunc
+func (s *Service) SyncChannelMetadata(channelID string, desired *slackv1alpha1.Channel) ([]slackservice.FieldChange, error) {
+	s.record("SyncChannelMetadata", channelID, desired)
+	if s.SyncChannelMetadataFunc != nil {
+		return s.SyncChannelMetadataFunc(channelID, desired)
+	}
+	return nil, nil
+}
+
+// IsValidChannel records the call and calls IsValidChannelFunc
+func (s *Service) IsValidChannel(channel *slackv1alpha1.Channel) error {
+	s.record("IsValidChannel", channel)
+	if s.IsValidChannelFunc != nil {
+		return s.IsValidChannelFunc(channel)
+	}
+	return nil
+}
+
+// GetChannelByName records the call and calls GetChannelByNameFunc
+func (s *Service) GetChannelByName(name string) (*slack.Channel, error) {
+	s.record("GetChannelByName", name)
+	if s.GetChannelByNameFunc != nil {
+		return s.GetChannelByNameFunc(name)
+	}
+	return nil, nil
+}
+
+// UnArchiveChannel records the call and calls UnArchiveChannelFunc
+func (s *Service) UnArchiveChannel(channel *slack.Channel) error {
+	s.record("UnArchiveChannel", channel)
+	if s.UnArchiveChannelFunc != nil {
+		return s.UnArchiveChannelFunc(channel)
+	}
+	return nil
+}
+
+// PostMessage records the call and calls PostMessageFunc
+func (s *Service) PostMessage(channelID string, options ...slack.MsgOption) (string, error) {
+	s.record("PostMessage", channelID, options)
+	if s.PostMessageFunc != nil {
+		return s.PostMessageFunc(channelID, options...)
+	}
+	return "", nil
+}
+
+// UpdateMessage records the call and calls UpdateMessageFunc
+func (s *Service) UpdateMessage(channelID string, timestamp string, options ...slack.MsgOption) error {
+	s.record("UpdateMessage", channelID, timestamp, options)
+	if s.UpdateMessageFunc != nil {
+		return s.UpdateMessageFunc(channelID, timestamp, options...)
+	}
+	return nil
+}
+
+// UploadFile records the call and calls UploadFileFunc
+func (s *Service) UploadFile(channelID string, filename string, title string, content []byte) (string, error) {
+	s.record("UploadFile", channelID, filename, title, content)
+	if s.UploadFileFunc != nil {
+		return s.UploadFileFunc(channelID, filename, title, content)
+	}
+	return "", nil
+}
+
+// DeleteFile records the call and calls DeleteFileFunc
+func (s *Service) DeleteFile(fileID string) error {
+	s.record("DeleteFile", fileID)
+	if s.DeleteFileFunc != nil {
+		return s.DeleteFileFunc(fileID)
+	}
+	return nil
+}
+
+// CreateChannelCanvas records the call and calls CreateChannelCanvasFunc
+func (s *Service) CreateChannelCanvas(channelID string, markdown string) (string, error) {
+	s.record("CreateChannelCanvas", channelID, markdown)
+	if s.CreateChannelCanvasFunc != nil {
+		return s.CreateChannelCanvasFunc(channelID, markdown)
+	}
+	return "", nil
+}
+
+// EditCanvas records the call and calls EditCanvasFunc
+func (s *Service) EditCanvas(canvasID string, markdown string) error {
+	s.record("EditCanvas", canvasID, markdown)
+	if s.EditCanvasFunc != nil {
+		return s.EditCanvasFunc(canvasID, markdown)
+	}
+	return nil
+}
+
+// AddReminder records the call and calls AddReminderFunc
+func (s *Service) AddReminder(channelID string, userEmail string, text string, time string) (string, error) {
+	s.record("AddReminder", channelID, userEmail, text, time)
+	if s.AddReminderFunc != nil {
+		return s.AddReminderFunc(channelID, userEmail, text, time)
+	}
+	return "", nil
+}
+
+// DeleteReminder records the call and calls DeleteReminderFunc
+func (s *Service) DeleteReminder(reminderID string) error {
+	s.record("DeleteReminder", reminderID)
+	if s.DeleteReminderFunc != nil {
+		return s.DeleteReminderFunc(reminderID)
+	}
+	return nil
+}
+
+// ListEmoji records the call and calls ListEmojiFunc
+func (s *Service) ListEmoji() (map[string]string, error) {
+	s.record("ListEmoji")
+	if s.ListEmojiFunc != nil {
+		return s.ListEmojiFunc()
+	}
+	return nil, nil
+}
+
+// AddEmoji records the call and calls AddEmojiFunc
+func (s *Service) AddEmoji(name string, imageURL string) error {
+	s.record("AddEmoji", name, imageURL)
+	if s.AddEmojiFunc != nil {
+		return s.AddEmojiFunc(name, imageURL)
+	}
+	return nil
+}
+
+// UploadEmoji records the call and calls UploadEmojiFunc
+func (s *Service) UploadEmoji(name string, image []byte) error {
+	s.record("UploadEmoji", name, image)
+	if s.UploadEmojiFunc != nil {
+		return s.UploadEmojiFunc(name, image)
+	}
+	return nil
+}
+
+// RemoveEmoji records the call and calls RemoveEmojiFunc
+func (s *Service) RemoveEmoji(name string) error {
+	s.record("RemoveEmoji", name)
+	if s.RemoveEmojiFunc != nil {
+		return s.RemoveEmojiFunc(name)
+	}
+	return nil
+}
+
+// RenameEmoji records the call and calls RenameEmojiFunc
+func (s *Service) RenameEmoji(name string, newName string) error {
+	s.record("RenameEmoji", name, newName)
+	if s.RenameEmojiFunc != nil {
+		return s.RenameEmojiFunc(name, newName)
+	}
+	return nil
+}
+
+// CreateApp records the call and calls CreateAppFunc
+func (s *Service) CreateApp(configToken string, manifest []byte) (*slackservice.AppCredentials, error) {
+	s.record("CreateApp", configToken, manifest)
+	if s.CreateAppFunc != nil {
+		return s.CreateAppFunc(configToken, manifest)
+	}
+	return &slackservice.AppCredentials{}, nil
+}
+
+// UpdateApp records the call and calls UpdateAppFunc
+func (s *Service) UpdateApp(configToken string, appID string, manifest []byte) error {
+	s.record("UpdateApp", configToken, appID, manifest)
+	if s.UpdateAppFunc != nil {
+		return s.UpdateAppFunc(configToken, appID, manifest)
+	}
+	return nil
+}
+
+// DeleteApp records the call and calls DeleteAppFunc
+func (s *Service) DeleteApp(configToken string, appID string) error {
+	s.record("DeleteApp", configToken, appID)
+	if s.DeleteAppFunc != nil {
+		return s.DeleteAppFunc(configToken, appID)
+	}
+	return nil
+}
+
+// InviteUserToWorkspace records the call and calls InviteUserToWorkspaceFunc
+func (s *Service) InviteUserToWorkspace(invite slackservice.WorkspaceInvite) error {
+	s.record("InviteUserToWorkspace", invite)
+	if s.InviteUserToWorkspaceFunc != nil {
+		return s.InviteUserToWorkspaceFunc(invite)
+	}
+	return nil
+}
+
+// GetTeamID records the call and calls GetTeamIDFunc
+func (s *Service) GetTeamID() (string, error) {
+	s.record("GetTeamID")
+	if s.GetTeamIDFunc != nil {
+		return s.GetTeamIDFunc()
+	}
+	return "", nil
+}
+
+// LookupUserByEmail records the call and calls LookupUserByEmailFunc
+func (s *Service) LookupUserByEmail(email string) (*slack.User, error) {
+	s.record("LookupUserByEmail", email)
+	if s.LookupUserByEmailFunc != nil {
+		return s.LookupUserByEmailFunc(email)
+	}
+	return nil, nil
+}
+
+// RemoveUserFromWorkspace records the call and calls RemoveUserFromWorkspaceFunc
+func (s *Service) RemoveUserFromWorkspace(teamID string, userID string) error {
+	s.record("RemoveUserFromWorkspace", teamID, userID)
+	if s.RemoveUserFromWorkspaceFunc != nil {
+		return s.RemoveUserFromWorkspaceFunc(teamID, userID)
+	}
+	return nil
+}
+
+// SetUserExpiration records the call and calls SetUserExpirationFunc
+func (s *Service) SetUserExpiration(teamID string, userID string, expiration int64) error {
+	s.record("SetUserExpiration", teamID, userID, expiration)
+	if s.SetUserExpirationFunc != nil {
+		return s.SetUserExpirationFunc(teamID, userID, expiration)
+	}
+	return nil
+}
+
+// GetChannelUpdated records the call and calls GetChannelUpdatedFunc
+func (s *Service) GetChannelUpdated(channelID string) (int64, error) {
+	s.record("GetChannelUpdated", channelID)
+	if s.GetChannelUpdatedFunc != nil {
+		return s.GetChannelUpdatedFunc(channelID)
+	}
+	return 0, nil
+}
+
+// RejoinChannel records the call and calls RejoinChannelFunc
+func (s *Service) RejoinChannel(channelID string) error {
+	s.record("RejoinChannel", channelID)
+	if s.RejoinChannelFunc != nil {
+		return s.RejoinChannelFunc(channelID)
+	}
+	return nil
+}
+
+// GetManagedBy records the call and calls GetManagedByFunc
+func (s *Service) GetManagedBy(channelID string) (string, error) {
+	s.record("GetManagedBy", channelID)
+	if s.GetManagedByFunc != nil {
+		return s.GetManagedByFunc(channelID)
+	}
+	return "", nil
+}
+
+// AddManagedByMarker records the call and calls AddManagedByMarkerFunc
+func (s *Service) AddManagedByMarker(channelID string, owner string) error {
+	s.record("AddManagedByMarker", channelID, owner)
+	if s.AddManagedByMarkerFunc != nil {
+		return s.AddManagedByMarkerFunc(channelID, owner)
+	}
+	return nil
+}
+
+// ListMembers records the call and calls ListMembersFunc
+func (s *Service) ListMembers(channelID string) ([]slackservice.ChannelMember, error) {
+	s.record("ListMembers", channelID)
+	if s.ListMembersFunc != nil {
+		return s.ListMembersFunc(channelID)
+	}
+	return nil, nil
+}
+
+// ListBookmarks records the call and calls ListBookmarksFunc
+func (s *Service) ListBookmarks(channelID string) ([]slackservice.Bookmark, error) {
+	s.record("ListBookmarks", channelID)
+	if s.ListBookmarksFunc != nil {
+		return s.ListBookmarksFunc(channelID)
+	}
+	return nil, nil
+}
+
+// ListPins records the call and calls ListPinsFunc
+func (s *Service) ListPins(channelID string) ([]slackservice.Pin, error) {
+	s.record("ListPins", channelID)
+	if s.ListPinsFunc != nil {
+		return s.ListPinsFunc(channelID)
+	}
+	return nil, nil
+}
+
+// AddBookmark records the call and calls AddBookmarkFunc
+func (s *Service) AddBookmark(channelID string, bookmark slackservice.Bookmark) error {
+	s.record("AddBookmark", channelID, bookmark)
+	if s.AddBookmarkFunc != nil {
+		return s.AddBookmarkFunc(channelID, bookmark)
+	}
+	return nil
+}
+
+// AddPin records the call and calls AddPinFunc
+func (s *Service) AddPin(channelID string, timestamp string) error {
+	s.record("AddPin", channelID, timestamp)
+	if s.AddPinFunc != nil {
+		return s.AddPinFunc(channelID, timestamp)
+	}
+	return nil
+}
+
+// ConversationHistory records the call and calls ConversationHistoryFunc
+func (s *Service) ConversationHistory(channelID string, oldest string, cursor string) ([]slack.Message, string, error) {
+	s.record("ConversationHistory", channelID, oldest, cursor)
+	if s.ConversationHistoryFunc != nil {
+		return s.ConversationHistoryFunc(channelID, oldest, cursor)
+	}
+	return nil, "", nil
+}
+
+// LatestActivity records the call and calls LatestActivityFunc
+func (s *Service) LatestActivity(channelID string, ignoreTimestamp string) (time.Time, error) {
+	s.record("LatestActivity", channelID, ignoreTimestamp)
+	if s.LatestActivityFunc != nil {
+		return s.LatestActivityFunc(channelID, ignoreTimestamp)
+	}
+	return time.Time{}, nil
+}
+
+// ChannelActivity records the call and calls ChannelActivityFunc
+func (s *Service) ChannelActivity(channelID string, since time.Time) (*slackservice.Activity, error) {
+	s.record("ChannelActivity", channelID, since)
+	if s.ChannelActivityFunc != nil {
+		return s.ChannelActivityFunc(channelID, since)
+	}
+	return &slackservice.Activity{}, nil
+}
+
+// GetRetention records the call and calls GetRetentionFunc
+func (s *Service) GetRetention(channelID string) (int, error) {
+	s.record("GetRetention", channelID)
+	if s.GetRetentionFunc != nil {
+		return s.GetRetentionFunc(channelID)
+	}
+	return 0, nil
+}
+
+// SetRetention records the call and calls SetRetentionFunc
+func (s *Service) SetRetention(channelID string, days int) error {
+	s.record("SetRetention", channelID, days)
+	if s.SetRetentionFunc != nil {
+		return s.SetRetentionFunc(channelID, days)
+	}
+	return nil
+}
+
+// ConvertChannel records the call and calls ConvertChannelFunc
+func (s *Service) ConvertChannel(channelID string, private bool) error {
+	s.record("ConvertChannel", channelID, private)
+	if s.ConvertChannelFunc != nil {
+		return s.ConvertChannelFunc(channelID, private)
+	}
+	return nil
+}
+
+// GetDefaultChannels records the call and calls GetDefaultChannelsFunc
+func (s *Service) GetDefaultChannels(teamID string) ([]string, error) {
+	s.record("GetDefaultChannels", teamID)
+	if s.GetDefaultChannelsFunc != nil {
+		return s.GetDefaultChannelsFunc(teamID)
+	}
+	return nil, nil
+}
+
+// SetDefaultChannels records the call and calls SetDefaultChannelsFunc
+func (s *Service) SetDefaultChannels(teamID string, channelIDs []string) error {
+	s.record("SetDefaultChannels", teamID, channelIDs)
+	if s.SetDefaultChannelsFunc != nil {
+		return s.SetDefaultChannelsFunc(teamID, channelIDs)
+	}
+	return nil
+}
+
+// ListBarriers records the call and calls ListBarriersFunc
+func (s *Service) ListBarriers() ([]slackservice.Barrier, error) {
+	s.record("ListBarriers")
+	if s.ListBarriersFunc != nil {
+		return s.ListBarriersFunc()
+	}
+	return nil, nil
+}
+
+// CreateBarrier records the call and calls CreateBarrierFunc, by default it returns a new ID
+func (s *Service) CreateBarrier(barrier slackservice.Barrier) (string, error) {
+	s.record("CreateBarrier", barrier)
+	if s.CreateBarrierFunc != nil {
+		return s.CreateBarrierFunc(barrier)
+	}
+	return s.newID(), nil
+}
+
+// UpdateBarrier records the call and calls UpdateBarrierFunc
+func (s *Service) UpdateBarrier(barrier slackservice.Barrier) error {
+	s.record("UpdateBarrier", barrier)
+	if s.UpdateBarrierFunc != nil {
+		return s.UpdateBarrierFunc(barrier)
+	}
+	return nil
+}
+
+// DeleteBarrier records the call and calls DeleteBarrierFunc
+func (s *Service) DeleteBarrier(barrierID string) error {
+	s.record("DeleteBarrier", barrierID)
+	if s.DeleteBarrierFunc != nil {
+		return s.DeleteBarrierFunc(barrierID)
+	}
+	return nil
+}
+
+// ListUsergroupChannels records the call and calls ListUsergroupChannelsFunc
+func (s *Service) ListUsergroupChannels(usergroupID string) ([]string, error) {
+	s.record("ListUsergroupChannels", usergroupID)
+	if s.ListUsergroupChannelsFunc != nil {
+		return s.ListUsergroupChannelsFunc(usergroupID)
+	}
+	return nil, nil
+}
+
+// AddUsergroupChannels records the call and calls AddUsergroupChannelsFunc
+func (s *Service) AddUsergroupChannels(usergroupID string, channelIDs []string) error {
+	s.record("AddUsergroupChannels", usergroupID, channelIDs)
+	if s.AddUsergroupChannelsFunc != nil {
+		return s.AddUsergroupChannelsFunc(usergroupID, channelIDs)
+	}
+	return nil
+}
+
+// RemoveUsergroupChannels records the call and calls RemoveUsergroupChannelsFunc
+func (s *Service) RemoveUsergroupChannels(usergroupID string, channelIDs []string) error {
+	s.record("RemoveUsergroupChannels", usergroupID, channelIDs)
+	if s.RemoveUsergroupChannelsFunc != nil {
+		return s.RemoveUsergroupChannelsFunc(usergroupID, channelIDs)
+	}
+	return nil
+}
+
+// AddUsergroupTeams records the call and calls AddUsergroupTeamsFunc
+func (s *Service) AddUsergroupTeams(usergroupID string, teamIDs []string) error {
+	s.record("AddUsergroupTeams", usergroupID, teamIDs)
+	if s.AddUsergroupTeamsFunc != nil {
+		return s.AddUsergroupTeamsFunc(usergroupID, teamIDs)
+	}
+	return nil
+}
+
+// GetSection records the call and calls GetSectionFunc
+func (s *Service) GetSection(channelID string) (string, error) {
+	s.record("GetSection", channelID)
+	if s.GetSectionFunc != nil {
+		return s.GetSectionFunc(channelID)
+	}
+	return "", nil
+}
+
+// SetSection records the call and calls SetSectionFunc
+func (s *Service) SetSection(channelID string, owner string, section string) error {
+	s.record("SetSection", channelID, owner, section)
+	if s.SetSectionFunc != nil {
+		return s.SetSectionFunc(channelID, owner, section)
+	}
+	return nil
+}
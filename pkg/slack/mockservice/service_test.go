@@ -0,0 +1,56 @@
+package mockservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_shouldCallProgrammedBehavior(t *testing.T) {
+	s := &Service{
+		SetTopicFunc: func(channelID string, topic string) (*slack.Channel, error) {
+			return nil, errors.New("not_in_channel")
+		},
+	}
+
+	_, err := s.SetTopic("C1", "topic")
+	assert.EqualError(t, err, "not_in_channel")
+}
+
+func TestService_shouldSucceedWithoutProgrammedBehavior(t *testing.T) {
+	s := &Service{}
+
+	id, err := s.CreateChannel("my-channel", true)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, *id)
+
+	channel, err := s.RenameChannel(*id, "renamed")
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", channel.Name)
+
+	plan, err := s.PlanMembership(context.Background(), *id, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.False(t, plan.Changed())
+}
+
+func TestService_shouldRecordCalls(t *testing.T) {
+	s := &Service{}
+
+	_, _ = s.SetTopic("C1", "topic")
+	_ = s.ArchiveChannel("C1")
+	_, _ = s.SetTopic("C2", "other")
+
+	assert.Equal(t, []Call{
+		{Method: "SetTopic", Args: []interface{}{"C1", "topic"}},
+		{Method: "SetTopic", Args: []interface{}{"C2", "other"}},
+	}, s.Calls("SetTopic"))
+	assert.Len(t, s.Calls(), 3)
+	assert.True(t, s.Called("ArchiveChannel"))
+	assert.False(t, s.Called("KickUser"))
+
+	s.Reset()
+	assert.Empty(t, s.Calls())
+}
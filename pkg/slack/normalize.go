@@ -0,0 +1,68 @@
+package slack
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+)
+
+// mrkdwnLink matches links formatted by Slack, e.g. <https://example.com> or <http://example.com|example.com>
+var mrkdwnLink = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)(?:\|([^>]*))?>`)
+
+// whitespace matches runs of whitespace, which Slack collapses
+var whitespace = regexp.MustCompile(`\s+`)
+
+// emojiShortcodes maps emoji which Slack stores as shortcodes to their shortcode
+var emojiShortcodes = strings.NewReplacer(
+	"😀", ":grinning:",
+	"😃", ":smiley:",
+	"😄", ":smile:",
+	"😉", ":wink:",
+	"🙂", ":slightly_smiling_face:",
+	"😎", ":sunglasses:",
+	"👍", ":+1:",
+	"👎", ":-1:",
+	"👋", ":wave:",
+	"👀", ":eyes:",
+	"🙏", ":pray:",
+	"🎉", ":tada:",
+	"🚀", ":rocket:",
+	"🔥", ":fire:",
+	"✅", ":white_check_mark:",
+	"❌", ":x:",
+	"⚠️", ":warning:",
+	"⚠", ":warning:",
+	"🚨", ":rotating_light:",
+	"💡", ":bulb:",
+	"📢", ":loudspeaker:",
+	"📌", ":pushpin:",
+	"❤️", ":heart:",
+	"❤", ":heart:",
+)
+
+// quotes maps typographic quotes to plain quotes
+var quotes = strings.NewReplacer("“", `"`, "”", `"`, "‘", "'", "’", "'")
+
+// normalizeText brings a topic or description into a form in which the value stored by Slack and the spec compare
+// equal: HTML entities are unescaped, links lose their mrkdwn formatting, emoji become shortcodes, typographic
+// quotes become plain quotes and whitespace is collapsed
+func normalizeText(text string) string {
+	text = html.UnescapeString(text)
+	text = mrkdwnLink.ReplaceAllStringFunc(text, func(link string) string {
+		match := mrkdwnLink.FindStringSubmatch(link)
+		if match[2] != "" {
+			return match[2]
+		}
+		return strings.TrimPrefix(match[1], "mailto:")
+	})
+	text = emojiShortcodes.Replace(text)
+	text = quotes.Replace(text)
+	return strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+}
+
+// normalizeName brings a channel name into the form Slack stores it in
+func normalizeName(name string) string {
+	return slackv1alpha1.NormalizeChannelName(html.UnescapeString(name))
+}
@@ -0,0 +1,144 @@
+package slack
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	// notifierCheckPeriod is how often the OperatorNotifier checks for problems
+	notifierCheckPeriod = time.Minute
+
+	// sustainedRateLimits is the number of rate limited responses per check which are reported as a problem
+	sustainedRateLimits = 10
+
+	// certExpiryWarning is how long before its expiry the webhook certificate is reported as a problem
+	certExpiryWarning = 7 * 24 * time.Hour
+)
+
+// RateLimitedResponses returns the number of responses rate limited by Slack since the service was created
+func (s *SlackService) RateLimitedResponses() uint64 {
+	return atomic.LoadUint64(&s.rateLimited)
+}
+
+// OperatorNotifier posts the operator's own critical problems to a slack channel: token failures, sustained rate
+// limiting and the expiry of the webhook certificate. Every problem is posted when it starts and when it's resolved.
+type OperatorNotifier struct {
+	service   *SlackService
+	channelID string
+	// certFile is the webhook serving certificate, empty if webhooks are disabled
+	certFile string
+
+	// problems are the messages of the problems which were posted, by kind
+	problems    map[string]string
+	rateLimited uint64
+}
+
+// NewOperatorNotifier creates a notifier posting to the channel, the expiry of the PEM certificate in certFile is
+// checked unless it's empty
+func NewOperatorNotifier(service *SlackService, channelID string, certFile string) *OperatorNotifier {
+	return &OperatorNotifier{
+		service:   service,
+		channelID: channelID,
+		certFile:  certFile,
+		problems:  map[string]string{},
+	}
+}
+
+// Start checks for problems every notifierCheckPeriod until the context is cancelled
+func (n *OperatorNotifier) Start(ctx context.Context) error {
+	n.rateLimited = n.service.RateLimitedResponses()
+
+	ticker := time.NewTicker(notifierCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		n.notify(n.check(time.Now()))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// check returns the messages of the current problems by kind
+func (n *OperatorNotifier) check(now time.Time) map[string]string {
+	problems := map[string]string{}
+
+	if err := n.service.AuthCheck(nil); err != nil {
+		problems["auth"] = fmt.Sprintf("Slack token failed validation: %v", err)
+	}
+
+	rateLimited := n.service.RateLimitedResponses()
+	if limited := rateLimited - n.rateLimited; limited >= sustainedRateLimits {
+		problems["rateLimit"] = fmt.Sprintf("Slack rate limited %d requests in the last %s", limited, notifierCheckPeriod)
+	}
+	n.rateLimited = rateLimited
+
+	if n.certFile != "" {
+		expiry, err := certificateExpiry(n.certFile)
+		if err != nil {
+			problems["webhookCert"] = fmt.Sprintf("Webhook certificate can't be read: %v", err)
+		} else if expiry.Sub(now) < certExpiryWarning {
+			problems["webhookCert"] = fmt.Sprintf("Webhook certificate expires at %s", expiry.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return problems
+}
+
+// notify posts the problems which started and the ones which were resolved since the last check
+func (n *OperatorNotifier) notify(problems map[string]string) {
+	var messages []string
+	for kind, message := range problems {
+		if _, posted := n.problems[kind]; !posted {
+			messages = append(messages, ":rotating_light: "+message)
+		}
+	}
+	for kind, message := range n.problems {
+		if _, active := problems[kind]; !active {
+			messages = append(messages, ":white_check_mark: Resolved: "+message)
+		}
+	}
+	sort.Strings(messages)
+
+	for _, message := range messages {
+		_, err := n.service.PostMessage(n.channelID, slack.MsgOptionText("*slack-operator*: "+message, false))
+		if err != nil {
+			n.service.log.Error(err, "Error posting operator notification", "message", message)
+		}
+	}
+	n.problems = problems
+}
+
+// NeedLeaderElection posts from the leader only, so problems aren't posted once per replica
+func (n *OperatorNotifier) NeedLeaderElection() bool {
+	return true
+}
+
+// certificateExpiry returns the expiry of the first certificate of the PEM file
+func certificateExpiry(file string) (time.Time, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return time.Time{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("%s contains no PEM certificate", file)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
@@ -0,0 +1,160 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// rotationCheckPeriod is how often a TokenRotator checks when the token expires
+	rotationCheckPeriod = time.Minute
+
+	// refreshBefore is how long before it expires a rotating token is refreshed
+	refreshBefore = 30 * time.Minute
+)
+
+// RotatingToken is a token of a Slack app with token rotation, which expires and is refreshed with its refresh
+// token, see https://api.slack.com/authentication/rotation
+type RotatingToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+
+	// ClientID and ClientSecret are the credentials of the app the token belongs to
+	ClientID     string
+	ClientSecret string
+}
+
+// TokenStore persists the rotating token, so refreshed tokens survive restarts and reach every replica
+type TokenStore interface {
+	// Load returns the stored token, or nil when token rotation isn't configured
+	Load(ctx context.Context) (*RotatingToken, error)
+	Save(ctx context.Context, token *RotatingToken) error
+}
+
+type oauthResponse struct {
+	OK           bool   `json:"ok"`
+	Error        string `json:"error"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// RefreshToken exchanges the refresh token for a new access token with oauth.v2.access. Slack returns a new refresh
+// token along with it, the previous one mustn't be used again.
+func (s *SlackService) RefreshToken(token *RotatingToken) (*RotatingToken, error) {
+	resp, err := s.httpClient.PostForm(s.apiURL+"oauth.v2.access", url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+		"client_id":     {token.ClientID},
+		"client_secret": {token.ClientSecret},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth.v2.access returned status %s", resp.Status)
+	}
+
+	response := &oauthResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, err
+	}
+	if !response.OK {
+		return nil, fmt.Errorf("error refreshing token: %s", response.Error)
+	}
+
+	return &RotatingToken{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(response.ExpiresIn) * time.Second),
+		ClientID:     token.ClientID,
+		ClientSecret: token.ClientSecret,
+	}, nil
+}
+
+// TokenRotator refreshes the rotating token of a SlackService before it expires and saves the new token to the
+// store. Other replicas pick the new token up with their TokenReloader.
+type TokenRotator struct {
+	service *SlackService
+	store   TokenStore
+	now     func() time.Time
+
+	// unsaved is a refreshed token which failed to be saved, it is saved before the store is read again
+	unsaved *RotatingToken
+}
+
+// NewTokenRotator creates a rotator for the token in store
+func NewTokenRotator(service *SlackService, store TokenStore) *TokenRotator {
+	return &TokenRotator{
+		service: service,
+		store:   store,
+		now:     time.Now,
+	}
+}
+
+// Start checks the token right away and then every rotationCheckPeriod until the context is cancelled. Failed
+// refreshes and saves are retried on the next check.
+func (r *TokenRotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(rotationCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		if err := r.rotate(ctx); err != nil {
+			r.service.log.Error(err, "Error rotating the Slack token")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// rotate refreshes the token when it expires within refreshBefore
+func (r *TokenRotator) rotate(ctx context.Context) error {
+	// The stored refresh token was used up already, the refreshed token is kept in memory until it was saved
+	if r.unsaved != nil {
+		if err := r.store.Save(ctx, r.unsaved); err != nil {
+			return err
+		}
+		r.service.log.Info("Saved refreshed Slack token", "expiresAt", r.unsaved.ExpiresAt)
+		r.unsaved = nil
+	}
+
+	token, err := r.store.Load(ctx)
+	if err != nil || token == nil {
+		return err
+	}
+
+	if r.now().Before(token.ExpiresAt.Add(-refreshBefore)) {
+		r.service.SetToken(token.AccessToken)
+		return nil
+	}
+
+	refreshed, err := r.service.RefreshToken(token)
+	if err != nil {
+		return err
+	}
+
+	// The refresh token was used up, the new token is used right away and saving it is retried until it succeeds
+	r.service.log.Info("Refreshed rotating Slack token", "expiresAt", refreshed.ExpiresAt)
+	r.service.SetToken(refreshed.AccessToken)
+	if err := r.store.Save(ctx, refreshed); err != nil {
+		r.unsaved = refreshed
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection refreshes the token on the leader only, a refresh token can only be used once
+func (r *TokenRotator) NeedLeaderElection() bool {
+	return true
+}
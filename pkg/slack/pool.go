@@ -0,0 +1,50 @@
+package slack
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/slack-go/slack"
+)
+
+// pooledClient is a client of another app installed in the workspace, which has rate limits of its own
+type pooledClient struct {
+	api API
+	// limiter budgets the calls of the app
+	limiter *RateLimiter
+}
+
+// WithTokenPool spreads the read-only users and emoji calls of the slack client round-robin across the token of the service and the
+// bot tokens of further apps. Every token is rate limited on its own, Slack applies its limits per app.
+func WithTokenPool(tokens []string) Option {
+	return func(s *SlackService) {
+		s.poolTokens = tokens
+	}
+}
+
+// newPool creates the clients of the pool tokens, every one with a RateLimiter of its own
+func (s *SlackService) newPool() {
+	for _, token := range s.poolTokens {
+		limiter := NewRateLimiter()
+		httpClient := &http.Client{
+			Transport: &missingScopeTransport{
+				base: s.newRateLimitedTransport(limiter),
+			},
+		}
+		api := slack.New(token, slack.OptionHTTPClient(httpClient), slack.OptionAPIURL(s.apiURL))
+		s.pool = append(s.pool, &pooledClient{api: api, limiter: limiter})
+	}
+}
+
+// next returns the client for the next read-only call, the pool takes turns with the client of the service
+func (s *SlackService) next() API {
+	if len(s.pool) == 0 {
+		return s.primary()
+	}
+
+	turn := atomic.AddUint64(&s.turn, 1) % uint64(len(s.pool)+1)
+	if turn == 0 {
+		return s.primary()
+	}
+	return s.pool[turn-1].api
+}
@@ -0,0 +1,171 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Requests per minute allowed by the rate limit tiers of the Slack Web API, see https://api.slack.com/docs/rate-limits
+const (
+	tier1 = 1
+	tier2 = 20
+	tier3 = 50
+	tier4 = 100
+)
+
+// methodTiers are the rate limit tiers of the Web API methods used by the operator, other methods default to tier 3
+var methodTiers = map[string]int{
+	"admin.barriers.create":                     tier2,
+	"admin.barriers.delete":                     tier2,
+	"admin.barriers.list":                       tier2,
+	"admin.barriers.update":                     tier2,
+	"admin.conversations.convertToPrivate":      tier2,
+	"admin.conversations.convertToPublic":       tier2,
+	"admin.conversations.getCustomRetention":    tier4,
+	"admin.conversations.removeCustomRetention": tier2,
+	"admin.conversations.setCustomRetention":    tier2,
+	"admin.emoji.add":                           tier2,
+	"admin.emoji.remove":                        tier2,
+	"admin.emoji.rename":                        tier2,
+	"admin.teams.settings.info":                 tier3,
+	"admin.teams.settings.setDefaultChannels":   tier2,
+	"admin.usergroups.addChannels":              tier2,
+	"admin.usergroups.addTeams":                 tier2,
+	"admin.usergroups.listChannels":             tier2,
+	"admin.usergroups.removeChannels":           tier2,
+	"admin.users.invite":                        tier2,
+	"admin.users.remove":                        tier2,
+	"admin.users.setExpiration":                 tier2,
+	"apps.manifest.create":                      tier1,
+	"apps.manifest.delete":                      tier1,
+	"apps.manifest.update":                      tier1,
+	"bookmarks.add":                             tier2,
+	"chat.postMessage":                          tier4,
+	"conversations.create":                      tier2,
+	"conversations.invite":                      tier3,
+	"conversations.kick":                        tier3,
+	"conversations.list":                        tier2,
+	"conversations.members":                     tier4,
+	"emoji.list":                                tier2,
+	"files.completeUploadExternal":              tier4,
+	"files.getUploadURLExternal":                tier4,
+	"pins.add":                                  tier2,
+	"reminders.add":                             tier2,
+	"reminders.delete":                          tier2,
+	"users.info":                                tier4,
+	"users.list":                                tier2,
+}
+
+// RateLimiter budgets the calls of every Web API method to the requests per minute of its tier. Slack applies the
+// limits per method and workspace, so one RateLimiter is shared by all reconcilers.
+type RateLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	// calls are the times of the calls of every method within the last minute
+	calls map[string][]time.Time
+}
+
+// NewRateLimiter creates a RateLimiter with a full budget for every method
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{limiters: map[string]*rate.Limiter{}, calls: map[string][]time.Time{}}
+}
+
+// Wait blocks until the method may be called or the context is done
+func (l *RateLimiter) Wait(ctx context.Context, method string) error {
+	if err := l.limiter(method).Wait(ctx); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.calls[method] = append(callsSince(l.calls[method], now.Add(-time.Minute)), now)
+	return nil
+}
+
+// Remaining returns how many more calls of the method Slack allows within the current minute
+func (l *RateLimiter) Remaining(method string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	calls, called := l.calls[method]
+	if called {
+		calls = callsSince(calls, time.Now().Add(-time.Minute))
+		l.calls[method] = calls
+	}
+	if remaining := tierOf(method) - len(calls); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Methods returns the methods which were called
+func (l *RateLimiter) Methods() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	methods := make([]string, 0, len(l.calls))
+	for method := range l.calls {
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// callsSince drops the calls before since, calls are sorted by time
+func callsSince(calls []time.Time, since time.Time) []time.Time {
+	for i, call := range calls {
+		if call.After(since) {
+			return calls[i:]
+		}
+	}
+	return calls[:0]
+}
+
+func (l *RateLimiter) limiter(method string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, found := l.limiters[method]
+	if !found {
+		perMinute := tierOf(method)
+		// Slack tolerates short bursts, a fifth of the per minute budget is allowed at once
+		burst := perMinute / 5
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), burst)
+		l.limiters[method] = limiter
+	}
+	return limiter
+}
+
+// tierOf returns the requests per minute allowed for the method
+func tierOf(method string) int {
+	if tier, found := methodTiers[method]; found {
+		return tier
+	}
+	return tier3
+}
+
+// rateLimitedTransport waits for the budget of the called Web API method before sending a request, responses
+// rate limited by Slack anyway are counted in limited
+type rateLimitedTransport struct {
+	limiter *RateLimiter
+	base    http.RoundTripper
+	limited *uint64
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context(), path.Base(req.URL.Path)); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests && t.limited != nil {
+		atomic.AddUint64(t.limited, 1)
+	}
+	return resp, err
+}
@@ -0,0 +1,64 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+
+	"github.com/go-logr/logr"
+)
+
+// requestIDHeader is the header of Slack's ID of a request, Slack support asks for it
+const requestIDHeader = "X-Slack-Req-Id"
+
+// errorResponse holds the fields of failed Web API responses
+type errorResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// requestLogTransport logs every failed Web API call with Slack's request ID, the Retry-After of rate limited
+// responses and the budget the limiter has left for the method
+type requestLogTransport struct {
+	base    http.RoundTripper
+	limiter *RateLimiter
+	log     logr.Logger
+}
+
+func (t *requestLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := path.Base(req.URL.Path)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.log.Info("Slack API call failed", "method", method, "error", err.Error(), "remaining", t.limiter.Remaining(method))
+		return resp, err
+	}
+
+	apiError := ""
+	if resp.StatusCode == http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		response := &errorResponse{OK: true}
+		if !bytes.Contains(body, []byte(`"ok":false`)) || json.Unmarshal(body, response) != nil || response.OK {
+			return resp, nil
+		}
+		apiError = response.Error
+	}
+
+	keysAndValues := []interface{}{"method", method, "status", resp.StatusCode, "requestID", resp.Header.Get(requestIDHeader),
+		"remaining", t.limiter.Remaining(method)}
+	if apiError != "" {
+		keysAndValues = append(keysAndValues, "error", apiError)
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		keysAndValues = append(keysAndValues, "retryAfter", retryAfter)
+	}
+	t.log.Info("Slack API call failed", keysAndValues...)
+	return resp, nil
+}
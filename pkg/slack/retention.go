@@ -0,0 +1,52 @@
+package slack
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/slack-go/slack"
+)
+
+type retentionResponse struct {
+	slack.SlackResponse
+	IsPolicyEnabled bool `json:"is_policy_enabled"`
+	DurationDays    int  `json:"duration_days"`
+}
+
+// GetRetention returns the days the messages of the channel are kept, 0 when the workspace retention applies.
+// It needs an Enterprise Grid user token with the admin.conversations:read scope.
+func (s *SlackService) GetRetention(channelID string) (int, error) {
+	response := &retentionResponse{}
+	err := s.callAPI("admin.conversations.getCustomRetention", url.Values{"channel_id": {channelID}}, response)
+	if err != nil {
+		s.log.Error(err, "Error fetching channel retention", "channelID", channelID)
+		return 0, err
+	}
+	if !response.IsPolicyEnabled {
+		return 0, nil
+	}
+	return response.DurationDays, nil
+}
+
+// SetRetention keeps the messages of the channel for the days, 0 removes the custom retention of the channel so the
+// workspace retention applies. It needs an Enterprise Grid user token with the admin.conversations:write scope.
+func (s *SlackService) SetRetention(channelID string, days int) error {
+	log := s.log.WithValues("channelID", channelID)
+
+	log.V(1).Info("Setting channel retention", "days", days)
+
+	var err error
+	if days == 0 {
+		err = s.callAPI("admin.conversations.removeCustomRetention", url.Values{"channel_id": {channelID}}, &slack.SlackResponse{})
+	} else {
+		err = s.callAPI("admin.conversations.setCustomRetention", url.Values{
+			"channel_id":    {channelID},
+			"duration_days": {strconv.Itoa(days)},
+		}, &slack.SlackResponse{})
+	}
+	if err != nil {
+		log.Error(err, "Error setting channel retention")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+)
+
+// MissingScopeError is returned for calls of Web API methods the token lacks a scope for
+type MissingScopeError struct {
+	// Method is the Web API method that was called, e.g. conversations.create
+	Method string
+	// Needed are the scopes the method needs, e.g. groups:write
+	Needed string
+	// Provided are the scopes of the token
+	Provided string
+}
+
+func (e *MissingScopeError) Error() string {
+	return fmt.Sprintf("missing_scope: %s needs the %s scope, the token has %s", e.Method, e.Needed, e.Provided)
+}
+
+// Is matches ErrMissingScope
+func (e *MissingScopeError) Is(target error) bool {
+	return target == ErrMissingScope
+}
+
+// scopeResponse holds the fields Slack returns along with a missing_scope error
+type scopeResponse struct {
+	Error    string `json:"error"`
+	Needed   string `json:"needed"`
+	Provided string `json:"provided"`
+}
+
+// missingScopeTransport turns missing_scope responses into a MissingScopeError, the slack client drops the scopes
+// Slack reports as needed
+type missingScopeTransport struct {
+	base http.RoundTripper
+}
+
+func (t *missingScopeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if !bytes.Contains(body, []byte(`"missing_scope"`)) {
+		return resp, nil
+	}
+
+	response := &scopeResponse{}
+	if err := json.Unmarshal(body, response); err != nil || response.Error != "missing_scope" {
+		return resp, nil
+	}
+	return nil, &MissingScopeError{Method: path.Base(req.URL.Path), Needed: response.Needed, Provided: response.Provided}
+}
@@ -1,8 +1,12 @@
 package slack
 
 import (
+	"context"
 	"fmt"
-	"html"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/slack-go/slack"
@@ -11,9 +15,33 @@ import (
 )
 
 const (
-	ChannelAlreadyExistsError string = "A channel with the same name already exists"
+	// MemberNotFound is the reason of member errors for users without a slack account
+	MemberNotFound string = "NotFound"
+	// MemberDeactivated is the reason of member errors for deactivated users
+	MemberDeactivated string = "Deactivated"
+	// MemberRestricted is the reason of member errors for guests who can't be added to the channel
+	MemberRestricted string = "Restricted"
+	// MemberOffboarded is the reason of member errors for users with a UserOffboarding
+	MemberOffboarded string = "Offboarded"
+	// MemberLookupFailed is the reason of member errors for users who couldn't be looked up
+	MemberLookupFailed string = "LookupFailed"
+	// MemberInviteFailed is the reason of member errors for users who couldn't be invited
+	MemberInviteFailed string = "InviteFailed"
+	// MemberRemoveFailed is the reason of member errors for members who couldn't be removed
+	MemberRemoveFailed string = "RemoveFailed"
+	// MemberForbidden is the reason of member errors for users a ChannelPolicy forbids
+	MemberForbidden string = "Forbidden"
+
+	// UserIDPrefix marks entries of channel users which are slack user IDs instead of emails
+	UserIDPrefix string = "id:"
+
+	// SlackbotUserID is the ID of the built-in Slackbot user in every workspace
+	SlackbotUserID string = "USLACKBOT"
 )
 
+// membersPageSize is the number of channel members fetched per conversations.members call, Slack caps it at 1000
+const membersPageSize = 1000
+
 // Service interface
 type Service interface {
 	CreateChannel(string, bool) (*string, error)
@@ -21,54 +49,311 @@ type Service interface {
 	SetTopic(string, string) (*slack.Channel, error)
 	RenameChannel(string, string) (*slack.Channel, error)
 	ArchiveChannel(string) error
-	InviteUsers(string, []string) []error
-	RemoveUsers(string, []string) error
+	PlanMembership(context.Context, string, []string, map[string][]string, []string) (*MembershipPlan, error)
+	PlanPolicyMembership(context.Context, string, []string, []string, map[string][]string) (*MembershipPlan, error)
+	ApplyMembership(context.Context, string, *MembershipPlan) ([]slackv1alpha1.MemberError, []error)
+	KickUser(string, string) (bool, error)
 	GetChannel(string) (*slack.Channel, error)
-	GetUsersInChannel(channelID string) ([]string, error)
+	GetUsersInChannel(ctx context.Context, channelID string) ([]string, error)
 	GetChannelCRFromChannel(*slack.Channel) *slackv1alpha1.Channel
-	IsChannelUpdated(*slackv1alpha1.Channel) (bool, error)
+	SyncChannelMetadata(string, *slackv1alpha1.Channel) ([]FieldChange, error)
 	IsValidChannel(*slackv1alpha1.Channel) error
 	GetChannelByName(string) (*slack.Channel, error)
 	UnArchiveChannel(*slack.Channel) error
+	PostMessage(string, ...slack.MsgOption) (string, error)
+	UpdateMessage(string, string, ...slack.MsgOption) error
+	UploadFile(string, string, string, []byte) (string, error)
+	DeleteFile(string) error
+	CreateChannelCanvas(string, string) (string, error)
+	EditCanvas(string, string) error
+	AddReminder(string, string, string, string) (string, error)
+	DeleteReminder(string) error
+	ListEmoji() (map[string]string, error)
+	AddEmoji(string, string) error
+	UploadEmoji(string, []byte) error
+	RemoveEmoji(string) error
+	RenameEmoji(string, string) error
+	CreateApp(string, []byte) (*AppCredentials, error)
+	UpdateApp(string, string, []byte) error
+	DeleteApp(string, string) error
+	InviteUserToWorkspace(WorkspaceInvite) error
+	GetTeamID() (string, error)
+	LookupUserByEmail(string) (*slack.User, error)
+	RemoveUserFromWorkspace(string, string) error
+	SetUserExpiration(string, string, int64) error
+	GetChannelUpdated(string) (int64, error)
+	RejoinChannel(string) error
+	GetManagedBy(string) (string, error)
+	AddManagedByMarker(string, string) error
+	ListMembers(string) ([]ChannelMember, error)
+	ListBookmarks(string) ([]Bookmark, error)
+	ListPins(string) ([]Pin, error)
+	AddBookmark(string, Bookmark) error
+	AddPin(string, string) error
+	ConversationHistory(string, string, string) ([]slack.Message, string, error)
+	LatestActivity(string, string) (time.Time, error)
+	ChannelActivity(string, time.Time) (*Activity, error)
+	GetRetention(string) (int, error)
+	SetRetention(string, int) error
+	ConvertChannel(string, bool) error
+	GetDefaultChannels(string) ([]string, error)
+	SetDefaultChannels(string, []string) error
+	ListBarriers() ([]Barrier, error)
+	CreateBarrier(Barrier) (string, error)
+	UpdateBarrier(Barrier) error
+	DeleteBarrier(string) error
+	ListUsergroupChannels(string) ([]string, error)
+	AddUsergroupChannels(string, []string) error
+	RemoveUsergroupChannels(string, []string) error
+	AddUsergroupTeams(string, []string) error
+	GetSection(string) (string, error)
+	SetSection(string, string, string) error
 }
 
 // SlackService structure
 type SlackService struct {
 	log logr.Logger
-	api *slack.Client
+
+	// mu guards api and token, which are replaced when the token is rotated
+	mu  sync.RWMutex
+	api API
+
+	// newAPI creates the client of a rotated token, it is nil for clients passed to NewWithClient
+	newAPI func(token string) API
+
+	// token, apiURL and httpClient are used for Web API methods not supported by the slack client
+	token      string
+	apiURL     string
+	httpClient *http.Client
+
+	// minChannelUsers is the minimum number of users a channel must list
+	minChannelUsers int
+
+	// userKey is what the users listed by Channels are matched with slack users by
+	userKey UserKey
+
+	// botAllowlist are user IDs, usernames or app IDs of integrations which are never removed from channels
+	botAllowlist []string
+
+	// users is the snapshot of the workspace users, it is empty unless a UserSnapshotRefresher is running
+	users *userSnapshot
+
+	// authErr is the result of the last CheckAuth
+	authErr error
+
+	// transport sends the rate limited requests
+	transport http.RoundTripper
+
+	// userToken is used for the Web API methods of userTokenMethods, e.g. admin methods
+	userToken string
+
+	// pool are the clients of further apps the calls of the slack client are spread across, turn counts the calls
+	poolTokens []string
+	pool       []*pooledClient
+	turn       uint64
+
+	// limiter budgets the calls of the token, rateLimited counts the responses rate limited by Slack anyway, it is
+	// updated atomically
+	limiter     *RateLimiter
+	rateLimited uint64
+
+	// auditLogsURL is the base URL of the Audit Logs API
+	auditLogsURL string
+
+	// auditor records the mutating actions, nil if they aren't audited
+	auditor Auditor
+}
+
+// Option configures a SlackService
+type Option func(*SlackService)
+
+// WithBotAllowlist keeps the users with the given IDs, usernames or app IDs in channels, for integrations which
+// Slack doesn't flag as bots
+func WithBotAllowlist(allowlist []string) Option {
+	return func(s *SlackService) {
+		s.botAllowlist = allowlist
+	}
+}
+
+// WithUserKey matches the users listed by Channels with slack users by the key instead of their email
+func WithUserKey(key UserKey) Option {
+	return func(s *SlackService) {
+		s.userKey = key
+	}
+}
+
+// WithToken sets the token of the service, New sets it to its token
+func WithToken(token string) Option {
+	return func(s *SlackService) {
+		s.token = token
+	}
+}
+
+// WithUserToken calls the Web API methods which need a user or admin token, e.g. admin.* and reminders.*, with the
+// user token instead of the token of the service
+func WithUserToken(token string) Option {
+	return func(s *SlackService) {
+		s.userToken = token
+	}
+}
+
+// WithAPIURL calls the Web API at apiURL instead of slack.com, e.g. a test server
+func WithAPIURL(apiURL string) Option {
+	return func(s *SlackService) {
+		s.apiURL = apiURL
+	}
+}
+
+// WithTransport sends the requests to Slack with the transport, e.g. one created by NewTransport for a proxy or
+// middleware recording the calls
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *SlackService) {
+		s.transport = transport
+	}
 }
 
-// New creates a new SlackService
-func New(APIToken string, logger logr.Logger) *SlackService {
-	return &SlackService{
-		api: slack.New(APIToken),
-		log: logger,
+// WithMinChannelUsers requires channels to list at least minUsers users
+func WithMinChannelUsers(minUsers int) Option {
+	return func(s *SlackService) {
+		s.minChannelUsers = minUsers
+	}
+}
+
+// New creates a new SlackService, its calls are rate limited to the tiers of the Web API methods. Calls failing
+// for a missing scope return a *MissingScopeError.
+func New(APIToken string, logger logr.Logger, options ...Option) *SlackService {
+	s := newService(logger, append([]Option{WithToken(APIToken)}, options...))
+	s.newAPI = func(token string) API {
+		return slack.New(token, slack.OptionHTTPClient(s.httpClient), slack.OptionAPIURL(s.apiURL))
+	}
+	s.api = s.newAPI(s.token)
+	return s
+}
+
+// NewWithClient creates a SlackService calling Slack with the given client. Web API methods which the client
+// doesn't cover are called with the token of WithToken, rate limited like the calls of New.
+func NewWithClient(api API, logger logr.Logger, options ...Option) *SlackService {
+	s := newService(logger, options)
+	s.api = api
+	return s
+}
+
+func newService(logger logr.Logger, options []Option) *SlackService {
+	s := &SlackService{
+		log:          logger,
+		apiURL:       slack.APIURL,
+		auditLogsURL: AuditLogsURL,
+		users:        &userSnapshot{},
+		transport:    http.DefaultTransport,
+		userKey:      UserKeyEmail,
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.limiter = NewRateLimiter()
+	s.httpClient = &http.Client{
+		Transport: &missingScopeTransport{
+			base: s.newRateLimitedTransport(s.limiter),
+		},
 	}
+	s.newPool()
+	return s
+}
+
+// newRateLimitedTransport sends the requests budgeted by the limiter, failed calls are logged
+func (s *SlackService) newRateLimitedTransport(limiter *RateLimiter) http.RoundTripper {
+	return &rateLimitedTransport{
+		limiter: limiter,
+		base:    &requestLogTransport{base: s.transport, limiter: limiter, log: s.log.WithName("api")},
+		limited: &s.rateLimited,
+	}
+}
+
+// RateLimitBudget returns how many more calls of every called Web API method the tokens of the service have within
+// the current minute
+func (s *SlackService) RateLimitBudget() map[string]int {
+	budget := map[string]int{}
+	limiters := []*RateLimiter{s.limiter}
+	for _, pooled := range s.pool {
+		limiters = append(limiters, pooled.limiter)
+	}
+	for _, limiter := range limiters {
+		for _, method := range limiter.Methods() {
+			budget[method] = 0
+		}
+	}
+	for method := range budget {
+		for _, limiter := range limiters {
+			budget[method] += limiter.Remaining(method)
+		}
+	}
+	return budget
+}
+
+// client returns the slack client for the next read-only call of users and emoji, which takes turns with the clients of
+// the token pool. Writes, calls which depend on the identity of the app and all conversations calls use primary, the
+// pooled apps may not be members of the channel, don't see the private channels they aren't members of and don't own
+// the operator's messages and files.
+func (s *SlackService) client() API {
+	return s.next()
+}
+
+// primary returns the slack client of the current token
+func (s *SlackService) primary() API {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.api
+}
+
+// apiToken returns the current token
+func (s *SlackService) apiToken() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.token
+}
+
+// SetToken replaces the token of the service, calls in flight complete with the previous token. Clients passed to
+// NewWithClient are kept. It returns false when the token didn't change.
+func (s *SlackService) SetToken(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token == s.token {
+		return false
+	}
+	s.token = token
+	if s.newAPI != nil {
+		s.api = s.newAPI(token)
+	}
+	return true
 }
 
 // GetChannel gets a channel on slack
 func (s *SlackService) GetChannel(channelID string) (*slack.Channel, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	channel, err := s.api.GetConversationInfo(channelID, false)
+	channel, err := s.primary().GetConversationInfo(channelID, false)
 	if err != nil {
 		log.Error(err, "Error fetching channel")
-		return nil, err
+		return nil, wrapError(err)
 	}
 
-	return channel, err
+	return channel, nil
 }
 
 // CreateChannel creates a public or private channel on slack with the given name
 func (s *SlackService) CreateChannel(name string, isPrivate bool) (*string, error) {
 	s.log.Info("Creating Slack Channel", "name", name, "isPrivate", isPrivate)
 
-	channel, err := s.api.CreateConversation(name, isPrivate)
+	channel, err := s.primary().CreateConversation(name, isPrivate)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 
-	s.log.V(1).Info("Created Slack Channel", "channel", channel)
+	s.log.V(1).Info("Created Slack Channel", "channelID", channel.ID, "name", channel.Name)
+	s.audit(AuditCreate, channel.ID, channel.Name)
 
 	return &channel.ID, nil
 }
@@ -77,25 +362,26 @@ func (s *SlackService) CreateChannel(name string, isPrivate bool) (*string, erro
 func (s *SlackService) SetDescription(channelID string, description string) (*slack.Channel, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	channel, err := s.api.GetConversationInfo(channelID, false)
+	channel, err := s.primary().GetConversationInfo(channelID, false)
 
 	if err != nil {
 		log.Error(err, "Error fetching channel")
-		return nil, err
+		return nil, wrapError(err)
 	}
 
-	if html.UnescapeString(channel.Purpose.Value) == description {
+	if normalizeText(channel.Purpose.Value) == normalizeText(description) {
 		return channel, nil
 	}
 
 	log.V(1).Info("Setting Description of the Slack Channel")
 
-	channel, err = s.api.SetPurposeOfConversation(channelID, description)
+	channel, err = s.primary().SetPurposeOfConversation(channelID, description)
 
 	if err != nil {
 		log.Error(err, "Error setting description of the channel")
-		return nil, err
+		return nil, wrapError(err)
 	}
+	s.audit(AuditSetDescription, channelID, description)
 	return channel, nil
 }
 
@@ -103,25 +389,26 @@ func (s *SlackService) SetDescription(channelID string, description string) (*sl
 func (s *SlackService) SetTopic(channelID string, topic string) (*slack.Channel, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	channel, err := s.api.GetConversationInfo(channelID, false)
+	channel, err := s.primary().GetConversationInfo(channelID, false)
 
 	if err != nil {
 		log.Error(err, "Error fetching channel")
-		return nil, err
+		return nil, wrapError(err)
 	}
 
-	if html.UnescapeString(channel.Topic.Value) == topic {
+	if normalizeText(channel.Topic.Value) == normalizeText(topic) {
 		return channel, nil
 	}
 
 	log.V(1).Info("Setting Topic of the Slack Channel")
 
-	channel, err = s.api.SetTopicOfConversation(channelID, topic)
+	channel, err = s.primary().SetTopicOfConversation(channelID, topic)
 
 	if err != nil {
 		log.Error(err, "Error setting topic of the channel")
-		return nil, err
+		return nil, wrapError(err)
 	}
+	s.audit(AuditSetTopic, channelID, topic)
 	return channel, nil
 }
 
@@ -129,24 +416,25 @@ func (s *SlackService) SetTopic(channelID string, topic string) (*slack.Channel,
 func (s *SlackService) RenameChannel(channelID string, newName string) (*slack.Channel, error) {
 	log := s.log.WithValues("channelID", channelID)
 
-	channel, err := s.api.GetConversationInfo(channelID, false)
+	channel, err := s.primary().GetConversationInfo(channelID, false)
 
 	if err != nil {
 		log.Error(err, "Error fetching channel")
-		return nil, err
+		return nil, wrapError(err)
 	}
-	if html.UnescapeString(channel.Name) == newName {
+	if normalizeName(channel.Name) == normalizeName(newName) {
 		return channel, nil
 	}
 
 	log.V(1).Info("Renaming Slack Channel", "newName", newName)
 
-	channel, err = s.api.RenameConversation(channelID, newName)
+	channel, err = s.primary().RenameConversation(channelID, newName)
 
 	if err != nil {
 		log.Error(err, "Error renaming channel")
-		return nil, err
+		return nil, wrapError(err)
 	}
+	s.audit(AuditRename, channelID, newName)
 	return channel, nil
 }
 
@@ -155,89 +443,52 @@ func (s *SlackService) ArchiveChannel(channelID string) error {
 	log := s.log.WithValues("channelID", channelID)
 
 	log.V(1).Info("Archiving channel")
-	err := s.api.ArchiveConversation(channelID)
+	err := s.primary().ArchiveConversation(channelID)
 
 	if err != nil {
 		log.Error(err, "Error archiving channel")
-		return err
+		return wrapError(err)
 	}
+	s.audit(AuditArchive, channelID, "")
 
 	return nil
 }
 
-// GetUsersInChannel get all the users in the slack channel
-func (s *SlackService) GetUsersInChannel(channelID string) ([]string, error) {
-	userIDs, _, err := s.api.GetUsersInConversation(&slack.GetUsersInConversationParameters{
-		ChannelID: channelID,
-		Limit:     100000,
+// GetUsersInChannel get all the users in the slack channel, following the cursor through all pages
+func (s *SlackService) GetUsersInChannel(ctx context.Context, channelID string) ([]string, error) {
+	var userIDs []string
+	err := s.forEachMembersPage(ctx, channelID, func(page []string) error {
+		userIDs = append(userIDs, page...)
+		return nil
 	})
-
-	return userIDs, err
-}
-
-// InviteUsers invites users to the slack channel
-func (s *SlackService) InviteUsers(channelID string, userEmails []string) []error {
-	log := s.log.WithValues("channelID", channelID)
-
-	var errorlist []error
-
-	for _, email := range userEmails {
-		user, err := s.api.GetUserByEmail(email)
-
-		if err != nil {
-			errorlist = append(errorlist, fmt.Errorf(fmt.Sprintf("Error fetching user by Email %s", email)))
-			continue
-		}
-
-		log.V(1).Info("Inviting user to Slack Channel", "userID", user.ID)
-		_, err = s.api.InviteUsersToConversation(channelID, user.ID)
-
-		if err != nil && err.Error() != "already_in_channel" && err.Error() != "cant_invite_self" {
-			log.Error(err, "Error Inviting user to channel", "userID", user.ID)
-			errorlist = append(errorlist, err)
-		}
-	}
-
-	return errorlist
-}
-
-// RemoveUsers remove users from the slack channel
-func (s *SlackService) RemoveUsers(channelID string, userEmails []string) error {
-	log := s.log.WithValues("channelID", channelID)
-
-	channelUserIDs, err := s.GetUsersInChannel(channelID)
 	if err != nil {
-		log.Error(err, "Error getting users in a conversation")
-		return err
+		return nil, err
 	}
+	return userIDs, nil
+}
 
-	for _, userId := range channelUserIDs {
-		user, err := s.api.GetUserInfo(userId)
+// forEachMembersPage calls fn with every page of membersPageSize members of the slack channel, following the cursor
+// until the last page or until fn fails
+func (s *SlackService) forEachMembersPage(ctx context.Context, channelID string, fn func(page []string) error) error {
+	var cursor string
+	for {
+		page, nextCursor, err := s.primary().GetUsersInConversationContext(ctx, &slack.GetUsersInConversationParameters{
+			ChannelID: channelID,
+			Cursor:    cursor,
+			Limit:     membersPageSize,
+		})
 		if err != nil {
-			log.Error(err, "Error fetching user info")
+			return wrapError(err)
+		}
+		if err := fn(page); err != nil {
 			return err
 		}
 
-		if !user.IsBot {
-			found := false
-			for _, email := range userEmails {
-				if email == user.Profile.Email {
-					found = true
-					break
-				}
-			}
-
-			if !found {
-				err = s.api.KickUserFromConversation(channelID, user.ID)
-				if err != nil {
-					log.Error(err, "Error removing user from the conversation")
-					return err
-				}
-			}
+		if nextCursor == "" {
+			return nil
 		}
+		cursor = nextCursor
 	}
-
-	return nil
 }
 
 func (s *SlackService) GetChannelCRFromChannel(existingChannel *slack.Channel) *slackv1alpha1.Channel {
@@ -252,90 +503,93 @@ func (s *SlackService) GetChannelCRFromChannel(existingChannel *slack.Channel) *
 	return &channel
 }
 
-func (s *SlackService) IsChannelUpdated(channel *slackv1alpha1.Channel) (bool, error) {
-	log := s.log.WithValues("channelID", channel.Status.ID)
+// FieldChange is a field of a slack channel which differs from the spec, with its value in Slack and in the spec
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
 
-	channelID := channel.Status.ID
-	name := channel.Spec.Name
-	topic := channel.Spec.Topic
-	description := channel.Spec.Description
-	userEmails := channel.Spec.Users
+// String describes the change, e.g. topic "old" -> "new"
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s %q -> %q", c.Field, c.Old, c.New)
+}
 
-	existingChannel, err := s.api.GetConversationInfo(channel.Status.ID, false)
-	if err != nil {
-		log.Error(err, "Error fetching channel")
-		return false, err
+// ChangedFields returns the names of the fields of the changes
+func ChangedFields(changes []FieldChange) []string {
+	var fields []string
+	for _, change := range changes {
+		fields = append(fields, change.Field)
 	}
+	return fields
+}
 
-	if html.UnescapeString(existingChannel.Name) != name {
-		return true, nil
-	}
-	if html.UnescapeString(existingChannel.Topic.Value) != topic {
-		return true, nil
-	}
-	if html.UnescapeString(existingChannel.Purpose.Value) != description {
-		return true, nil
-	}
+// SyncChannelMetadata renames the channel and sets its topic and description when they differ from the spec. The
+// channel is fetched once for all of them, only the changed fields are updated and returned.
+func (s *SlackService) SyncChannelMetadata(channelID string, desired *slackv1alpha1.Channel) ([]FieldChange, error) {
+	log := s.log.WithValues("channelID", channelID)
 
-	channelUserIDs, err := s.GetUsersInChannel(channelID)
+	existingChannel, err := s.primary().GetConversationInfo(channelID, false)
 	if err != nil {
-		log.Error(err, "Error getting users in a conversation")
-		return false, err
+		log.Error(err, "Error fetching channel")
+		return nil, wrapError(err)
 	}
 
-	// Checking if the user is added
-	for _, email := range userEmails {
-		user, err := s.api.GetUserByEmail(email)
-		if err != nil {
-			log.Error(err, fmt.Sprintf("Error fetching user by Email %s", email))
-			return false, err
-		}
-
-		found := false
-		for _, id := range channelUserIDs {
-			if user.ID == id {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return true, nil
+	var applied []FieldChange
+	for _, change := range MetadataDrift(existingChannel, desired) {
+		log.V(1).Info("Updating field of the Slack Channel", "field", change.Field)
+		switch change.Field {
+		case "name":
+			_, err = s.primary().RenameConversation(channelID, change.New)
+		case "topic":
+			_, err = s.primary().SetTopicOfConversation(channelID, change.New)
+		case "description":
+			_, err = s.primary().SetPurposeOfConversation(channelID, change.New)
 		}
-	}
-
-	// Checking if the user is removed
-	for _, userId := range channelUserIDs {
-		user, err := s.api.GetUserInfo(userId)
 		if err != nil {
-			log.Error(err, "Error fetching user info")
-			return false, err
+			log.Error(err, "Error updating field of the channel", "field", change.Field)
+			return applied, wrapError(err)
 		}
+		applied = append(applied, change)
+	}
 
-		if !user.IsBot {
-			found := false
-			for _, email := range userEmails {
-				if email == user.Profile.Email {
-					found = true
-					break
-				}
-			}
+	return applied, nil
+}
 
-			if !found {
-				return true, nil
-			}
-		}
+// MetadataDrift returns the fields of the slack channel, i.e. name, topic and description, which differ from the
+// desired Channel along with their values
+func MetadataDrift(existing *slack.Channel, desired *slackv1alpha1.Channel) []FieldChange {
+	var changes []FieldChange
+	if normalizeName(existing.Name) != normalizeName(desired.Spec.Name) {
+		changes = append(changes, FieldChange{Field: "name", Old: existing.Name, New: desired.Spec.Name})
+	}
+	if topicDrifted(existing.Topic.Value, desired) {
+		changes = append(changes, FieldChange{Field: "topic", Old: existing.Topic.Value, New: desired.ManagedTopic()})
+	}
+	if normalizeText(existing.Purpose.Value) != normalizeText(desired.Spec.Description) {
+		changes = append(changes, FieldChange{Field: "description", Old: existing.Purpose.Value, New: desired.Spec.Description})
 	}
+	return changes
+}
 
-	return false, nil
+// topicDrifted checks whether the topic of the slack channel differs from the managed topic. Topics of channels with a
+// topic marker drift only when they start with the marker but not with the managed topic, or are empty.
+func topicDrifted(existing string, desired *slackv1alpha1.Channel) bool {
+	existing, topic := normalizeText(existing), normalizeText(desired.ManagedTopic())
+	if desired.Spec.TopicMarker == "" {
+		return existing != topic
+	}
+	if strings.HasPrefix(existing, topic) {
+		return false
+	}
+	return existing == "" || strings.HasPrefix(existing, normalizeText(desired.Spec.TopicMarker))
 }
 
 func (s *SlackService) IsValidChannel(channel *slackv1alpha1.Channel) error {
-	if len(channel.Spec.Users) < 1 {
-		return fmt.Errorf("Users can not be empty")
+	if err := slackv1alpha1.ValidateMinUsers(channel, s.minChannelUsers); err != nil {
+		return err
 	}
-
-	return nil
+	return slackv1alpha1.ValidateFieldLengths(channel)
 }
 
 // GetChannelByName search for the channel on slack by name
@@ -343,7 +597,7 @@ func (s *SlackService) GetChannelByName(name string) (*slack.Channel, error) {
 	var cursor string
 
 	for {
-		channels, nextCursor, err := s.api.GetConversations(&slack.GetConversationsParameters{
+		channels, nextCursor, err := s.primary().GetConversations(&slack.GetConversationsParameters{
 			Types: []string{
 				"private_channel",
 				"public_channel",
@@ -353,7 +607,7 @@ func (s *SlackService) GetChannelByName(name string) (*slack.Channel, error) {
 			ExcludeArchived: "false",
 		})
 		if err != nil {
-			return nil, err
+			return nil, wrapError(err)
 		}
 
 		for _, channel := range channels {
@@ -368,14 +622,79 @@ func (s *SlackService) GetChannelByName(name string) (*slack.Channel, error) {
 		cursor = nextCursor
 	}
 
-	return nil, fmt.Errorf(ChannelAlreadyExistsError)
+	return nil, ErrChannelNotFound
+}
+
+// ListChannels lists the public and private channels, including archived ones, whose names start with the prefix.
+// Names are compared the way Slack stores them, so "#Team-" matches team-a.
+func (s *SlackService) ListChannels(prefix string) ([]slack.Channel, error) {
+	prefix = normalizeName(prefix)
+
+	var matching []slack.Channel
+	var cursor string
+	for {
+		channels, nextCursor, err := s.primary().GetConversations(&slack.GetConversationsParameters{
+			Types: []string{
+				"private_channel",
+				"public_channel",
+			},
+			Cursor:          cursor,
+			Limit:           200,
+			ExcludeArchived: "false",
+		})
+		if err != nil {
+			return nil, wrapError(err)
+		}
+
+		for _, channel := range channels {
+			if strings.HasPrefix(normalizeName(channel.Name), prefix) {
+				matching = append(matching, channel)
+			}
+		}
+
+		if nextCursor == "" {
+			return matching, nil
+		}
+		cursor = nextCursor
+	}
 }
 
 // UnArchiveChannel unarchives the channel
 func (s *SlackService) UnArchiveChannel(channel *slack.Channel) error {
-	err := s.api.UnArchiveConversation(channel.ID)
+	err := s.primary().UnArchiveConversation(channel.ID)
 	if err != nil {
-		return err
+		return wrapError(err)
+	}
+	s.audit(AuditUnarchive, channel.ID, "")
+	return nil
+}
+
+// PostMessage posts a message to the slack channel and returns its timestamp
+func (s *SlackService) PostMessage(channelID string, options ...slack.MsgOption) (string, error) {
+	log := s.log.WithValues("channelID", channelID)
+
+	log.V(1).Info("Posting message to Slack Channel")
+
+	_, timestamp, err := s.primary().PostMessage(channelID, options...)
+	if err != nil {
+		log.Error(err, "Error posting message to channel")
+		return "", wrapError(err)
 	}
+
+	return timestamp, nil
+}
+
+// UpdateMessage replaces the content of a previously posted message
+func (s *SlackService) UpdateMessage(channelID string, timestamp string, options ...slack.MsgOption) error {
+	log := s.log.WithValues("channelID", channelID, "timestamp", timestamp)
+
+	log.V(1).Info("Updating message in Slack Channel")
+
+	_, _, _, err := s.primary().UpdateMessage(channelID, timestamp, options...)
+	if err != nil {
+		log.Error(err, "Error updating message in channel")
+		return wrapError(err)
+	}
+
 	return nil
 }
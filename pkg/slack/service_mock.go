@@ -1,6 +1,8 @@
 package slack
 
 import (
+	"net/http"
+
 	"github.com/go-logr/logr"
 	"github.com/slack-go/slack"
 	"github.com/stakater/slack-operator/pkg/slack/mock"
@@ -20,8 +22,12 @@ func NewMockService(log logr.Logger) *SlackService {
 		opts := slack.OptionAPIURL(testServer.GetAPIURL())
 
 		mockSlackService = &SlackService{
-			api: slack.New("apitoken", opts),
-			log: log.WithName("SlackService"),
+			api:        slack.New("apitoken", opts),
+			log:        log.WithName("SlackService"),
+			token:      "apitoken",
+			apiURL:     testServer.GetAPIURL(),
+			httpClient: http.DefaultClient,
+			users:      &userSnapshot{},
 		}
 	}
 
@@ -1,9 +1,25 @@
 package slack
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/slack-go/slack"
+	slackv1alpha1 "github.com/stakater/slack-operator/api/v1alpha1"
+	"github.com/stakater/slack-operator/pkg/slack/fake"
 	"github.com/stakater/slack-operator/pkg/slack/mock"
 	"github.com/stretchr/testify/assert"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -41,6 +57,7 @@ func TestSlackService_CreateChannel_shouldThrowError_whenChannelWithSameNameExis
 	_, err := s.CreateChannel(mock.NameTakenConversationName, true)
 
 	assert.EqualError(t, err, "name_taken")
+	assert.True(t, errors.Is(err, ErrNameTaken))
 }
 
 func TestSlackService_SetDescription_shouldSetPurpose(t *testing.T) {
@@ -65,6 +82,78 @@ func TestSlackService_RenameChannel_shouldSetNewName(t *testing.T) {
 	assert.Equal(t, "new-channel", channel.Name)
 }
 
+func TestSlackService_SyncChannelMetadata_shouldReturnNoChanges_whenNothingChanged(t *testing.T) {
+	s := NewMockService(log)
+	channel := &slackv1alpha1.Channel{Spec: slackv1alpha1.ChannelSpec{Name: mock.ConversationName}}
+
+	changes, err := s.SyncChannelMetadata(mock.PublicConversationID, channel)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	channel.Spec.Topic = "myTopic"
+	changes, err = s.SyncChannelMetadata(mock.PublicConversationID, channel)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"topic"}, ChangedFields(changes))
+	assert.Equal(t, "myTopic", changes[0].New)
+}
+
+func TestMetadataDrift_shouldKeepNotesAppendedToMarkedTopics(t *testing.T) {
+	desired := &slackv1alpha1.Channel{Spec: slackv1alpha1.ChannelSpec{Name: "ops", Topic: "Runbook: wiki/ops", TopicMarker: "[ops]"}}
+	existing := &slack.Channel{}
+	existing.Name = "ops"
+
+	drift := func(topic string) []FieldChange {
+		existing.Topic.Value = topic
+		return MetadataDrift(existing, desired)
+	}
+
+	assert.Empty(t, drift("[ops] Runbook: wiki/ops"))
+	assert.Empty(t, drift("[ops] Runbook: wiki/ops :fire: incident ongoing"))
+	assert.Empty(t, drift("Taken over for the offsite"))
+	assert.Equal(t, []FieldChange{{Field: "topic", Old: "[ops] Runbook: old", New: "[ops] Runbook: wiki/ops"}}, drift("[ops] Runbook: old"))
+	assert.Equal(t, []string{"topic"}, ChangedFields(drift("")))
+}
+
+func TestSlackService_SyncChannelMetadata_shouldThrowError_whenChannelNotFound(t *testing.T) {
+	s := NewMockService(log)
+	_, err := s.SyncChannelMetadata(mock.NotFoundConversationID, &slackv1alpha1.Channel{})
+	assert.Error(t, err)
+}
+
+func TestSlackService_GetChannelUpdated_shouldReturnTimestamp(t *testing.T) {
+	s := NewMockService(log)
+	updated, err := s.GetChannelUpdated(mock.PublicConversationID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1600000000000), updated)
+}
+
+func TestSlackService_RejoinChannel_shouldInviteBotToPrivateChannels(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.RejoinChannel(mock.PublicConversationID))
+	assert.NoError(t, s.RejoinChannel(mock.PrivateConversationID))
+
+	err := s.RejoinChannel(mock.NotFoundConversationID)
+	assert.True(t, errors.Is(err, ErrChannelNotFound))
+}
+
+func TestSlackService_GetManagedBy_shouldReturnOwnerOfBookmark(t *testing.T) {
+	s := NewMockService(log)
+
+	owner, err := s.GetManagedBy(mock.PublicConversationID)
+	assert.NoError(t, err)
+	assert.Equal(t, mock.ManagedByOwner, owner)
+
+	owner, err = s.GetManagedBy(mock.PrivateConversationID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", owner)
+}
+
+func TestSlackService_AddManagedByMarker_shouldAddBookmark(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.AddManagedByMarker(mock.PrivateConversationID, "default/ops"))
+	assert.Error(t, s.AddManagedByMarker(mock.NotFoundConversationID, "default/ops"))
+}
+
 func TestSlackService_ArchiveChannel_shouldArchiveChannel(t *testing.T) {
 	s := NewMockService(log)
 	err := s.ArchiveChannel(mock.PublicConversationID)
@@ -75,18 +164,1157 @@ func TestSlackService_ArchiveChannel_shouldThrowError_whenChannelNotFound(t *tes
 	s := NewMockService(log)
 	err := s.ArchiveChannel(mock.NotFoundConversationID)
 	assert.EqualError(t, err, "channel_not_found")
+	assert.True(t, errors.Is(err, ErrChannelNotFound))
 }
 
-func TestSlackService_InviteUsers_shouldSendUserInvites_whenUserExists(t *testing.T) {
+func TestWrapError_shouldMatchErrorsOfKnownCodes(t *testing.T) {
+	assert.True(t, errors.Is(wrapError(errors.New("missing_scope")), ErrMissingScope))
+	assert.True(t, errors.Is(wrapError(&slack.RateLimitedError{RetryAfter: time.Second}), ErrRateLimited))
+	assert.False(t, errors.Is(wrapError(errors.New("invalid_auth")), ErrMissingScope))
+	assert.Nil(t, wrapError(nil))
+}
+
+func TestSlackService_GetUsersInChannel_shouldFollowCursor(t *testing.T) {
 	s := NewMockService(log)
-	errs := s.InviteUsers(mock.PublicConversationID, []string{mock.ExistingUserEmail})
-	assert.Equal(t, 0, len(errs))
+	userIDs, err := s.GetUsersInChannel(context.Background(), mock.PublicConversationID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U023BECGF", "U061F7AUR", "W012A3CDE", "U0G9QF9C6"}, userIDs)
+}
+
+func TestSlackService_PlanMembership_shouldOnlyRemoveMembersWhoAreNotListed(t *testing.T) {
+	s := NewMockService(log)
+	plan, err := s.PlanMembership(context.Background(), mock.PublicConversationID, []string{mock.ExistingUserEmail, "id:W012A3CDE"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Invite)
+	assert.Equal(t, []string{"U023BECGF", "U061F7AUR", "U0G9QF9C6"}, plan.Remove)
+	assert.Equal(t, 0, len(plan.MemberErrors))
+	assert.True(t, plan.Changed())
 }
 
-func TestSlackService_InviteUsers_shouldThowError_whenUserDoesNotExists(t *testing.T) {
+func TestSlackService_PlanMembership_shouldReturnMemberError_whenUserDoesNotExists(t *testing.T) {
 	s := NewMockService(log)
 	emailList := []string{"spengler@ghostbusters.example.com"}
-	errs := s.InviteUsers(mock.PublicConversationID, emailList)
-	assert.Equal(t, 1, len(errs))
-	assert.EqualError(t, errs[0], fmt.Sprintf("Error fetching user by Email %s", emailList[0]))
+	plan, err := s.PlanMembership(context.Background(), mock.PublicConversationID, emailList, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []slackv1alpha1.MemberError{{User: emailList[0], Reason: MemberNotFound}}, plan.MemberErrors)
+	assert.Empty(t, plan.Invite)
+}
+
+func TestSlackService_PlanMembership_shouldNotRemoveMembers_whenListedUsersCantBeLookedUp(t *testing.T) {
+	s := *NewMockService(log)
+	s.users = &userSnapshot{}
+	s.users.set(channelMembers())
+
+	plan, err := s.PlanMembership(context.Background(), mock.PublicConversationID, []string{mock.NotVisibleUserEmail, "id:W012A3CDE"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Remove)
+	assert.Equal(t, []slackv1alpha1.MemberError{{User: mock.NotVisibleUserEmail, Reason: MemberLookupFailed}}, plan.MemberErrors)
+
+	_, err = s.PlanMembership(context.Background(), mock.PublicConversationID, []string{mock.InternalErrorUserEmail, "id:W012A3CDE"}, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestSlackService_PlanMembership_shouldKeepMembersWithHiddenEmails_whenListedEmailsAreNotFound(t *testing.T) {
+	s := *NewMockService(log)
+	s.users = &userSnapshot{}
+	s.users.set(channelMembers())
+
+	plan, err := s.PlanMembership(context.Background(), mock.PublicConversationID, []string{"spengler@ghostbusters.example.com", "id:W012A3CDE"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U061F7AUR", "U0G9QF9C6"}, plan.Remove)
+
+	plan, err = s.PlanMembership(context.Background(), mock.PublicConversationID, []string{"id:W012A3CDE"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U023BECGF", "U061F7AUR", "U0G9QF9C6"}, plan.Remove)
+}
+
+func TestSlackService_PlanMembership_shouldMatchUsersByUsername(t *testing.T) {
+	s := *NewMockService(log)
+	s.users = &userSnapshot{}
+	WithUserKey(UserKeyUsername)(&s)
+
+	_, err := s.PlanMembership(context.Background(), mock.PublicConversationID, []string{"venkman"}, nil, nil)
+	assert.Error(t, err, "matching by username needs the users snapshot")
+
+	s.users.set(channelMembers())
+	plan, err := s.PlanMembership(context.Background(), mock.PublicConversationID, []string{"venkman", "@spengler", "stantz"}, nil, []string{"@zeddemore"})
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Invite)
+	assert.Equal(t, []string{"U061F7AUR"}, plan.Remove)
+	assert.Equal(t, []slackv1alpha1.MemberError{{User: "stantz", Reason: MemberNotFound}}, plan.MemberErrors)
+}
+
+func TestParseUserKey(t *testing.T) {
+	key, err := ParseUserKey("Username")
+	assert.NoError(t, err)
+	assert.Equal(t, UserKeyUsername, key)
+
+	_, err = ParseUserKey("handle")
+	assert.Error(t, err)
+}
+
+// channelMembers are the members of the public channel of the mock, U023BECGF hides their email
+func TestSlackService_PlanPolicyMembership_shouldOnlyInviteMandatoryAndRemoveForbiddenMembers(t *testing.T) {
+	s := *NewMockService(log)
+	s.users = &userSnapshot{}
+	s.users.set(append(channelMembers(), slack.User{ID: "U0COMPLY", Name: "compliance", Profile: slack.UserProfile{Email: "compliance@example.com"}}))
+
+	plan, err := s.PlanPolicyMembership(context.Background(), mock.PublicConversationID, []string{"compliance@example.com", "egon@example.com"},
+		[]string{"winston@example.com", "id:U023BECGF", "ray@example.com"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U0COMPLY"}, plan.Invite)
+	assert.Equal(t, []string{"U023BECGF", "U0G9QF9C6"}, plan.Remove)
+	assert.Empty(t, plan.MemberErrors)
+	assert.Equal(t, 4, plan.Members)
+}
+
+func channelMembers() []slack.User {
+	user := func(id, name, email string) slack.User {
+		return slack.User{ID: id, Name: name, Profile: slack.UserProfile{Email: email}}
+	}
+	return []slack.User{
+		user("U023BECGF", "venkman", ""),
+		user("U061F7AUR", "barrett", "dana@example.com"),
+		user("W012A3CDE", "spengler", "egon@example.com"),
+		user("U0G9QF9C6", "zeddemore", "winston@example.com"),
+	}
+}
+
+func TestSlackService_ApplyMembership_shouldReturnMemberErrorsOfThePlan(t *testing.T) {
+	s := NewMockService(log)
+	plan := &MembershipPlan{
+		Invite:       []string{"U012ABC"},
+		Remove:       []string{"U023BECGF"},
+		MemberErrors: []slackv1alpha1.MemberError{{User: "jane@example.com", Reason: MemberNotFound}},
+	}
+	memberErrors, errs := s.ApplyMembership(context.Background(), mock.PublicConversationID, plan)
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, plan.MemberErrors, memberErrors)
+}
+
+func TestSlackService_ApplyMembership_shouldReportMembersWhoFailedToBeRemoved(t *testing.T) {
+	s := NewMockService(log)
+	plan := &MembershipPlan{Remove: []string{mock.NotFoundUserID, "U023BECGF"}}
+
+	memberErrors, errs := s.ApplyMembership(context.Background(), mock.PublicConversationID, plan)
+	assert.Empty(t, errs)
+	assert.Equal(t, []slackv1alpha1.MemberError{{User: "id:" + mock.NotFoundUserID, Reason: MemberRemoveFailed}}, memberErrors)
+
+	_, errs = s.ApplyMembership(context.Background(), mock.NotFoundConversationID, plan)
+	assert.Len(t, errs, 2)
+}
+
+func TestSlackService_ApplyMembership_shouldStop_whenContextIsDone(t *testing.T) {
+	s := NewMockService(log)
+	plan := &MembershipPlan{Invite: []string{"U012ABC"}, Remove: []string{"U023BECGF"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, errs := s.ApplyMembership(ctx, mock.PublicConversationID, plan)
+	assert.Equal(t, []error{context.Canceled}, errs)
+}
+
+func TestMembershipPlan_Split_shouldKeepMemberErrorsInFirstPart(t *testing.T) {
+	plan := &MembershipPlan{
+		Invite:       []string{"U1", "U2", "U3"},
+		Remove:       []string{"U4"},
+		MemberErrors: []slackv1alpha1.MemberError{{User: "jane@example.com", Reason: MemberNotFound}},
+		emails:       map[string]string{"U1": "u1@example.com"},
+	}
+
+	parts := plan.Split(2)
+	assert.Len(t, parts, 3)
+	assert.Equal(t, []string{"U1", "U2"}, parts[0].Invite)
+	assert.Equal(t, plan.MemberErrors, parts[0].MemberErrors)
+	assert.Equal(t, "u1@example.com", parts[0].emails["U1"])
+	assert.Equal(t, []string{"U3"}, parts[1].Invite)
+	assert.Empty(t, parts[1].MemberErrors)
+	assert.Equal(t, []string{"U4"}, parts[2].Remove)
+
+	assert.Len(t, (&MembershipPlan{}).Split(2), 1)
+}
+
+func TestIsMemberFailure_shouldOnlyMatchErrorsOfTheUser(t *testing.T) {
+	assert.True(t, isMemberFailure(errors.New("user_not_visible")))
+	assert.True(t, isMemberFailure(wrapError(errors.New("users_not_found"))))
+
+	assert.False(t, isMemberFailure(wrapError(errors.New("channel_not_found"))))
+	assert.False(t, isMemberFailure(wrapError(errors.New("ratelimited"))))
+	assert.False(t, isMemberFailure(errors.New("invalid_auth")))
+	assert.False(t, isMemberFailure(errors.New("internal_error")))
+	assert.False(t, isMemberFailure(errors.New("slack server error: 503 Service Unavailable")))
+	assert.False(t, isMemberFailure(&url.Error{Op: "Post", URL: "https://slack.com/api/users.info", Err: errors.New("connection refused")}))
+}
+
+func TestSlackService_PostMessage_shouldReturnTimestamp(t *testing.T) {
+	s := NewMockService(log)
+	ts, err := s.PostMessage(mock.PublicConversationID)
+	assert.NoError(t, err)
+	assert.Equal(t, mock.MessageTimestamp, ts)
+}
+
+func TestSlackService_UpdateMessage_shouldThrowError_whenChannelIsNotFound(t *testing.T) {
+	s := NewMockService(log)
+	err := s.UpdateMessage(mock.NotFoundConversationID, mock.MessageTimestamp)
+	assert.Error(t, err)
+}
+
+func TestSlackService_UploadFile_shouldReturnFileID(t *testing.T) {
+	s := NewMockService(log)
+	fileID, err := s.UploadFile(mock.PublicConversationID, "values.yaml", "", []byte("replicas: 1"))
+	assert.NoError(t, err)
+	assert.Equal(t, mock.FileID, fileID)
+}
+
+func TestSlackService_UploadFile_shouldThrowError_whenChannelIsNotFound(t *testing.T) {
+	s := NewMockService(log)
+	_, err := s.UploadFile(mock.NotFoundConversationID, "values.yaml", "", []byte("replicas: 1"))
+	assert.Error(t, err)
+}
+
+func TestSlackService_DeleteFile_shouldIgnoreMissingFiles(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.DeleteFile(mock.FileID))
+	assert.NoError(t, s.DeleteFile("F0000000000"))
+}
+
+func TestSlackService_CreateChannelCanvas_shouldReturnCanvasID(t *testing.T) {
+	s := NewMockService(log)
+	canvasID, err := s.CreateChannelCanvas(mock.PublicConversationID, "# Runbook")
+	assert.NoError(t, err)
+	assert.Equal(t, mock.CanvasID, canvasID)
+}
+
+func TestSlackService_EditCanvas_shouldThrowError_whenCanvasIsNotFound(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.EditCanvas(mock.CanvasID, "# Runbook"))
+	assert.Error(t, s.EditCanvas("F0000000000", "# Runbook"))
+}
+
+func TestSlackService_AddReminder_shouldReturnReminderID(t *testing.T) {
+	s := NewMockService(log)
+	reminderID, err := s.AddReminder(mock.PublicConversationID, "", "Standup", "every weekday at 9am")
+	assert.NoError(t, err)
+	assert.Equal(t, mock.ReminderID, reminderID)
+}
+
+func TestSlackService_AddReminder_shouldResolveUserByEmail(t *testing.T) {
+	s := NewMockService(log)
+	reminderID, err := s.AddReminder("", mock.ExistingUserEmail, "Standup", "every weekday at 9am")
+	assert.NoError(t, err)
+	assert.Equal(t, mock.ReminderID, reminderID)
+}
+
+func TestSlackService_DeleteReminder_shouldIgnoreMissingReminders(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.DeleteReminder(mock.ReminderID))
+	assert.NoError(t, s.DeleteReminder("Rm00000000"))
+}
+
+func TestSlackService_ListEmoji_shouldReturnCustomEmoji(t *testing.T) {
+	s := NewMockService(log)
+	emoji, err := s.ListEmoji()
+	assert.NoError(t, err)
+	assert.Contains(t, emoji, mock.ExistingEmoji)
+}
+
+func TestSlackService_UploadEmoji_shouldSendMultipartForm(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.UploadEmoji("shipit", []byte("GIF89a")))
+}
+
+func TestSlackService_RemoveEmoji_shouldIgnoreMissingEmoji(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.RemoveEmoji(mock.NotFoundEmoji))
+}
+
+func TestSlackService_RenameEmoji_shouldThrowError_whenEmojiIsNotFound(t *testing.T) {
+	s := NewMockService(log)
+	assert.Error(t, s.RenameEmoji(mock.NotFoundEmoji, "shipit"))
+}
+
+func TestSlackService_CreateApp_shouldReturnCredentials(t *testing.T) {
+	s := NewMockService(log)
+	credentials, err := s.CreateApp("xoxe.xoxp-config", []byte(`{"display_information": {"name": "ci-bot"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, mock.AppID, credentials.AppID)
+	assert.Equal(t, mock.AppClientID, credentials.ClientID)
+}
+
+func TestSlackService_CreateApp_shouldIncludeManifestErrors(t *testing.T) {
+	s := NewMockService(log)
+	_, err := s.CreateApp("xoxe.xoxp-config", []byte(`{}`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "display_information")
+}
+
+func TestSlackService_DeleteApp_shouldIgnoreMissingApps(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.DeleteApp("xoxe.xoxp-config", "A0000000000"))
+}
+
+func TestSlackService_InviteUserToWorkspace_shouldThrowError_whenUserIsInTeam(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.InviteUserToWorkspace(WorkspaceInvite{TeamID: "T1", Email: "new@slack.com", ChannelIDs: []string{mock.PublicConversationID}}))
+
+	err := s.InviteUserToWorkspace(WorkspaceInvite{TeamID: "T1", Email: mock.ExistingUserEmail, ChannelIDs: []string{mock.PublicConversationID}})
+	assert.EqualError(t, err, "already_in_team")
+}
+
+func TestSlackService_LookupUserByEmail_shouldReturnNil_whenUserIsNotFound(t *testing.T) {
+	s := NewMockService(log)
+	user, err := s.LookupUserByEmail("new@slack.com")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+
+	user, err = s.LookupUserByEmail(mock.ExistingUserEmail)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", user.ID)
+}
+
+func TestSlackService_RemoveUserFromWorkspace_shouldIgnoreMissingUsers(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.RemoveUserFromWorkspace("T1", "U1"))
+	assert.NoError(t, s.RemoveUserFromWorkspace("T1", mock.NotFoundUserID))
+}
+
+func TestSlackService_SetUserExpiration_shouldThrowError_whenUserDoesNotExist(t *testing.T) {
+	s := NewMockService(log)
+	assert.NoError(t, s.SetUserExpiration("T1", "U1", 1893456000))
+	assert.EqualError(t, s.SetUserExpiration("T1", mock.NotFoundUserID, 1893456000), "user_not_found")
+}
+
+func TestIsProtected_shouldMatchEmailOrID(t *testing.T) {
+	user := &slack.User{ID: "U0123ABCD", Profile: slack.UserProfile{Email: "Admin@example.com"}}
+
+	assert.True(t, isProtected(user, []string{"admin@example.com"}))
+	assert.True(t, isProtected(user, []string{"U0123ABCD"}))
+	assert.False(t, isProtected(user, []string{"jane@example.com"}))
+	assert.False(t, isProtected(user, nil))
+}
+
+func TestSlackService_isIntegration_shouldKeepBotsAppsAndAllowlistedUsers(t *testing.T) {
+	s := New("apitoken", log, WithBotAllowlist([]string{"legacy-deploy"}))
+
+	assert.True(t, s.isIntegration(&slack.User{ID: "U1", IsBot: true}))
+	assert.True(t, s.isIntegration(&slack.User{ID: "U1", IsAppUser: true}))
+	assert.True(t, s.isIntegration(&slack.User{ID: SlackbotUserID}))
+	assert.True(t, s.isIntegration(&slack.User{ID: "U1", Profile: slack.UserProfile{BotID: "B1"}}))
+	assert.True(t, s.isIntegration(&slack.User{ID: "U1", Name: "legacy-deploy"}))
+	assert.False(t, s.isIntegration(&slack.User{ID: "U1", Name: "jane"}))
+}
+
+func TestSlackService_IsValidChannel_shouldAllowEmptyUsers_unlessMinimumIsConfigured(t *testing.T) {
+	channel := &slackv1alpha1.Channel{}
+
+	assert.NoError(t, New("apitoken", log).IsValidChannel(channel))
+	assert.EqualError(t, New("apitoken", log, WithMinChannelUsers(1)).IsValidChannel(channel), "At least 1 users are required")
+}
+
+func TestSlackService_IsValidChannel_shouldCountEveryMemberSource(t *testing.T) {
+	s := New("apitoken", log, WithMinChannelUsers(3))
+	channel := &slackv1alpha1.Channel{Spec: slackv1alpha1.ChannelSpec{
+		Users:          []string{"jane@example.com"},
+		TemporaryUsers: []slackv1alpha1.TemporaryUser{{Email: "Jane@example.com"}, {Email: "joe@example.com"}},
+	}}
+	assert.EqualError(t, s.IsValidChannel(channel), "At least 3 users are required")
+
+	channel.Spec.MemberSchedules = []slackv1alpha1.MemberSchedule{{Name: "emea", Users: []string{"ann@example.com"}}}
+	assert.NoError(t, s.IsValidChannel(channel))
+
+	channel = &slackv1alpha1.Channel{Spec: slackv1alpha1.ChannelSpec{SCIMGroups: []string{"engineering"}}}
+	assert.NoError(t, s.IsValidChannel(channel))
+}
+
+func TestSlackService_PlanMembership_shouldFallBackToAliases(t *testing.T) {
+	s := NewMockService(log)
+	aliases := map[string][]string{"jdoe@corp.example.com": {"unknown@example.com", mock.ExistingUserEmail}}
+
+	plan, err := s.PlanMembership(context.Background(), mock.PublicConversationID, []string{"jdoe@corp.example.com"}, aliases, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(plan.MemberErrors))
+	assert.NotContains(t, plan.Remove, "W012A3CDE")
+}
+
+func TestSlackService_KickUser_shouldThrowError_whenUserDoesNotExist(t *testing.T) {
+	s := NewMockService(log)
+
+	removed, err := s.KickUser(mock.PublicConversationID, "U012ABC")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+
+	_, err = s.KickUser(mock.PublicConversationID, mock.NotFoundUserID)
+	assert.Error(t, err)
+}
+
+func TestChunk_shouldSplitListIntoBatches(t *testing.T) {
+	assert.Equal(t, [][]string{{"U1", "U2"}, {"U3"}}, chunk([]string{"U1", "U2", "U3"}, 2))
+	assert.Equal(t, [][]string{{"U1", "U2"}}, chunk([]string{"U1", "U2"}, 2))
+	assert.Nil(t, chunk(nil, 2))
+}
+
+func TestSlackService_RefreshUsers_shouldServeLookupsFromSnapshot(t *testing.T) {
+	s := *NewMockService(log)
+	s.users = &userSnapshot{}
+
+	user, err := s.getUserInfo(context.Background(), mock.SnapshotUserID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, mock.SnapshotUserID, user.ID)
+
+	assert.NoError(t, s.RefreshUsers(context.Background()))
+
+	user, err = s.getUserInfo(context.Background(), mock.SnapshotUserID)
+	assert.NoError(t, err)
+	assert.True(t, user.IsBot)
+
+	user, err = s.getUserByEmail(context.Background(), "Snapshot@slack.com")
+	assert.NoError(t, err)
+	assert.Equal(t, mock.SnapshotUserID, user.ID)
+}
+
+func TestRateLimiter_shouldBudgetMethodsByTier(t *testing.T) {
+	assert.Equal(t, tier4, tierOf("users.info"))
+	assert.Equal(t, tier3, tierOf("conversations.setTopic"))
+
+	limiter := NewRateLimiter()
+	assert.NoError(t, limiter.Wait(context.Background(), "apps.manifest.create"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, limiter.Wait(ctx, "apps.manifest.create"))
+}
+
+func TestSlackService_IsValidChannel_shouldRejectLongTopic_unlessTruncated(t *testing.T) {
+	channel := &slackv1alpha1.Channel{Spec: slackv1alpha1.ChannelSpec{Topic: strings.Repeat("ü", 251)}}
+	assert.Error(t, New("apitoken", log).IsValidChannel(channel))
+
+	channel.Spec.TruncateLongFields = true
+	assert.NoError(t, New("apitoken", log).IsValidChannel(channel))
+
+	truncated, fields := slackv1alpha1.TruncateLongFields(channel)
+	assert.Equal(t, []string{"topic"}, fields)
+	assert.Equal(t, strings.Repeat("ü", 249)+"…", truncated.Spec.Topic)
+	assert.Equal(t, strings.Repeat("ü", 251), channel.Spec.Topic)
+}
+
+func TestNormalizeText_shouldIgnoreSlackFormatting(t *testing.T) {
+	assert.Equal(t, normalizeText("Runbook: https://example.com 🚀 “ship it”"), normalizeText("Runbook:  <https://example.com> :rocket: \"ship it\""))
+	assert.Equal(t, normalizeText("see example.com & docs"), normalizeText("see <http://example.com|example.com> &amp; docs"))
+	assert.Equal(t, "mail jane@example.com", normalizeText("mail <mailto:jane@example.com>"))
+	assert.NotEqual(t, normalizeText("on-call: jane"), normalizeText("on-call: john"))
+	assert.Equal(t, normalizeName("#Ops"), normalizeName("ops"))
+}
+
+func TestMissingScopeTransport_shouldReturnNeededScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":false,"error":"missing_scope","needed":"groups:write","provided":"channels:manage"}`))
+	}))
+	defer server.Close()
+
+	api := slack.New("token", slack.OptionAPIURL(server.URL+"/"), slack.OptionHTTPClient(&http.Client{
+		Transport: &missingScopeTransport{base: http.DefaultTransport},
+	}))
+	_, err := api.CreateConversation("private", true)
+
+	var missingScope *MissingScopeError
+	assert.True(t, errors.As(err, &missingScope))
+	assert.Equal(t, "conversations.create", missingScope.Method)
+	assert.Equal(t, "groups:write", missingScope.Needed)
+	assert.True(t, errors.Is(wrapError(err), ErrMissingScope))
+}
+
+func TestSlackService_CheckAuth_shouldReportMissingScopes(t *testing.T) {
+	scopes := strings.Join(RequiredScopes, ",")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", scopes)
+		_, _ = w.Write([]byte(`{"ok":true,"team":"Stakater","user":"slack-operator"}`))
+	}))
+	defer server.Close()
+
+	s := New("token", log)
+	s.apiURL = server.URL + "/"
+	assert.NoError(t, s.CheckAuth())
+
+	scopes = "channels:manage,channels:read,users:read"
+	err := s.CheckAuth()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "groups:write")
+	assert.NotContains(t, err.Error(), "channels:manage")
+}
+
+type memoryTokenStore struct {
+	token *RotatingToken
+	// failSaves is the number of saves which fail
+	failSaves int
+}
+
+func (m *memoryTokenStore) Load(context.Context) (*RotatingToken, error) {
+	return m.token, nil
+}
+
+func (m *memoryTokenStore) Save(_ context.Context, token *RotatingToken) error {
+	if m.failSaves > 0 {
+		m.failSaves--
+		return errors.New("conflict")
+	}
+	m.token = token
+	return nil
+}
+
+func TestTokenRotator_shouldRefreshToken_whenItExpiresSoon(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "xoxe-1", r.FormValue("refresh_token"))
+		_, _ = w.Write([]byte(`{"ok":true,"access_token":"xoxe.xoxb-2","refresh_token":"xoxe-2","expires_in":43200}`))
+	}))
+	defer server.Close()
+
+	s := New("xoxe.xoxb-1", log)
+	s.apiURL = server.URL + "/"
+	store := &memoryTokenStore{token: &RotatingToken{
+		AccessToken:  "xoxe.xoxb-1",
+		RefreshToken: "xoxe-1",
+		ExpiresAt:    time.Now().Add(2 * time.Hour),
+	}}
+	rotator := NewTokenRotator(s, store)
+
+	assert.NoError(t, rotator.rotate(context.Background()))
+	assert.Equal(t, "xoxe.xoxb-1", s.apiToken())
+
+	rotator.now = func() time.Time { return time.Now().Add(time.Hour + 45*time.Minute) }
+	assert.NoError(t, rotator.rotate(context.Background()))
+	assert.Equal(t, "xoxe.xoxb-2", s.apiToken())
+	assert.Equal(t, "xoxe-2", store.token.RefreshToken)
+}
+
+func TestTokenRotator_shouldKeepRefreshedToken_whenSavingItFails(t *testing.T) {
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		_, _ = w.Write([]byte(`{"ok":true,"access_token":"xoxe.xoxb-2","refresh_token":"xoxe-2","expires_in":43200}`))
+	}))
+	defer server.Close()
+
+	s := New("xoxe.xoxb-1", log)
+	s.apiURL = server.URL + "/"
+	store := &memoryTokenStore{failSaves: 2, token: &RotatingToken{
+		AccessToken:  "xoxe.xoxb-1",
+		RefreshToken: "xoxe-1",
+		ExpiresAt:    time.Now().Add(10 * time.Minute),
+	}}
+	rotator := NewTokenRotator(s, store)
+
+	assert.Error(t, rotator.rotate(context.Background()))
+	assert.Equal(t, "xoxe.xoxb-2", s.apiToken())
+	assert.Equal(t, "xoxe-1", store.token.RefreshToken)
+
+	assert.Error(t, rotator.rotate(context.Background()))
+	assert.NoError(t, rotator.rotate(context.Background()))
+	assert.Equal(t, "xoxe-2", store.token.RefreshToken)
+	assert.Equal(t, 1, refreshes)
+}
+
+func TestNewTransport_shouldApplyProxyAndTLSVersion(t *testing.T) {
+	transport, err := NewTransport(TransportConfig{ProxyURL: "http://proxy.example.com:3128", MinTLSVersion: "1.3"})
+	assert.NoError(t, err)
+
+	proxy, err := transport.Proxy(httptest.NewRequest(http.MethodPost, "https://slack.com/api/auth.test", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "proxy.example.com:3128", proxy.Host)
+	assert.Equal(t, uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion)
+
+	_, err = NewTransport(TransportConfig{MinTLSVersion: "1.4"})
+	assert.Error(t, err)
+	_, err = NewTransport(TransportConfig{CABundle: "/does/not/exist.pem"})
+	assert.Error(t, err)
+}
+
+func TestNewTransport_shouldTrustCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, ioutil.WriteFile(bundle, certificate, 0600))
+
+	transport, err := NewTransport(TransportConfig{CABundle: bundle})
+	assert.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+}
+
+// recordingAPI records the channels created through it and passes other calls to the embedded API
+type recordingAPI struct {
+	API
+	created []string
+}
+
+func (r *recordingAPI) CreateConversation(channelName string, isPrivate bool) (*slack.Channel, error) {
+	r.created = append(r.created, channelName)
+	return r.API.CreateConversation(channelName, isPrivate)
+}
+
+func TestNewWithClient_shouldCallSlackWithClient(t *testing.T) {
+	mockService := NewMockService(log)
+	api := &recordingAPI{API: mockService.api}
+
+	s := NewWithClient(api, log, WithToken("apitoken"), WithAPIURL(mockService.apiURL))
+	id, err := s.CreateChannel("recorded", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, mock.PublicConversationID, *id)
+	assert.Equal(t, []string{"recorded"}, api.created)
+
+	s.SetToken("rotated")
+	assert.Equal(t, api, s.client())
+}
+
+func TestSlackService_tokenFor_shouldRouteAdminMethodsToUserToken(t *testing.T) {
+	s := New("xoxb-bot", log)
+	assert.Equal(t, "xoxb-bot", s.tokenFor("admin.conversations.invite"))
+
+	s = New("xoxb-bot", log, WithUserToken("xoxp-user"))
+	assert.Equal(t, "xoxp-user", s.tokenFor("admin.conversations.invite"))
+	assert.Equal(t, "xoxp-user", s.tokenFor("reminders.add"))
+	assert.Equal(t, "xoxb-bot", s.tokenFor("conversations.info"))
+}
+
+func TestSlackService_WithTokenPool_shouldTakeTurns(t *testing.T) {
+	mockService := NewMockService(log)
+	s := New("apitoken", log, WithAPIURL(mockService.apiURL), WithTokenPool([]string{"pooled"}))
+
+	assert.Len(t, s.pool, 1)
+	assert.Equal(t, s.pool[0].api, s.client())
+	assert.Equal(t, s.primary(), s.client())
+	assert.Equal(t, s.pool[0].api, s.client())
+}
+
+func TestSlackService_WithTokenPool_shouldSendConversationsCallsThroughThePrimaryClient(t *testing.T) {
+	mockService := NewMockService(log)
+	s := New("apitoken", log, WithAPIURL(mockService.apiURL), WithTokenPool([]string{"pooled"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := s.CreateChannel("pooled", true)
+			assert.NoError(t, err)
+			assert.Equal(t, mock.PrivateConversationID, *id)
+
+			_, err = s.GetChannel(mock.PrivateConversationID)
+			assert.NoError(t, err)
+			_, err = s.GetUsersInChannel(context.Background(), mock.PrivateConversationID)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	for _, method := range s.pool[0].limiter.Methods() {
+		assert.NotContains(t, method, "conversations.")
+	}
+}
+
+func TestSlackService_WithTokenPool_shouldSendWritesThroughThePrimaryClient(t *testing.T) {
+	mockService := NewMockService(log)
+	s := New("apitoken", log, WithAPIURL(mockService.apiURL), WithTokenPool([]string{"pooled"}))
+
+	for i := 0; i < 2; i++ {
+		_, err := s.KickUser(mock.PublicConversationID, "U023BECGF")
+		assert.NoError(t, err)
+		assert.NoError(t, s.ArchiveChannel(mock.PublicConversationID))
+	}
+	assert.NotContains(t, s.pool[0].limiter.Methods(), "conversations.kick")
+	assert.NotContains(t, s.pool[0].limiter.Methods(), "conversations.archive")
+}
+
+func TestAuditLogPoller_shouldHandleChannelChangesOfOtherUsers(t *testing.T) {
+	entries := `[]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth.test":
+			_, _ = w.Write([]byte(`{"ok":true,"user_id":"UBOT"}`))
+		case "/logs":
+			assert.Equal(t, "Bearer xoxp-user", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"entries":` + entries + `}`))
+		}
+	}))
+	defer server.Close()
+
+	s := New("token", log, WithAPIURL(server.URL+"/"), WithUserToken("xoxp-user"))
+	s.auditLogsURL = server.URL + "/"
+
+	var handled []string
+	poller := NewAuditLogPoller(s, time.Minute, func(_ context.Context, entry AuditLogEntry) {
+		handled = append(handled, entry.ID)
+	})
+	poller.oldest = 100
+	poller.seen = map[string]bool{}
+
+	entries = `[
+		{"id":"4","date_create":102,"action":"user_channel_join","actor":{"user":{"id":"UBOT"}},"entity":{"type":"channel","channel":{"id":"C1"}}},
+		{"id":"3","date_create":102,"action":"file_downloaded","actor":{"user":{"id":"U1"}},"entity":{"type":"file"}},
+		{"id":"2","date_create":101,"action":"channel_renamed","actor":{"user":{"id":"U1"}},"entity":{"type":"channel","channel":{"id":"C1"}}},
+		{"id":"1","date_create":100,"action":"user_channel_leave","actor":{"user":{"id":"U2"}},"entity":{"type":"channel","channel":{"id":"C2"}}}
+	]`
+	assert.NoError(t, poller.poll(context.Background()))
+	assert.Equal(t, []string{"1", "2"}, handled)
+	assert.Equal(t, int64(102), poller.oldest)
+
+	// Entries of the last second which were already handled are skipped
+	handled = nil
+	entries = `[
+		{"id":"5","date_create":102,"action":"channel_archive","actor":{"user":{"id":"U1"}},"entity":{"type":"channel","channel":{"id":"C1"}}},
+		{"id":"4","date_create":102,"action":"user_channel_join","actor":{"user":{"id":"UBOT"}},"entity":{"type":"channel","channel":{"id":"C1"}}}
+	]`
+	assert.NoError(t, poller.poll(context.Background()))
+	assert.Equal(t, []string{"5"}, handled)
+}
+
+func TestOperatorNotifier_shouldPostProblemsOnceAndWhenResolved(t *testing.T) {
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chat.postMessage" {
+			posted = append(posted, r.FormValue("text"))
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"COPS","ts":"1"}`))
+	}))
+	defer server.Close()
+
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	certFile := filepath.Join(t.TempDir(), "tls.crt")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+	assert.NoError(t, ioutil.WriteFile(certFile, certificate, 0600))
+
+	s := New("token", log, WithAPIURL(server.URL+"/"))
+	notifier := NewOperatorNotifier(s, "COPS", certFile)
+	expiry := tlsServer.Certificate().NotAfter
+
+	notifier.notify(notifier.check(expiry.Add(-24 * time.Hour)))
+	notifier.notify(notifier.check(expiry.Add(-24 * time.Hour)))
+	if assert.Len(t, posted, 1) {
+		assert.Contains(t, posted[0], "Webhook certificate expires")
+	}
+
+	s.rateLimited = sustainedRateLimits
+	notifier.notify(notifier.check(expiry.Add(-30 * 24 * time.Hour)))
+	if assert.Len(t, posted, 3) {
+		assert.Contains(t, posted[1], "Slack rate limited 10 requests")
+		assert.Contains(t, posted[2], "Resolved: Webhook certificate expires")
+	}
+}
+
+func TestSlackService_shouldReconcileChannelAgainstFakeServer(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	alice := server.AddUser(fake.User{Name: "alice", Email: "alice@example.com"})
+	bob := server.AddUser(fake.User{Name: "bob", Email: "bob@example.com"})
+
+	s := New("token", log, WithAPIURL(server.URL()))
+
+	id, err := s.CreateChannel("my-channel", false)
+	assert.NoError(t, err)
+	assert.NoError(t, s.AddManagedByMarker(*id, "default/my-channel"))
+
+	desired := &slackv1alpha1.Channel{Spec: slackv1alpha1.ChannelSpec{
+		Name: "renamed-channel", Topic: "topic", Description: "description", Users: []string{"alice@example.com"},
+	}}
+	changes, err := s.SyncChannelMetadata(*id, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "topic", "description"}, ChangedFields(changes))
+
+	plan, err := s.PlanMembership(context.Background(), *id, desired.Spec.Users, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{alice.ID}, plan.Invite)
+	memberErrors, errs := s.ApplyMembership(context.Background(), *id, plan)
+	assert.Empty(t, memberErrors)
+	assert.Empty(t, errs)
+
+	channel, found := server.Channel(*id)
+	assert.True(t, found)
+	assert.Equal(t, "renamed-channel", channel.Name)
+	assert.Equal(t, "topic", channel.Topic)
+	assert.Equal(t, "description", channel.Description)
+	assert.ElementsMatch(t, []string{server.BotUserID, alice.ID}, channel.Members)
+
+	owner, err := s.GetManagedBy(*id)
+	assert.NoError(t, err)
+	assert.Equal(t, "default/my-channel", owner)
+
+	// Changes made in Slack are reverted by the next reconcile
+	server.UpdateChannel(*id, func(channel *fake.Channel) {
+		channel.Topic = "changed in slack"
+		channel.Members = append(channel.Members, bob.ID)
+	})
+	changes, err = s.SyncChannelMetadata(*id, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldChange{{Field: "topic", Old: "changed in slack", New: "topic"}}, changes)
+	plan, err = s.PlanMembership(context.Background(), *id, desired.Spec.Users, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{bob.ID}, plan.Remove)
+	_, errs = s.ApplyMembership(context.Background(), *id, plan)
+	assert.Empty(t, errs)
+
+	channel, _ = server.Channel(*id)
+	assert.Equal(t, "topic", channel.Topic)
+	assert.ElementsMatch(t, []string{server.BotUserID, alice.ID}, channel.Members)
+
+	_, err = s.CreateChannel("renamed-channel", false)
+	assert.Error(t, err)
+}
+
+func TestSlackService_ListChannels_shouldListChannelsOfThePrefix(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	server.AddChannel(fake.Channel{Name: "team-a-alerts"})
+	server.AddChannel(fake.Channel{Name: "team-a-old", Archived: true})
+	server.AddChannel(fake.Channel{Name: "team-b-alerts"})
+
+	s := New("token", log, WithAPIURL(server.URL()))
+
+	channels, err := s.ListChannels("#Team-A-")
+	assert.NoError(t, err)
+	var names []string
+	for _, channel := range channels {
+		names = append(names, channel.Name)
+	}
+	assert.ElementsMatch(t, []string{"team-a-alerts", "team-a-old"}, names)
+}
+
+func TestSlackService_ListMembers_shouldReportTheKindOfMembers(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	member := server.AddUser(fake.User{Name: "member", Email: "member@example.com"})
+	guest := server.AddUser(fake.User{Name: "guest", Restricted: true})
+	deactivated := server.AddUser(fake.User{Name: "deactivated", Deleted: true})
+	channel := server.AddChannel(fake.Channel{
+		Name:    "audited",
+		Members: []string{server.BotUserID, member.ID, guest.ID, deactivated.ID},
+	})
+
+	s := New("token", log, WithAPIURL(server.URL()))
+
+	members, err := s.ListMembers(channel.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChannelMember{
+		{ID: server.BotUserID, Name: "slack-operator", Kind: MemberKindIntegration},
+		{ID: member.ID, Name: "member", Email: "member@example.com", Kind: MemberKindMember},
+		{ID: guest.ID, Name: "guest", Kind: MemberKindGuest},
+		{ID: deactivated.ID, Name: "deactivated", Kind: MemberKindDeactivated},
+	}, members)
+}
+
+func TestSlackService_LatestActivity_shouldIgnoreTheWarning(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	channel := server.AddChannel(fake.Channel{Name: "quiet", Members: []string{server.BotUserID}})
+
+	s := New("token", log, WithAPIURL(server.URL()))
+
+	latest, err := s.LatestActivity(channel.ID, "")
+	assert.NoError(t, err)
+	assert.True(t, latest.IsZero())
+
+	first, err := s.PostMessage(channel.ID, slack.MsgOptionText("hello", false))
+	assert.NoError(t, err)
+	warning, err := s.PostMessage(channel.ID, slack.MsgOptionText("this channel will be archived", false))
+	assert.NoError(t, err)
+
+	latest, err = s.LatestActivity(channel.ID, warning)
+	assert.NoError(t, err)
+	assert.Equal(t, parseTimestamp(first), latest)
+	assert.WithinDuration(t, time.Now(), latest, time.Minute)
+}
+
+func TestSlackService_ChannelActivity_shouldCountMembersAndRecentMessages(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	alice := server.AddUser(fake.User{Name: "alice", Email: "alice@example.com"})
+	channel := server.AddChannel(fake.Channel{Name: "busy", Members: []string{server.BotUserID, alice.ID}})
+	quiet := server.AddChannel(fake.Channel{Name: "quiet", Members: []string{server.BotUserID}})
+
+	lastMonth := time.Now().Add(-30 * 24 * time.Hour)
+	server.AddMessage(fake.Message{Channel: channel.ID, Timestamp: fmt.Sprintf("%d.000001", lastMonth.Unix()), Text: "old"})
+	server.AddMessage(fake.Message{Channel: quiet.ID, Timestamp: fmt.Sprintf("%d.000002", lastMonth.Unix()), Text: "old"})
+
+	s := New("token", log, WithAPIURL(server.URL()))
+	_, err := s.PostMessage(channel.ID, slack.MsgOptionText("first", false))
+	assert.NoError(t, err)
+	latest, err := s.PostMessage(channel.ID, slack.MsgOptionText("second", false))
+	assert.NoError(t, err)
+
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	activity, err := s.ChannelActivity(channel.ID, lastWeek)
+	assert.NoError(t, err)
+	assert.Equal(t, &Activity{Members: 2, LastMessage: parseTimestamp(latest), Messages: 2}, activity)
+
+	activity, err = s.ChannelActivity(quiet.ID, lastWeek)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, activity.Members)
+	assert.Equal(t, 0, activity.Messages)
+	assert.Equal(t, lastMonth.Unix(), activity.LastMessage.Unix())
+}
+
+func TestSlackService_SetRetention_shouldSetAndRemoveCustomRetention(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	channel := server.AddChannel(fake.Channel{Name: "legal"})
+
+	s := New("token", log, WithAPIURL(server.URL()), WithUserToken("user-token"))
+
+	days, err := s.GetRetention(channel.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, days)
+
+	assert.NoError(t, s.SetRetention(channel.ID, 365))
+	days, err = s.GetRetention(channel.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 365, days)
+
+	assert.NoError(t, s.SetRetention(channel.ID, 0))
+	days, err = s.GetRetention(channel.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, days)
+}
+
+func TestUpdateDefaultChannels_shouldKeepOtherDefaultChannels(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	manual := server.AddChannel(fake.Channel{Name: "general"})
+	onboarding := server.AddChannel(fake.Channel{Name: "onboarding"})
+	retired := server.AddChannel(fake.Channel{Name: "retired"})
+
+	s := New("token", log, WithAPIURL(server.URL()), WithUserToken("user-token"))
+	assert.NoError(t, s.SetDefaultChannels(server.TeamID, []string{manual.ID, retired.ID}))
+
+	changed, err := UpdateDefaultChannels(s, []string{onboarding.ID}, []string{retired.ID})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{manual.ID, onboarding.ID}, server.DefaultChannels())
+
+	changed, err = UpdateDefaultChannels(s, []string{onboarding.ID}, []string{retired.ID})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestSlackService_shouldManageBarriers(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	s := New("token", log, WithAPIURL(server.URL()), WithUserToken("user-token"))
+
+	id, err := s.CreateBarrier(Barrier{PrimaryUsergroup: "S1", BarrieredFrom: []string{"S2"}, RestrictedSubjects: []string{"im", "mpim", "call"}})
+	assert.NoError(t, err)
+	assert.NoError(t, s.UpdateBarrier(Barrier{ID: id, PrimaryUsergroup: "S1", BarrieredFrom: []string{"S2", "S3"}, RestrictedSubjects: []string{"im", "mpim", "call"}}))
+
+	barriers, err := s.ListBarriers()
+	assert.NoError(t, err)
+	assert.Equal(t, []Barrier{{ID: id, PrimaryUsergroup: "S1", BarrieredFrom: []string{"S2", "S3"}, RestrictedSubjects: []string{"im", "mpim", "call"}}}, barriers)
+
+	assert.NoError(t, s.DeleteBarrier(id))
+	assert.True(t, errors.Is(s.DeleteBarrier(id), ErrBarrierNotFound))
+	assert.Empty(t, server.Barriers())
+}
+
+func TestSlackService_shouldManageUsergroupChannels(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	onboarding := server.AddChannel(fake.Channel{Name: "onboarding"})
+	engineering := server.AddChannel(fake.Channel{Name: "engineering"})
+
+	s := New("token", log, WithAPIURL(server.URL()), WithUserToken("user-token"))
+
+	assert.NoError(t, s.AddUsergroupChannels("S1", []string{onboarding.ID, engineering.ID}))
+	assert.NoError(t, s.RemoveUsergroupChannels("S1", []string{onboarding.ID}))
+
+	channelIDs, err := s.ListUsergroupChannels("S1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{engineering.ID}, channelIDs)
+	assert.True(t, errors.Is(s.AddUsergroupChannels("S1", []string{"CMISSING"}), ErrChannelNotFound))
+}
+
+func TestSlackService_SetSection_shouldRecordTheSectionInTheMarker(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	channel := server.AddChannel(fake.Channel{Name: "platform-alerts", Members: []string{server.BotUserID}})
+
+	s := New("token", log, WithAPIURL(server.URL()))
+	assert.NoError(t, s.AddManagedByMarker(channel.ID, "default/platform-alerts"))
+
+	assert.NoError(t, s.SetSection(channel.ID, "default/platform-alerts", "Platform"))
+	section, err := s.GetSection(channel.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Platform", section)
+
+	owner, err := s.GetManagedBy(channel.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "default/platform-alerts", owner)
+	assert.Len(t, server.Bookmarks(channel.ID), 1)
+}
+
+func TestCloneChannel_shouldCopyPrototypeAgainstFakeServer(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	alice := server.AddUser(fake.User{Name: "alice", Email: "alice@example.com"})
+	guest := server.AddUser(fake.User{Name: "guest", Restricted: true})
+	prototype := server.AddChannel(fake.Channel{
+		Name:        "project-template",
+		Topic:       "Project kickoff",
+		Description: "Template for project channels",
+		Members:     []string{server.BotUserID, alice.ID, guest.ID},
+	})
+	target := server.AddChannel(fake.Channel{Name: "project-x", Members: []string{server.BotUserID}})
+
+	s := New("token", log, WithAPIURL(server.URL()))
+	assert.NoError(t, s.AddManagedByMarker(prototype.ID, "default/project-template"))
+	assert.NoError(t, s.AddBookmark(prototype.ID, Bookmark{Title: "Runbook", Link: "https://runbooks/project", Type: "link"}))
+	timestamp, err := s.PostMessage(prototype.ID, slack.MsgOptionText("Read the runbook first", false))
+	assert.NoError(t, err)
+	assert.NoError(t, s.AddPin(prototype.ID, timestamp))
+
+	clone, err := CloneChannel(s, prototype.ID, target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Project kickoff", clone.Topic)
+	assert.Equal(t, "Template for project channels", clone.Description)
+	assert.Equal(t, []string{alice.ID}, clone.Members)
+	assert.Equal(t, 1, clone.Bookmarks)
+	assert.Equal(t, 1, clone.Pins)
+
+	bookmarks, err := s.ListBookmarks(target.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, bookmarks, 1) {
+		assert.Equal(t, "https://runbooks/project", bookmarks[0].Link)
+	}
+	pins, err := s.ListPins(target.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, pins, 1) {
+		assert.Equal(t, "Read the runbook first", pins[0].Text)
+	}
+	channel, _ := server.Channel(target.ID)
+	assert.ElementsMatch(t, []string{server.BotUserID, alice.ID}, channel.Members)
+
+	// Cloning again doesn't copy bookmarks and pins twice
+	clone, err = CloneChannel(s, prototype.ID, target.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, clone.Bookmarks)
+	assert.Equal(t, 0, clone.Pins)
+}
+
+func TestMergeChannel_shouldMoveMembersAndPinsAgainstFakeServer(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	alice := server.AddUser(fake.User{Name: "alice", Email: "alice@example.com"})
+	bob := server.AddUser(fake.User{Name: "bob", Email: "bob@example.com"})
+	source := server.AddChannel(fake.Channel{Name: "payments-alerts", Members: []string{server.BotUserID, alice.ID, bob.ID}})
+	target := server.AddChannel(fake.Channel{Name: "payments", Members: []string{server.BotUserID, bob.ID}})
+
+	s := New("token", log, WithAPIURL(server.URL()))
+	timestamp, err := s.PostMessage(source.ID, slack.MsgOptionText("Escalation policy", false))
+	assert.NoError(t, err)
+	assert.NoError(t, s.AddPin(source.ID, timestamp))
+
+	result, err := MergeChannel(s, source.ID, target.ID, false)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{alice.ID, bob.ID}, result.Members)
+	assert.Equal(t, 0, result.Pins)
+	channel, _ := server.Channel(target.ID)
+	assert.ElementsMatch(t, []string{server.BotUserID, alice.ID, bob.ID}, channel.Members)
+
+	result, err = MergeChannel(s, source.ID, target.ID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Pins)
+	pins, err := s.ListPins(target.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, pins, 1) {
+		assert.Equal(t, "Escalation policy", pins[0].Text)
+	}
+}
+
+func TestAuthChecker_shouldRefreshAuthCheck(t *testing.T) {
+	valid := int32(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", strings.Join(RequiredScopes, ","))
+		if atomic.LoadInt32(&valid) == 1 {
+			_, _ = w.Write([]byte(`{"ok":true,"team":"Stakater","user":"slack-operator"}`))
+		} else {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"token_revoked"}`))
+		}
+	}))
+	defer server.Close()
+
+	s := New("token", log, WithAPIURL(server.URL+"/"))
+	assert.NoError(t, s.CheckAuth())
+	assert.NoError(t, s.AuthCheck(nil))
+
+	atomic.StoreInt32(&valid, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = NewAuthChecker(s, 10*time.Millisecond).Start(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		err := s.AuthCheck(nil)
+		return err != nil && strings.Contains(err.Error(), "token_revoked")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCertificateCheck_shouldFail_whenCertificateIsExpiredOrMissing(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+	certFile := filepath.Join(t.TempDir(), "tls.crt")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+	assert.NoError(t, ioutil.WriteFile(certFile, certificate, 0600))
+	expiry := tlsServer.Certificate().NotAfter
+
+	assert.NoError(t, CertificateCheck(certFile)(nil))
+	assert.NoError(t, checkCertificate(certFile, expiry.Add(-time.Minute)))
+	assert.Error(t, checkCertificate(certFile, expiry))
+	assert.Error(t, CertificateCheck(filepath.Join(t.TempDir(), "missing.crt"))(nil))
+}
+
+func TestSlackService_ConvertChannel_shouldConvertBetweenPrivateAndPublic(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	channel := server.AddChannel(fake.Channel{Name: "incident"})
+
+	s := New("token", log, WithAPIURL(server.URL()), WithUserToken("user-token"))
+
+	assert.NoError(t, s.ConvertChannel(channel.ID, true))
+	converted, _ := server.Channel(channel.ID)
+	assert.True(t, converted.Private)
+
+	assert.NoError(t, s.ConvertChannel(channel.ID, false))
+	converted, _ = server.Channel(channel.ID)
+	assert.False(t, converted.Private)
+
+	assert.True(t, errors.Is(s.ConvertChannel("C404", true), ErrChannelNotFound))
+}
+
+func TestWrapError_shouldMatchAdminUnavailable(t *testing.T) {
+	assert.True(t, errors.Is(wrapError(errors.New("not_allowed_token_type")), ErrAdminUnavailable))
+	assert.False(t, errors.Is(wrapError(errors.New("channel_not_found")), ErrAdminUnavailable))
+}
+
+func TestRequestLogTransport_shouldPassFailedResponsesOn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, "c2d8f9a1")
+		_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter()
+	api := slack.New("token", slack.OptionAPIURL(server.URL+"/"), slack.OptionHTTPClient(&http.Client{
+		Transport: &rateLimitedTransport{limiter: limiter, base: &requestLogTransport{base: http.DefaultTransport, limiter: limiter, log: log}},
+	}))
+
+	_, err := api.GetConversationInfo("C0123", false)
+	assert.EqualError(t, err, "channel_not_found")
+	assert.Equal(t, tier3-1, limiter.Remaining("conversations.info"))
+	assert.Equal(t, tier2, limiter.Remaining("conversations.create"))
+	assert.Equal(t, []string{"conversations.info"}, limiter.Methods())
 }
@@ -0,0 +1,167 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// userSnapshot holds the users of the workspace from the last users.list call, indexed by ID, lowercased email and
+// username
+type userSnapshot struct {
+	mu      sync.RWMutex
+	byID    map[string]slack.User
+	byEmail map[string]slack.User
+	byName  map[string]slack.User
+}
+
+func (c *userSnapshot) set(users []slack.User) {
+	byID := make(map[string]slack.User, len(users))
+	byEmail := make(map[string]slack.User, len(users))
+	byName := make(map[string]slack.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+		if user.Profile.Email != "" {
+			byEmail[strings.ToLower(user.Profile.Email)] = user
+		}
+		byName[user.Name] = user
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byID = byID
+	c.byEmail = byEmail
+	c.byName = byName
+}
+
+// loaded checks whether the users were listed at least once
+func (c *userSnapshot) loaded() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.byID != nil
+}
+
+func (c *userSnapshot) getByID(id string) (*slack.User, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	user, found := c.byID[id]
+	return &user, found
+}
+
+func (c *userSnapshot) getByEmail(email string) (*slack.User, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	user, found := c.byEmail[strings.ToLower(email)]
+	return &user, found
+}
+
+func (c *userSnapshot) getByName(name string) (*slack.User, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	user, found := c.byName[name]
+	return &user, found
+}
+
+// SnapshotUsers returns the users of the snapshot sorted by ID, nil if the users weren't listed yet
+func (s *SlackService) SnapshotUsers() []slack.User {
+	s.users.mu.RLock()
+	defer s.users.mu.RUnlock()
+	if s.users.byID == nil {
+		return nil
+	}
+
+	users := make([]slack.User, 0, len(s.users.byID))
+	for _, user := range s.users.byID {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	return users
+}
+
+// RefreshUsers replaces the users snapshot with the current users of the workspace
+func (s *SlackService) RefreshUsers(ctx context.Context) error {
+	users, err := s.client().GetUsersContext(ctx)
+	if err != nil {
+		s.log.Error(err, "Error listing users")
+		return wrapError(err)
+	}
+
+	s.users.set(users)
+	s.log.V(1).Info("Refreshed users snapshot", "users", len(users))
+	return nil
+}
+
+// getUserInfo gets the user by ID from the snapshot, the API is called for users who aren't in it
+func (s *SlackService) getUserInfo(ctx context.Context, userID string) (*slack.User, error) {
+	if user, found := s.users.getByID(userID); found {
+		return user, nil
+	}
+	user, err := s.client().GetUserInfoContext(ctx, userID)
+	return user, wrapError(err)
+}
+
+// getUserByEmail gets the user by email from the snapshot, the API is called for users who aren't in it
+func (s *SlackService) getUserByEmail(ctx context.Context, email string) (*slack.User, error) {
+	if user, found := s.users.getByEmail(email); found {
+		return user, nil
+	}
+	user, err := s.client().GetUserByEmailContext(ctx, email)
+	return user, wrapError(err)
+}
+
+// errNoUserSnapshot fails lookups by username before the users were listed
+var errNoUserSnapshot = errors.New("users are matched by username, which needs the users snapshot")
+
+// getUserByName gets the user by username from the snapshot, there is no API method to look up users by name
+func (s *SlackService) getUserByName(name string) (*slack.User, error) {
+	if !s.users.loaded() {
+		return nil, errNoUserSnapshot
+	}
+	if user, found := s.users.getByName(strings.TrimPrefix(name, "@")); found {
+		return user, nil
+	}
+	return nil, ErrUserNotFound
+}
+
+// UserSnapshotRefresher refreshes the users snapshot of a SlackService periodically while it is running
+type UserSnapshotRefresher struct {
+	service *SlackService
+	period  time.Duration
+}
+
+// NewUserSnapshotRefresher creates a refresher which lists the users of the workspace every period
+func NewUserSnapshotRefresher(service *SlackService, period time.Duration) *UserSnapshotRefresher {
+	return &UserSnapshotRefresher{
+		service: service,
+		period:  period,
+	}
+}
+
+// Start refreshes the snapshot right away and then every period until the context is cancelled. Failed refreshes
+// keep the previous snapshot, lookups of users missing from it fall back to the API.
+func (r *UserSnapshotRefresher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.period)
+	defer ticker.Stop()
+
+	for {
+		_ = r.service.RefreshUsers(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection keeps the snapshot of every replica up to date
+func (r *UserSnapshotRefresher) NeedLeaderElection() bool {
+	return false
+}
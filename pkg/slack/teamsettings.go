@@ -0,0 +1,85 @@
+package slack
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+type teamSettingsResponse struct {
+	slack.SlackResponse
+	Team struct {
+		ID              string   `json:"id"`
+		DefaultChannels []string `json:"default_channels"`
+	} `json:"team"`
+}
+
+// GetDefaultChannels returns the IDs of the channels new members of the workspace join automatically.
+// It needs an Enterprise Grid user token with the admin.teams:read scope.
+func (s *SlackService) GetDefaultChannels(teamID string) ([]string, error) {
+	response := &teamSettingsResponse{}
+	if err := s.callAPI("admin.teams.settings.info", url.Values{"team_id": {teamID}}, response); err != nil {
+		s.log.Error(err, "Error fetching default channels", "teamID", teamID)
+		return nil, err
+	}
+	return response.Team.DefaultChannels, nil
+}
+
+// SetDefaultChannels replaces the channels new members of the workspace join automatically.
+// It needs an Enterprise Grid user token with the admin.teams:write scope.
+func (s *SlackService) SetDefaultChannels(teamID string, channelIDs []string) error {
+	log := s.log.WithValues("teamID", teamID)
+
+	log.V(1).Info("Setting default channels", "channelIDs", channelIDs)
+	err := s.callAPI("admin.teams.settings.setDefaultChannels", url.Values{
+		"team_id":     {teamID},
+		"channel_ids": {strings.Join(channelIDs, ",")},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error setting default channels")
+		return err
+	}
+	return nil
+}
+
+// UpdateDefaultChannels adds and removes channels from the default channels of the workspace of the token, other
+// default channels are kept. It returns whether the default channels changed.
+func UpdateDefaultChannels(service Service, add []string, remove []string) (bool, error) {
+	teamID, err := service.GetTeamID()
+	if err != nil {
+		return false, err
+	}
+	current, err := service.GetDefaultChannels(teamID)
+	if err != nil {
+		return false, err
+	}
+
+	removed := map[string]bool{}
+	for _, channelID := range remove {
+		removed[channelID] = true
+	}
+	present := map[string]bool{}
+	var channelIDs []string
+	changed := false
+	for _, channelID := range current {
+		if removed[channelID] {
+			changed = true
+			continue
+		}
+		present[channelID] = true
+		channelIDs = append(channelIDs, channelID)
+	}
+	for _, channelID := range add {
+		if !present[channelID] {
+			present[channelID] = true
+			channelIDs = append(channelIDs, channelID)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+	return true, service.SetDefaultChannels(teamID, channelIDs)
+}
@@ -0,0 +1,87 @@
+package slack
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// tokenRotations counts the tokens swapped in by a TokenReloader
+var tokenRotations = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "slack_operator_token_rotations_total",
+	Help: "Number of times a rotated Slack token was loaded without restarting the operator",
+})
+
+func init() {
+	metrics.Registry.MustRegister(tokenRotations)
+}
+
+// TokenReloader re-reads the token of a SlackService periodically, so rotated tokens are used without restarting
+// the operator
+type TokenReloader struct {
+	service *SlackService
+	read    func(context.Context) (string, error)
+	period  time.Duration
+
+	// recorder records a TokenRotated event on object, the secret holding the token if there is one
+	recorder record.EventRecorder
+	object   runtime.Object
+}
+
+// NewTokenReloader creates a reloader which reads the token with read every period
+func NewTokenReloader(service *SlackService, read func(context.Context) (string, error), period time.Duration, recorder record.EventRecorder, object runtime.Object) *TokenReloader {
+	return &TokenReloader{
+		service:  service,
+		read:     read,
+		period:   period,
+		recorder: recorder,
+		object:   object,
+	}
+}
+
+// Start reads the token every period until the context is cancelled. A changed token replaces the token of the
+// service and is checked with CheckAuth, tokens which can't be read keep the current one.
+func (r *TokenReloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		token, err := r.read(ctx)
+		if err != nil {
+			r.service.log.Error(err, "Error reading the Slack token, keeping the current token")
+			continue
+		}
+		if token == "" {
+			r.service.log.Info("Slack token is empty, keeping the current token")
+			continue
+		}
+		if !r.service.SetToken(token) {
+			continue
+		}
+
+		r.service.log.Info("Slack token was rotated")
+		tokenRotations.Inc()
+		if r.object != nil {
+			r.recorder.Event(r.object, corev1.EventTypeNormal, "TokenRotated", "Rotated Slack token was loaded")
+		}
+		if err := r.service.CheckAuth(); err != nil {
+			r.service.log.Error(err, "Rotated Slack token failed validation")
+		}
+	}
+}
+
+// NeedLeaderElection keeps the token of every replica up to date
+func (r *TokenReloader) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,70 @@
+package slack
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// tlsVersions are the accepted values of TransportConfig.MinTLSVersion
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TransportConfig configures how the operator reaches Slack from clusters behind an egress proxy or a TLS
+// intercepting firewall
+type TransportConfig struct {
+	// ProxyURL is the proxy for requests to Slack, the HTTPS_PROXY and NO_PROXY environment variables apply
+	// when it's empty
+	ProxyURL string
+	// CABundle is a PEM file of certificates trusted in addition to the system roots
+	CABundle string
+	// MinTLSVersion is the lowest TLS version accepted, one of 1.0, 1.1, 1.2 or 1.3, defaults to 1.2
+	MinTLSVersion string
+}
+
+// NewTransport creates a transport for the configuration
+func NewTransport(config TransportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %v", config.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.MinTLSVersion != "" {
+		version, found := tlsVersions[config.MinTLSVersion]
+		if !found {
+			return nil, fmt.Errorf("invalid minimum TLS version %q, expected 1.0, 1.1, 1.2 or 1.3", config.MinTLSVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if config.CABundle != "" {
+		pem, err := ioutil.ReadFile(config.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle: %v", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s has no PEM certificates", config.CABundle)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
@@ -0,0 +1,60 @@
+package slack
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+type usergroupChannelsResponse struct {
+	slack.SlackResponse
+	Channels []struct {
+		ID string `json:"id"`
+	} `json:"channels"`
+}
+
+// ListUsergroupChannels returns the IDs of the default channels of the usergroup, which its members join when they
+// join the usergroup. It needs an Enterprise Grid user token with the admin.usergroups:read scope.
+func (s *SlackService) ListUsergroupChannels(usergroupID string) ([]string, error) {
+	response := &usergroupChannelsResponse{}
+	if err := s.callAPI("admin.usergroups.listChannels", url.Values{"usergroup_id": {usergroupID}}, response); err != nil {
+		s.log.Error(err, "Error listing usergroup channels", "usergroupID", usergroupID)
+		return nil, err
+	}
+
+	var channelIDs []string
+	for _, channel := range response.Channels {
+		channelIDs = append(channelIDs, channel.ID)
+	}
+	return channelIDs, nil
+}
+
+// AddUsergroupChannels adds default channels to the usergroup.
+// It needs an Enterprise Grid user token with the admin.usergroups:write scope.
+func (s *SlackService) AddUsergroupChannels(usergroupID string, channelIDs []string) error {
+	return s.updateUsergroup("admin.usergroups.addChannels", usergroupID, url.Values{"channel_ids": {strings.Join(channelIDs, ",")}})
+}
+
+// RemoveUsergroupChannels removes default channels from the usergroup
+func (s *SlackService) RemoveUsergroupChannels(usergroupID string, channelIDs []string) error {
+	return s.updateUsergroup("admin.usergroups.removeChannels", usergroupID, url.Values{"channel_ids": {strings.Join(channelIDs, ",")}})
+}
+
+// AddUsergroupTeams adds the workspaces to an organization-wide usergroup, so that it can be used in them
+func (s *SlackService) AddUsergroupTeams(usergroupID string, teamIDs []string) error {
+	return s.updateUsergroup("admin.usergroups.addTeams", usergroupID, url.Values{"team_ids": {strings.Join(teamIDs, ",")}})
+}
+
+// updateUsergroup calls an admin.usergroups method which changes the usergroup
+func (s *SlackService) updateUsergroup(method string, usergroupID string, values url.Values) error {
+	log := s.log.WithValues("usergroupID", usergroupID)
+
+	log.V(1).Info("Updating usergroup", "method", method)
+	values.Set("usergroup_id", usergroupID)
+	if err := s.callAPI(method, values, &slack.SlackResponse{}); err != nil {
+		log.Error(err, "Error updating usergroup", "method", method)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,829 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// userTokenMethods are prefixes of the Web API methods which need a user or admin token
+var userTokenMethods = []string{"admin.", "reminders.", "search."}
+
+// tokenFor returns the token to call the method with, the user token for userTokenMethods if there is one
+func (s *SlackService) tokenFor(method string) string {
+	if s.userToken != "" {
+		for _, prefix := range userTokenMethods {
+			if strings.HasPrefix(method, prefix) {
+				return s.userToken
+			}
+		}
+	}
+	return s.apiToken()
+}
+
+// callAPI calls a Web API method that isn't supported by the slack client and decodes the response into
+// result, which has to embed slack.SlackResponse. The token for the method is used unless values has one
+func (s *SlackService) callAPI(method string, values url.Values, result interface{ Err() error }) error {
+	if values.Get("token") == "" {
+		values.Set("token", s.tokenFor(method))
+	}
+
+	resp, err := s.httpClient.PostForm(s.apiURL+method, values)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %s", method, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return err
+	}
+
+	return wrapError(result.Err())
+}
+
+type uploadURLResponse struct {
+	slack.SlackResponse
+	UploadURL string `json:"upload_url"`
+	FileID    string `json:"file_id"`
+}
+
+// UploadFile uploads content as a file shared in the channel and returns the file ID,
+// it implements files.uploadV2 which reserves an upload URL, uploads the content and completes the upload
+func (s *SlackService) UploadFile(channelID string, filename string, title string, content []byte) (string, error) {
+	log := s.log.WithValues("channelID", channelID, "filename", filename)
+
+	log.V(1).Info("Uploading file to Slack Channel")
+
+	upload := &uploadURLResponse{}
+	err := s.callAPI("files.getUploadURLExternal", url.Values{
+		"filename": {filename},
+		"length":   {strconv.Itoa(len(content))},
+	}, upload)
+	if err != nil {
+		log.Error(err, "Error reserving file upload")
+		return "", err
+	}
+
+	resp, err := s.httpClient.Post(upload.UploadURL, "application/octet-stream", bytes.NewReader(content))
+	if err != nil {
+		log.Error(err, "Error uploading file")
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("File upload returned status %s", resp.Status)
+		log.Error(err, "Error uploading file")
+		return "", err
+	}
+
+	if title == "" {
+		title = filename
+	}
+	files, err := json.Marshal([]map[string]string{{"id": upload.FileID, "title": title}})
+	if err != nil {
+		return "", err
+	}
+
+	err = s.callAPI("files.completeUploadExternal", url.Values{
+		"files":      {string(files)},
+		"channel_id": {channelID},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error completing file upload")
+		return "", err
+	}
+
+	return upload.FileID, nil
+}
+
+// DeleteFile deletes a file, files which are already gone are ignored
+func (s *SlackService) DeleteFile(fileID string) error {
+	log := s.log.WithValues("fileID", fileID)
+
+	log.V(1).Info("Deleting file")
+
+	err := s.primary().DeleteFile(fileID)
+	if err != nil && !isNotFound(err) {
+		log.Error(err, "Error deleting file")
+		return err
+	}
+
+	return nil
+}
+
+// isNotFound checks for slack errors like not_found, file_not_found or channel_not_found
+func isNotFound(err error) bool {
+	return err.Error() == "not_found" || strings.HasSuffix(err.Error(), "_not_found")
+}
+
+type canvasResponse struct {
+	slack.SlackResponse
+	CanvasID string `json:"canvas_id"`
+}
+
+// markdownDocument returns the document_content of a markdown canvas
+func markdownDocument(markdown string) (string, error) {
+	document, err := json.Marshal(map[string]string{"type": "markdown", "markdown": markdown})
+	return string(document), err
+}
+
+// CreateChannelCanvas creates the canvas of the channel from markdown and returns its ID
+func (s *SlackService) CreateChannelCanvas(channelID string, markdown string) (string, error) {
+	log := s.log.WithValues("channelID", channelID)
+
+	log.V(1).Info("Creating channel canvas")
+
+	document, err := markdownDocument(markdown)
+	if err != nil {
+		return "", err
+	}
+
+	canvas := &canvasResponse{}
+	err = s.callAPI("conversations.canvases.create", url.Values{
+		"channel_id":       {channelID},
+		"document_content": {document},
+	}, canvas)
+	if err != nil {
+		log.Error(err, "Error creating channel canvas")
+		return "", err
+	}
+
+	return canvas.CanvasID, nil
+}
+
+// EditCanvas replaces the content of the canvas with markdown
+func (s *SlackService) EditCanvas(canvasID string, markdown string) error {
+	log := s.log.WithValues("canvasID", canvasID)
+
+	log.V(1).Info("Editing canvas")
+
+	document, err := markdownDocument(markdown)
+	if err != nil {
+		return err
+	}
+
+	changes, err := json.Marshal([]map[string]json.RawMessage{{
+		"operation":        json.RawMessage(`"replace"`),
+		"document_content": json.RawMessage(document),
+	}})
+	if err != nil {
+		return err
+	}
+
+	err = s.callAPI("canvases.edit", url.Values{
+		"canvas_id": {canvasID},
+		"changes":   {string(changes)},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error editing canvas")
+		return err
+	}
+
+	return nil
+}
+
+type reminderResponse struct {
+	slack.SlackResponse
+	Reminder struct {
+		ID        string `json:"id"`
+		Recurring bool   `json:"recurring"`
+	} `json:"reminder"`
+}
+
+// AddReminder adds a reminder for the channel or, when channelID is empty, the user with the given email and
+// returns the reminder ID, time accepts unix timestamps and natural language like "every weekday at 9am"
+func (s *SlackService) AddReminder(channelID string, userEmail string, text string, time string) (string, error) {
+	log := s.log.WithValues("channelID", channelID, "user", userEmail)
+
+	values := url.Values{
+		"text": {text},
+		"time": {time},
+	}
+	if channelID != "" {
+		values.Set("channel", channelID)
+	} else {
+		user, err := s.getUserByEmail(context.Background(), userEmail)
+		if err != nil {
+			log.Error(err, "Error fetching user by Email")
+			return "", err
+		}
+		values.Set("user", user.ID)
+	}
+
+	log.V(1).Info("Adding reminder")
+
+	reminder := &reminderResponse{}
+	if err := s.callAPI("reminders.add", values, reminder); err != nil {
+		log.Error(err, "Error adding reminder")
+		return "", err
+	}
+
+	return reminder.Reminder.ID, nil
+}
+
+// DeleteReminder deletes a reminder, reminders which are already gone are ignored
+func (s *SlackService) DeleteReminder(reminderID string) error {
+	log := s.log.WithValues("reminderID", reminderID)
+
+	log.V(1).Info("Deleting reminder")
+
+	err := s.callAPI("reminders.delete", url.Values{"reminder": {reminderID}}, &slack.SlackResponse{})
+	if err != nil && !isNotFound(err) {
+		log.Error(err, "Error deleting reminder")
+		return err
+	}
+
+	return nil
+}
+
+// callMultipartAPI calls a Web API method that expects a multipart form with a file field
+func (s *SlackService) callMultipartAPI(method string, values url.Values, field string, filename string, content []byte, result interface{ Err() error }) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	values.Set("token", s.tokenFor(method))
+	for key := range values {
+		if err := writer.WriteField(key, values.Get(key)); err != nil {
+			return err
+		}
+	}
+
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(content); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Post(s.apiURL+method, writer.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %s", method, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return err
+	}
+
+	return wrapError(result.Err())
+}
+
+// ListEmoji returns the custom emoji of the workspace mapped to their image URL or alias
+func (s *SlackService) ListEmoji() (map[string]string, error) {
+	emoji, err := s.client().GetEmoji()
+	if err != nil {
+		s.log.Error(err, "Error listing emoji")
+		return nil, err
+	}
+	return emoji, nil
+}
+
+// AddEmoji adds a custom emoji from an image URL
+func (s *SlackService) AddEmoji(name string, imageURL string) error {
+	log := s.log.WithValues("emoji", name)
+
+	log.V(1).Info("Adding emoji", "url", imageURL)
+
+	err := s.callAPI("admin.emoji.add", url.Values{
+		"name": {name},
+		"url":  {imageURL},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error adding emoji")
+		return err
+	}
+
+	return nil
+}
+
+// UploadEmoji adds a custom emoji from image data, admin.emoji.add only accepts URLs so emoji.add is used
+func (s *SlackService) UploadEmoji(name string, image []byte) error {
+	log := s.log.WithValues("emoji", name)
+
+	log.V(1).Info("Uploading emoji")
+
+	err := s.callMultipartAPI("emoji.add", url.Values{
+		"name": {name},
+		"mode": {"data"},
+	}, "image", name, image, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error uploading emoji")
+		return err
+	}
+
+	return nil
+}
+
+// RemoveEmoji removes a custom emoji, emoji which are already gone are ignored
+func (s *SlackService) RemoveEmoji(name string) error {
+	log := s.log.WithValues("emoji", name)
+
+	log.V(1).Info("Removing emoji")
+
+	err := s.callAPI("admin.emoji.remove", url.Values{"name": {name}}, &slack.SlackResponse{})
+	if err != nil && !isNotFound(err) {
+		log.Error(err, "Error removing emoji")
+		return err
+	}
+
+	return nil
+}
+
+// RenameEmoji renames a custom emoji
+func (s *SlackService) RenameEmoji(name string, newName string) error {
+	log := s.log.WithValues("emoji", name)
+
+	log.V(1).Info("Renaming emoji", "newName", newName)
+
+	err := s.callAPI("admin.emoji.rename", url.Values{
+		"name":     {name},
+		"new_name": {newName},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error renaming emoji")
+		return err
+	}
+
+	return nil
+}
+
+// AppCredentials are the IDs and secrets of a slack app created from a manifest
+type AppCredentials struct {
+	AppID             string
+	ClientID          string
+	ClientSecret      string
+	VerificationToken string
+	SigningSecret     string
+	OAuthAuthorizeURL string
+}
+
+type appManifestResponse struct {
+	slack.SlackResponse
+	AppID       string `json:"app_id"`
+	Credentials struct {
+		ClientID          string `json:"client_id"`
+		ClientSecret      string `json:"client_secret"`
+		VerificationToken string `json:"verification_token"`
+		SigningSecret     string `json:"signing_secret"`
+	} `json:"credentials"`
+	OAuthAuthorizeURL string `json:"oauth_authorize_url"`
+	Errors            []struct {
+		Message string `json:"message"`
+		Pointer string `json:"pointer"`
+	} `json:"errors"`
+}
+
+// Err includes the manifest validation errors in the error
+func (r appManifestResponse) Err() error {
+	err := r.SlackResponse.Err()
+	if err == nil || len(r.Errors) == 0 {
+		return err
+	}
+
+	messages := []string{err.Error()}
+	for _, manifestErr := range r.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", manifestErr.Pointer, manifestErr.Message))
+	}
+	return errors.New(strings.Join(messages, "\n"))
+}
+
+// CreateApp creates a slack app from a JSON manifest, configToken is an app configuration token
+func (s *SlackService) CreateApp(configToken string, manifest []byte) (*AppCredentials, error) {
+	s.log.V(1).Info("Creating app from manifest")
+
+	response := &appManifestResponse{}
+	err := s.callAPI("apps.manifest.create", url.Values{
+		"token":    {configToken},
+		"manifest": {string(manifest)},
+	}, response)
+	if err != nil {
+		s.log.Error(err, "Error creating app")
+		return nil, err
+	}
+
+	return &AppCredentials{
+		AppID:             response.AppID,
+		ClientID:          response.Credentials.ClientID,
+		ClientSecret:      response.Credentials.ClientSecret,
+		VerificationToken: response.Credentials.VerificationToken,
+		SigningSecret:     response.Credentials.SigningSecret,
+		OAuthAuthorizeURL: response.OAuthAuthorizeURL,
+	}, nil
+}
+
+// UpdateApp replaces the manifest of the slack app
+func (s *SlackService) UpdateApp(configToken string, appID string, manifest []byte) error {
+	log := s.log.WithValues("appID", appID)
+
+	log.V(1).Info("Updating app manifest")
+
+	err := s.callAPI("apps.manifest.update", url.Values{
+		"token":    {configToken},
+		"app_id":   {appID},
+		"manifest": {string(manifest)},
+	}, &appManifestResponse{})
+	if err != nil {
+		log.Error(err, "Error updating app")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteApp deletes the slack app, apps which are already gone are ignored
+func (s *SlackService) DeleteApp(configToken string, appID string) error {
+	log := s.log.WithValues("appID", appID)
+
+	log.V(1).Info("Deleting app")
+
+	err := s.callAPI("apps.manifest.delete", url.Values{
+		"token":  {configToken},
+		"app_id": {appID},
+	}, &slack.SlackResponse{})
+	if err != nil && !isNotFound(err) && err.Error() != "invalid_app_id" {
+		log.Error(err, "Error deleting app")
+		return err
+	}
+
+	return nil
+}
+
+// WorkspaceInvite are the details of an invitation to the workspace
+type WorkspaceInvite struct {
+	TeamID        string
+	Email         string
+	RealName      string
+	ChannelIDs    []string
+	CustomMessage string
+	// Restricted invites a multi-channel guest, UltraRestricted a single-channel guest
+	Restricted      bool
+	UltraRestricted bool
+	// GuestExpiration is the unix timestamp guests are deactivated at, 0 for no expiration
+	GuestExpiration int64
+}
+
+// InviteUserToWorkspace invites a user to the workspace with admin.users.invite
+func (s *SlackService) InviteUserToWorkspace(invite WorkspaceInvite) error {
+	log := s.log.WithValues("email", invite.Email, "teamID", invite.TeamID)
+
+	values := url.Values{
+		"team_id":     {invite.TeamID},
+		"email":       {invite.Email},
+		"channel_ids": {strings.Join(invite.ChannelIDs, ",")},
+	}
+	if invite.RealName != "" {
+		values.Set("real_name", invite.RealName)
+	}
+	if invite.CustomMessage != "" {
+		values.Set("custom_message", invite.CustomMessage)
+	}
+	if invite.Restricted {
+		values.Set("is_restricted", "true")
+	}
+	if invite.UltraRestricted {
+		values.Set("is_ultra_restricted", "true")
+	}
+	if invite.GuestExpiration > 0 {
+		values.Set("guest_expiration_ts", strconv.FormatInt(invite.GuestExpiration, 10))
+	}
+
+	log.V(1).Info("Inviting user to workspace")
+
+	err := s.callAPI("admin.users.invite", values, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error inviting user to workspace")
+		return err
+	}
+
+	return nil
+}
+
+// GetTeamID returns the ID of the workspace the token belongs to
+func (s *SlackService) GetTeamID() (string, error) {
+	auth, err := s.primary().AuthTest()
+	if err != nil {
+		s.log.Error(err, "Error fetching team ID")
+		return "", err
+	}
+	return auth.TeamID, nil
+}
+
+// LookupUserByEmail returns the user with the email, or nil when there is no such user
+func (s *SlackService) LookupUserByEmail(email string) (*slack.User, error) {
+	user, err := s.getUserByEmail(context.Background(), email)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, nil
+		}
+		s.log.Error(err, "Error fetching user by Email", "email", email)
+		return nil, err
+	}
+	return user, nil
+}
+
+// RemoveUserFromWorkspace deactivates the user in the workspace, users which are already gone are ignored
+func (s *SlackService) RemoveUserFromWorkspace(teamID string, userID string) error {
+	log := s.log.WithValues("userID", userID, "teamID", teamID)
+
+	log.V(1).Info("Removing user from workspace")
+
+	err := s.callAPI("admin.users.remove", url.Values{
+		"team_id": {teamID},
+		"user_id": {userID},
+	}, &slack.SlackResponse{})
+	if err != nil && !isNotFound(err) {
+		log.Error(err, "Error removing user from workspace")
+		return err
+	}
+
+	return nil
+}
+
+// SetUserExpiration sets the unix timestamp a guest account is deactivated at
+func (s *SlackService) SetUserExpiration(teamID string, userID string, expiration int64) error {
+	log := s.log.WithValues("userID", userID, "teamID", teamID)
+
+	log.V(1).Info("Setting expiration of guest", "expiration", expiration)
+
+	err := s.callAPI("admin.users.setExpiration", url.Values{
+		"team_id":       {teamID},
+		"user_id":       {userID},
+		"expiration_ts": {strconv.FormatInt(expiration, 10)},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error setting expiration of guest")
+		return err
+	}
+
+	return nil
+}
+
+type channelUpdatedResponse struct {
+	slack.SlackResponse
+	Channel struct {
+		Updated int64 `json:"updated"`
+	} `json:"channel"`
+}
+
+// GetChannelUpdated returns the timestamp in milliseconds of the last change of the channel, which the slack client
+// doesn't expose
+func (s *SlackService) GetChannelUpdated(channelID string) (int64, error) {
+	response := &channelUpdatedResponse{}
+	err := s.callAPI("conversations.info", url.Values{"channel": {channelID}}, response)
+	if err != nil {
+		s.log.Error(err, "Error fetching channel", "channelID", channelID)
+		return 0, err
+	}
+
+	return response.Channel.Updated, nil
+}
+
+// RejoinChannel adds the bot back to a channel it was removed from. Public channels are joined with
+// conversations.join, the bot is invited to private channels with admin.conversations.invite, which requires the
+// token to have the admin.conversations:write scope.
+func (s *SlackService) RejoinChannel(channelID string) error {
+	log := s.log.WithValues("channelID", channelID)
+
+	_, _, _, err := s.primary().JoinConversation(channelID)
+	if err == nil {
+		log.Info("Rejoined channel")
+		s.audit(AuditJoin, channelID, "")
+		return nil
+	}
+	log.V(1).Info("Error joining channel, inviting the bot with the admin API", "error", err.Error())
+
+	auth, err := s.primary().AuthTest()
+	if err != nil {
+		log.Error(err, "Error fetching bot user")
+		return wrapError(err)
+	}
+
+	err = s.callAPI("admin.conversations.invite", url.Values{
+		"channel_id": {channelID},
+		"user_ids":   {auth.UserID},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		log.Error(err, "Error inviting the bot to the channel")
+		return err
+	}
+
+	log.Info("Invited the bot to the channel")
+	s.audit(AuditJoin, channelID, "")
+	return nil
+}
+
+// ManagedByTitle is the title of the bookmark which marks channels managed by the operator
+const ManagedByTitle = "Managed by slack-operator"
+
+// managedByLink is the link of the managed-by bookmark, the Channel managing the slack channel is its owner parameter
+// and the section of the channel its section parameter
+const managedByLink = "https://github.com/stakater/slack-operator"
+
+// Bookmark is a bookmark of a slack channel
+type Bookmark struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+	Type  string `json:"type"`
+}
+
+type bookmarksResponse struct {
+	slack.SlackResponse
+	Bookmarks []Bookmark `json:"bookmarks"`
+}
+
+// ListBookmarks returns the bookmarks of the channel
+func (s *SlackService) ListBookmarks(channelID string) ([]Bookmark, error) {
+	response := &bookmarksResponse{}
+	err := s.callAPI("bookmarks.list", url.Values{"channel_id": {channelID}}, response)
+	if err != nil {
+		s.log.Error(err, "Error listing bookmarks", "channelID", channelID)
+		return nil, err
+	}
+	return response.Bookmarks, nil
+}
+
+// AddBookmark adds a link bookmark to the channel
+func (s *SlackService) AddBookmark(channelID string, bookmark Bookmark) error {
+	err := s.callAPI("bookmarks.add", url.Values{
+		"channel_id": {channelID},
+		"title":      {bookmark.Title},
+		"type":       {"link"},
+		"link":       {bookmark.Link},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		s.log.Error(err, "Error adding bookmark", "channelID", channelID)
+		return err
+	}
+	return nil
+}
+
+// GetManagedBy returns the namespace/name of the Channel in the managed-by bookmark of the channel, it is empty
+// for channels without the bookmark
+func (s *SlackService) GetManagedBy(channelID string) (string, error) {
+	bookmarks, err := s.ListBookmarks(channelID)
+	if err != nil {
+		return "", err
+	}
+
+	_, params, err := managedByMarker(bookmarks)
+	return params.Get("owner"), err
+}
+
+// managedByMarker returns the managed-by bookmark and the parameters of its link, nil if there is none
+func managedByMarker(bookmarks []Bookmark) (*Bookmark, url.Values, error) {
+	for i := range bookmarks {
+		if bookmarks[i].Title != ManagedByTitle {
+			continue
+		}
+		link, err := url.Parse(bookmarks[i].Link)
+		if err != nil {
+			return nil, url.Values{}, fmt.Errorf("Error parsing link of the managed-by bookmark: %v", err)
+		}
+		return &bookmarks[i], link.Query(), nil
+	}
+	return nil, url.Values{}, nil
+}
+
+// managedByURL is the link of the managed-by bookmark of the owner and section
+func managedByURL(owner string, section string) string {
+	params := url.Values{"owner": {owner}}
+	if section != "" {
+		params.Set("section", section)
+	}
+	return managedByLink + "?" + params.Encode()
+}
+
+// AddManagedByMarker bookmarks the channel as managed by the Channel with the namespace/name owner
+func (s *SlackService) AddManagedByMarker(channelID string, owner string) error {
+	s.log.Info("Marking channel as managed by the operator", "channelID", channelID, "owner", owner)
+
+	err := s.callAPI("bookmarks.add", url.Values{
+		"channel_id": {channelID},
+		"title":      {ManagedByTitle},
+		"type":       {"link"},
+		"link":       {managedByURL(owner, "")},
+	}, &slack.SlackResponse{})
+	if err != nil {
+		s.log.Error(err, "Error adding managed-by bookmark", "channelID", channelID)
+		return err
+	}
+	return nil
+}
+
+// GetSection returns the workspace section recorded in the managed-by bookmark of the channel
+func (s *SlackService) GetSection(channelID string) (string, error) {
+	bookmarks, err := s.ListBookmarks(channelID)
+	if err != nil {
+		return "", err
+	}
+	_, params, err := managedByMarker(bookmarks)
+	return params.Get("section"), err
+}
+
+// SetSection records the workspace section of the channel in its managed-by bookmark, the bookmark of the owner is
+// added if the channel has none. Slack has no public API for sidebar sections, so the section is only recorded.
+func (s *SlackService) SetSection(channelID string, owner string, section string) error {
+	log := s.log.WithValues("channelID", channelID)
+
+	bookmarks, err := s.ListBookmarks(channelID)
+	if err != nil {
+		return err
+	}
+	marker, params, err := managedByMarker(bookmarks)
+	if err != nil {
+		return err
+	}
+
+	log.V(1).Info("Setting channel section", "section", section)
+	if marker == nil {
+		err = s.callAPI("bookmarks.add", url.Values{
+			"channel_id": {channelID},
+			"title":      {ManagedByTitle},
+			"type":       {"link"},
+			"link":       {managedByURL(owner, section)},
+		}, &slack.SlackResponse{})
+	} else {
+		err = s.callAPI("bookmarks.edit", url.Values{
+			"channel_id":  {channelID},
+			"bookmark_id": {marker.ID},
+			"link":        {managedByURL(params.Get("owner"), section)},
+		}, &slack.SlackResponse{})
+	}
+	if err != nil {
+		log.Error(err, "Error setting channel section")
+		return err
+	}
+	return nil
+}
+
+// Pin is a message or file pinned to a slack channel
+type Pin struct {
+	Type string `json:"type"`
+	// Timestamp of the pinned message
+	Timestamp string `json:"ts,omitempty"`
+	// Text of the pinned message
+	Text string `json:"text,omitempty"`
+	// FileID of the pinned file
+	FileID string `json:"fileId,omitempty"`
+}
+
+type pinsResponse struct {
+	slack.SlackResponse
+	Items []slack.Item `json:"items"`
+}
+
+// ListPins returns the messages and files pinned to the channel
+func (s *SlackService) ListPins(channelID string) ([]Pin, error) {
+	response := &pinsResponse{}
+	err := s.callAPI("pins.list", url.Values{"channel": {channelID}}, response)
+	if err != nil {
+		s.log.Error(err, "Error listing pins", "channelID", channelID)
+		return nil, err
+	}
+
+	pins := make([]Pin, 0, len(response.Items))
+	for _, item := range response.Items {
+		pin := Pin{Type: item.Type}
+		if item.Message != nil {
+			pin.Timestamp = item.Message.Timestamp
+			pin.Text = item.Message.Text
+		}
+		if item.File != nil {
+			pin.FileID = item.File.ID
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+// AddPin pins the message with the timestamp to the channel
+func (s *SlackService) AddPin(channelID string, timestamp string) error {
+	err := s.callAPI("pins.add", url.Values{"channel": {channelID}, "timestamp": {timestamp}}, &slack.SlackResponse{})
+	if err != nil {
+		s.log.Error(err, "Error pinning message", "channelID", channelID, "timestamp", timestamp)
+		return err
+	}
+	return nil
+}
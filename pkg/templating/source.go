@@ -0,0 +1,37 @@
+package templating
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxSourceSize is the number of bytes of a topic source which are used, the rest of the response is ignored
+const MaxSourceSize = 4096
+
+// Fetch reads the text of a topic source URL, only the first MaxSourceSize bytes are read
+func Fetch(httpClient *http.Client, url string) (string, error) {
+	response, err := httpClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Fetching %s failed with status %d", url, response.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, MaxSourceSize))
+	if err != nil {
+		return "", err
+	}
+
+	// A multibyte character cut off at the limit is dropped
+	text := string(body)
+	for !utf8.ValidString(text) && len(text) > 0 {
+		text = text[:len(text)-1]
+	}
+	return strings.TrimSpace(text), nil
+}
@@ -0,0 +1,42 @@
+package templating
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetch_shouldReturnTrimmedText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Deployment freeze until Monday\n"))
+	}))
+	defer server.Close()
+
+	text, err := Fetch(server.Client(), server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, "Deployment freeze until Monday", text)
+}
+
+func TestFetch_shouldLimitText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", MaxSourceSize-1) + "é and more"))
+	}))
+	defer server.Close()
+
+	text, err := Fetch(server.Client(), server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", MaxSourceSize-1), text)
+}
+
+func TestFetch_shouldThrowError_whenStatusIsNotOK(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := Fetch(server.Client(), server.URL)
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package templating
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/stakater/slack-operator/pkg/blockkit"
+)
+
+// EnvPrefix is the prefix of the operator's environment variables available to templates, without the prefix
+const EnvPrefix = "SLACK_TEMPLATE_"
+
+// Data is what the topic and description templates of a Channel are rendered with
+type Data struct {
+	// Name and Namespace of the Channel
+	Name      string
+	Namespace string
+
+	// Labels and Annotations of the Channel
+	Labels      map[string]string
+	Annotations map[string]string
+
+	// NamespaceLabels are the labels of the namespace of the Channel
+	NamespaceLabels map[string]string
+
+	// Values are the template values of the Channel, read from ConfigMaps
+	Values map[string]string
+
+	// Env are the operator's environment variables prefixed with EnvPrefix
+	Env map[string]string
+
+	// OnCall are the @mentions of who is on call for the schedule of the Channel
+	OnCall string
+
+	// TopicSource is the text of the topic source of the Channel
+	TopicSource string
+
+	// Now is the time the template is rendered at, used to find the current on-call
+	Now time.Time
+}
+
+// IsTemplate checks whether the text is a template, plain text is used as it is
+func IsTemplate(text string) bool {
+	return strings.Contains(text, "{{")
+}
+
+// Validate parses the text, plain text is always valid
+func Validate(name string, text string) error {
+	if !IsTemplate(text) {
+		return nil
+	}
+	_, err := parse(name, text, time.Time{})
+	return err
+}
+
+// Render renders the text with the data, plain text is returned as it is
+func Render(name string, text string, data Data) (string, error) {
+	if !IsTemplate(text) {
+		return text, nil
+	}
+
+	tmpl, err := parse(name, text, data.Now)
+	if err != nil {
+		return "", fmt.Errorf("Invalid %s template: %v", name, err)
+	}
+	rendered, err := blockkit.RenderText(tmpl, data)
+	if err != nil {
+		return "", fmt.Errorf("Error rendering %s template: %v", name, err)
+	}
+	return strings.TrimSpace(rendered), nil
+}
+
+// Environment returns the variables of environ, as from os.Environ, prefixed with EnvPrefix without the prefix
+func Environment(environ []string) map[string]string {
+	env := map[string]string{}
+	for _, variable := range environ {
+		if !strings.HasPrefix(variable, EnvPrefix) {
+			continue
+		}
+		if parts := strings.SplitN(strings.TrimPrefix(variable, EnvPrefix), "=", 2); len(parts) == 2 && parts[0] != "" {
+			env[parts[0]] = parts[1]
+		}
+	}
+	return env
+}
+
+// OnCall returns who is on call at now according to a schedule with one "<RFC3339 start> <name>" entry per line,
+// the entry with the latest start before now wins. Empty lines and lines starting with # are skipped.
+func OnCall(schedule string, now time.Time) (string, error) {
+	var onCall string
+	var onCallSince time.Time
+
+	scanner := bufio.NewScanner(strings.NewReader(schedule))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return "", fmt.Errorf("Invalid on-call entry %q, expected \"<RFC3339 start> <name>\"", line)
+		}
+		start, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			return "", fmt.Errorf("Invalid start of on-call entry %q: %v", line, err)
+		}
+
+		if !start.After(now) && (onCall == "" || start.After(onCallSince)) {
+			onCall, onCallSince = strings.TrimSpace(fields[1]), start
+		}
+	}
+
+	return onCall, scanner.Err()
+}
+
+// parse parses the text with the Block Kit helpers, onCall, which finds the current on-call of a schedule, and
+// fromJSON, which decodes JSON such as a status page summary
+func parse(name string, text string, now time.Time) (*template.Template, error) {
+	tmpl, err := blockkit.Parse(name, "")
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.Funcs(template.FuncMap{
+		"onCall": func(schedule string) (string, error) {
+			return OnCall(schedule, now)
+		},
+		"fromJSON": fromJSON,
+	}).Parse(text)
+}
+
+func fromJSON(text string) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return nil, fmt.Errorf("Invalid JSON: %v", err)
+	}
+	return value, nil
+}
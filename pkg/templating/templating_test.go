@@ -0,0 +1,82 @@
+package templating
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const schedule = `
+# weekly rotation
+2026-10-05T09:00:00Z @alice
+2026-10-12T09:00:00Z @bob
+2026-10-19T09:00:00Z @carol
+`
+
+func TestRender_shouldReturnText_whenItIsNoTemplate(t *testing.T) {
+	rendered, err := Render("topic", "Alerts of {team}", Data{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Alerts of {team}", rendered)
+}
+
+func TestRender_shouldRenderTemplate(t *testing.T) {
+	data := Data{
+		Namespace:       "payments",
+		NamespaceLabels: map[string]string{"team": "billing"},
+		Values:          map[string]string{"schedule": schedule, "runbook": "https://runbooks/payments"},
+		Env:             map[string]string{"CLUSTER": "prod-eu"},
+		Now:             time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC),
+	}
+
+	rendered, err := Render("topic", `{{ .NamespaceLabels.team | upper }} on {{ .Env.CLUSTER }}, on call: {{ onCall .Values.schedule }} {{ .Values.runbook }}`, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "BILLING on prod-eu, on call: @bob https://runbooks/payments", rendered)
+}
+
+func TestRender_shouldRenderMissingValuesEmpty(t *testing.T) {
+	rendered, err := Render("topic", `Team {{ .NamespaceLabels.team | default "unknown" }}`, Data{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Team unknown", rendered)
+}
+
+func TestRender_shouldThrowError_whenTemplateIsInvalid(t *testing.T) {
+	_, err := Render("topic", `{{ .Values.team }`, Data{})
+	assert.Error(t, err)
+	assert.Error(t, Validate("topic", `{{ .Values.team }`))
+	assert.NoError(t, Validate("topic", `{{ onCall .Values.schedule }}`))
+}
+
+func TestEnvironment_shouldOnlyReturnPrefixedVariables(t *testing.T) {
+	env := Environment([]string{"SLACK_TEMPLATE_CLUSTER=prod-eu", "SLACK_TEMPLATE_URL=https://x?a=b", "SLACK_TEMPLATE_=x", "SLACK_BOT_TOKEN=secret"})
+	assert.Equal(t, map[string]string{"CLUSTER": "prod-eu", "URL": "https://x?a=b"}, env)
+}
+
+func TestOnCall_shouldReturnLatestStartedEntry(t *testing.T) {
+	onCall, err := OnCall(schedule, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "@carol", onCall)
+
+	onCall, err = OnCall(schedule, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, "", onCall)
+}
+
+func TestOnCall_shouldThrowError_whenEntryIsInvalid(t *testing.T) {
+	_, err := OnCall("next week @alice", time.Now())
+	assert.Error(t, err)
+
+	_, err = OnCall("@alice", time.Now())
+	assert.Error(t, err)
+}
+
+func TestRender_shouldDecodeJSONTopicSource(t *testing.T) {
+	data := Data{TopicSource: `{"status": {"indicator": "minor", "description": "Partial outage"}}`}
+
+	rendered, err := Render("topic", `Status: {{ (fromJSON .TopicSource).status.description }}`, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Status: Partial outage", rendered)
+
+	_, err = Render("topic", `{{ (fromJSON .TopicSource).status }}`, Data{TopicSource: "not json"})
+	assert.Error(t, err)
+}
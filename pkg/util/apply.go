@@ -0,0 +1,122 @@
+package pkgutil
+
+import (
+	"context"
+	goerrors "errors"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// FieldManager is the field manager of the statuses the operator applies
+const FieldManager = "slack-operator"
+
+// ApplyClient is a client whose status updates and patches are server-side applies of the whole status by the
+// FieldManager, status fields of other managers are kept. The resource version is applied along with the status, so
+// writes of stale objects fail with a conflict like updates do.
+type ApplyClient struct {
+	k8sClient.Client
+}
+
+// NewApplyClient wraps the client to apply statuses
+func NewApplyClient(client k8sClient.Client) *ApplyClient {
+	return &ApplyClient{Client: client}
+}
+
+// Status returns a status writer which applies the statuses
+func (c *ApplyClient) Status() k8sClient.StatusWriter {
+	return &applyStatusWriter{client: c.Client}
+}
+
+type applyStatusWriter struct {
+	client k8sClient.Client
+}
+
+// Update applies the status of the object
+func (w *applyStatusWriter) Update(ctx context.Context, obj k8sClient.Object, _ ...k8sClient.UpdateOption) error {
+	return ApplyStatus(ctx, w.client, obj)
+}
+
+// Patch applies the status of the object instead of the patch, the object holds the patched status
+func (w *applyStatusWriter) Patch(ctx context.Context, obj k8sClient.Object, _ k8sClient.Patch, _ ...k8sClient.PatchOption) error {
+	return ApplyStatus(ctx, w.client, obj)
+}
+
+// ApplyStatus applies the status of the object at its resource version with server-side apply, forcing the
+// ownership of the FieldManager. The new resource version is set on the object.
+func ApplyStatus(ctx context.Context, client k8sClient.Client, obj k8sClient.Object) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	applied, err := applyConfiguration(client, obj, map[string]interface{}{"status": content["status"]})
+	if err != nil {
+		return err
+	}
+	applied.SetResourceVersion(obj.GetResourceVersion())
+
+	err = client.Status().Patch(ctx, applied, k8sClient.Apply, k8sClient.FieldOwner(FieldManager), k8sClient.ForceOwnership)
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(applied.GetResourceVersion())
+	return nil
+}
+
+// Apply applies the fields of the object, e.g. {"spec": {"name": "general"}}, with server-side apply by the field
+// manager. Fields the manager applied before and which are left out are removed, fields of other managers are kept.
+// Fields of other managers with other values fail with a conflict unless force is set, see IsApplyConflict. The
+// metadata of the applied object is copied to the object.
+func Apply(ctx context.Context, client k8sClient.Client, obj k8sClient.Object, manager string, fields map[string]interface{}, force bool) error {
+	applied, err := applyConfiguration(client, obj, fields)
+	if err != nil {
+		return err
+	}
+
+	opts := []k8sClient.PatchOption{k8sClient.FieldOwner(manager)}
+	if force {
+		opts = append(opts, k8sClient.ForceOwnership)
+	}
+	if err := client.Patch(ctx, applied, k8sClient.Apply, opts...); err != nil {
+		return err
+	}
+
+	obj.SetResourceVersion(applied.GetResourceVersion())
+	obj.SetGeneration(applied.GetGeneration())
+	obj.SetFinalizers(applied.GetFinalizers())
+	obj.SetLabels(applied.GetLabels())
+	obj.SetAnnotations(applied.GetAnnotations())
+	return nil
+}
+
+// IsApplyConflict checks whether the error is a conflict with fields of another field manager
+func IsApplyConflict(err error) bool {
+	var status errors.APIStatus
+	if !errors.IsConflict(err) || !goerrors.As(err, &status) || status.Status().Details == nil {
+		return false
+	}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			return true
+		}
+	}
+	return false
+}
+
+// applyConfiguration returns the fields as an object of the kind of obj with its name and namespace
+func applyConfiguration(client k8sClient.Client, obj k8sClient.Object, fields map[string]interface{}) (*unstructured.Unstructured, error) {
+	gvk, err := apiutil.GVKForObject(obj, client.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	applied := &unstructured.Unstructured{Object: fields}
+	applied.SetGroupVersionKind(gvk)
+	applied.SetNamespace(obj.GetNamespace())
+	applied.SetName(obj.GetName())
+	return applied, nil
+}
@@ -0,0 +1,27 @@
+package pkgutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsApplyConflict_shouldOnlyMatchFieldManagerConflicts(t *testing.T) {
+	resource := schema.GroupResource{Group: "slack.stakater.com", Resource: "channels"}
+
+	conflict := errors.NewApplyConflict([]metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldManagerConflict,
+		Message: `conflict with "argocd-controller"`,
+		Field:   ".spec.name",
+	}}, "Apply failed with 1 conflict")
+	assert.True(t, IsApplyConflict(conflict))
+	assert.True(t, IsApplyConflict(fmt.Errorf("Error renaming channel: %w", conflict)))
+
+	assert.False(t, IsApplyConflict(errors.NewConflict(resource, "general", fmt.Errorf("the object has been modified"))))
+	assert.False(t, IsApplyConflict(errors.NewNotFound(resource, "general")))
+	assert.False(t, IsApplyConflict(nil))
+}
@@ -6,9 +6,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/slack-go/slack"
+	"github.com/stakater/slack-operator/pkg/blockkit"
 	"github.com/stakater/slack-operator/pkg/config"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,7 +41,7 @@ func ManageError(ctx context.Context, client k8sClient.Client, channelInstance *
 	channelInstancePatchBase := k8sClient.MergeFrom(channelInstance.DeepCopy())
 
 	// Update status
-	channelInstance.Status.Conditions = []metav1.Condition{
+	channelInstance.SetReconcileStatus([]metav1.Condition{
 		{
 			Type:               "ReconcileError",
 			LastTransitionTime: metav1.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), time.Now().Hour(), time.Now().Minute(), 0, 0, time.Now().Location()),
@@ -46,7 +49,7 @@ func ManageError(ctx context.Context, client k8sClient.Client, channelInstance *
 			Reason:             reconcilerUtil.FailedReason,
 			Status:             metav1.ConditionTrue,
 		},
-	}
+	})
 
 	// Patch status
 	err := client.Status().Patch(ctx, channelInstance, channelInstancePatchBase)
@@ -56,3 +59,92 @@ func ManageError(ctx context.Context, client k8sClient.Client, channelInstance *
 
 	return reconcilerUtil.RequeueAfter(config.ErrorRequeueTime)
 }
+
+// GetChannelID fetches the Channel resource and returns the ID of its slack channel
+func GetChannelID(ctx context.Context, client k8sClient.Reader, key types.NamespacedName) (string, error) {
+	channel := &slackv1alpha1.Channel{}
+	if err := client.Get(ctx, key, channel); err != nil {
+		return "", fmt.Errorf("Error fetching Channel %s: %v", key, err)
+	}
+
+	if channel.Status.ID == "" {
+		return "", fmt.Errorf("Channel %s has not been created on Slack yet", key)
+	}
+
+	return channel.Status.ID, nil
+}
+
+// ChannelReferenceKey returns the namespaced name of the referenced Channel, defaulting to the given namespace
+func ChannelReferenceKey(ref slackv1alpha1.ChannelReference, defaultNamespace string) types.NamespacedName {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+// CheckNamespaceAccess checks that a resource in the namespace may reach the target namespace, resources only reach
+// other namespaces when their namespace is one of the TrustedNamespaces
+func CheckNamespaceAccess(namespace string, target string) error {
+	if target == namespace || slackv1alpha1.IsNamespaceTrusted(namespace) {
+		return nil
+	}
+	return fmt.Errorf("Resources in namespace %s can't reach namespace %s, it isn't a trusted namespace", namespace, target)
+}
+
+// GetNotificationTemplate fetches the NotificationTemplate resource and compiles it
+func GetNotificationTemplate(ctx context.Context, client k8sClient.Reader, key types.NamespacedName) (*blockkit.Template, error) {
+	notificationTemplate := &slackv1alpha1.NotificationTemplate{}
+	if err := client.Get(ctx, key, notificationTemplate); err != nil {
+		return nil, fmt.Errorf("Error fetching NotificationTemplate %s: %v", key, err)
+	}
+
+	return blockkit.Compile(key.String(), notificationTemplate.Spec.Blocks, notificationTemplate.Spec.Text)
+}
+
+// NotificationTemplateReferenceKey returns the namespaced name of the referenced NotificationTemplate, defaulting to the given namespace
+func NotificationTemplateReferenceKey(ref slackv1alpha1.NotificationTemplateReference, defaultNamespace string) types.NamespacedName {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+// MessageOptions renders the text or referenced template of a message, the template is executed with data
+// and text is used as fallback text when the template has none
+func MessageOptions(ctx context.Context, client k8sClient.Reader, text string, templateRef *slackv1alpha1.NotificationTemplateReference, namespace string, data interface{}) ([]slack.MsgOption, error) {
+	if templateRef == nil {
+		return []slack.MsgOption{slack.MsgOptionText(text, false)}, nil
+	}
+
+	tmpl, err := GetNotificationTemplate(ctx, client, NotificationTemplateReferenceKey(*templateRef, namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, renderedText, err := tmpl.Render(data)
+	if err != nil {
+		return nil, err
+	}
+	if renderedText == "" {
+		renderedText = text
+	}
+
+	return []slack.MsgOption{slack.MsgOptionText(renderedText, false), slack.MsgOptionBlocks(blocks...)}, nil
+}
+
+// GetOffboardedEmails returns the emails of all users with a UserOffboarding, they must not be added to channels
+// or groups again
+func GetOffboardedEmails(ctx context.Context, client k8sClient.Reader) (map[string]bool, error) {
+	offboardings := &slackv1alpha1.UserOffboardingList{}
+	if err := client.List(ctx, offboardings); err != nil {
+		return nil, fmt.Errorf("Error listing UserOffboardings: %v", err)
+	}
+
+	emails := map[string]bool{}
+	for _, offboarding := range offboardings.Items {
+		emails[strings.ToLower(offboarding.Spec.Email)] = true
+	}
+	return emails, nil
+}
@@ -0,0 +1,171 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// ServiceAccountTokenPath is where the token of the operator's service account is mounted
+	ServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// renewBefore is how long before its lease ends the Vault token is replaced by logging in again
+	renewBefore = time.Minute
+)
+
+// Config configures where the Slack tokens are read from in Vault
+type Config struct {
+	// Address of the Vault server, e.g. https://vault.example.com:8200
+	Address string
+	// AuthMount is the mount path of the Kubernetes auth method, e.g. kubernetes
+	AuthMount string
+	// Role is the Vault role the service account logs in as
+	Role string
+	// SecretPath is the API path of the secret, e.g. secret/data/slack-operator for a KV v2 engine
+	SecretPath string
+	// JWTPath is the file of the service account token, defaults to ServiceAccountTokenPath
+	JWTPath string
+}
+
+// Client reads secrets from Vault, it logs in with the Kubernetes auth method and logs in again before the lease
+// of its Vault token ends
+type Client struct {
+	log        logr.Logger
+	config     Config
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+type loginResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+type secretResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// New creates a Vault client
+func New(config Config, httpClient *http.Client, logger logr.Logger) *Client {
+	config.Address = strings.TrimSuffix(config.Address, "/")
+	if config.AuthMount == "" {
+		config.AuthMount = "kubernetes"
+	}
+	if config.JWTPath == "" {
+		config.JWTPath = ServiceAccountTokenPath
+	}
+	return &Client{
+		log:        logger,
+		config:     config,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+// ReadSecret returns the string values of the secret. Values of KV v2 secrets are unwrapped from their data field.
+func (c *Client) ReadSecret(ctx context.Context) (map[string]string, error) {
+	token, err := c.vaultToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &secretResponse{}
+	if err := c.do(ctx, http.MethodGet, c.config.SecretPath, token, nil, response); err != nil {
+		return nil, err
+	}
+
+	data := response.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	values := map[string]string{}
+	for key, value := range data {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		}
+	}
+	return values, nil
+}
+
+// vaultToken returns the Vault token, logging in when there is none or its lease ends within renewBefore
+func (c *Client) vaultToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.now().Before(c.expiresAt.Add(-renewBefore)) {
+		return c.token, nil
+	}
+
+	jwt, err := ioutil.ReadFile(c.config.JWTPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading service account token: %v", err)
+	}
+
+	c.log.V(1).Info("Logging in to Vault", "role", c.config.Role)
+	response := &loginResponse{}
+	err = c.do(ctx, http.MethodPost, "auth/"+c.config.AuthMount+"/login", "", map[string]string{
+		"role": c.config.Role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	}, response)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = response.Auth.ClientToken
+	c.expiresAt = c.now().Add(time.Duration(response.Auth.LeaseDuration) * time.Second)
+	return c.token, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, token string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.config.Address+"/v1/"+path, reader)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		vaultErr := &errorResponse{}
+		_ = json.NewDecoder(resp.Body).Decode(vaultErr)
+		return fmt.Errorf("vault request %s %s failed with status %d: %s", method, path, resp.StatusCode, strings.Join(vaultErr.Errors, ", "))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
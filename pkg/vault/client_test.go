@@ -0,0 +1,75 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var log = zap.New()
+
+func newTestClient(t *testing.T) (*Client, *int) {
+	logins := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/kubernetes/login", func(w http.ResponseWriter, r *http.Request) {
+		login := map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		assert.Equal(t, "slack-operator", login["role"])
+		assert.Equal(t, "service-account-jwt", login["jwt"])
+		logins++
+		_, _ = w.Write([]byte(`{"auth": {"client_token": "s.vault", "lease_duration": 3600}}`))
+	})
+	mux.HandleFunc("/v1/secret/data/slack-operator", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.vault" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"APIToken": "xoxb-vault"}, "metadata": {"version": 2}}}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	jwtPath := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, ioutil.WriteFile(jwtPath, []byte("service-account-jwt\n"), os.ModePerm))
+
+	client := New(Config{
+		Address:    server.URL,
+		Role:       "slack-operator",
+		SecretPath: "secret/data/slack-operator",
+		JWTPath:    jwtPath,
+	}, http.DefaultClient, log)
+	return client, &logins
+}
+
+func TestClient_ReadSecret_shouldReadKVv2Secret(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	values, err := client.ReadSecret(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "xoxb-vault", values["APIToken"])
+}
+
+func TestClient_ReadSecret_shouldLoginAgain_whenLeaseEnds(t *testing.T) {
+	client, logins := newTestClient(t)
+
+	_, _ = client.ReadSecret(context.Background())
+	_, _ = client.ReadSecret(context.Background())
+	assert.Equal(t, 1, *logins)
+
+	client.now = func() time.Time { return time.Now().Add(time.Hour) }
+	_, err := client.ReadSecret(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *logins)
+}